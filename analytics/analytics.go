@@ -0,0 +1,277 @@
+// Package analytics computes screening statistics over candle series: average
+// volume, volatility, price gaps, rolling extremes and volume profiles. Series
+// are expected in ascending time order, as Fetch returns them.
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/indicators"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// GapDirection tells a gap up from a gap down.
+type GapDirection string
+
+const (
+	GapUp   GapDirection = "up"
+	GapDown GapDirection = "down"
+)
+
+// PriceGap is a candle opening away from the previous candle's close.
+type PriceGap struct {
+	// Index is the position in the series of the candle that gapped.
+	Index     int
+	DateTime  time.Time
+	Direction GapDirection
+	PrevClose float64
+	Open      float64
+	// Percent is the move from PrevClose to Open, negative for gaps down.
+	Percent float64
+}
+
+// Extremes is the highest high and lowest low of a span of candles.
+type Extremes struct {
+	High   float64
+	HighAt time.Time
+	Low    float64
+	LowAt  time.Time
+}
+
+// Volatility is the latest average true range of a series, in price and as a
+// percentage of the last close.
+type Volatility struct {
+	ATR        float64
+	ATRPercent float64
+}
+
+// VolumeBin is the volume traded between Low and High.
+type VolumeBin struct {
+	Low    float64
+	High   float64
+	Volume int64
+}
+
+// Stats summarizes a daily series with the common screening numbers.
+type Stats struct {
+	Last               types.OHLCV
+	AverageDailyVolume float64
+	Volatility         Volatility
+	FiftyTwoWeek       Extremes
+	PercentFromHigh    float64
+	PercentFromLow     float64
+	ChangePercent      float64
+	LastGap            *PriceGap
+}
+
+// AverageDailyVolume returns the mean volume of the last days trading days
+// of candles. Intraday candles are summed per day first, in the candles'
+// location. It is NaN if candles is empty or days is not positive.
+func AverageDailyVolume(candles types.Series, days int) float64 {
+	if len(candles) == 0 || days <= 0 {
+		return math.NaN()
+	}
+
+	var (
+		total, day int64
+		count      int
+	)
+	for i := len(candles) - 1; i >= 0; i-- {
+		day += candles[i].Volume
+		if i > 0 && sameDay(candles[i].DateTime, candles[i-1].DateTime) {
+			continue
+		}
+
+		total += day
+		day = 0
+		count++
+		if count == days {
+			break
+		}
+	}
+	return float64(total) / float64(count)
+}
+
+// ATRVolatility returns the latest Wilder's average true range of candles
+// over period, and the same as a percentage of the last close. Both are NaN
+// while there are fewer than period candles.
+func ATRVolatility(candles types.Series, period int) Volatility {
+	atr := indicators.ATR(candles, period)
+	if len(atr) == 0 {
+		return Volatility{ATR: math.NaN(), ATRPercent: math.NaN()}
+	}
+
+	last := atr[len(atr)-1]
+	v := Volatility{ATR: last, ATRPercent: math.NaN()}
+	if c := candles[len(candles)-1].Close; c != 0 {
+		v.ATRPercent = last / c * 100
+	}
+	return v
+}
+
+// Gaps returns the candles that opened at least minPercent away from the
+// previous close, above it for gaps up and below it for gaps down.
+func Gaps(candles types.Series, minPercent float64) []PriceGap {
+	var gaps []PriceGap
+	for i := 1; i < len(candles); i++ {
+		prev, c := candles[i-1].Close, candles[i].Open
+		if prev == 0 {
+			continue
+		}
+
+		pct := (c - prev) / prev * 100
+		if math.Abs(pct) < minPercent || pct == 0 {
+			continue
+		}
+
+		dir := GapUp
+		if pct < 0 {
+			dir = GapDown
+		}
+		gaps = append(gaps, PriceGap{
+			Index:     i,
+			DateTime:  candles[i].DateTime,
+			Direction: dir,
+			PrevClose: prev,
+			Open:      c,
+			Percent:   pct,
+		})
+	}
+	return gaps
+}
+
+// RollingHigh returns the highest high of the last period candles up to each
+// candle, NaN until period candles are available.
+func RollingHigh(candles types.Series, period int) []float64 {
+	return rolling(candles, period, func(c types.OHLCV) float64 { return c.High }, math.Max)
+}
+
+// RollingLow returns the lowest low of the last period candles up to each
+// candle, NaN until period candles are available.
+func RollingLow(candles types.Series, period int) []float64 {
+	return rolling(candles, period, func(c types.OHLCV) float64 { return c.Low }, math.Min)
+}
+
+func rolling(candles types.Series, period int, field func(types.OHLCV) float64, pick func(a, b float64) float64) []float64 {
+	out := make([]float64, len(candles))
+	for i := range out {
+		out[i] = math.NaN()
+		if period <= 0 || i < period-1 {
+			continue
+		}
+
+		v := field(candles[i])
+		for _, c := range candles[i-period+1 : i] {
+			v = pick(v, field(c))
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// ExtremesSince returns the highest high and lowest low of the candles at or
+// after since. ok is false if there are none.
+func ExtremesSince(candles types.Series, since time.Time) (e Extremes, ok bool) {
+	for _, c := range candles {
+		if c.DateTime.Before(since) {
+			continue
+		}
+		if !ok || c.High > e.High {
+			e.High, e.HighAt = c.High, c.DateTime
+		}
+		if !ok || c.Low < e.Low {
+			e.Low, e.LowAt = c.Low, c.DateTime
+		}
+		ok = true
+	}
+	return e, ok
+}
+
+// FiftyTwoWeek returns the 52-week high and low: the extremes of the candles
+// within 52 weeks of the last one.
+func FiftyTwoWeek(candles types.Series) (Extremes, bool) {
+	if len(candles) == 0 {
+		return Extremes{}, false
+	}
+	return ExtremesSince(candles, candles[len(candles)-1].DateTime.AddDate(0, 0, -52*7))
+}
+
+// VolumeProfile splits the price range of candles into bins of equal width
+// and adds each candle's volume to the bin holding its typical price. Bins
+// are ordered from the lowest price up.
+func VolumeProfile(candles types.Series, bins int) []VolumeBin {
+	if len(candles) == 0 || bins <= 0 {
+		return nil
+	}
+
+	low, high := candles[0].Low, candles[0].High
+	for _, c := range candles[1:] {
+		low = min(low, c.Low)
+		high = max(high, c.High)
+	}
+
+	width := (high - low) / float64(bins)
+	profile := make([]VolumeBin, bins)
+	for i := range profile {
+		profile[i].Low = low + float64(i)*width
+		profile[i].High = low + float64(i+1)*width
+	}
+	profile[bins-1].High = high
+
+	for _, c := range candles {
+		i := bins - 1
+		if width > 0 {
+			i = min(int(((c.High+c.Low+c.Close)/3-low)/width), bins-1)
+		}
+		profile[max(i, 0)].Volume += c.Volume
+	}
+	return profile
+}
+
+// Summarize computes Stats for a daily series: 20-day average volume, 14-day
+// ATR, the 52-week range and how far the last close is from it, the last
+// day's change and its most recent gap of at least 1%.
+func Summarize(candles types.Series) Stats {
+	if len(candles) == 0 {
+		return Stats{}
+	}
+
+	last := candles[len(candles)-1]
+	s := Stats{
+		Last:               last,
+		AverageDailyVolume: AverageDailyVolume(candles, 20),
+		Volatility:         ATRVolatility(candles, 14),
+		PercentFromHigh:    math.NaN(),
+		PercentFromLow:     math.NaN(),
+		ChangePercent:      math.NaN(),
+	}
+
+	if e, ok := FiftyTwoWeek(candles); ok {
+		s.FiftyTwoWeek = e
+		if e.High != 0 {
+			s.PercentFromHigh = (last.Close - e.High) / e.High * 100
+		}
+		if e.Low != 0 {
+			s.PercentFromLow = (last.Close - e.Low) / e.Low * 100
+		}
+	}
+
+	if len(candles) > 1 {
+		if prev := candles[len(candles)-2].Close; prev != 0 {
+			s.ChangePercent = (last.Close - prev) / prev * 100
+		}
+	}
+
+	if gaps := Gaps(candles, 1); len(gaps) > 0 {
+		s.LastGap = &gaps[len(gaps)-1]
+	}
+
+	return s
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,209 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var nan = math.NaN()
+
+func assertSeries(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %d values, got %d", name, len(want), len(got))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				t.Errorf("%s[%d]: expected NaN, got %v", name, i, got[i])
+			}
+			continue
+		}
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("%s[%d]: expected %v, got %v", name, i, want[i], got[i])
+		}
+	}
+}
+
+// daily returns one candle per day from 2024-01-01 with the given closes,
+// opening at the previous close and ranging one point either side.
+func daily(closes ...float64) types.Series {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := make(types.Series, len(closes))
+	for i, c := range closes {
+		open := c
+		if i > 0 {
+			open = closes[i-1]
+		}
+		s[i] = types.OHLCV{
+			DateTime: start.AddDate(0, 0, i),
+			Open:     open,
+			High:     max(open, c) + 1,
+			Low:      min(open, c) - 1,
+			Close:    c,
+			Volume:   int64(100 * (i + 1)),
+		}
+	}
+	return s
+}
+
+func TestAverageDailyVolume(t *testing.T) {
+	s := daily(10, 11, 12, 13)
+
+	if got := AverageDailyVolume(s, 2); got != 350 {
+		t.Errorf("Expected 350, got %v", got)
+	}
+	if got := AverageDailyVolume(s, 10); got != 250 {
+		t.Errorf("Expected the mean of all 4 days, got %v", got)
+	}
+	if got := AverageDailyVolume(nil, 2); !math.IsNaN(got) {
+		t.Errorf("Expected NaN for no candles, got %v", got)
+	}
+}
+
+func TestAverageDailyVolume_Intraday(t *testing.T) {
+	day := time.Date(2024, 1, 2, 9, 15, 0, 0, time.UTC)
+	s := types.Series{
+		{DateTime: day, Volume: 100},
+		{DateTime: day.Add(time.Minute), Volume: 300},
+		{DateTime: day.AddDate(0, 0, 1), Volume: 50},
+		{DateTime: day.AddDate(0, 0, 1).Add(time.Minute), Volume: 150},
+	}
+
+	if got := AverageDailyVolume(s, 2); got != 300 {
+		t.Errorf("Expected days of 400 and 200 to average 300, got %v", got)
+	}
+}
+
+func TestATRVolatility(t *testing.T) {
+	candles := types.Series{
+		{High: 10, Low: 8, Close: 9},
+		{High: 12, Low: 9, Close: 11},
+		{High: 11, Low: 10, Close: 10.5},
+		{High: 15, Low: 12, Close: 12.5},
+	}
+
+	v := ATRVolatility(candles, 2)
+	if v.ATR != 3.125 || v.ATRPercent != 25 {
+		t.Errorf("Expected ATR 3.125 at 25%%, got %+v", v)
+	}
+
+	v = ATRVolatility(candles, 10)
+	if !math.IsNaN(v.ATR) || !math.IsNaN(v.ATRPercent) {
+		t.Errorf("Expected NaN with too few candles, got %+v", v)
+	}
+}
+
+func TestGaps(t *testing.T) {
+	s := types.Series{
+		{Open: 100, Close: 100},
+		{Open: 103, Close: 104},
+		{Open: 103.5, Close: 100},
+		{Open: 95, Close: 96},
+	}
+
+	gaps := Gaps(s, 1)
+	if len(gaps) != 2 {
+		t.Fatalf("Expected 2 gaps, got %+v", gaps)
+	}
+	if gaps[0].Index != 1 || gaps[0].Direction != GapUp || gaps[0].Percent != 3 {
+		t.Errorf("Expected a 3%% gap up at 1, got %+v", gaps[0])
+	}
+	if gaps[1].Index != 3 || gaps[1].Direction != GapDown || gaps[1].Percent != -5 || gaps[1].PrevClose != 100 {
+		t.Errorf("Expected a 5%% gap down at 3, got %+v", gaps[1])
+	}
+}
+
+func TestRollingHighLow(t *testing.T) {
+	s := types.Series{
+		{High: 10, Low: 5},
+		{High: 12, Low: 7},
+		{High: 11, Low: 4},
+		{High: 9, Low: 6},
+	}
+
+	assertSeries(t, "RollingHigh", RollingHigh(s, 2), []float64{nan, 12, 12, 11})
+	assertSeries(t, "RollingLow", RollingLow(s, 3), []float64{nan, nan, 4, 4})
+	assertSeries(t, "RollingHigh(0)", RollingHigh(s, 0), []float64{nan, nan, nan, nan})
+}
+
+func TestFiftyTwoWeek(t *testing.T) {
+	end := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	s := types.Series{
+		{DateTime: end.AddDate(-1, 0, 0), High: 500, Low: 10},
+		{DateTime: end.AddDate(0, -6, 0), High: 120, Low: 80},
+		{DateTime: end.AddDate(0, -1, 0), High: 150, Low: 90},
+		{DateTime: end, High: 110, Low: 100},
+	}
+
+	e, ok := FiftyTwoWeek(s)
+	if !ok {
+		t.Fatal("Expected extremes")
+	}
+	if e.High != 150 || !e.HighAt.Equal(s[2].DateTime) {
+		t.Errorf("Expected 52-week high 150 a month ago, got %v at %v", e.High, e.HighAt)
+	}
+	if e.Low != 80 || !e.LowAt.Equal(s[1].DateTime) {
+		t.Errorf("Expected 52-week low 80 six months ago, got %v at %v", e.Low, e.LowAt)
+	}
+
+	if _, ok := FiftyTwoWeek(nil); ok {
+		t.Error("Expected no extremes for no candles")
+	}
+}
+
+func TestVolumeProfile(t *testing.T) {
+	s := types.Series{
+		{High: 102, Low: 100, Close: 101, Volume: 100},
+		{High: 110, Low: 108, Close: 109, Volume: 300},
+		{High: 103, Low: 101, Close: 102, Volume: 50},
+	}
+
+	profile := VolumeProfile(s, 2)
+	if len(profile) != 2 {
+		t.Fatalf("Expected 2 bins, got %d", len(profile))
+	}
+	if profile[0].Low != 100 || profile[0].High != 105 || profile[0].Volume != 150 {
+		t.Errorf("Unexpected lower bin: %+v", profile[0])
+	}
+	if profile[1].Low != 105 || profile[1].High != 110 || profile[1].Volume != 300 {
+		t.Errorf("Unexpected upper bin: %+v", profile[1])
+	}
+
+	flat := VolumeProfile(types.Series{{High: 10, Low: 10, Close: 10, Volume: 5}}, 3)
+	if flat[2].Volume != 5 {
+		t.Errorf("Expected a flat series in the top bin, got %+v", flat)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := daily(100, 100, 110, 99)
+	stats := Summarize(s)
+
+	if stats.Last.Close != 99 {
+		t.Errorf("Expected last close 99, got %v", stats.Last.Close)
+	}
+	if stats.AverageDailyVolume != 250 {
+		t.Errorf("Expected average volume 250, got %v", stats.AverageDailyVolume)
+	}
+	if !math.IsNaN(stats.Volatility.ATR) {
+		t.Errorf("Expected NaN ATR with fewer than 14 candles, got %v", stats.Volatility.ATR)
+	}
+	if stats.FiftyTwoWeek.High != 111 || stats.PercentFromHigh != (99.0-111)/111*100 {
+		t.Errorf("Unexpected 52-week high: %+v, %v", stats.FiftyTwoWeek, stats.PercentFromHigh)
+	}
+	if stats.ChangePercent != -10 {
+		t.Errorf("Expected -10%% change, got %v", stats.ChangePercent)
+	}
+	if stats.LastGap != nil {
+		t.Errorf("Expected no gaps when candles open at the previous close, got %+v", stats.LastGap)
+	}
+
+	if got := Summarize(nil); got.LastGap != nil || got.Last.Close != 0 {
+		t.Errorf("Expected zero Stats for no candles, got %+v", got)
+	}
+}
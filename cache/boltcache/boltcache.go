@@ -0,0 +1,256 @@
+package boltcache
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var (
+	candlesBucket  = []byte("candles")
+	coverageBucket = []byte("coverage")
+)
+
+// segment is a range whose candles have been stored, valid until ExpiresAt.
+type segment struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type Option func(*BoltCache)
+
+// WithTTLs overrides cache.DefaultTTLs.
+func WithTTLs(ttls cache.TTLs) Option {
+	return func(b *BoltCache) {
+		b.ttls = ttls
+	}
+}
+
+// BoltCache is a persistent cache.RangeCache backed by a bbolt database.
+// Candles are stored per symbol, exchange and interval, keyed by time, along
+// with the ranges that have been fetched, so overlapping requests only need
+// the uncovered parts from providers.
+type BoltCache struct {
+	db   *bolt.DB
+	ttls cache.TTLs
+	now  func() time.Time
+}
+
+func NewBoltCache(path string, opts ...Option) (*BoltCache, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(candlesBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(coverageBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise cache database: %w", err)
+	}
+
+	b := &BoltCache{
+		db:   db,
+		ttls: cache.DefaultTTLs,
+		now:  time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b, nil
+}
+
+func (b *BoltCache) Close() error {
+	return b.db.Close()
+}
+
+// Get returns the cached candles for key only if its whole range is cached.
+func (b *BoltCache) Get(key cache.Key) ([]types.OHLCV, bool) {
+	ohlcvs, missing := b.GetRange(key)
+	if len(missing) > 0 || len(ohlcvs) == 0 {
+		return nil, false
+	}
+	return ohlcvs, true
+}
+
+func (b *BoltCache) GetRange(key cache.Key) ([]types.OHLCV, []cache.Range) {
+	want := cache.Range{From: key.From, To: key.To}
+	if key.To.IsZero() {
+		// Open-ended ranges keep growing, so they can never be fully covered.
+		return nil, []cache.Range{want}
+	}
+
+	var ohlcvs []types.OHLCV
+	var covered []cache.Range
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		segments, err := readSegments(tx, seriesKey(key))
+		if err != nil {
+			return err
+		}
+
+		now := b.now()
+		for _, s := range segments {
+			if now.Before(s.ExpiresAt) {
+				covered = append(covered, cache.Range{From: s.From, To: s.To})
+			}
+		}
+		if len(covered) == 0 {
+			return nil
+		}
+
+		bucket := tx.Bucket(candlesBucket).Bucket(seriesKey(key))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, v := c.Seek(timeKey(key.From)); k != nil && !keyTime(k).After(key.To); k, v = c.Next() {
+			t := keyTime(k)
+			if !contains(covered, t) {
+				continue
+			}
+
+			var ohlcv types.OHLCV
+			if err := json.Unmarshal(v, &ohlcv); err != nil {
+				return err
+			}
+			// JSON keeps only the UTC offset, so restore the caller's zone.
+			ohlcv.DateTime = ohlcv.DateTime.In(key.From.Location())
+			ohlcvs = append(ohlcvs, ohlcv)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, []cache.Range{want}
+	}
+
+	return ohlcvs, subtract(want, covered)
+}
+
+// Set stores ohlcvs and marks key's range as covered for the TTL of their
+// freshness class.
+func (b *BoltCache) Set(key cache.Key, ohlcvs []types.OHLCV) {
+	ttl := b.ttls.For(ohlcvs)
+	if ttl <= 0 || key.To.IsZero() {
+		return
+	}
+
+	_ = b.db.Update(func(tx *bolt.Tx) error {
+		series := seriesKey(key)
+
+		bucket, err := tx.Bucket(candlesBucket).CreateBucketIfNotExists(series)
+		if err != nil {
+			return err
+		}
+		for _, ohlcv := range ohlcvs {
+			v, err := json.Marshal(ohlcv)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(timeKey(ohlcv.DateTime), v); err != nil {
+				return err
+			}
+		}
+
+		segments, err := readSegments(tx, series)
+		if err != nil {
+			return err
+		}
+
+		now := b.now()
+		segments = slices.DeleteFunc(segments, func(s segment) bool {
+			return !now.Before(s.ExpiresAt)
+		})
+		segments = append(segments, segment{From: key.From, To: key.To, ExpiresAt: now.Add(ttl)})
+
+		v, err := json.Marshal(segments)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(coverageBucket).Put(series, v)
+	})
+}
+
+func readSegments(tx *bolt.Tx, series []byte) ([]segment, error) {
+	v := tx.Bucket(coverageBucket).Get(series)
+	if v == nil {
+		return nil, nil
+	}
+
+	var segments []segment
+	if err := json.Unmarshal(v, &segments); err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+func seriesKey(key cache.Key) []byte {
+	series := fmt.Sprintf("%s:%s:%s", key.Exchange, key.Symbol, key.Interval)
+	if key.Adjusted {
+		series += ":adj"
+	}
+	return []byte(series)
+}
+
+func timeKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	return k
+}
+
+func keyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k)))
+}
+
+func contains(ranges []cache.Range, t time.Time) bool {
+	for _, r := range ranges {
+		if !t.Before(r.From) && !t.After(r.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// subtract returns the parts of want not covered by any range in covered.
+func subtract(want cache.Range, covered []cache.Range) []cache.Range {
+	covered = slices.Clone(covered)
+	slices.SortFunc(covered, func(a, b cache.Range) int {
+		return a.From.Compare(b.From)
+	})
+
+	var missing []cache.Range
+	cursor := want.From
+	for _, r := range covered {
+		if r.To.Before(cursor) || r.From.After(want.To) {
+			continue
+		}
+		if r.From.After(cursor) {
+			missing = append(missing, cache.Range{From: cursor, To: r.From.Add(-time.Nanosecond)})
+		}
+		if !r.To.Before(cursor) {
+			cursor = r.To.Add(time.Nanosecond)
+		}
+		if cursor.After(want.To) {
+			return missing
+		}
+	}
+
+	return append(missing, cache.Range{From: cursor, To: want.To})
+}
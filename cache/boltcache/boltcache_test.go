@@ -0,0 +1,194 @@
+package boltcache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var day0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time {
+	return day0.AddDate(0, 0, n)
+}
+
+func candles(from, to int) []types.OHLCV {
+	var ohlcvs []types.OHLCV
+	for i := from; i <= to; i++ {
+		ohlcvs = append(ohlcvs, types.OHLCV{
+			Symbol:    "INFY",
+			Exchange:  types.ExchangeNSE,
+			Close:     float64(i),
+			DateTime:  day(i),
+			Freshness: types.FreshnessHistorical,
+		})
+	}
+	return ohlcvs
+}
+
+func key(from, to int) cache.Key {
+	return cache.Key{Symbol: "INFY", Exchange: types.ExchangeNSE, Interval: types.Interval1d, From: day(from), To: day(to)}
+}
+
+func newTestCache(t *testing.T, path string, opts ...Option) *BoltCache {
+	t.Helper()
+
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "cache.db")
+	}
+	b, err := NewBoltCache(path, opts...)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestNewBoltCache_InvalidPath(t *testing.T) {
+	_, err := NewBoltCache(filepath.Join(t.TempDir(), "missing", "cache.db"))
+	if err == nil {
+		t.Error("Expected error for invalid path")
+	}
+}
+
+func TestBoltCache_GetSet(t *testing.T) {
+	b := newTestCache(t, "")
+
+	if _, ok := b.Get(key(0, 4)); ok {
+		t.Error("Expected miss on empty cache")
+	}
+
+	b.Set(key(0, 4), candles(0, 4))
+
+	got, ok := b.Get(key(0, 4))
+	if !ok {
+		t.Fatal("Expected hit after Set")
+	}
+	if len(got) != 5 || got[0].Close != 0 || got[4].Close != 4 {
+		t.Errorf("Unexpected cached candles %v", got)
+	}
+
+	sub, ok := b.Get(key(1, 2))
+	if !ok || len(sub) != 2 || sub[0].Close != 1 {
+		t.Errorf("Expected covered sub-range to hit, got %v %v", sub, ok)
+	}
+}
+
+func TestBoltCache_GetRange_Missing(t *testing.T) {
+	b := newTestCache(t, "")
+	b.Set(key(2, 4), candles(2, 4))
+	b.Set(key(7, 8), candles(7, 8))
+
+	got, missing := b.GetRange(key(0, 10))
+
+	if len(got) != 5 {
+		t.Errorf("Expected 5 cached candles, got %d", len(got))
+	}
+	if len(missing) != 3 {
+		t.Fatalf("Expected 3 missing ranges, got %v", missing)
+	}
+	if !missing[0].From.Equal(day(0)) || !missing[0].To.Before(day(2)) {
+		t.Errorf("Unexpected first missing range %v", missing[0])
+	}
+	if !missing[1].From.After(day(4)) || !missing[1].To.Before(day(7)) {
+		t.Errorf("Unexpected second missing range %v", missing[1])
+	}
+	if !missing[2].From.After(day(8)) || !missing[2].To.Equal(day(10)) {
+		t.Errorf("Unexpected third missing range %v", missing[2])
+	}
+
+	if _, ok := b.Get(key(0, 10)); ok {
+		t.Error("Expected Get to miss a partially cached range")
+	}
+}
+
+func TestBoltCache_OpenEndedRange(t *testing.T) {
+	b := newTestCache(t, "")
+	k := key(0, 0)
+	k.To = time.Time{}
+
+	b.Set(k, candles(0, 2))
+	_, missing := b.GetRange(k)
+
+	if len(missing) != 1 {
+		t.Errorf("Expected open-ended range to be reported missing, got %v", missing)
+	}
+}
+
+func TestBoltCache_Expiry(t *testing.T) {
+	now := day(100)
+	b := newTestCache(t, "", WithTTLs(cache.TTLs{types.FreshnessHistorical: time.Hour}))
+	b.now = func() time.Time { return now }
+
+	b.Set(key(0, 2), candles(0, 2))
+	if _, ok := b.Get(key(0, 2)); !ok {
+		t.Error("Expected hit before expiry")
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := b.Get(key(0, 2)); ok {
+		t.Error("Expected miss after expiry")
+	}
+}
+
+func TestBoltCache_SkipsUncacheable(t *testing.T) {
+	b := newTestCache(t, "")
+	realtime := candles(0, 1)
+	for i := range realtime {
+		realtime[i].Freshness = types.FreshnessRealtime
+	}
+
+	b.Set(key(0, 1), realtime)
+
+	if _, ok := b.Get(key(0, 1)); ok {
+		t.Error("Expected realtime candles not to be cached")
+	}
+}
+
+func TestBoltCache_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	b, err := NewBoltCache(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b.Set(key(0, 3), candles(0, 3))
+	b.Close()
+
+	reopened := newTestCache(t, path)
+	got, ok := reopened.Get(key(0, 3))
+	if !ok || len(got) != 4 {
+		t.Errorf("Expected candles to survive reopening, got %v %v", got, ok)
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	r := func(from, to int) cache.Range { return cache.Range{From: day(from), To: day(to)} }
+
+	tests := []struct {
+		name    string
+		want    cache.Range
+		covered []cache.Range
+		missing int
+	}{
+		{"NothingCovered", r(0, 5), nil, 1},
+		{"FullyCovered", r(1, 2), []cache.Range{r(0, 5)}, 0},
+		{"CoveredStart", r(0, 5), []cache.Range{r(0, 2)}, 1},
+		{"CoveredEnd", r(0, 5), []cache.Range{r(3, 5)}, 1},
+		{"CoveredMiddle", r(0, 5), []cache.Range{r(2, 3)}, 2},
+		{"Overlapping", r(0, 5), []cache.Range{r(0, 3), r(2, 5)}, 0},
+		{"Unsorted", r(0, 9), []cache.Range{r(6, 7), r(2, 3)}, 3},
+		{"Disjoint", r(0, 2), []cache.Range{r(4, 5)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subtract(tt.want, tt.covered); len(got) != tt.missing {
+				t.Errorf("Expected %d missing ranges, got %v", tt.missing, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Key identifies a cached Fetch result.
+type Key struct {
+	Symbol   string
+	Exchange types.Exchange
+	Interval types.Interval
+	From     time.Time
+	To       time.Time
+	Adjusted bool
+}
+
+func (k Key) String() string {
+	s := fmt.Sprintf("%s:%s:%s:%d:%d", k.Symbol, k.Exchange, k.Interval, unixNano(k.From), unixNano(k.To))
+	if k.Adjusted {
+		s += ":adj"
+	}
+	return s
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+type Cache interface {
+	Get(key Key) ([]types.OHLCV, bool)
+	Set(key Key, ohlcvs []types.OHLCV)
+}
+
+// Range is an inclusive time range.
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// RangeCache is implemented by caches that store candles individually, so a
+// request that is only partly cached can be completed by fetching just the
+// missing ranges.
+type RangeCache interface {
+	Cache
+	// GetRange returns the cached candles within key's range along with the
+	// sub-ranges that are not cached.
+	GetRange(key Key) (ohlcvs []types.OHLCV, missing []Range)
+}
+
+// StaleCache is implemented by caches that keep entries past their TTL for a
+// grace period, so that they can be served while being refreshed in the
+// background.
+type StaleCache interface {
+	Cache
+	// GetStale returns key's candles like Get, and also those past their
+	// TTL but still within the grace period, reporting them as stale.
+	GetStale(key Key) (ohlcvs []types.OHLCV, stale bool, ok bool)
+}
+
+// TTLs maps a freshness class to how long candles of that class stay cached.
+// Classes that are missing or have a non-positive TTL are not cached.
+type TTLs map[types.DataFreshness]time.Duration
+
+// DefaultTTLs never caches realtime data and keeps historical data for a day.
+var DefaultTTLs = TTLs{
+	types.FreshnessDelayed:    time.Minute,
+	types.FreshnessEndOfDay:   time.Hour,
+	types.FreshnessHistorical: 24 * time.Hour,
+}
+
+// For returns the TTL for ohlcvs, which is the shortest TTL of the freshness
+// classes present.
+func (t TTLs) For(ohlcvs []types.OHLCV) time.Duration {
+	if len(ohlcvs) == 0 {
+		return 0
+	}
+
+	ttl := t[ohlcvs[0].Freshness]
+	for _, c := range ohlcvs[1:] {
+		ttl = min(ttl, t[c.Freshness])
+	}
+
+	return ttl
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestKey_String(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a := Key{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, Interval: types.Interval1d, From: from}
+	b := Key{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, Interval: types.Interval1d, From: from.In(ist)}
+	c := Key{Symbol: "RELIANCE", Exchange: types.ExchangeBSE, Interval: types.Interval1d, From: from}
+
+	if a.String() != b.String() {
+		t.Errorf("Expected same key for equal instants, got %s and %s", a, b)
+	}
+	if a.String() == c.String() {
+		t.Error("Expected different keys for different exchanges")
+	}
+
+	adjusted := a
+	adjusted.Adjusted = true
+	if a.String() == adjusted.String() {
+		t.Error("Expected different keys for raw and adjusted prices")
+	}
+}
+
+func TestTTLs_For(t *testing.T) {
+	ttls := TTLs{
+		types.FreshnessDelayed:    time.Minute,
+		types.FreshnessHistorical: time.Hour,
+	}
+
+	tests := []struct {
+		name   string
+		ohlcvs []types.OHLCV
+		want   time.Duration
+	}{
+		{"Empty", nil, 0},
+		{"Historical", []types.OHLCV{{Freshness: types.FreshnessHistorical}}, time.Hour},
+		{"Mixed", []types.OHLCV{{Freshness: types.FreshnessHistorical}, {Freshness: types.FreshnessDelayed}}, time.Minute},
+		{"Unknown", []types.OHLCV{{Freshness: types.FreshnessRealtime}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ttls.For(tt.ohlcvs); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
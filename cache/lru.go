@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type lruEntry struct {
+	key       string
+	ohlcvs    []types.OHLCV
+	expiresAt time.Time
+	// staleUntil is when the entry stops being served stale by GetStale.
+	staleUntil time.Time
+}
+
+type LRUOption func(*LRU)
+
+// WithTTLs overrides DefaultTTLs.
+func WithTTLs(ttls TTLs) LRUOption {
+	return func(l *LRU) {
+		l.ttls = ttls
+	}
+}
+
+// WithStaleWhileRevalidate keeps entries for the given extra time past their
+// TTL, per freshness class, during which GetStale still returns them as
+// stale. MarketData serves such entries at once and refreshes them in the
+// background. Classes that are missing get no extra time.
+func WithStaleWhileRevalidate(stale TTLs) LRUOption {
+	return func(l *LRU) {
+		l.stale = stale
+	}
+}
+
+// LRU is an in-memory Cache that evicts the least recently used entry once it
+// holds capacity entries.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	ttls     TTLs
+	stale    TTLs
+	order    *list.List
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+func NewLRU(capacity int, opts ...LRUOption) *LRU {
+	l := &LRU{
+		capacity: capacity,
+		ttls:     DefaultTTLs,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+func (l *LRU) Get(key Key) ([]types.OHLCV, bool) {
+	ohlcvs, stale, ok := l.GetStale(key)
+	if stale {
+		return nil, false
+	}
+	return ohlcvs, ok
+}
+
+// GetStale implements StaleCache. Without WithStaleWhileRevalidate it is Get.
+func (l *LRU) GetStale(key Key) ([]types.OHLCV, bool, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.entries[key.String()]
+	if !ok {
+		return nil, false, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	now := l.now()
+	if !now.Before(entry.staleUntil) {
+		l.remove(el)
+		return nil, false, false
+	}
+
+	l.order.MoveToFront(el)
+	return slices.Clone(entry.ohlcvs), !now.Before(entry.expiresAt), true
+}
+
+func (l *LRU) Set(key Key, ohlcvs []types.OHLCV) {
+	ttl := l.ttls.For(ohlcvs)
+	if ttl <= 0 || l.capacity <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k := key.String()
+	expiresAt := l.now().Add(ttl)
+	entry := &lruEntry{
+		key:        k,
+		ohlcvs:     slices.Clone(ohlcvs),
+		expiresAt:  expiresAt,
+		staleUntil: expiresAt.Add(max(l.stale.For(ohlcvs), 0)),
+	}
+
+	if el, ok := l.entries[k]; ok {
+		el.Value = entry
+		l.order.MoveToFront(el)
+		return
+	}
+
+	l.entries[k] = l.order.PushFront(entry)
+	for l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *LRU) remove(el *list.Element) {
+	l.order.Remove(el)
+	delete(l.entries, el.Value.(*lruEntry).key)
+}
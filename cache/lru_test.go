@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func historical(close float64) []types.OHLCV {
+	return []types.OHLCV{{Close: close, Freshness: types.FreshnessHistorical}}
+}
+
+func TestLRU_GetSet(t *testing.T) {
+	lru := NewLRU(10)
+	key := Key{Symbol: "INFY", Exchange: types.ExchangeNSE, Interval: types.Interval1d}
+
+	if _, ok := lru.Get(key); ok {
+		t.Error("Expected miss on empty cache")
+	}
+
+	lru.Set(key, historical(100))
+
+	got, ok := lru.Get(key)
+	if !ok {
+		t.Fatal("Expected hit after Set")
+	}
+	if len(got) != 1 || got[0].Close != 100 {
+		t.Errorf("Unexpected cached value %v", got)
+	}
+
+	got[0].Close = 0
+	again, _ := lru.Get(key)
+	if again[0].Close != 100 {
+		t.Error("Expected cached value to be isolated from caller mutations")
+	}
+}
+
+func TestLRU_Eviction(t *testing.T) {
+	lru := NewLRU(2)
+	k1 := Key{Symbol: "A"}
+	k2 := Key{Symbol: "B"}
+	k3 := Key{Symbol: "C"}
+
+	lru.Set(k1, historical(1))
+	lru.Set(k2, historical(2))
+	lru.Get(k1)
+	lru.Set(k3, historical(3))
+
+	if lru.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", lru.Len())
+	}
+	if _, ok := lru.Get(k2); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, ok := lru.Get(k1); !ok {
+		t.Error("Expected recently used entry to be kept")
+	}
+	if _, ok := lru.Get(k3); !ok {
+		t.Error("Expected newest entry to be kept")
+	}
+}
+
+func TestLRU_UpdateExisting(t *testing.T) {
+	lru := NewLRU(2)
+	key := Key{Symbol: "A"}
+
+	lru.Set(key, historical(1))
+	lru.Set(key, historical(2))
+
+	got, _ := lru.Get(key)
+	if lru.Len() != 1 || got[0].Close != 2 {
+		t.Errorf("Expected single updated entry, got len %d value %v", lru.Len(), got)
+	}
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lru := NewLRU(10, WithTTLs(TTLs{types.FreshnessDelayed: time.Minute}))
+	lru.now = func() time.Time { return now }
+
+	key := Key{Symbol: "A"}
+	lru.Set(key, []types.OHLCV{{Freshness: types.FreshnessDelayed}})
+
+	now = now.Add(30 * time.Second)
+	if _, ok := lru.Get(key); !ok {
+		t.Error("Expected hit before expiry")
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, ok := lru.Get(key); ok {
+		t.Error("Expected miss after expiry")
+	}
+	if lru.Len() != 0 {
+		t.Error("Expected expired entry to be removed")
+	}
+}
+
+func TestLRU_GetStale(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lru := NewLRU(10,
+		WithTTLs(TTLs{types.FreshnessDelayed: time.Minute}),
+		WithStaleWhileRevalidate(TTLs{types.FreshnessDelayed: 5 * time.Minute}),
+	)
+	lru.now = func() time.Time { return now }
+
+	key := Key{Symbol: "A"}
+	lru.Set(key, []types.OHLCV{{Freshness: types.FreshnessDelayed}})
+
+	if _, stale, ok := lru.GetStale(key); !ok || stale {
+		t.Errorf("Expected a fresh hit, got stale %v ok %v", stale, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, stale, ok := lru.GetStale(key); !ok || !stale {
+		t.Errorf("Expected a stale hit, got stale %v ok %v", stale, ok)
+	}
+	if _, ok := lru.Get(key); ok {
+		t.Error("Expected Get to miss a stale entry")
+	}
+
+	now = now.Add(4 * time.Minute)
+	if _, _, ok := lru.GetStale(key); ok {
+		t.Error("Expected a miss once the stale period is over")
+	}
+	if lru.Len() != 0 {
+		t.Error("Expected the entry to be removed")
+	}
+}
+
+func TestLRU_SkipsUncacheable(t *testing.T) {
+	lru := NewLRU(10)
+
+	lru.Set(Key{Symbol: "A"}, []types.OHLCV{{Freshness: types.FreshnessRealtime}})
+	lru.Set(Key{Symbol: "B"}, nil)
+
+	if lru.Len() != 0 {
+		t.Errorf("Expected realtime and empty results not to be cached, got %d entries", lru.Len())
+	}
+
+	zero := NewLRU(0)
+	zero.Set(Key{Symbol: "A"}, historical(1))
+	if zero.Len() != 0 {
+		t.Error("Expected zero-capacity cache to store nothing")
+	}
+}
@@ -0,0 +1,177 @@
+// Package calendar describes exchange trading days and session hours.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const dateLayout = "2006-01-02"
+
+// ErrUnknownExchange is returned by ForExchange for exchanges it has no
+// calendar of.
+var ErrUnknownExchange = errors.New("unknown exchange")
+
+// Calendar knows which days an exchange trades and when its regular session
+// opens and closes. Saturdays and Sundays are never trading days, except on
+// a Continuous calendar.
+type Calendar struct {
+	location *time.Location
+	open     time.Duration
+	close    time.Duration
+	holidays map[string]bool
+	everyDay bool
+	// firstYear and lastYear are the years holidays are known for; zero
+	// means every year.
+	firstYear int
+	lastYear  int
+}
+
+// New creates a Calendar whose session runs from open to close, as offsets
+// from midnight in loc, with the given holidays.
+func New(loc *time.Location, open, close time.Duration, holidays ...time.Time) *Calendar {
+	c := &Calendar{
+		location: loc,
+		open:     open,
+		close:    close,
+		holidays: make(map[string]bool, len(holidays)),
+	}
+	for _, h := range holidays {
+		c.holidays[h.In(loc).Format(dateLayout)] = true
+	}
+	return c
+}
+
+// Continuous creates a Calendar for a market that trades around the clock
+// every day, such as crypto: every day is a trading day and its session spans
+// the whole day in loc.
+func Continuous(loc *time.Location) *Calendar {
+	c := New(loc, 0, 24*time.Hour)
+	c.everyDay = true
+	return c
+}
+
+// ForExchange returns the calendar of exchange. NSE and BSE share a calendar,
+// as do NASDAQ and NYSE, and crypto exchanges get a Continuous calendar in
+// UTC. Other exchanges fail with ErrUnknownExchange.
+func ForExchange(exchange types.Exchange) (*Calendar, error) {
+	if exchange.Market() == types.MarketCrypto {
+		return crypto, nil
+	}
+
+	switch exchange {
+	case types.ExchangeNSE, types.ExchangeBSE:
+		return indian, nil
+	case types.ExchangeNASDAQ, types.ExchangeNYSE:
+		return us, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExchange, exchange)
+	}
+}
+
+// Covers reports whether c knows the holidays of the year of t. The NSE/BSE
+// and NASDAQ/NYSE calendars list holidays from 2024 to 2026 only; outside
+// those years they count every weekday as a trading day. Calendars made with
+// New or Continuous cover every year.
+func (c *Calendar) Covers(t time.Time) bool {
+	if c.firstYear == 0 {
+		return true
+	}
+	year := t.In(c.location).Year()
+	return year >= c.firstYear && year <= c.lastYear
+}
+
+// covering limits the years c knows the holidays of to first through last.
+func covering(c *Calendar, first, last int) *Calendar {
+	c.firstYear, c.lastYear = first, last
+	return c
+}
+
+// Location returns the time zone the exchange trades in.
+func (c *Calendar) Location() *time.Location {
+	return c.location
+}
+
+// SessionHours returns the session open and close as offsets from midnight.
+func (c *Calendar) SessionHours() (open, close time.Duration) {
+	return c.open, c.close
+}
+
+// IsHoliday reports whether t falls on an exchange holiday. It is false for
+// days in years c doesn't cover.
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	return c.holidays[t.In(c.location).Format(dateLayout)]
+}
+
+// IsTradingDay reports whether the exchange trades on the day of t.
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	t = t.In(c.location)
+	if c.everyDay {
+		return !c.IsHoliday(t)
+	}
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return !c.IsHoliday(t)
+}
+
+// NextTradingDay returns the start of the first trading day after the day of
+// t.
+func (c *Calendar) NextTradingDay(t time.Time) time.Time {
+	day := c.startOfDay(t).AddDate(0, 0, 1)
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// PreviousTradingDay returns the start of the last trading day before the day
+// of t.
+func (c *Calendar) PreviousTradingDay(t time.Time) time.Time {
+	day := c.startOfDay(t).AddDate(0, 0, -1)
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// SessionBounds returns when the session opens and closes on the day of t.
+// ok is false if the day isn't a trading day.
+func (c *Calendar) SessionBounds(t time.Time) (open, close time.Time, ok bool) {
+	if !c.IsTradingDay(t) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	day := c.startOfDay(t)
+	return day.Add(c.open), day.Add(c.close), true
+}
+
+// IsOpen reports whether the regular session is in progress at t.
+func (c *Calendar) IsOpen(t time.Time) bool {
+	open, close, ok := c.SessionBounds(t)
+	return ok && !t.Before(open) && t.Before(close)
+}
+
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	t = t.In(c.location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.location)
+}
+
+// crypto is the calendar of crypto exchanges, which never close.
+var crypto = Continuous(time.UTC)
+
+// parseDatesIn parses holiday dates as midnight in loc.
+func parseDatesIn(dates []string, loc *time.Location) []time.Time {
+	out := make([]time.Time, 0, len(dates))
+	for _, d := range dates {
+		t, err := time.ParseInLocation(dateLayout, d, loc)
+		if err != nil {
+			panic("calendar: invalid holiday " + d)
+		}
+		out = append(out, t)
+	}
+	return out
+}
@@ -0,0 +1,204 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestCalendar_IsTradingDay(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeNSE)
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{"weekday", time.Date(2024, 1, 2, 12, 0, 0, 0, ist), true},
+		{"saturday", time.Date(2024, 1, 6, 12, 0, 0, 0, ist), false},
+		{"sunday", time.Date(2024, 1, 7, 12, 0, 0, 0, ist), false},
+		{"republic day", time.Date(2024, 1, 26, 12, 0, 0, 0, ist), false},
+		{"christmas 2025", time.Date(2025, 12, 25, 12, 0, 0, 0, ist), false},
+		{"utc evening of an IST saturday", time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsTradingDay(tt.day); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_NextTradingDay(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeBSE)
+
+	// Thursday 25 Jan 2024 is followed by Republic Day and a weekend.
+	got := c.NextTradingDay(time.Date(2024, 1, 25, 15, 0, 0, 0, ist))
+	want := time.Date(2024, 1, 29, 0, 0, 0, 0, ist)
+
+	if !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestCalendar_PreviousTradingDay(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeNSE)
+
+	// Monday 29 Jan 2024 is preceded by a weekend and Republic Day.
+	got := c.PreviousTradingDay(time.Date(2024, 1, 29, 10, 0, 0, 0, ist))
+	want := time.Date(2024, 1, 25, 0, 0, 0, 0, ist)
+
+	if !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestCalendar_SessionBounds(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeNSE)
+
+	open, close, ok := c.SessionBounds(time.Date(2024, 1, 2, 18, 0, 0, 0, ist))
+	if !ok {
+		t.Fatal("Expected a session on a trading day")
+	}
+	if want := time.Date(2024, 1, 2, 9, 15, 0, 0, ist); !open.Equal(want) {
+		t.Errorf("Expected open %v, got %v", want, open)
+	}
+	if want := time.Date(2024, 1, 2, 15, 30, 0, 0, ist); !close.Equal(want) {
+		t.Errorf("Expected close %v, got %v", want, close)
+	}
+
+	if _, _, ok := c.SessionBounds(time.Date(2024, 1, 6, 10, 0, 0, 0, ist)); ok {
+		t.Error("Expected no session on a saturday")
+	}
+}
+
+func TestCalendar_IsOpen(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeNSE)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"before open", time.Date(2024, 1, 2, 9, 0, 0, 0, ist), false},
+		{"at open", time.Date(2024, 1, 2, 9, 15, 0, 0, ist), true},
+		{"midday", time.Date(2024, 1, 2, 12, 0, 0, 0, ist), true},
+		{"at close", time.Date(2024, 1, 2, 15, 30, 0, 0, ist), false},
+		{"holiday", time.Date(2024, 1, 26, 12, 0, 0, 0, ist), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsOpen(tt.at); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNew_CustomHolidays(t *testing.T) {
+	holiday := time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC)
+	c := New(time.UTC, 9*time.Hour, 17*time.Hour, holiday)
+
+	if c.IsTradingDay(holiday) {
+		t.Error("Expected custom holiday not to be a trading day")
+	}
+	if !c.IsTradingDay(holiday.AddDate(0, 0, 1)) {
+		t.Error("Expected the next day to be a trading day")
+	}
+}
+
+func TestCalendar_US(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeNASDAQ)
+	if nyse, _ := ForExchange(types.ExchangeNYSE); c != nyse {
+		t.Error("Expected NASDAQ and NYSE to share a calendar")
+	}
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{"weekday", time.Date(2024, 1, 2, 12, 0, 0, 0, eastern), true},
+		{"independence day", time.Date(2024, 7, 4, 12, 0, 0, 0, eastern), false},
+		{"thanksgiving 2025", time.Date(2025, 11, 27, 12, 0, 0, 0, eastern), false},
+		{"observed independence day 2026", time.Date(2026, 7, 3, 12, 0, 0, 0, eastern), false},
+		{"republic day trades", time.Date(2024, 1, 26, 12, 0, 0, 0, eastern), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsTradingDay(tt.day); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_US_SessionAcrossDST(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeNYSE)
+
+	tests := []struct {
+		name string
+		open time.Time
+	}{
+		{"winter", time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)},
+		{"summer", time.Date(2024, 7, 2, 13, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, close, ok := c.SessionBounds(tt.open)
+			if !ok {
+				t.Fatal("Expected a trading day")
+			}
+			if !open.Equal(tt.open) {
+				t.Errorf("Expected open at %v, got %v", tt.open, open.UTC())
+			}
+			if want := tt.open.Add(6*time.Hour + 30*time.Minute); !close.Equal(want) {
+				t.Errorf("Expected close at %v, got %v", want, close.UTC())
+			}
+		})
+	}
+}
+
+func TestContinuous(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeBinance)
+
+	saturday := time.Date(2024, 1, 6, 23, 59, 0, 0, time.UTC)
+	if !c.IsTradingDay(saturday) {
+		t.Error("Expected crypto to trade on Saturday")
+	}
+	if !c.IsOpen(saturday) {
+		t.Error("Expected crypto to be open just before midnight")
+	}
+	if next := c.NextTradingDay(saturday); !next.Equal(time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected Sunday to be the next trading day, got %v", next)
+	}
+}
+
+func TestForExchange_Unknown(t *testing.T) {
+	if c, err := ForExchange("LSE"); !errors.Is(err, ErrUnknownExchange) || c != nil {
+		t.Errorf("Expected ErrUnknownExchange, got %v, %v", c, err)
+	}
+}
+
+func TestCalendar_Covers(t *testing.T) {
+	c, _ := ForExchange(types.ExchangeNSE)
+
+	if !c.Covers(time.Date(2025, 6, 2, 0, 0, 0, 0, ist)) {
+		t.Error("Expected 2025 to be covered")
+	}
+	for _, year := range []int{2023, 2027} {
+		if c.Covers(time.Date(year, 6, 2, 0, 0, 0, 0, ist)) {
+			t.Errorf("Expected %d not to be covered", year)
+		}
+	}
+	if !New(time.UTC, 0, time.Hour).Covers(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected a custom calendar to cover every year")
+	}
+}
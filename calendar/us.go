@@ -0,0 +1,29 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// usHolidays are the NYSE and NASDAQ full-day closures that fall on weekdays.
+// Early closes, such as the day after Thanksgiving, are not modelled. They
+// cover 2024 to 2026.
+var usHolidays = []string{
+	// 2024
+	"2024-01-01", "2024-01-15", "2024-02-19", "2024-03-29", "2024-05-27",
+	"2024-06-19", "2024-07-04", "2024-09-02", "2024-11-28", "2024-12-25",
+	// 2025
+	"2025-01-01", "2025-01-09", "2025-01-20", "2025-02-17", "2025-04-18",
+	"2025-05-26", "2025-06-19", "2025-07-04", "2025-09-01", "2025-11-27",
+	"2025-12-25",
+	// 2026
+	"2026-01-01", "2026-01-19", "2026-02-16", "2026-04-03", "2026-05-25",
+	"2026-06-19", "2026-07-03", "2026-09-07", "2026-11-26", "2026-12-25",
+}
+
+var eastern = types.ExchangeNYSE.Location()
+
+// us is the calendar shared by NASDAQ and NYSE, with the regular session
+// running 09:30-16:00 US Eastern time.
+var us = covering(New(eastern, 9*time.Hour+30*time.Minute, 16*time.Hour, parseDatesIn(usHolidays, eastern)...), 2024, 2026)
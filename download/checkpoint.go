@@ -0,0 +1,137 @@
+package download
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// checkpoint is the set of tasks done, kept in a JSON file if it has a path.
+type checkpoint struct {
+	path string
+
+	mu   sync.Mutex
+	done map[string]bool
+}
+
+type checkpointFile struct {
+	Done []string `json:"done"`
+}
+
+// loadCheckpoint reads the checkpoint at path. A missing file is an empty
+// checkpoint, as is an empty path, which is never written.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{path: path, done: make(map[string]bool)}
+	if path == "" {
+		return cp, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var f checkpointFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %s: %w", path, err)
+	}
+	for _, task := range f.Done {
+		cp.done[task] = true
+	}
+	return cp, nil
+}
+
+func (c *checkpoint) isDone(t Task) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.done[t.String()]
+}
+
+// markDone records t as done and saves the checkpoint.
+func (c *checkpoint) markDone(t Task) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.done[t.String()] = true
+	if c.path == "" {
+		return nil
+	}
+	return c.save()
+}
+
+// retain removes the tasks done of the same series as tasks, by symbol,
+// exchange and interval, that aren't among them, and saves the checkpoint if
+// any was.
+func (c *checkpoint) retain(tasks []Task) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keep := make(map[string]bool, len(tasks))
+	series := make(map[string]bool)
+	for _, t := range tasks {
+		keep[t.String()] = true
+		series[t.series()] = true
+	}
+
+	removed := false
+	for task := range c.done {
+		if keep[task] {
+			continue
+		}
+		for s := range series {
+			if strings.HasPrefix(task, s) {
+				delete(c.done, task)
+				removed = true
+				break
+			}
+		}
+	}
+
+	if !removed || c.path == "" {
+		return nil
+	}
+	return c.save()
+}
+
+// save writes the checkpoint to its file, replacing it atomically so a crash
+// never leaves it half-written.
+func (c *checkpoint) save() error {
+	f := checkpointFile{Done: make([]string, 0, len(c.done))}
+	for task := range c.done {
+		f.Done = append(f.Done, task)
+	}
+	slices.Sort(f.Done)
+
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
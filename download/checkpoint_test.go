@@ -0,0 +1,46 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestCheckpoint_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	task := Task{Symbol: "INFY", Exchange: types.ExchangeNSE, Interval: types.Interval1d, From: time.Unix(0, 0), To: time.Unix(86400, 0)}
+
+	cp, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Expected a missing file to be an empty checkpoint, got %v", err)
+	}
+	if err := cp.markDone(task); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	loaded, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !loaded.isDone(task) {
+		t.Error("Expected the task to be done after loading")
+	}
+
+	other := task
+	other.Interval = types.Interval1h
+	if loaded.isDone(other) {
+		t.Error("Expected another task not to be done")
+	}
+}
+
+func TestCheckpoint_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	os.WriteFile(path, []byte("{not json"), 0o644)
+
+	if _, err := loadCheckpoint(path); err == nil {
+		t.Error("Expected a corrupt checkpoint to fail")
+	}
+}
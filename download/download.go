@@ -0,0 +1,325 @@
+// Package download builds a historical dataset in a storage.Store from a
+// manifest of symbols, intervals and a date range, checkpointing its progress
+// to disk so that a run stopped by a crash or a cancellation resumes where it
+// left off.
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/storage"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const (
+	// DefaultConcurrency is how many tasks run at once unless
+	// WithConcurrency is given.
+	DefaultConcurrency = 4
+	// DefaultIntradaySegment and DefaultDailySegment are how long a range
+	// each task of intraday and of daily or longer intervals fetches, unless
+	// WithSegment is given.
+	DefaultIntradaySegment = 30 * 24 * time.Hour
+	DefaultDailySegment    = 365 * 24 * time.Hour
+)
+
+// Manifest describes a dataset: the candles of every symbol at every
+// interval between From and To.
+type Manifest struct {
+	Symbols   []string
+	Intervals []types.Interval
+	// Exchange defaults to the MarketData's own exchange.
+	Exchange types.Exchange
+	From     time.Time
+	// To defaults to now, for a dataset kept up to date by running again.
+	To time.Time
+}
+
+// Task is the part of a manifest fetched and stored in one go: a segment of
+// its range for one symbol and interval. A task ending after now is open: it
+// is fetched up to now and never checkpointed.
+type Task struct {
+	Symbol   string
+	Exchange types.Exchange
+	Interval types.Interval
+	From, To time.Time
+}
+
+func (t Task) String() string {
+	return t.series() + t.From.UTC().Format(time.RFC3339) + "/" + t.To.UTC().Format(time.RFC3339)
+}
+
+// series is the prefix String gives every task of t's symbol, exchange and
+// interval.
+func (t Task) series() string {
+	return fmt.Sprintf("%s:%s:%s:", t.Symbol, t.Exchange, t.Interval)
+}
+
+// Result describes a task done.
+type Result struct {
+	Task Task
+	// Candles is how many candles were fetched and stored.
+	Candles int
+}
+
+type config struct {
+	checkpoint  string
+	concurrency int
+	segment     time.Duration
+	onSuccess   func(Result)
+	onFailure   func(Task, error)
+	progress    marketdata.ProgressFunc
+}
+
+type Option func(*config)
+
+// WithCheckpoint records the tasks done in the file at path, and skips them
+// when Run is called again, by the same or a later process. Without it, every
+// run starts over.
+func WithCheckpoint(path string) Option {
+	return func(c *config) {
+		c.checkpoint = path
+	}
+}
+
+// WithConcurrency sets how many tasks run at once. The default is
+// DefaultConcurrency. Tasks share the MarketData's providers and so their
+// rate limits.
+func WithConcurrency(n int) Option {
+	return func(c *config) {
+		c.concurrency = n
+	}
+}
+
+// WithSegment sets how long a range each task fetches, which is how much
+// work a crash can lose. By default it is DefaultIntradaySegment for
+// intraday intervals and DefaultDailySegment for the others.
+func WithSegment(d time.Duration) Option {
+	return func(c *config) {
+		c.segment = d
+	}
+}
+
+// WithSuccessHandler calls fn after each task is done.
+func WithSuccessHandler(fn func(Result)) Option {
+	return func(c *config) {
+		c.onSuccess = fn
+	}
+}
+
+// WithFailureHandler calls fn when a task fails.
+func WithFailureHandler(fn func(Task, error)) Option {
+	return func(c *config) {
+		c.onFailure = fn
+	}
+}
+
+// WithProgress calls fn after each task, done or failed, with how many of
+// the manifest's tasks are finished, counting those done by earlier runs, of
+// how many there are, and the task's symbol. Calls don't overlap.
+func WithProgress(fn marketdata.ProgressFunc) Option {
+	return func(c *config) {
+		c.progress = fn
+	}
+}
+
+// Downloader fetches the candles of a manifest into a store, task by task.
+type Downloader struct {
+	md       *marketdata.MarketData
+	store    storage.Store
+	manifest Manifest
+	cfg      config
+	now      func() time.Time
+}
+
+// NewDownloader creates a Downloader fetching manifest through md into store.
+func NewDownloader(md *marketdata.MarketData, store storage.Store, manifest Manifest, opts ...Option) *Downloader {
+	cfg := config{concurrency: DefaultConcurrency}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if manifest.Exchange == "" {
+		manifest.Exchange = md.Exchange()
+	}
+
+	return &Downloader{md: md, store: store, manifest: manifest, cfg: cfg, now: time.Now}
+}
+
+// Tasks returns the tasks of the manifest, by symbol, then interval, then
+// time. With a zero To, the manifest ranges up to now, and the last segment
+// of each series keeps its full length, so that it is the same task from one
+// run to the next until it closes.
+func (d *Downloader) Tasks() ([]Task, error) {
+	m := d.manifest
+	to := m.To
+	if to.IsZero() {
+		to = d.now()
+	}
+	if m.From.IsZero() || !m.From.Before(to) {
+		return nil, fmt.Errorf("%w: manifest range %v to %v", provider.ErrInvalidRange, m.From, to)
+	}
+
+	var tasks []Task
+	for _, symbol := range m.Symbols {
+		for _, interval := range m.Intervals {
+			segment := d.segment(interval)
+			for from := m.From; from.Before(to); from = from.Add(segment) {
+				end := from.Add(segment)
+				if !m.To.IsZero() {
+					end = minTime(end, m.To)
+				}
+				tasks = append(tasks, Task{
+					Symbol:   symbol,
+					Exchange: m.Exchange,
+					Interval: interval,
+					From:     from,
+					To:       end,
+				})
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// open reports whether t ends after now, so has candles still to come.
+func (d *Downloader) open(t Task) bool {
+	return t.To.After(d.now())
+}
+
+func (d *Downloader) segment(interval types.Interval) time.Duration {
+	switch {
+	case d.cfg.segment > 0:
+		return d.cfg.segment
+	case interval.IsIntraday():
+		return DefaultIntradaySegment
+	default:
+		return DefaultDailySegment
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// Run fetches and stores every task of the manifest not already done, as
+// recorded by the checkpoint, and returns the failures joined. A failed task
+// isn't checkpointed, so calling Run again retries it, and neither is an open
+// one, so calling Run again refreshes it. Checkpointed tasks of the
+// manifest's series that are no longer among its tasks, such as an open
+// segment recorded by an older version, are removed from the checkpoint. Requests have
+// provider.PriorityLow unless ctx sets another priority. Cancelling ctx stops
+// the run once the tasks under way are done.
+func (d *Downloader) Run(ctx context.Context) error {
+	tasks, err := d.Tasks()
+	if err != nil {
+		return err
+	}
+
+	cp, err := loadCheckpoint(d.cfg.checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := cp.retain(tasks); err != nil {
+		return err
+	}
+
+	if _, ok := provider.PriorityFromContext(ctx); !ok {
+		ctx = provider.WithPriority(ctx, provider.PriorityLow)
+	}
+
+	finished := 0
+	for _, t := range tasks {
+		if cp.isDone(t) {
+			finished++
+		}
+	}
+
+	jobs := make(chan Task)
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	report := func(t Task) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		finished++
+		if d.cfg.progress != nil {
+			d.cfg.progress(finished, len(tasks), t.Symbol)
+		}
+	}
+	for range max(d.cfg.concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				n, err := d.download(ctx, t)
+				if err == nil && !d.open(t) {
+					err = cp.markDone(t)
+				}
+
+				if err != nil {
+					err = fmt.Errorf("%s: %w", t, err)
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					if d.cfg.onFailure != nil {
+						d.cfg.onFailure(t, err)
+					}
+				} else if d.cfg.onSuccess != nil {
+					d.cfg.onSuccess(Result{Task: t, Candles: n})
+				}
+				report(t)
+			}
+		}()
+	}
+
+	for _, t := range tasks {
+		if cp.isDone(t) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// download fetches and stores t, up to now if it is open, returning how many
+// candles it had. A range with no candles, such as one before the symbol was
+// listed, is done too.
+func (d *Downloader) download(ctx context.Context, t Task) (int, error) {
+	end := minTime(t.To, d.now())
+	data, err := d.md.Fetch(ctx, t.Symbol, t.Interval, t.From, end, marketdata.WithExchange(t.Exchange))
+	if errors.Is(err, provider.ErrNoData) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	if err := d.store.Save(ctx, t.Interval, data); err != nil {
+		return 0, fmt.Errorf("failed to store candles: %w", err)
+	}
+	return len(data), nil
+}
@@ -0,0 +1,280 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type mockProvider struct {
+	mu     sync.Mutex
+	calls  []Task
+	failOn string
+}
+
+func (m *mockProvider) Name() string {
+	return "mock"
+}
+
+func (m *mockProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Task{Symbol: symbol, Exchange: exchange, Interval: interval, From: start, To: end})
+	m.mu.Unlock()
+
+	if symbol == m.failOn {
+		return nil, errors.New("upstream down")
+	}
+	if symbol == "NEW" {
+		return nil, provider.ErrNoData
+	}
+	return []types.OHLCV{{Symbol: symbol, Exchange: exchange, DateTime: start, Close: 100, Freshness: types.FreshnessHistorical}}, nil
+}
+
+func (m *mockProvider) callCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.calls)
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	candles []types.OHLCV
+}
+
+func (s *memoryStore) Save(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.candles = append(s.candles, candles...)
+	return nil
+}
+
+func (s *memoryStore) Load(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	return nil, nil
+}
+
+func (s *memoryStore) Latest(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval) (time.Time, bool, error) {
+	return time.Time{}, false, nil
+}
+
+var manifest = Manifest{
+	Symbols:   []string{"INFY", "TCS"},
+	Intervals: []types.Interval{types.Interval1d, types.Interval1h},
+	From:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	To:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestDownloader_Tasks(t *testing.T) {
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(&mockProvider{}))
+	tasks, err := NewDownloader(md, &memoryStore{}, manifest).Tasks()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Two yearly segments of 1d and 25 monthly ones of 1h, for each symbol.
+	if len(tasks) != 2*(2+25) {
+		t.Fatalf("Expected 54 tasks, got %d", len(tasks))
+	}
+	first, last := tasks[0], tasks[len(tasks)-1]
+	if first.Symbol != "INFY" || first.Interval != types.Interval1d || first.Exchange != types.ExchangeNSE || !first.From.Equal(manifest.From) {
+		t.Errorf("Unexpected first task %v", first)
+	}
+	if last.Symbol != "TCS" || last.Interval != types.Interval1h || !last.To.Equal(manifest.To) {
+		t.Errorf("Unexpected last task %v", last)
+	}
+
+	custom, _ := NewDownloader(md, &memoryStore{}, manifest, WithSegment(100*24*time.Hour)).Tasks()
+	if len(custom) != 2*2*8 {
+		t.Errorf("Expected 32 tasks of 100 days, got %d", len(custom))
+	}
+}
+
+func TestDownloader_Tasks_InvalidRange(t *testing.T) {
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(&mockProvider{}))
+	bad := manifest
+	bad.From, bad.To = bad.To, bad.From
+
+	if _, err := NewDownloader(md, &memoryStore{}, bad).Tasks(); !errors.Is(err, provider.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
+
+func TestDownloader_Run_Resumes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	m := Manifest{
+		Symbols:   []string{"INFY", "TCS", "NEW"},
+		Intervals: []types.Interval{types.Interval1d},
+		From:      manifest.From,
+		To:        manifest.To,
+	}
+
+	p := &mockProvider{failOn: "TCS"}
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p))
+	store := &memoryStore{}
+
+	var (
+		mu       sync.Mutex
+		results  []Result
+		failures []Task
+	)
+	d := NewDownloader(md, store, m, WithCheckpoint(path),
+		WithSuccessHandler(func(r Result) {
+			mu.Lock()
+			results = append(results, r)
+			mu.Unlock()
+		}),
+		WithFailureHandler(func(task Task, err error) {
+			mu.Lock()
+			failures = append(failures, task)
+			mu.Unlock()
+		}),
+	)
+
+	err := d.Run(context.Background())
+	if err == nil {
+		t.Fatal("Expected the TCS tasks to fail")
+	}
+	if len(results) != 4 || len(failures) != 2 {
+		t.Errorf("Expected 4 tasks done and 2 failed, got %d and %d", len(results), len(failures))
+	}
+	if len(store.candles) != 2 {
+		t.Errorf("Expected 2 INFY candles stored, got %d", len(store.candles))
+	}
+
+	// A new process resumes from the checkpoint and only retries TCS.
+	p2 := &mockProvider{}
+	md2 := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p2))
+	if err := NewDownloader(md2, store, m, WithCheckpoint(path)).Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p2.callCount() != 2 {
+		t.Fatalf("Expected only the 2 failed tasks retried, got %d", p2.callCount())
+	}
+	for _, c := range p2.calls {
+		if c.Symbol != "TCS" {
+			t.Errorf("Expected only TCS to be fetched again, got %s", c.Symbol)
+		}
+	}
+
+	p3 := &mockProvider{}
+	md3 := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p3))
+	if err := NewDownloader(md3, store, m, WithCheckpoint(path)).Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p3.callCount() != 0 {
+		t.Errorf("Expected a finished manifest to send no requests, got %d", p3.callCount())
+	}
+}
+
+func TestDownloader_Run_Progress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	m := Manifest{
+		Symbols:   []string{"INFY", "TCS"},
+		Intervals: []types.Interval{types.Interval1d},
+		From:      manifest.From,
+		To:        manifest.To,
+	}
+
+	p := &mockProvider{failOn: "TCS"}
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p))
+	var fetched []int
+	progress := func(n, total int, symbol string) {
+		if total != 4 {
+			t.Errorf("Expected 4 tasks in total, got %d", total)
+		}
+		fetched = append(fetched, n)
+	}
+	NewDownloader(md, &memoryStore{}, m, WithCheckpoint(path), WithProgress(progress)).Run(context.Background())
+
+	if len(fetched) != 4 || fetched[3] != 4 {
+		t.Errorf("Expected progress up to 4 of 4, got %v", fetched)
+	}
+
+	// Resuming counts the tasks done by the first run.
+	fetched = nil
+	md2 := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(&mockProvider{}))
+	NewDownloader(md2, &memoryStore{}, m, WithCheckpoint(path), WithProgress(progress)).Run(context.Background())
+
+	if len(fetched) != 2 || fetched[0] != 3 || fetched[1] != 4 {
+		t.Errorf("Expected progress 3 and 4 of 4, got %v", fetched)
+	}
+}
+
+func TestDownloader_Run_Cancelled(t *testing.T) {
+	p := &mockProvider{}
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewDownloader(md, &memoryStore{}, manifest).Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if p.callCount() != 0 {
+		t.Errorf("Expected no requests once cancelled, got %d", p.callCount())
+	}
+}
+
+func TestDownloader_Run_OpenEnded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	now := time.Now().Add(-time.Hour).Truncate(time.Second)
+	m := Manifest{
+		Symbols:   []string{"INFY"},
+		Intervals: []types.Interval{types.Interval1d},
+		From:      now.AddDate(0, 0, -25),
+	}
+
+	// An older version keyed the open segment by the time it ran to.
+	stale := Task{Symbol: "INFY", Exchange: types.ExchangeNSE, Interval: types.Interval1d, From: m.From.AddDate(0, 0, 20), To: now.Add(-time.Hour)}
+	other := stale
+	other.Symbol = "TCS"
+	seed, _ := loadCheckpoint(path)
+	seed.markDone(stale)
+	seed.markDone(other)
+
+	p := &mockProvider{}
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p))
+	d := NewDownloader(md, &memoryStore{}, m, WithCheckpoint(path), WithSegment(10*24*time.Hour))
+	d.now = func() time.Time { return now }
+
+	tasks, _ := d.Tasks()
+	if len(tasks) != 3 || !tasks[2].To.Equal(m.From.AddDate(0, 0, 30)) {
+		t.Fatalf("Expected 3 tasks, the last keyed by its full segment, got %v", tasks)
+	}
+	if err := d.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p.callCount() != 3 || !slices.ContainsFunc(p.calls, func(c Task) bool { return c.To.Equal(now) }) {
+		t.Fatalf("Expected the open segment fetched up to now, got %v", p.calls)
+	}
+
+	cp, _ := loadCheckpoint(path)
+	if cp.isDone(tasks[2]) || !cp.isDone(tasks[0]) || !cp.isDone(tasks[1]) {
+		t.Error("Expected the closed segments checkpointed and the open one not")
+	}
+	if cp.isDone(stale) || !cp.isDone(other) {
+		t.Error("Expected the superseded key of the series removed and other series kept")
+	}
+
+	// A later run refreshes only the open segment, up to the new now.
+	later := now.Add(30 * time.Minute)
+	p2 := &mockProvider{}
+	md2 := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p2))
+	d2 := NewDownloader(md2, &memoryStore{}, m, WithCheckpoint(path), WithSegment(10*24*time.Hour))
+	d2.now = func() time.Time { return later }
+	if err := d2.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p2.callCount() != 1 || !p2.calls[0].To.Equal(later) {
+		t.Errorf("Expected only the open segment refreshed up to %v, got %v", later, p2.calls)
+	}
+}
@@ -0,0 +1,211 @@
+// Package indicators computes technical indicators over candle series. Every
+// indicator returns a slice aligned with its input: element i belongs to
+// candle i, and is NaN while there is not yet enough data, or for every
+// element if the period is not positive.
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// SMA returns the simple moving average of values over period.
+func SMA(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period <= 0 {
+		return out
+	}
+
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// EMA returns the exponential moving average of values over period, seeded
+// with the simple average of the first period values. Leading NaNs in values
+// are skipped.
+func EMA(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period <= 0 {
+		return out
+	}
+
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < period {
+		return out
+	}
+
+	var sum float64
+	for _, v := range values[start : start+period] {
+		sum += v
+	}
+	prev := sum / float64(period)
+	out[start+period-1] = prev
+
+	k := 2 / float64(period+1)
+	for i := start + period; i < len(values); i++ {
+		prev = values[i]*k + prev*(1-k)
+		out[i] = prev
+	}
+	return out
+}
+
+// RSI returns Wilder's relative strength index of values over period, from 0
+// to 100.
+func RSI(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period <= 0 || len(values) <= period {
+		return out
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		gain += max(values[i]-values[i-1], 0)
+		loss += max(values[i-1]-values[i], 0)
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	out[period] = rsi(gain, loss)
+
+	for i := period + 1; i < len(values); i++ {
+		gain = (gain*float64(period-1) + max(values[i]-values[i-1], 0)) / float64(period)
+		loss = (loss*float64(period-1) + max(values[i-1]-values[i], 0)) / float64(period)
+		out[i] = rsi(gain, loss)
+	}
+	return out
+}
+
+func rsi(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// MACD returns the difference between the fast and slow EMAs of values, its
+// signal EMA, and the histogram of their difference. The common periods are
+// 12, 26 and 9.
+func MACD(values []float64, fast, slow, signal int) (macd, signalLine, histogram []float64) {
+	fastEMA := EMA(values, fast)
+	slowEMA := EMA(values, slow)
+
+	macd = make([]float64, len(values))
+	for i := range values {
+		macd[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	signalLine = EMA(macd, signal)
+
+	histogram = make([]float64, len(values))
+	for i := range values {
+		histogram[i] = macd[i] - signalLine[i]
+	}
+	return macd, signalLine, histogram
+}
+
+// BollingerBands returns the simple moving average of values over period as
+// the middle band, with bands k population standard deviations above and
+// below it. The common settings are 20 and 2.
+func BollingerBands(values []float64, period int, k float64) (upper, middle, lower []float64) {
+	middle = SMA(values, period)
+	upper = nans(len(values))
+	lower = nans(len(values))
+
+	for i := range values {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+
+		var variance float64
+		for _, v := range values[i-period+1 : i+1] {
+			variance += (v - middle[i]) * (v - middle[i])
+		}
+		sd := math.Sqrt(variance / float64(period))
+
+		upper[i] = middle[i] + k*sd
+		lower[i] = middle[i] - k*sd
+	}
+	return upper, middle, lower
+}
+
+// ATR returns Wilder's average true range of candles over period. The first
+// candle's true range is its high minus its low.
+func ATR(candles types.Series, period int) []float64 {
+	out := nans(len(candles))
+	if period <= 0 || len(candles) < period {
+		return out
+	}
+
+	tr := make([]float64, len(candles))
+	for i, c := range candles {
+		tr[i] = c.High - c.Low
+		if i > 0 {
+			prev := candles[i-1].Close
+			tr[i] = max(tr[i], math.Abs(c.High-prev), math.Abs(c.Low-prev))
+		}
+	}
+
+	var sum float64
+	for _, v := range tr[:period] {
+		sum += v
+	}
+	prev := sum / float64(period)
+	out[period-1] = prev
+
+	for i := period; i < len(candles); i++ {
+		prev = (prev*float64(period-1) + tr[i]) / float64(period)
+		out[i] = prev
+	}
+	return out
+}
+
+// VWAP returns the running volume-weighted average of the typical price,
+// restarting at the first candle of each day in the candles' location as
+// intraday VWAP does. It is NaN until a day has traded volume.
+func VWAP(candles types.Series) []float64 {
+	out := nans(len(candles))
+
+	var pv, volume float64
+	for i, c := range candles {
+		if i > 0 && !sameDay(c.DateTime, candles[i-1].DateTime) {
+			pv, volume = 0, 0
+		}
+
+		pv += (c.High + c.Low + c.Close) / 3 * float64(c.Volume)
+		volume += float64(c.Volume)
+		if volume > 0 {
+			out[i] = pv / volume
+		}
+	}
+	return out
+}
+
+func nans(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,109 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func assertSeries(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %d values, got %d", name, len(want), len(got))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				t.Errorf("%s[%d]: expected NaN, got %v", name, i, got[i])
+			}
+			continue
+		}
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("%s[%d]: expected %v, got %v", name, i, want[i], got[i])
+		}
+	}
+}
+
+var nan = math.NaN()
+
+func TestSMA(t *testing.T) {
+	assertSeries(t, "SMA", SMA([]float64{1, 2, 3, 4, 5}, 3), []float64{nan, nan, 2, 3, 4})
+	assertSeries(t, "SMA(0)", SMA([]float64{1, 2}, 0), []float64{nan, nan})
+}
+
+func TestEMA(t *testing.T) {
+	// With period 3 the smoothing factor is 0.5 and the seed is the mean of
+	// the first three values.
+	assertSeries(t, "EMA", EMA([]float64{1, 2, 3, 4, 5, 6}, 3), []float64{nan, nan, 2, 3, 4, 5})
+	assertSeries(t, "EMA(short)", EMA([]float64{1, 2}, 3), []float64{nan, nan})
+	assertSeries(t, "EMA(leading NaN)", EMA([]float64{nan, 1, 2, 3, 4}, 3), []float64{nan, nan, nan, 2, 3})
+}
+
+func TestRSI(t *testing.T) {
+	assertSeries(t, "RSI", RSI([]float64{1, 2, 3, 2, 3}, 2), []float64{nan, nan, 100, 50, 75})
+	assertSeries(t, "RSI(flat)", RSI([]float64{5, 5, 5}, 2), []float64{nan, nan, 50})
+}
+
+func TestMACD(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	macd, signal, hist := MACD(values, 2, 4, 2)
+
+	fast := EMA(values, 2)
+	slow := EMA(values, 4)
+	for i := range values {
+		if i < 3 {
+			if !math.IsNaN(macd[i]) {
+				t.Errorf("macd[%d]: expected NaN, got %v", i, macd[i])
+			}
+			continue
+		}
+		if want := fast[i] - slow[i]; math.Abs(macd[i]-want) > 1e-9 {
+			t.Errorf("macd[%d]: expected %v, got %v", i, want, macd[i])
+		}
+	}
+
+	if !math.IsNaN(signal[3]) || math.IsNaN(signal[4]) {
+		t.Errorf("Expected signal to start at index 4, got %v", signal)
+	}
+	for i := 4; i < len(values); i++ {
+		if math.Abs(hist[i]-(macd[i]-signal[i])) > 1e-9 {
+			t.Errorf("hist[%d]: expected %v, got %v", i, macd[i]-signal[i], hist[i])
+		}
+	}
+}
+
+func TestBollingerBands(t *testing.T) {
+	upper, middle, lower := BollingerBands([]float64{1, 2, 3, 4}, 3, 2)
+
+	sd := math.Sqrt(2.0 / 3)
+	assertSeries(t, "middle", middle, []float64{nan, nan, 2, 3})
+	assertSeries(t, "upper", upper, []float64{nan, nan, 2 + 2*sd, 3 + 2*sd})
+	assertSeries(t, "lower", lower, []float64{nan, nan, 2 - 2*sd, 3 - 2*sd})
+}
+
+func TestATR(t *testing.T) {
+	candles := types.Series{
+		{High: 10, Low: 8, Close: 9},
+		{High: 12, Low: 9, Close: 11},
+		{High: 11, Low: 10, Close: 10.5},
+		{High: 15, Low: 12, Close: 14},
+	}
+
+	// True ranges are 2, 3, 1 and 4.5 (high minus the previous close).
+	assertSeries(t, "ATR", ATR(candles, 2), []float64{nan, 2.5, 1.75, 3.125})
+}
+
+func TestVWAP(t *testing.T) {
+	day := time.Date(2024, 1, 2, 9, 15, 0, 0, time.UTC)
+	candles := types.Series{
+		{DateTime: day, High: 10, Low: 10, Close: 10, Volume: 100},
+		{DateTime: day.Add(time.Minute), High: 20, Low: 20, Close: 20, Volume: 300},
+		{DateTime: day.AddDate(0, 0, 1), High: 30, Low: 30, Close: 30, Volume: 0},
+		{DateTime: day.AddDate(0, 0, 1).Add(time.Minute), High: 40, Low: 40, Close: 40, Volume: 50},
+	}
+
+	assertSeries(t, "VWAP", VWAP(candles), []float64{10, 17.5, nan, 40})
+}
@@ -0,0 +1,58 @@
+package indicators
+
+import (
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// SessionVWAP returns VWAP restarting at each regular session of cal. Days
+// are taken in the calendar's time zone rather than the candles', and candles
+// outside the session, such as pre-market or after-hours trading, are NaN and
+// left out of the average. It is meant for intraday candles.
+func SessionVWAP(candles types.Series, cal *calendar.Calendar) []float64 {
+	out := nans(len(candles))
+
+	var (
+		pv, volume float64
+		session    time.Time
+	)
+	for i, c := range candles {
+		open, close, ok := cal.SessionBounds(c.DateTime)
+		if !ok || c.DateTime.Before(open) || !c.DateTime.Before(close) {
+			continue
+		}
+		if !open.Equal(session) {
+			session, pv, volume = open, 0, 0
+		}
+
+		pv += (c.High + c.Low + c.Close) / 3 * float64(c.Volume)
+		volume += float64(c.Volume)
+		if volume > 0 {
+			out[i] = pv / volume
+		}
+	}
+	return out
+}
+
+// AnchoredVWAP returns VWAP accumulated from the first candle at or after
+// anchor, such as an earnings release or a swing low, without restarting. It
+// is NaN before the anchor and until volume has traded since it.
+func AnchoredVWAP(candles types.Series, anchor time.Time) []float64 {
+	out := nans(len(candles))
+
+	var pv, volume float64
+	for i, c := range candles {
+		if c.DateTime.Before(anchor) {
+			continue
+		}
+
+		pv += (c.High + c.Low + c.Close) / 3 * float64(c.Volume)
+		volume += float64(c.Volume)
+		if volume > 0 {
+			out[i] = pv / volume
+		}
+	}
+	return out
+}
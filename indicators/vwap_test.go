@@ -0,0 +1,52 @@
+package indicators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestSessionVWAP(t *testing.T) {
+	// 2024-01-02 and 2024-01-03 are trading days; the session runs 14:30-21:00
+	// UTC, so the candles below are in UTC to check the calendar's zone is used.
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	at := func(d int, h, m int) time.Time {
+		return day.AddDate(0, 0, d).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	candles := types.Series{
+		{DateTime: at(0, 13, 0), High: 50, Low: 50, Close: 50, Volume: 1000},
+		{DateTime: at(0, 14, 30), High: 10, Low: 10, Close: 10, Volume: 100},
+		{DateTime: at(0, 20, 59), High: 20, Low: 20, Close: 20, Volume: 300},
+		{DateTime: at(1, 0, 30), High: 60, Low: 60, Close: 60, Volume: 1000},
+		{DateTime: at(1, 14, 30), High: 30, Low: 30, Close: 30, Volume: 0},
+		{DateTime: at(1, 15, 0), High: 40, Low: 40, Close: 40, Volume: 50},
+	}
+
+	cal, _ := calendar.ForExchange(types.ExchangeNYSE)
+	got := SessionVWAP(candles, cal)
+	assertSeries(t, "SessionVWAP", got, []float64{nan, 10, 17.5, nan, nan, 40})
+}
+
+func TestSessionVWAP_Holiday(t *testing.T) {
+	holiday := time.Date(2024, 1, 1, 10, 0, 0, 0, types.ExchangeNYSE.Location())
+	candles := types.Series{{DateTime: holiday, High: 10, Low: 10, Close: 10, Volume: 100}}
+
+	cal, _ := calendar.ForExchange(types.ExchangeNYSE)
+	got := SessionVWAP(candles, cal)
+	assertSeries(t, "SessionVWAP", got, []float64{nan})
+}
+
+func TestAnchoredVWAP(t *testing.T) {
+	day := time.Date(2024, 1, 2, 9, 15, 0, 0, time.UTC)
+	candles := types.Series{
+		{DateTime: day, High: 5, Low: 5, Close: 5, Volume: 100},
+		{DateTime: day.Add(time.Minute), High: 10, Low: 10, Close: 10, Volume: 100},
+		{DateTime: day.AddDate(0, 0, 1), High: 20, Low: 20, Close: 20, Volume: 300},
+		{DateTime: day.AddDate(0, 0, 2), High: 40, Low: 40, Close: 40, Volume: 100},
+	}
+
+	got := AnchoredVWAP(candles, day.Add(30*time.Second))
+	assertSeries(t, "AnchoredVWAP", got, []float64{nan, 10, 17.5, 22})
+}
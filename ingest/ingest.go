@@ -0,0 +1,247 @@
+// Package ingest keeps a storage.Store up to date with the candles of a
+// watchlist, fetching only what is new since the last stored candle.
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/storage"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const (
+	// DefaultInterval is how often Run syncs the watchlist unless
+	// WithInterval is given.
+	DefaultInterval = 5 * time.Minute
+	// DefaultLookback is how far back a target with nothing stored yet is
+	// fetched unless WithLookback is given.
+	DefaultLookback = 30 * 24 * time.Hour
+)
+
+// closeLookback bounds how many days back the last session close is looked
+// for.
+const closeLookback = 30
+
+// Target is a symbol and interval to keep stored. Exchange defaults to the
+// MarketData's own exchange.
+type Target struct {
+	Symbol   string
+	Exchange types.Exchange
+	Interval types.Interval
+}
+
+func (t Target) String() string {
+	return fmt.Sprintf("%s:%s:%s", t.Symbol, t.Exchange, t.Interval)
+}
+
+// Result describes a successful sync of a target.
+type Result struct {
+	Target Target
+	// From and To are the range fetched.
+	From, To time.Time
+	// Candles is how many candles were fetched.
+	Candles int
+	// Saved is how many of them were new or corrected and so were stored.
+	Saved int
+}
+
+type config struct {
+	interval  time.Duration
+	lookback  time.Duration
+	onSuccess func(Result)
+	onFailure func(Target, error)
+}
+
+type Option func(*config)
+
+// WithInterval sets how often Run syncs the watchlist. The default is
+// DefaultInterval.
+func WithInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.interval = d
+	}
+}
+
+// WithLookback sets how far back a target with nothing stored yet is fetched.
+// The default is DefaultLookback.
+func WithLookback(d time.Duration) Option {
+	return func(c *config) {
+		c.lookback = d
+	}
+}
+
+// WithSuccessHandler calls fn after each target is synced.
+func WithSuccessHandler(fn func(Result)) Option {
+	return func(c *config) {
+		c.onSuccess = fn
+	}
+}
+
+// WithFailureHandler calls fn when syncing a target fails.
+func WithFailureHandler(fn func(Target, error)) Option {
+	return func(c *config) {
+		c.onFailure = fn
+	}
+}
+
+// Syncer periodically fetches the candles of a watchlist that are newer than
+// the last one stored and saves those that are new or changed. The last stored
+// candle is fetched again, since it may have been stored before it closed. While an exchange is closed,
+// a target synced since the last session ended is skipped without a request.
+type Syncer struct {
+	md        *marketdata.MarketData
+	store     storage.Store
+	watchlist []Target
+	interval  time.Duration
+	lookback  time.Duration
+	onSuccess func(Result)
+	onFailure func(Target, error)
+	now       func() time.Time
+
+	mu       sync.Mutex
+	lastSync map[Target]time.Time
+}
+
+// NewSyncer creates a Syncer fetching watchlist through md into store.
+func NewSyncer(md *marketdata.MarketData, store storage.Store, watchlist []Target, opts ...Option) *Syncer {
+	cfg := config{interval: DefaultInterval, lookback: DefaultLookback}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	targets := make([]Target, len(watchlist))
+	for i, t := range watchlist {
+		if t.Exchange == "" {
+			t.Exchange = md.Exchange()
+		}
+		targets[i] = t
+	}
+
+	return &Syncer{
+		md:        md,
+		store:     store,
+		watchlist: targets,
+		interval:  cfg.interval,
+		lookback:  cfg.lookback,
+		onSuccess: cfg.onSuccess,
+		onFailure: cfg.onFailure,
+		now:       time.Now,
+		lastSync:  make(map[Target]time.Time),
+	}
+}
+
+// Run syncs the watchlist right away and then every interval until ctx is
+// done, which is the error it returns. Failures of single targets are only
+// reported to the failure handler.
+func (s *Syncer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.SyncOnce(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// SyncOnce syncs every target of the watchlist once, in order, and returns
+// the failures joined. Its requests have provider.PriorityLow, so that
+// interactive fetches sharing a provider's rate limit go first, unless ctx
+// sets another priority.
+func (s *Syncer) SyncOnce(ctx context.Context) error {
+	if _, ok := provider.PriorityFromContext(ctx); !ok {
+		ctx = provider.WithPriority(ctx, provider.PriorityLow)
+	}
+
+	var errs []error
+	for _, t := range s.watchlist {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		if err := s.sync(ctx, t); err != nil {
+			err = fmt.Errorf("%s: %w", t, err)
+			errs = append(errs, err)
+			if s.onFailure != nil {
+				s.onFailure(t, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Syncer) sync(ctx context.Context, t Target) error {
+	now := s.now()
+	if !s.due(t, now) {
+		return nil
+	}
+
+	latest, ok, err := s.store.Latest(ctx, t.Symbol, t.Exchange, t.Interval)
+	if err != nil {
+		return fmt.Errorf("failed to read latest candle: %w", err)
+	}
+
+	from := now.Add(-s.lookback)
+	if ok {
+		from = latest
+	}
+
+	data, err := s.md.Fetch(ctx, t.Symbol, t.Interval, from, now, marketdata.WithExchange(t.Exchange))
+	if err != nil {
+		return err
+	}
+
+	saved, err := storage.SaveChanges(ctx, s.store, t.Interval, data)
+	if err != nil {
+		return fmt.Errorf("failed to store %s candles: %w", t.Symbol, err)
+	}
+
+	s.mu.Lock()
+	s.lastSync[t] = now
+	s.mu.Unlock()
+
+	if s.onSuccess != nil {
+		s.onSuccess(Result{Target: t, From: from, To: now, Candles: len(data), Saved: saved})
+	}
+	return nil
+}
+
+// due reports whether t needs syncing at now: always while its exchange is
+// open or if it has no calendar, and otherwise only if it hasn't been synced
+// since the last session closed.
+func (s *Syncer) due(t Target, now time.Time) bool {
+	s.mu.Lock()
+	last, ok := s.lastSync[t]
+	s.mu.Unlock()
+
+	cal, err := calendar.ForExchange(t.Exchange)
+	if !ok || err != nil || cal.IsOpen(now) {
+		return true
+	}
+
+	lastClose, found := lastSessionClose(cal, now)
+	return !found || last.Before(lastClose)
+}
+
+// lastSessionClose returns when the most recent session before now closed.
+func lastSessionClose(cal *calendar.Calendar, now time.Time) (time.Time, bool) {
+	day := now
+	for range closeLookback {
+		if _, close, ok := cal.SessionBounds(day); ok && !close.After(now) {
+			return close, true
+		}
+		day = cal.PreviousTradingDay(day)
+	}
+	return time.Time{}, false
+}
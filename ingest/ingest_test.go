@@ -0,0 +1,190 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var ist = types.ExchangeNSE.Location()
+
+type mockProvider struct {
+	starts     []time.Time
+	priorities []provider.Priority
+	err        error
+}
+
+func (m *mockProvider) Name() string {
+	return "mock"
+}
+
+func (m *mockProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+	m.starts = append(m.starts, start)
+	priority, _ := provider.PriorityFromContext(ctx)
+	m.priorities = append(m.priorities, priority)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []types.OHLCV{
+		{Symbol: symbol, Exchange: exchange, Close: 100, DateTime: start},
+		{Symbol: symbol, Exchange: exchange, Close: 101, DateTime: start.Add(time.Minute)},
+	}, nil
+}
+
+type memoryStore struct {
+	candles []types.OHLCV
+}
+
+func (s *memoryStore) Save(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	s.candles = append(s.candles, candles...)
+	return nil
+}
+
+func (s *memoryStore) Load(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	return s.candles, nil
+}
+
+func (s *memoryStore) Latest(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval) (time.Time, bool, error) {
+	if len(s.candles) == 0 {
+		return time.Time{}, false, nil
+	}
+	return s.candles[len(s.candles)-1].DateTime, true, nil
+}
+
+func newTestSyncer(p *mockProvider, store *memoryStore, now time.Time, opts ...Option) *Syncer {
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p))
+	s := NewSyncer(md, store, []Target{{Symbol: "INFY", Interval: types.Interval1m}}, opts...)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestSyncer_SyncOnce(t *testing.T) {
+	p := &mockProvider{}
+	store := &memoryStore{}
+	// Tuesday 2 Jan 2024, during the session.
+	now := time.Date(2024, 1, 2, 11, 0, 0, 0, ist)
+
+	var results []Result
+	s := newTestSyncer(p, store, now, WithLookback(time.Hour), WithSuccessHandler(func(r Result) {
+		results = append(results, r)
+	}))
+
+	if err := s.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(p.starts) != 1 || !p.starts[0].Equal(now.Add(-time.Hour)) {
+		t.Fatalf("Expected a first fetch over the lookback, got %v", p.starts)
+	}
+	if len(results) != 1 || results[0].Candles != 2 || results[0].Saved != 2 || results[0].Target.Exchange != types.ExchangeNSE {
+		t.Errorf("Expected a result with 2 candles, got %+v", results)
+	}
+
+	if err := s.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := now.Add(-time.Hour + time.Minute); len(p.starts) != 2 || !p.starts[1].Equal(want) {
+		t.Errorf("Expected the next fetch to start at the last stored candle %v, got %v", want, p.starts)
+	}
+}
+
+func TestSyncer_SyncOnce_Priority(t *testing.T) {
+	p := &mockProvider{}
+	now := time.Date(2024, 1, 2, 11, 0, 0, 0, ist)
+	s := newTestSyncer(p, &memoryStore{}, now, WithLookback(time.Hour))
+
+	s.SyncOnce(context.Background())
+	s.SyncOnce(provider.WithPriority(context.Background(), provider.PriorityHigh))
+
+	if len(p.priorities) != 2 || p.priorities[0] != provider.PriorityLow || p.priorities[1] != provider.PriorityHigh {
+		t.Errorf("Expected low priority unless the context sets one, got %v", p.priorities)
+	}
+}
+
+func TestSyncer_SavesOnlyChanges(t *testing.T) {
+	p := &mockProvider{}
+	now := time.Date(2024, 1, 2, 11, 0, 0, 0, ist)
+	last := now.Add(-time.Hour)
+	store := &memoryStore{candles: []types.OHLCV{
+		{Symbol: "INFY", Exchange: types.ExchangeNSE, Close: 100, DateTime: last},
+	}}
+
+	var results []Result
+	s := newTestSyncer(p, store, now, WithSuccessHandler(func(r Result) {
+		results = append(results, r)
+	}))
+
+	if err := s.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 1 || results[0].Candles != 2 || results[0].Saved != 1 {
+		t.Fatalf("Expected 2 candles fetched and 1 saved, got %+v", results)
+	}
+	if len(store.candles) != 2 || !store.candles[1].DateTime.Equal(last.Add(time.Minute)) {
+		t.Errorf("Expected only the new candle to be stored, got %+v", store.candles)
+	}
+}
+
+func TestSyncer_SkipsClosedMarket(t *testing.T) {
+	p := &mockProvider{}
+	store := &memoryStore{}
+	// Saturday 6 Jan 2024.
+	saturday := time.Date(2024, 1, 6, 11, 0, 0, 0, ist)
+	s := newTestSyncer(p, store, saturday)
+
+	for range 3 {
+		if err := s.SyncOnce(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(p.starts) != 1 {
+		t.Errorf("Expected a single fetch while the market is closed, got %d", len(p.starts))
+	}
+
+	// Monday's session has closed since the last sync.
+	s.now = func() time.Time { return time.Date(2024, 1, 8, 16, 0, 0, 0, ist) }
+	if err := s.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(p.starts) != 2 {
+		t.Errorf("Expected a fetch after the next session, got %d", len(p.starts))
+	}
+}
+
+func TestSyncer_FailureHandler(t *testing.T) {
+	providerErr := errors.New("upstream down")
+	p := &mockProvider{err: providerErr}
+	now := time.Date(2024, 1, 2, 11, 0, 0, 0, ist)
+
+	var failed []Target
+	s := newTestSyncer(p, &memoryStore{}, now, WithFailureHandler(func(t Target, err error) {
+		failed = append(failed, t)
+	}))
+
+	if err := s.SyncOnce(context.Background()); !errors.Is(err, providerErr) {
+		t.Errorf("Expected the provider error, got %v", err)
+	}
+	if len(failed) != 1 || failed[0].Symbol != "INFY" {
+		t.Errorf("Expected INFY to be reported as failed, got %v", failed)
+	}
+}
+
+func TestSyncer_Run(t *testing.T) {
+	p := &mockProvider{}
+	now := time.Date(2024, 1, 2, 11, 0, 0, 0, ist)
+	s := newTestSyncer(p, &memoryStore{}, now, WithInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Millisecond)
+	defer cancel()
+
+	if err := s.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if len(p.starts) < 2 {
+		t.Errorf("Expected repeated syncs, got %d", len(p.starts))
+	}
+}
@@ -2,24 +2,52 @@ package httpclient
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shahid-2020/gohlcv/internal/ratelimit"
 	"github.com/shahid-2020/gohlcv/internal/retry"
+	"github.com/shahid-2020/gohlcv/provider"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// TracerName is the instrumentation name of spans started by gohlcv.
+const TracerName = "github.com/shahid-2020/gohlcv"
+
 type Client struct {
 	httpClient    *http.Client
 	limiter       *ratelimit.RateLimiter
+	hostLimiters  map[string]*ratelimit.RateLimiter
 	retryer       *retry.Retryer
 	retryOnStatus []uint
+	tracer        trace.Tracer
+	compress      bool
+	conditional   *conditionalCache
+	maxResponse   int64
 }
 
 type RateLimitConfig struct {
 	RequestsPerSecond int
 	RequestsPerMinute int
 	RequestsPerHour   int
+	// Shared names a process-wide budget: clients with the same Shared name
+	// and limits use one limiter. A client gets its own limiter if it is empty.
+	Shared string
+	// MaxQueue caps how many requests may wait for the budget at once; more
+	// fail with an error matching provider.ErrRateLimited. Zero means no
+	// limit. Clients sharing a limiter share its queue and the limit set last.
+	MaxQueue int
+	// Hosts gives hosts, such as "query2.finance.yahoo.com", budgets of their
+	// own, so one client can serve several providers. Requests to other hosts
+	// draw from the limits above. The Hosts of the entries are ignored.
+	Hosts map[string]RateLimitConfig
 }
 
 type RetryConfig struct {
@@ -27,52 +55,354 @@ type RetryConfig struct {
 	BaseDelay     time.Duration
 	MaxDelay      time.Duration
 	RetryOnStatus []uint
+	// Backoff decides the delay between attempts, retry.Exponential if nil.
+	Backoff retry.BackoffStrategy
+	// Jitter randomizes the delays so concurrent clients spread their retries.
+	Jitter retry.Jitter
+	// Budget caps the retries of all requests to Budget per BudgetWindow, a
+	// minute if zero, on top of MaxRetries per request. Clients with the same
+	// RateLimitConfig.Shared name draw from one budget. Zero means no cap.
+	Budget       int
+	BudgetWindow time.Duration
+	// Hooks are told of every retry and of the requests given up on.
+	Hooks provider.RetryHooks
 }
 
 type ClientConfig struct {
+	// Name names the provider in retry events.
+	Name            string
 	HttpClient      *http.Client
 	RateLimitConfig RateLimitConfig
 	RetryConfig     RetryConfig
+	// TracerProvider traces every attempt of a request as a client span. No
+	// spans are recorded if it is nil.
+	TracerProvider trace.TracerProvider
+	// Transport tunes the transport built for HttpClient if it has none of
+	// its own.
+	Transport TransportConfig
+	// DisableCompression stops the client from asking for gzip or deflate
+	// encoded responses. Otherwise requests without an Accept-Encoding header
+	// ask for them and their responses are decompressed whatever transport
+	// HttpClient uses.
+	DisableCompression bool
+	// ConditionalCacheBytes keeps up to this many bytes of GET responses
+	// carrying an ETag or Last-Modified header, and sends later requests for
+	// the same URL with If-None-Match or If-Modified-Since. A 304 is then
+	// answered with the response kept. Zero disables conditional requests.
+	ConditionalCacheBytes int64
+	// MaxResponseBytes caps the size of a response body, after
+	// decompression. Reading past it fails with an error matching
+	// provider.ErrResponseTooLarge. Zero means no limit.
+	MaxResponseBytes int64
 }
 
 func NewClient(config ClientConfig) *Client {
 	if config.HttpClient == nil {
 		config.HttpClient = &http.Client{Timeout: 30 * time.Second}
 	}
+	if config.HttpClient.Transport == nil && !config.Transport.isZero() {
+		httpClient := *config.HttpClient
+		httpClient.Transport = newTransport(config.Transport)
+		config.HttpClient = &httpClient
+	}
+	if config.TracerProvider == nil {
+		config.TracerProvider = noop.NewTracerProvider()
+	}
+
+	retryOpts := []retry.Option{
+		retry.WithBackoff(config.RetryConfig.Backoff),
+		retry.WithJitter(config.RetryConfig.Jitter),
+	}
+	if config.RetryConfig.Budget > 0 {
+		retryOpts = append(retryOpts, retry.WithBudget(newBudget(config)))
+	}
+	if hook := config.RetryConfig.Hooks.OnRetry; hook != nil {
+		retryOpts = append(retryOpts, retry.WithOnRetry(func(a retry.Attempt) {
+			hook(retryEvent(config.Name, a))
+		}))
+	}
+	if hook := config.RetryConfig.Hooks.OnGiveUp; hook != nil {
+		retryOpts = append(retryOpts, retry.WithOnGiveUp(func(a retry.Attempt) {
+			hook(retryEvent(config.Name, a))
+		}))
+	}
+	retryer := retry.NewRetryer(
+		config.RetryConfig.MaxRetries,
+		config.RetryConfig.BaseDelay,
+		config.RetryConfig.MaxDelay,
+		retryOpts...,
+	)
+
+	var hostLimiters map[string]*ratelimit.RateLimiter
+	for host, limits := range config.RateLimitConfig.Hosts {
+		if hostLimiters == nil {
+			hostLimiters = make(map[string]*ratelimit.RateLimiter)
+		}
+		hostLimiters[strings.ToLower(host)] = newLimiter(limits)
+	}
+
+	var conditional *conditionalCache
+	if config.ConditionalCacheBytes > 0 {
+		conditional = newConditionalCache(config.ConditionalCacheBytes)
+	}
 
 	return &Client{
 		httpClient:    config.HttpClient,
-		limiter:       ratelimit.NewRateLimiter(config.RateLimitConfig.RequestsPerSecond, config.RateLimitConfig.RequestsPerMinute, config.RateLimitConfig.RequestsPerHour),
-		retryer:       retry.NewRetryer(config.RetryConfig.MaxRetries, config.RetryConfig.BaseDelay, config.RetryConfig.MaxDelay),
+		limiter:       newLimiter(config.RateLimitConfig),
+		hostLimiters:  hostLimiters,
+		retryer:       retryer,
 		retryOnStatus: config.RetryConfig.RetryOnStatus,
+		tracer:        config.TracerProvider.Tracer(TracerName),
+		compress:      !config.DisableCompression,
+		conditional:   conditional,
+		maxResponse:   config.MaxResponseBytes,
+	}
+}
+
+func newLimiter(limits RateLimitConfig) *ratelimit.RateLimiter {
+	limiter := ratelimit.NewRateLimiter(limits.RequestsPerSecond, limits.RequestsPerMinute, limits.RequestsPerHour)
+	if limits.Shared != "" {
+		limiter = ratelimit.Shared(limits.Shared, limits.RequestsPerSecond, limits.RequestsPerMinute, limits.RequestsPerHour)
+	}
+	if limits.MaxQueue > 0 {
+		limiter.SetQueueLimit(limits.MaxQueue)
 	}
+	return limiter
+}
+
+func newBudget(config ClientConfig) *retry.Budget {
+	window := config.RetryConfig.BudgetWindow
+	if window <= 0 {
+		window = time.Minute
+	}
+	if name := config.RateLimitConfig.Shared; name != "" {
+		return retry.SharedBudget(name, config.RetryConfig.Budget, window)
+	}
+	return retry.NewBudget(config.RetryConfig.Budget, window)
+}
+
+// Remaining returns how many more requests the client's rate limiter lets
+// through in the current second, minute and hour.
+func (c *Client) Remaining() (perSecond, perMinute, perHour int) {
+	return c.limiter.Remaining()
+}
+
+// RemainingFor is Remaining for requests to host.
+func (c *Client) RemainingFor(host string) (perSecond, perMinute, perHour int) {
+	return c.limiterFor(host).Remaining()
+}
+
+// limiterFor returns the limiter of requests to host.
+func (c *Client) limiterFor(host string) *ratelimit.RateLimiter {
+	if l, ok := c.hostLimiters[strings.ToLower(host)]; ok {
+		return l
+	}
+	return c.limiter
 }
 
 func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
 	var resp *http.Response
+	attempt := 0
+	replayable := canReplay(req)
+	limiter := c.limiterFor(req.URL.Hostname())
+	priority, _ := provider.PriorityFromContext(ctx)
 
-	err := c.retryer.Do(ctx, func() (bool, error) {
-		if err := c.limiter.Wait(ctx); err != nil {
-			return false, err
+	err := c.retryer.DoWithDelay(ctx, func() (bool, time.Duration, error) {
+		if err := limiter.WaitPriority(ctx, ratelimit.Priority(priority)); err != nil {
+			if errors.Is(err, ratelimit.ErrQueueFull) {
+				return false, 0, fmt.Errorf("%w: %w", provider.ErrRateLimited, err)
+			}
+			return false, 0, err
 		}
+		attempt++
 
 		var err error
-		resp, err = c.httpClient.Do(req)
+		resp, err = c.send(req, attempt)
 		if err != nil {
-			return true, err
+			return replayable, 0, err
 		}
 
-		if c.retryOnStatus != nil {
+		after := observeLimits(resp, limiter)
+
+		if replayable && c.retryOnStatus != nil {
 			for _, status := range c.retryOnStatus {
 				if resp.StatusCode == int(status) {
 					resp.Body.Close()
-					return true, nil
+					return true, after, retryableStatus(resp.StatusCode)
 				}
 			}
 		}
 
-		return false, nil
+		return false, 0, nil
 	})
 
+	// Running out of retries on a status leaves the last response to the
+	// caller, not an error.
+	var status retryableStatus
+	if errors.As(err, &status) {
+		err = nil
+	}
+
 	return resp, err
 }
+
+// retryableStatus is the error of an attempt answered with a status in
+// RetryOnStatus, which tells the retry hooks the status.
+type retryableStatus int
+
+func (s retryableStatus) Error() string {
+	return "retryable status " + strconv.Itoa(int(s))
+}
+
+func retryEvent(name string, a retry.Attempt) provider.RetryEvent {
+	e := provider.RetryEvent{Provider: name, Attempt: int(a.Number), Delay: a.Delay, Err: a.Err}
+	var status retryableStatus
+	if errors.As(a.Err, &status) {
+		e.Err = nil
+		e.StatusCode = int(status)
+	}
+	return e
+}
+
+// send performs a single attempt of req inside a client span.
+func (c *Client) send(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, span := c.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("url.path", req.URL.Path),
+			attribute.Int("gohlcv.attempt", attempt),
+		),
+	)
+	defer span.End()
+
+	attemptReq, err := rewind(ctx, req, attempt)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	compressed := c.compress && requestCompression(attemptReq)
+
+	var (
+		key         string
+		entry       *conditionalEntry
+		conditional bool
+	)
+	if c.conditional != nil {
+		if key, conditional = conditionalKey(attemptReq); conditional {
+			entry = c.conditional.prepare(key, attemptReq)
+		}
+	}
+
+	resp, err := c.httpClient.Do(attemptReq)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if compressed {
+		decompress(resp)
+	}
+	if c.maxResponse > 0 {
+		limitBody(resp, c.maxResponse)
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	if conditional {
+		c.conditional.complete(key, entry, resp)
+	}
+
+	return resp, nil
+}
+
+// canReplay reports whether req can be sent more than once: it has no body,
+// or GetBody can produce a fresh copy of it. Requests that can't be replayed
+// are sent once and never retried.
+func canReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req for the given attempt. The transport consumes
+// and closes the body of every request it sends, so attempts after the first
+// get a fresh body from GetBody.
+func rewind(ctx context.Context, req *http.Request, attempt int) (*http.Request, error) {
+	r := req.Clone(ctx)
+	if attempt == 1 || req.GetBody == nil {
+		return r, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("rewinding request body: %w", err)
+	}
+	r.Body = body
+
+	return r, nil
+}
+
+// observeLimits reads the rate-limit headers of resp. When the server reports
+// its quota as used up, limiter is paused until the reset time so no request
+// sharing it goes out before then. On 429 and 503 responses
+// it returns how long Retry-After asks to wait, which takes precedence over a
+// shorter backoff.
+func observeLimits(resp *http.Response, limiter *ratelimit.RateLimiter) time.Duration {
+	now := time.Now()
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, ok := parseReset(resp.Header.Get("X-RateLimit-Reset"), now); ok {
+			limiter.PauseUntil(reset)
+		}
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+
+	after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	if !ok {
+		return 0
+	}
+	limiter.PauseUntil(now.Add(after))
+
+	return after
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0), true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// parseReset parses an X-RateLimit-Reset header. Providers disagree on its
+// meaning, so values that look like Unix timestamps are read as one and
+// smaller values as seconds from now.
+func parseReset(v string, now time.Time) (time.Time, bool) {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+
+	if n >= 1_000_000_000 {
+		return time.Unix(n, 0), true
+	}
+	return now.Add(time.Duration(n) * time.Second), true
+}
@@ -6,13 +6,22 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/ratelimit"
+	"github.com/shahid-2020/gohlcv/provider"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 type mockResponse struct {
 	statusCode int
 	body       string
+	header     http.Header
 	err        error
 }
 
@@ -20,6 +29,7 @@ type mockTransport struct {
 	attempts  *int
 	responses []*mockResponse
 	index     int
+	bodies    []string
 }
 
 func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -27,6 +37,12 @@ func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		*m.attempts++
 	}
 
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		m.bodies = append(m.bodies, string(body))
+	}
+
 	if m.index >= len(m.responses) {
 		return nil, errors.New("no more mock responses")
 	}
@@ -38,10 +54,15 @@ func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, response.err
 	}
 
+	header := response.header
+	if header == nil {
+		header = make(http.Header)
+	}
+
 	return &http.Response{
 		StatusCode: response.statusCode,
 		Body:       io.NopCloser(bytes.NewBufferString(response.body)),
-		Header:     make(http.Header),
+		Header:     header,
 		Request:    req,
 	}, nil
 }
@@ -492,3 +513,487 @@ func TestClient_Do_MaxRetriesExceeded(t *testing.T) {
 
 	resp.Body.Close()
 }
+
+func TestClient_Do_TracesAttempts(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	config := ClientConfig{
+		HttpClient: &http.Client{
+			Transport: &mockTransport{
+				responses: []*mockResponse{
+					{statusCode: 503, body: "Error"},
+					{statusCode: 200, body: "Success"},
+				},
+			},
+		},
+		RateLimitConfig: RateLimitConfig{
+			RequestsPerSecond: 100,
+			RequestsPerMinute: 1000,
+			RequestsPerHour:   10000,
+		},
+		RetryConfig: RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     10 * time.Millisecond,
+			MaxDelay:      100 * time.Millisecond,
+			RetryOnStatus: []uint{503},
+		},
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+	}
+
+	client := NewClient(config)
+	req, _ := http.NewRequest("GET", "http://example.com/v8/finance/chart/RELIANCE.NS", nil)
+	resp, err := client.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	for i, span := range spans {
+		if v := spanAttr(span, "gohlcv.attempt"); v.AsInt64() != int64(i+1) {
+			t.Errorf("Span %d: expected attempt %d, got %d", i, i+1, v.AsInt64())
+		}
+		if v := spanAttr(span, "server.address"); v.AsString() != "example.com" {
+			t.Errorf("Span %d: expected server.address example.com, got %s", i, v.AsString())
+		}
+	}
+
+	if v := spanAttr(spans[0], "http.response.status_code"); v.AsInt64() != 503 {
+		t.Errorf("Expected first attempt status 503, got %d", v.AsInt64())
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("Expected first attempt to be marked as error, got %v", spans[0].Status().Code)
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Error("Expected second attempt not to be marked as error")
+	}
+}
+
+func TestClient_Do_RetryAfter(t *testing.T) {
+	config := ClientConfig{
+		HttpClient: &http.Client{
+			Transport: &mockTransport{
+				responses: []*mockResponse{
+					{statusCode: 429, body: "Slow down", header: http.Header{"Retry-After": {"1"}}},
+					{statusCode: 200, body: "Success"},
+				},
+			},
+		},
+		RateLimitConfig: RateLimitConfig{
+			RequestsPerSecond: 100,
+			RequestsPerMinute: 1000,
+			RequestsPerHour:   10000,
+		},
+		RetryConfig: RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     10 * time.Millisecond,
+			MaxDelay:      100 * time.Millisecond,
+			RetryOnStatus: []uint{429},
+		},
+	}
+
+	client := NewClient(config)
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	start := time.Now()
+	resp, err := client.Do(context.Background(), req)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if elapsed < 900*time.Millisecond {
+		t.Errorf("Expected to wait about 1s as asked by Retry-After, waited %v", elapsed)
+	}
+}
+
+func TestClient_Do_RateLimitReset(t *testing.T) {
+	attempts := 0
+	config := ClientConfig{
+		HttpClient: &http.Client{
+			Transport: &mockTransport{
+				attempts: &attempts,
+				responses: []*mockResponse{
+					{statusCode: 200, body: "OK", header: http.Header{
+						"X-Ratelimit-Remaining": {"0"},
+						"X-Ratelimit-Reset":     {"1"},
+					}},
+					{statusCode: 200, body: "OK"},
+				},
+			},
+		},
+		RateLimitConfig: RateLimitConfig{
+			RequestsPerSecond: 100,
+			RequestsPerMinute: 1000,
+			RequestsPerHour:   10000,
+		},
+	}
+
+	client := NewClient(config)
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	resp, err := client.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err = client.Do(ctx, req)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded while the quota resets, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestClient_Do_RetriesRewindBody(t *testing.T) {
+	transport := &mockTransport{
+		responses: []*mockResponse{
+			{statusCode: 500, body: "Error"},
+			{statusCode: 500, body: "Error"},
+			{statusCode: 200, body: "Success"},
+		},
+	}
+	config := ClientConfig{
+		HttpClient: &http.Client{Transport: transport},
+		RateLimitConfig: RateLimitConfig{
+			RequestsPerSecond: 100,
+			RequestsPerMinute: 1000,
+			RequestsPerHour:   10000,
+		},
+		RetryConfig: RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     10 * time.Millisecond,
+			MaxDelay:      100 * time.Millisecond,
+			RetryOnStatus: []uint{500},
+		},
+	}
+
+	client := NewClient(config)
+	req, _ := http.NewRequest("POST", "http://example.com", strings.NewReader(`{"symbol":"RELIANCE"}`))
+	resp, err := client.Do(context.Background(), req)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(transport.bodies) != 3 {
+		t.Fatalf("Expected 3 bodies sent, got %d", len(transport.bodies))
+	}
+	for i, body := range transport.bodies {
+		if body != `{"symbol":"RELIANCE"}` {
+			t.Errorf("Expected attempt %d to send the full body, got %q", i+1, body)
+		}
+	}
+}
+
+func TestClient_Do_NoRetryWithoutGetBody(t *testing.T) {
+	attempts := 0
+	config := ClientConfig{
+		HttpClient: &http.Client{
+			Transport: &mockTransport{
+				attempts: &attempts,
+				responses: []*mockResponse{
+					{statusCode: 500, body: "Error"},
+					{statusCode: 200, body: "Success"},
+				},
+			},
+		},
+		RateLimitConfig: RateLimitConfig{
+			RequestsPerSecond: 100,
+			RequestsPerMinute: 1000,
+			RequestsPerHour:   10000,
+		},
+		RetryConfig: RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     10 * time.Millisecond,
+			MaxDelay:      100 * time.Millisecond,
+			RetryOnStatus: []uint{500},
+		},
+	}
+
+	client := NewClient(config)
+	req, _ := http.NewRequest("POST", "http://example.com", io.NopCloser(strings.NewReader("payload")))
+	resp, err := client.Do(context.Background(), req)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+	if resp.StatusCode != 500 {
+		t.Errorf("Expected status 500, got %d", resp.StatusCode)
+	}
+}
+
+func TestNewClient_SharedLimiter(t *testing.T) {
+	config := ClientConfig{
+		RateLimitConfig: RateLimitConfig{
+			RequestsPerSecond: 1,
+			RequestsPerMinute: 10,
+			RequestsPerHour:   100,
+			Shared:            "TestNewClient_SharedLimiter",
+		},
+	}
+
+	a, b := NewClient(config), NewClient(config)
+	if a.limiter != b.limiter {
+		t.Error("Expected clients with the same Shared name to share a limiter")
+	}
+
+	config.RateLimitConfig.Shared = ""
+	if c := NewClient(config); c.limiter == a.limiter {
+		t.Error("Expected a client without a Shared name to get its own limiter")
+	}
+}
+
+func TestClient_Do_HostRateLimits(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header := make(http.Header)
+		if req.URL.Hostname() == "api.upstox.com" {
+			header.Set("X-Ratelimit-Remaining", "0")
+			header.Set("X-Ratelimit-Reset", "60")
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody, Header: header, Request: req}, nil
+	})
+	client := NewClient(ClientConfig{
+		HttpClient: &http.Client{Transport: transport},
+		RateLimitConfig: RateLimitConfig{
+			RequestsPerSecond: 100,
+			RequestsPerMinute: 1000,
+			RequestsPerHour:   10000,
+			Hosts: map[string]RateLimitConfig{
+				"Query2.Finance.Yahoo.com": {RequestsPerSecond: 1, RequestsPerMinute: 1, RequestsPerHour: 1},
+				"api.upstox.com":           {RequestsPerSecond: 50, RequestsPerMinute: 500, RequestsPerHour: 2000},
+			},
+		},
+	})
+
+	do := func(ctx context.Context, url string) error {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp, err := client.Do(ctx, req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := do(context.Background(), "https://query2.finance.yahoo.com/v8/finance/chart/INFY.NS"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	if err := do(ctx, "https://query2.finance.yahoo.com/v8/finance/chart/TCS.NS"); err != context.DeadlineExceeded {
+		t.Errorf("Expected the yahoo budget to be used up, got %v", err)
+	}
+
+	if err := do(context.Background(), "https://api.upstox.com/v3/historical-candle"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if s, m, h := client.RemainingFor("api.upstox.com"); s != 0 || m != 0 || h != 0 {
+		t.Errorf("Expected the upstox budget paused by its reset header, got %d/%d/%d", s, m, h)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := do(context.Background(), "https://example.com"); err != nil {
+			t.Fatalf("Expected other hosts to use the default budget, got %v", err)
+		}
+	}
+	if _, m, _ := client.Remaining(); m != 997 {
+		t.Errorf("Expected 997 requests left this minute, got %d", m)
+	}
+}
+
+func TestClient_Do_RetryBudget(t *testing.T) {
+	attempts := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: 503, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+	})
+	client := NewClient(ClientConfig{
+		HttpClient:      &http.Client{Transport: transport},
+		RateLimitConfig: RateLimitConfig{RequestsPerSecond: 10, RequestsPerMinute: 10, RequestsPerHour: 10},
+		RetryConfig: RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     time.Millisecond,
+			MaxDelay:      time.Millisecond,
+			RetryOnStatus: []uint{503},
+			Budget:        2,
+		},
+	})
+
+	for range 2 {
+		req, _ := http.NewRequest("GET", "http://example.com", nil)
+		resp, err := client.Do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if resp.StatusCode != 503 {
+			t.Errorf("Expected the last 503 response, got %d", resp.StatusCode)
+		}
+	}
+
+	// The first request uses up the budget after 2 retries; the second gets
+	// none.
+	if attempts != 4 {
+		t.Errorf("Expected 4 attempts, got %d", attempts)
+	}
+}
+
+func TestClient_Do_RetryHooks(t *testing.T) {
+	transport := &mockTransport{responses: []*mockResponse{
+		{err: errors.New("connection reset")},
+		{statusCode: 503},
+		{statusCode: 503},
+	}}
+	var retries, giveUps []provider.RetryEvent
+	client := NewClient(ClientConfig{
+		Name:            "yahoo",
+		HttpClient:      &http.Client{Transport: transport},
+		RateLimitConfig: RateLimitConfig{RequestsPerSecond: 10, RequestsPerMinute: 10, RequestsPerHour: 10},
+		RetryConfig: RetryConfig{
+			MaxRetries:    2,
+			BaseDelay:     time.Millisecond,
+			MaxDelay:      time.Millisecond,
+			RetryOnStatus: []uint{503},
+			Hooks: provider.RetryHooks{
+				OnRetry:  func(e provider.RetryEvent) { retries = append(retries, e) },
+				OnGiveUp: func(e provider.RetryEvent) { giveUps = append(giveUps, e) },
+			},
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	resp, err := client.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected the last response without an error, got %v", err)
+	}
+	if resp.StatusCode != 503 {
+		t.Errorf("Expected status 503, got %d", resp.StatusCode)
+	}
+
+	if len(retries) != 2 {
+		t.Fatalf("Expected 2 retries, got %+v", retries)
+	}
+	if e := retries[0]; e.Provider != "yahoo" || e.Attempt != 1 || e.Err == nil || e.StatusCode != 0 || e.Delay != time.Millisecond {
+		t.Errorf("Expected a retry of the network error, got %+v", e)
+	}
+	if e := retries[1]; e.Attempt != 2 || e.Err != nil || e.StatusCode != 503 {
+		t.Errorf("Expected a retry of the 503, got %+v", e)
+	}
+	if len(giveUps) != 1 || giveUps[0].Attempt != 3 || giveUps[0].StatusCode != 503 || giveUps[0].Delay != 0 {
+		t.Errorf("Expected to give up after attempt 3, got %+v", giveUps)
+	}
+}
+
+func TestClient_Do_QueueFull(t *testing.T) {
+	client := NewClient(ClientConfig{
+		HttpClient: &http.Client{Transport: &mockTransport{}},
+		// Zero limits keep every request waiting.
+		RateLimitConfig: RateLimitConfig{MaxQueue: 1},
+	})
+
+	ctx, cancel := context.WithCancel(provider.WithPriority(context.Background(), provider.PriorityLow))
+	defer cancel()
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			_, err := client.Do(ctx, req)
+			errs <- err
+		}()
+	}
+
+	// One request is queued and the other turned away.
+	err := <-errs
+	if !errors.Is(err, provider.ErrRateLimited) || !errors.Is(err, ratelimit.ErrQueueFull) {
+		t.Errorf("Expected a full queue to be rate limited, got %v", err)
+	}
+
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("Expected the queued request to be cancelled, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"Seconds", "120", 2 * time.Minute, true},
+		{"HTTPDate", "Tue, 02 Jan 2024 10:00:30 GMT", 30 * time.Second, true},
+		{"PastDate", "Tue, 02 Jan 2024 09:00:00 GMT", 0, true},
+		{"Empty", "", 0, false},
+		{"Invalid", "soon", 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			after, ok := parseRetryAfter(tc.value, now)
+			if ok != tc.ok {
+				t.Errorf("Expected ok %v, got %v", tc.ok, ok)
+			}
+			if after != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, after)
+			}
+		})
+	}
+}
+
+func TestParseReset(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		ok       bool
+	}{
+		{"Seconds", "60", now.Add(time.Minute), true},
+		{"UnixTime", "1704189600", time.Unix(1704189600, 0), true},
+		{"Empty", "", time.Time{}, false},
+		{"Negative", "-5", time.Time{}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reset, ok := parseReset(tc.value, now)
+			if ok != tc.ok {
+				t.Errorf("Expected ok %v, got %v", tc.ok, ok)
+			}
+			if !reset.Equal(tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, reset)
+			}
+		})
+	}
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
@@ -0,0 +1,79 @@
+package httpclient
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is the Accept-Encoding header sent with requests that don't
+// set their own.
+const acceptEncoding = "gzip, deflate"
+
+// requestCompression asks for a compressed response to req, unless req
+// already says which encodings it accepts. It reports whether the response
+// is then the client's to decompress.
+func requestCompression(req *http.Request) bool {
+	if req.Header.Get("Accept-Encoding") != "" || req.Method == http.MethodHead {
+		return false
+	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	return true
+}
+
+// decompress replaces the body of a gzip or deflate encoded resp with its
+// decompressed content, as http.Transport does for the requests it
+// compresses itself. Other responses are left as they are.
+func decompress(resp *http.Response) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "deflate" {
+		return
+	}
+
+	resp.Body = &decompressingBody{body: resp.Body, encoding: encoding}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// decompressingBody decompresses body on its first read, so that an empty
+// body closed unread, as of a 204 or an error response, isn't an error.
+type decompressingBody struct {
+	body     io.ReadCloser
+	encoding string
+	r        io.Reader
+	err      error
+}
+
+func (b *decompressingBody) Read(p []byte) (int, error) {
+	if b.r == nil && b.err == nil {
+		b.r, b.err = b.reader()
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.r.Read(p)
+}
+
+func (b *decompressingBody) reader() (io.Reader, error) {
+	if b.encoding == "gzip" {
+		return gzip.NewReader(b.body)
+	}
+
+	// Deflate should be zlib-wrapped, but some servers send raw deflate.
+	br := bufio.NewReader(b.body)
+	header, err := br.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+func (b *decompressingBody) Close() error {
+	return b.body.Close()
+}
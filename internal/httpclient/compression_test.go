@@ -0,0 +1,150 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func compressed(t *testing.T, encoding, body string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	if _, err := w.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestClient_Do_Decompresses(t *testing.T) {
+	const body = `{"chart":{"result":[]}}`
+
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			var accepted string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accepted = r.Header.Get("Accept-Encoding")
+				w.Header().Set("Content-Encoding", strings.TrimPrefix(encoding, "raw-"))
+				w.Write(compressed(t, encoding, body))
+			}))
+			defer server.Close()
+
+			// A custom transport, which wouldn't decompress on its own.
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return http.DefaultTransport.RoundTrip(req)
+			})
+			client := NewClient(ClientConfig{
+				HttpClient:      &http.Client{Transport: transport},
+				RateLimitConfig: RateLimitConfig{RequestsPerSecond: 10, RequestsPerMinute: 10, RequestsPerHour: 10},
+			})
+
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			resp, err := client.Do(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			defer resp.Body.Close()
+
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Expected no error reading the body, got %v", err)
+			}
+			if string(got) != body {
+				t.Errorf("Expected %s, got %q", body, got)
+			}
+			if accepted != acceptEncoding {
+				t.Errorf("Expected Accept-Encoding %q, got %q", acceptEncoding, accepted)
+			}
+			if resp.Header.Get("Content-Encoding") != "" || !resp.Uncompressed || resp.ContentLength != -1 {
+				t.Errorf("Expected the response marked uncompressed, got %v %v %d", resp.Header, resp.Uncompressed, resp.ContentLength)
+			}
+		})
+	}
+}
+
+func TestClient_Do_CallerAcceptEncoding(t *testing.T) {
+	gzipped := compressed(t, "gzip", "hello")
+	transport := &mockTransport{responses: []*mockResponse{
+		{statusCode: 200, body: string(gzipped), header: http.Header{"Content-Encoding": {"gzip"}}},
+	}}
+	client := NewClient(ClientConfig{
+		HttpClient:      &http.Client{Transport: transport},
+		RateLimitConfig: RateLimitConfig{RequestsPerSecond: 10, RequestsPerMinute: 10, RequestsPerHour: 10},
+	})
+
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := client.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got, _ := io.ReadAll(resp.Body); !bytes.Equal(got, gzipped) {
+		t.Errorf("Expected the body left compressed for the caller, got %q", got)
+	}
+}
+
+func TestClient_Do_DisableCompression(t *testing.T) {
+	var accepted []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		accepted = append(accepted, req.Header.Get("Accept-Encoding"))
+		return &http.Response{StatusCode: 200, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+	})
+
+	for _, disable := range []bool{false, true} {
+		client := NewClient(ClientConfig{
+			HttpClient:         &http.Client{Transport: transport},
+			RateLimitConfig:    RateLimitConfig{RequestsPerSecond: 10, RequestsPerMinute: 10, RequestsPerHour: 10},
+			DisableCompression: disable,
+		})
+		req, _ := http.NewRequest("GET", "https://example.com", nil)
+		resp, err := client.Do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if accepted[0] != acceptEncoding || accepted[1] != "" {
+		t.Errorf("Expected compression asked for only when enabled, got %q", accepted)
+	}
+}
+
+func TestDecompress_EmptyBody(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     http.Header{"Content-Encoding": {"gzip"}},
+		Body:       http.NoBody,
+	}
+	decompress(resp)
+
+	if err := resp.Body.Close(); err != nil {
+		t.Errorf("Expected closing an unread empty body to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package httpclient
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// conditionalCache keeps the validators and bodies of recent GET responses,
+// so that requests for the same URL can be made conditional and a 304 answered
+// from the copy kept. It holds at most maxBytes of bodies, evicting the least
+// recently used first.
+type conditionalCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	entries  map[string]*list.Element
+	order    *list.List // of *conditionalEntry, most recently used first
+}
+
+type conditionalEntry struct {
+	key          string
+	etag         string
+	lastModified string
+	status       string
+	statusCode   int
+	header       http.Header
+	body         []byte
+}
+
+func newConditionalCache(maxBytes int64) *conditionalCache {
+	return &conditionalCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// conditionalKey returns the key of req, or false if req can't be made
+// conditional. Credentials are part of the key, since responses may differ
+// by user.
+func conditionalKey(req *http.Request) (string, bool) {
+	if req.Method != http.MethodGet ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return "", false
+	}
+	return req.URL.String() + "\x00" + req.Header.Get("Authorization"), true
+}
+
+// prepare adds the validators kept for key to req and returns their entry,
+// or nil if there is none.
+func (c *conditionalCache) prepare(key string, req *http.Request) *conditionalEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(el)
+
+	e := el.Value.(*conditionalEntry)
+	if e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		req.Header.Set("If-Modified-Since", e.lastModified)
+	}
+	return e
+}
+
+// complete handles resp to a request prepared with entry e. A 304 is
+// replaced with the response kept in e; a 200 carrying validators is kept
+// for next time.
+func (c *conditionalCache) complete(key string, e *conditionalEntry, resp *http.Response) {
+	if resp.StatusCode == http.StatusNotModified && e != nil {
+		resp.Body.Close()
+		resp.StatusCode, resp.Status = e.statusCode, e.status
+		resp.Header = e.header.Clone()
+		resp.Body = io.NopCloser(bytes.NewReader(e.body))
+		resp.ContentLength = int64(len(e.body))
+		return
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") ||
+		resp.ContentLength > c.maxBytes {
+		return
+	}
+
+	// Read at most maxBytes; a larger body is handed on unkept.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBytes+1))
+	if err != nil || int64(len(body)) > c.maxBytes {
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.store(&conditionalEntry{
+		key:          key,
+		etag:         etag,
+		lastModified: lastModified,
+		status:       resp.Status,
+		statusCode:   resp.StatusCode,
+		header:       resp.Header.Clone(),
+		body:         body,
+	})
+}
+
+func (c *conditionalCache) store(e *conditionalEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[e.key]; ok {
+		c.size -= int64(len(el.Value.(*conditionalEntry).body))
+		c.order.Remove(el)
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	c.size += int64(len(e.body))
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		old := oldest.Value.(*conditionalEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, old.key)
+		c.size -= int64(len(old.body))
+	}
+}
+
+// readCloser reads from Reader and closes Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Do_ConditionalRequests(t *testing.T) {
+	const body = `{"chart":{"result":[]}}`
+	var sent, validated int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			validated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		RateLimitConfig:       RateLimitConfig{RequestsPerSecond: 10, RequestsPerMinute: 10, RequestsPerHour: 10},
+		ConditionalCacheBytes: 1 << 20,
+	})
+
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("GET", server.URL+"/v8/finance/chart/INFY.NS", nil)
+		resp, err := client.Do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		got, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || string(got) != body {
+			t.Errorf("Request %d: expected the full response, got %d %q", i, resp.StatusCode, got)
+		}
+		if resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Request %d: expected the kept headers, got %v", i, resp.Header)
+		}
+	}
+
+	if sent != 3 || validated != 2 {
+		t.Errorf("Expected 3 requests, 2 of them conditional, got %d and %d", sent, validated)
+	}
+}
+
+func TestConditionalCache_Evicts(t *testing.T) {
+	c := newConditionalCache(10)
+	respond := func(key, body string) {
+		resp := &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Etag": {`"` + key + `"`}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: -1,
+		}
+		c.complete(key, nil, resp)
+		if got, _ := io.ReadAll(resp.Body); string(got) != body {
+			t.Errorf("Expected the body %q handed on, got %q", body, got)
+		}
+	}
+
+	respond("a", "123456")
+	respond("b", "1234")
+	respond("c", "12")
+	respond("d", "12345678901")
+
+	if _, ok := c.entries["a"]; ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := c.entries["d"]; ok {
+		t.Error("Expected a body over the limit not to be kept")
+	}
+	if c.size != 6 || len(c.entries) != 2 {
+		t.Errorf("Expected 2 entries of 6 bytes, got %d of %d", len(c.entries), c.size)
+	}
+}
+
+func TestConditionalKey(t *testing.T) {
+	get, _ := http.NewRequest("GET", "https://api.upstox.com/v3/historical-candle", nil)
+	if _, ok := conditionalKey(get); !ok {
+		t.Error("Expected a GET to be made conditional")
+	}
+
+	post, _ := http.NewRequest("POST", "https://api.upstox.com/v2/login", nil)
+	if _, ok := conditionalKey(post); ok {
+		t.Error("Expected a POST not to be made conditional")
+	}
+
+	own, _ := http.NewRequest("GET", "https://api.upstox.com/v3/historical-candle", nil)
+	own.Header.Set("If-None-Match", `"mine"`)
+	if _, ok := conditionalKey(own); ok {
+		t.Error("Expected a request with its own validators to be left alone")
+	}
+
+	authed := get.Clone(context.Background())
+	authed.Header.Set("Authorization", "Bearer token")
+	if a, _ := conditionalKey(get); a == func() string { k, _ := conditionalKey(authed); return k }() {
+		t.Error("Expected credentials to be part of the key")
+	}
+}
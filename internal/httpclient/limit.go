@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/shahid-2020/gohlcv/provider"
+)
+
+// LimitReader returns a reader of r that fails with an error matching
+// provider.ErrResponseTooLarge once r holds more than n bytes. Unlike
+// io.LimitReader it doesn't end quietly at the limit, so a response cut
+// short isn't mistaken for a whole one.
+func LimitReader(r io.Reader, n int64) io.Reader {
+	return &limitedReader{r: r, n: n, left: n}
+}
+
+type limitedReader struct {
+	r       io.Reader
+	n, left int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, l.err()
+	}
+	// Read one byte past the limit to tell a body of exactly n bytes from a
+	// longer one.
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return n + int(l.left), l.err()
+	}
+	return n, err
+}
+
+func (l *limitedReader) err() error {
+	return fmt.Errorf("%w: more than %d bytes", provider.ErrResponseTooLarge, l.n)
+}
+
+// limitBody caps the body of resp at n bytes.
+func limitBody(resp *http.Response, n int64) {
+	resp.Body = readCloser{LimitReader(resp.Body, n), resp.Body}
+}
@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+)
+
+func TestLimitReader(t *testing.T) {
+	got, err := io.ReadAll(LimitReader(strings.NewReader("12345"), 5))
+	if err != nil || string(got) != "12345" {
+		t.Errorf("Expected a body of exactly the limit read whole, got %q, %v", got, err)
+	}
+
+	got, err = io.ReadAll(LimitReader(strings.NewReader("123456"), 5))
+	if !errors.Is(err, provider.ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+	if len(got) > 5 {
+		t.Errorf("Expected at most 5 bytes read, got %q", got)
+	}
+}
+
+func TestClient_Do_MaxResponseBytes(t *testing.T) {
+	body := strings.Repeat("x", 1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed(t, "gzip", body))
+	}))
+	defer server.Close()
+
+	for _, limit := range []int64{1024, 1023} {
+		client := NewClient(ClientConfig{
+			RateLimitConfig:  RateLimitConfig{RequestsPerSecond: 10, RequestsPerMinute: 10, RequestsPerHour: 10},
+			MaxResponseBytes: limit,
+		})
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		resp, err := client.Do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if tooLarge := errors.Is(err, provider.ErrResponseTooLarge); tooLarge != (limit < 1024) {
+			t.Errorf("Limit %d: expected the decompressed size checked, got %v", limit, err)
+		}
+	}
+}
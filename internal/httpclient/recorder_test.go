@@ -0,0 +1,160 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, rt http.RoundTripper, url string) (*http.Response, string, error) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body), nil
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	var attempts int
+	transport := &mockTransport{attempts: &attempts, responses: []*mockResponse{{
+		statusCode: 200,
+		body:       `{"chart":{}}`,
+		header:     http.Header{"Content-Type": {"application/json"}, "Set-Cookie": {"session=secret"}},
+	}}}
+
+	rec := NewRecorder(dir, ModeRecord, transport)
+	_, body, err := get(t, rec, "https://example.com/chart/INFY?interval=1d&apikey=secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body != `{"chart":{}}` || attempts != 1 {
+		t.Errorf("Expected the live response while recording, got %q after %d attempts", body, attempts)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 || !strings.HasPrefix(filepath.Base(files[0]), "example.com_") {
+		t.Fatalf("Expected one fixture named after the host, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("Expected credentials redacted from the fixture, got %s", data)
+	}
+
+	// Replay never touches the transport, and a different key finds the same
+	// fixture.
+	replay := NewRecorder(dir, ModeReplay, &mockTransport{attempts: &attempts})
+	resp, body, err := get(t, replay, "https://example.com/chart/INFY?interval=1d&apikey=other")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.StatusCode != 200 || body != `{"chart":{}}` || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected replayed response: %d %q %v", resp.StatusCode, body, resp.Header)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected no network call on replay, got %d attempts", attempts)
+	}
+}
+
+func TestRecorder_ReplayMissing(t *testing.T) {
+	rec := NewRecorder(t.TempDir(), ModeReplay, &mockTransport{})
+
+	_, _, err := get(t, rec, "https://example.com/chart/TCS")
+	if !errors.Is(err, ErrNoFixture) {
+		t.Errorf("Expected ErrNoFixture, got %v", err)
+	}
+}
+
+func TestRecorder_RecordMissing(t *testing.T) {
+	dir := t.TempDir()
+	var attempts int
+	transport := &mockTransport{attempts: &attempts, responses: []*mockResponse{
+		{statusCode: 200, body: "first"},
+		{statusCode: 404, body: "second"},
+	}}
+	rec := NewRecorder(dir, ModeRecordMissing, transport)
+
+	for range 2 {
+		if _, body, err := get(t, rec, "https://example.com/a"); err != nil || body != "first" {
+			t.Fatalf("Expected the recorded response, got %q, %v", body, err)
+		}
+	}
+	resp, body, err := get(t, rec, "https://example.com/b")
+	if err != nil || resp.StatusCode != 404 || body != "second" {
+		t.Errorf("Expected error responses recorded too, got %v %q", resp, body)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected one network call per new request, got %d", attempts)
+	}
+}
+
+func TestRecorder_Binary(t *testing.T) {
+	dir := t.TempDir()
+	payload := string([]byte{0x1f, 0x8b, 0xff, 0x00})
+	NewRecorder(dir, ModeRecord, &mockTransport{responses: []*mockResponse{{statusCode: 200, body: payload}}}).
+		RoundTrip(mustRequest(t, "https://example.com/bin"))
+
+	_, body, err := get(t, NewRecorder(dir, ModeReplay, nil), "https://example.com/bin")
+	if err != nil || body != payload {
+		t.Errorf("Expected binary bodies to round-trip, got %q, %v", body, err)
+	}
+}
+
+func TestRecorder_RequestBody(t *testing.T) {
+	dir := t.TempDir()
+	transport := &mockTransport{responses: []*mockResponse{{statusCode: 200, body: "a"}, {statusCode: 200, body: "b"}}}
+	rec := NewRecorder(dir, ModeRecord, transport)
+
+	for _, payload := range []string{`{"q":"a"}`, `{"q":"b"}`} {
+		req, _ := http.NewRequest(http.MethodPost, "https://example.com/search", strings.NewReader(payload))
+		if _, err := rec.RoundTrip(req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) != 2 {
+		t.Errorf("Expected a fixture per request body, got %v", files)
+	}
+	if transport.bodies[0] != `{"q":"a"}` {
+		t.Errorf("Expected the body passed on to the transport, got %q", transport.bodies[0])
+	}
+}
+
+func TestRecordModeFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  RecordMode
+	}{
+		{"", ModeReplay},
+		{"1", ModeRecord},
+		{"all", ModeRecord},
+		{"new", ModeRecordMissing},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(RecordEnv, tt.value)
+		if got := RecordModeFromEnv(); got != tt.want {
+			t.Errorf("Expected %v for %q, got %v", tt.want, tt.value, got)
+		}
+	}
+}
+
+func mustRequest(t *testing.T, url string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
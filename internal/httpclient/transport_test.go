@@ -0,0 +1,147 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "example.com"}
+	tr := newTransport(TransportConfig{
+		MaxIdleConns:          200,
+		MaxIdleConnsPerHost:   50,
+		MaxConnsPerHost:       100,
+		IdleConnTimeout:       time.Minute,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	})
+
+	if tr.MaxIdleConns != 200 || tr.MaxIdleConnsPerHost != 50 || tr.MaxConnsPerHost != 100 {
+		t.Errorf("Expected pool limits 200/50/100, got %d/%d/%d", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+	if tr.IdleConnTimeout != time.Minute || tr.TLSHandshakeTimeout != 5*time.Second || tr.ResponseHeaderTimeout != 10*time.Second {
+		t.Errorf("Unexpected timeouts: %v %v %v", tr.IdleConnTimeout, tr.TLSHandshakeTimeout, tr.ResponseHeaderTimeout)
+	}
+	if tr.TLSClientConfig == tlsConfig || tr.TLSClientConfig.ServerName != "example.com" {
+		t.Error("Expected a copy of the TLS config")
+	}
+	if !tr.ForceAttemptHTTP2 {
+		t.Error("Expected HTTP/2 to be attempted")
+	}
+	if tr.Proxy == nil {
+		t.Error("Expected the default proxy from the environment")
+	}
+}
+
+func TestNewTransport_Defaults(t *testing.T) {
+	def := http.DefaultTransport.(*http.Transport)
+	tr := newTransport(TransportConfig{DisableHTTP2: true})
+
+	if tr.MaxIdleConns != def.MaxIdleConns || tr.IdleConnTimeout != def.IdleConnTimeout {
+		t.Errorf("Expected the default pool settings, got %d %v", tr.MaxIdleConns, tr.IdleConnTimeout)
+	}
+	if tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil || len(tr.TLSNextProto) != 0 {
+		t.Error("Expected HTTP/2 to be disabled")
+	}
+}
+
+func TestNewClient_Transport(t *testing.T) {
+	config := TransportConfig{MaxIdleConnsPerHost: 32}
+
+	own := &http.Client{Timeout: time.Second}
+	c := NewClient(ClientConfig{HttpClient: own, Transport: config})
+	tr, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok || tr.MaxIdleConnsPerHost != 32 {
+		t.Fatalf("Expected a tuned transport, got %T", c.httpClient.Transport)
+	}
+	if own.Transport != nil || c.httpClient.Timeout != time.Second {
+		t.Error("Expected the given client to be copied, not modified")
+	}
+
+	custom := &http.Client{Transport: roundTripFunc(nil)}
+	if c := NewClient(ClientConfig{HttpClient: custom, Transport: config}); c.httpClient != custom {
+		t.Error("Expected a client with its own transport to be used as is")
+	}
+
+	if c := NewClient(ClientConfig{HttpClient: own}); c.httpClient != own {
+		t.Error("Expected the client used as is without a transport config")
+	}
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte("ok"))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := &http.Client{Transport: newTransport(TransportConfig{Proxy: proxyURL})}
+	resp, err := client.Get("http://query2.finance.yahoo.com/v8/finance/chart/INFY.NS")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if proxied != "http://query2.finance.yahoo.com/v8/finance/chart/INFY.NS" {
+		t.Errorf("Expected the request to go through the proxy, got %q", proxied)
+	}
+
+	socks, _ := url.Parse("socks5://127.0.0.1:1080")
+	req, _ := http.NewRequest("GET", "https://api.upstox.com", nil)
+	if got, _ := newTransport(TransportConfig{Proxy: socks}).Proxy(req); got.String() != socks.String() {
+		t.Errorf("Expected the SOCKS5 proxy, got %v", got)
+	}
+}
+
+func TestNewTransport_DialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var dialed string
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = addr
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	client := &http.Client{Transport: newTransport(TransportConfig{DialContext: dial})}
+	resp, err := client.Get("http://api.upstox.com/v2/login")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if dialed != "api.upstox.com:80" {
+		t.Errorf("Expected the custom dialer to be used, got %q", dialed)
+	}
+}
+
+func TestNewTransport_Resolver(t *testing.T) {
+	var lookups atomic.Int32
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			lookups.Add(1)
+			return nil, errors.New("no DNS here")
+		},
+	}
+	client := &http.Client{Transport: newTransport(TransportConfig{Resolver: resolver})}
+
+	if _, err := client.Get("http://gohlcv.invalid/"); err == nil {
+		t.Fatal("Expected the lookup to fail")
+	}
+	if lookups.Load() == 0 {
+		t.Error("Expected the custom resolver to be used")
+	}
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrQueueFull is returned by WaitPriority when the limiter's queue limit is
+// reached.
+var ErrQueueFull = errors.New("rate limit queue full")
+
+// Priority orders the requests waiting on a limiter.
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+const numPriorities = 3
+
+// passLimit is how many requests of higher priorities may be served while a
+// lower priority waits before its next request is served, so that a steady
+// stream of urgent requests slows background work down but never stops it.
+const passLimit = 4
+
+// index returns the queue of p, treating priorities out of range as the
+// nearest one in range.
+func (p Priority) index() int {
+	return int(min(max(p, PriorityLow), PriorityHigh) - PriorityLow)
+}
+
+// dispatch lets waiting requests through while the budget allows. r.mu must
+// be held.
+func (r *RateLimiter) dispatch(now time.Time) {
+	for r.queued > 0 && r.allowed(now) {
+		i := r.next()
+		ready := r.queue[i][0]
+		r.queue[i] = r.queue[i][1:]
+		r.queued--
+
+		r.passed[i] = 0
+		for lower := 0; lower < i; lower++ {
+			if len(r.queue[lower]) > 0 {
+				r.passed[lower]++
+			}
+		}
+
+		r.count()
+		close(ready)
+	}
+}
+
+// next returns the queue to serve next: the highest priority with requests
+// waiting, unless a lower one has been passed over passLimit times. r.mu must
+// be held and a request must be waiting.
+func (r *RateLimiter) next() int {
+	top := numPriorities - 1
+	for len(r.queue[top]) == 0 {
+		top--
+	}
+
+	for i := top - 1; i >= 0; i-- {
+		if len(r.queue[i]) > 0 && r.passed[i] >= passLimit {
+			return i
+		}
+	}
+	return top
+}
+
+// remove takes the request waiting on ready out of queue i, if it is still
+// there. r.mu must be held.
+func (r *RateLimiter) remove(i int, ready chan struct{}) {
+	for j, c := range r.queue[i] {
+		if c == ready {
+			r.queue[i] = append(r.queue[i][:j], r.queue[i][j+1:]...)
+			r.queued--
+			if len(r.queue[i]) == 0 {
+				r.passed[i] = 0
+			}
+			return
+		}
+	}
+}
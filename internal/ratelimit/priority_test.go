@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// enqueue adds a waiting request of priority p to rl without blocking.
+func enqueue(rl *RateLimiter, p Priority) chan struct{} {
+	ready := make(chan struct{})
+	rl.queue[p.index()] = append(rl.queue[p.index()], ready)
+	rl.queued++
+	return ready
+}
+
+func closed(c chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestRateLimiter_Dispatch_HighestPriorityFirst(t *testing.T) {
+	rl := NewRateLimiter(2, 100, 1000)
+	low := enqueue(rl, PriorityLow)
+	normal := enqueue(rl, PriorityNormal)
+	high := enqueue(rl, PriorityHigh)
+
+	rl.dispatch(time.Now().UTC())
+
+	if !closed(high) || !closed(normal) || closed(low) {
+		t.Errorf("Expected the high and normal requests through, got high %v normal %v low %v", closed(high), closed(normal), closed(low))
+	}
+	if rl.queued != 1 {
+		t.Errorf("Expected 1 request still queued, got %d", rl.queued)
+	}
+}
+
+func TestRateLimiter_Dispatch_Fairness(t *testing.T) {
+	rl := NewRateLimiter(1, 100, 1000)
+	low := enqueue(rl, PriorityLow)
+	for i := 0; i < passLimit+2; i++ {
+		enqueue(rl, PriorityHigh)
+	}
+
+	lowAt := -1
+	for step := 0; rl.queued > 0; step++ {
+		rl.secCount = 0
+		rl.dispatch(time.Now().UTC())
+		if lowAt < 0 && closed(low) {
+			lowAt = step
+		}
+	}
+
+	if lowAt != passLimit {
+		t.Errorf("Expected the low request served after %d high ones, got %d", passLimit, lowAt)
+	}
+}
+
+func TestRateLimiter_WaitPriority_QueueFull(t *testing.T) {
+	rl := NewRateLimiter(100, 1000, 10000)
+	rl.SetQueueLimit(1)
+	rl.PauseUntil(time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rl.WaitPriority(ctx, PriorityLow) }()
+
+	for queued := 0; queued == 0; {
+		time.Sleep(time.Millisecond)
+		rl.mu.Lock()
+		queued = rl.queued
+		rl.mu.Unlock()
+	}
+
+	if err := rl.WaitPriority(context.Background(), PriorityHigh); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if rl.queued != 0 {
+		t.Errorf("Expected the cancelled request removed from the queue, got %d queued", rl.queued)
+	}
+}
+
+func TestRateLimiter_WaitPriority_AfterPause(t *testing.T) {
+	rl := NewRateLimiter(100, 1000, 10000)
+	rl.PauseUntil(time.Now().Add(150 * time.Millisecond))
+
+	done := make(chan Priority, 2)
+	for _, p := range []Priority{PriorityLow, PriorityHigh} {
+		go func() {
+			if err := rl.WaitPriority(context.Background(), p); err == nil {
+				done <- p
+			}
+		}()
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected both requests through once the pause ends")
+		}
+	}
+}
+
+func TestPriority_Index(t *testing.T) {
+	if PriorityLow.index() != 0 || PriorityHigh.index() != 2 || Priority(7).index() != 2 || Priority(-7).index() != 0 {
+		t.Error("Expected priorities out of range clamped")
+	}
+}
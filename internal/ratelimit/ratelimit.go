@@ -17,6 +17,43 @@ type RateLimiter struct {
 	requestsPerSecond int
 	requestsPerMinute int
 	requestsPerHour   int
+	pausedUntil       time.Time
+
+	// queue holds the requests waiting for budget, by priority.
+	queue    [numPriorities][]chan struct{}
+	queued   int
+	maxQueue int
+	// passed counts, for each priority, the requests of a higher priority
+	// served since its last one while it had requests waiting.
+	passed [numPriorities]int
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   = make(map[sharedKey]*RateLimiter)
+)
+
+type sharedKey struct {
+	name                                                  string
+	requestsPerSecond, requestsPerMinute, requestsPerHour int
+}
+
+// Shared returns the process-wide limiter named name with the given limits,
+// creating it on first use. Every caller asking for the same name and limits
+// draws from one budget, however many clients it builds.
+func Shared(name string, requestsPerSecond, requestsPerMinute, requestsPerHour int) *RateLimiter {
+	key := sharedKey{name, requestsPerSecond, requestsPerMinute, requestsPerHour}
+
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	if r, ok := shared[key]; ok {
+		return r
+	}
+
+	r := NewRateLimiter(requestsPerSecond, requestsPerMinute, requestsPerHour)
+	shared[key] = r
+	return r
 }
 
 func NewRateLimiter(requestsPerSecond, requestsPerMinute, requestsPerHour int) *RateLimiter {
@@ -31,25 +68,98 @@ func NewRateLimiter(requestsPerSecond, requestsPerMinute, requestsPerHour int) *
 	}
 }
 
+// Wait is WaitPriority with PriorityNormal.
 func (r *RateLimiter) Wait(ctx context.Context) error {
-	for {
-		if err := ctx.Err(); err != nil {
-			return err
-		}
+	return r.WaitPriority(ctx, PriorityNormal)
+}
 
-		if r.canProceed() {
-			r.increment()
-			return nil
-		}
+// WaitPriority blocks until the limiter lets a request of priority p
+// through. Waiting requests get the budget highest priority first and, within
+// a priority, in the order they arrived. It fails with ErrQueueFull if the
+// queue limit is reached.
+func (r *RateLimiter) WaitPriority(ctx context.Context, p Priority) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
+	r.mu.Lock()
+	now := time.Now().UTC()
+	r.resetIfNeeded(now)
+	if r.queued == 0 && r.allowed(now) {
+		r.count()
+		r.mu.Unlock()
+		return nil
+	}
+	if r.maxQueue > 0 && r.queued >= r.maxQueue {
+		r.mu.Unlock()
+		return ErrQueueFull
+	}
+
+	ready := make(chan struct{})
+	r.queue[p.index()] = append(r.queue[p.index()], ready)
+	r.queued++
+	r.dispatch(now)
+	r.mu.Unlock()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
 		select {
-		case <-time.After(100 * time.Millisecond):
+		case <-ready:
+			return nil
 		case <-ctx.Done():
+			r.mu.Lock()
+			r.remove(p.index(), ready)
+			r.mu.Unlock()
 			return ctx.Err()
+		case <-ticker.C:
+			r.mu.Lock()
+			now := time.Now().UTC()
+			r.resetIfNeeded(now)
+			r.dispatch(now)
+			r.mu.Unlock()
 		}
 	}
 }
 
+// SetQueueLimit caps how many requests may wait at once; more fail with
+// ErrQueueFull. Zero, the default, means no limit.
+func (r *RateLimiter) SetQueueLimit(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.maxQueue = n
+}
+
+// PauseUntil holds back every request until t, as when a server reports that
+// its quota is used up until then. An earlier pause is never shortened.
+func (r *RateLimiter) PauseUntil(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if t.After(r.pausedUntil) {
+		r.pausedUntil = t
+	}
+}
+
+// Remaining returns how many more requests the limiter lets through in the
+// current second, minute and hour. All three are zero while it is paused.
+func (r *RateLimiter) Remaining() (perSecond, perMinute, perHour int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	r.resetIfNeeded(now)
+
+	if now.Before(r.pausedUntil) {
+		return 0, 0, 0
+	}
+	return max(r.requestsPerSecond-r.secCount, 0),
+		max(r.requestsPerMinute-r.minCount, 0),
+		max(r.requestsPerHour-r.hrCount, 0)
+}
+
 func (r *RateLimiter) canProceed() bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -57,7 +167,14 @@ func (r *RateLimiter) canProceed() bool {
 	now := time.Now().UTC()
 	r.resetIfNeeded(now)
 
-	return r.secCount < r.requestsPerSecond &&
+	return r.allowed(now)
+}
+
+// allowed reports whether the budget lets a request through at now. r.mu must
+// be held.
+func (r *RateLimiter) allowed(now time.Time) bool {
+	return !now.Before(r.pausedUntil) &&
+		r.secCount < r.requestsPerSecond &&
 		r.minCount < r.requestsPerMinute &&
 		r.hrCount < r.requestsPerHour
 }
@@ -83,6 +200,11 @@ func (r *RateLimiter) increment() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.count()
+}
+
+// count takes one request from the budget. r.mu must be held.
+func (r *RateLimiter) count() {
 	r.secCount++
 	r.minCount++
 	r.hrCount++
@@ -338,3 +338,61 @@ func TestRateLimiter_MinimalLimits(t *testing.T) {
 		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
 	}
 }
+
+func TestRateLimiter_PauseUntil(t *testing.T) {
+	rl := NewRateLimiter(10, 100, 1000)
+
+	rl.PauseUntil(time.Now().Add(time.Hour))
+	if rl.canProceed() {
+		t.Error("Expected canProceed to be false while paused")
+	}
+
+	rl.PauseUntil(time.Now())
+	if rl.canProceed() {
+		t.Error("Expected an earlier pause not to shorten the current one")
+	}
+}
+
+func TestRateLimiter_Remaining(t *testing.T) {
+	rl := NewRateLimiter(10, 100, 1000)
+	rl.increment()
+	rl.increment()
+
+	if s, m, h := rl.Remaining(); s != 8 || m != 98 || h != 998 {
+		t.Errorf("Expected 8/98/998 remaining, got %d/%d/%d", s, m, h)
+	}
+
+	rl.PauseUntil(time.Now().Add(time.Hour))
+	if s, m, h := rl.Remaining(); s != 0 || m != 0 || h != 0 {
+		t.Errorf("Expected nothing remaining while paused, got %d/%d/%d", s, m, h)
+	}
+}
+
+func TestRateLimiter_Wait_AfterPause(t *testing.T) {
+	rl := NewRateLimiter(10, 100, 1000)
+	rl.PauseUntil(time.Now().Add(150 * time.Millisecond))
+
+	start := time.Now()
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("Expected to wait out the pause, waited %v", elapsed)
+	}
+}
+
+func TestShared(t *testing.T) {
+	a := Shared("TestShared", 10, 100, 1000)
+	b := Shared("TestShared", 10, 100, 1000)
+	if a != b {
+		t.Error("Expected the same limiter for the same name and limits")
+	}
+
+	if c := Shared("TestShared", 1, 100, 1000); c == a {
+		t.Error("Expected a different limiter for different limits")
+	}
+	if d := Shared("TestShared-other", 10, 100, 1000); d == a {
+		t.Error("Expected a different limiter for a different name")
+	}
+}
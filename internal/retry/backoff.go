@@ -0,0 +1,102 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// BackoffStrategy decides how long to wait before retrying. Delay is given the
+// zero-based attempt that just failed, the configured base delay and the delay
+// used before it, zero on the first retry. The Retryer caps the result at its
+// max delay and then applies its Jitter.
+type BackoffStrategy interface {
+	Delay(attempt uint, base, prev time.Duration) time.Duration
+}
+
+// BackoffFunc adapts a function to a BackoffStrategy.
+type BackoffFunc func(attempt uint, base, prev time.Duration) time.Duration
+
+func (f BackoffFunc) Delay(attempt uint, base, prev time.Duration) time.Duration {
+	return f(attempt, base, prev)
+}
+
+// Exponential doubles the delay after every attempt: base, 2*base, 4*base...
+// It is the default.
+func Exponential() BackoffStrategy {
+	return BackoffFunc(func(attempt uint, base, _ time.Duration) time.Duration {
+		if attempt >= 62 || base > maxDuration>>attempt {
+			return maxDuration
+		}
+		return base << attempt
+	})
+}
+
+// Linear grows the delay by base after every attempt: base, 2*base, 3*base...
+func Linear() BackoffStrategy {
+	return BackoffFunc(func(attempt uint, base, _ time.Duration) time.Duration {
+		n := time.Duration(attempt) + 1
+		if base > maxDuration/n {
+			return maxDuration
+		}
+		return base * n
+	})
+}
+
+// Constant waits base between every attempt.
+func Constant() BackoffStrategy {
+	return BackoffFunc(func(_ uint, base, _ time.Duration) time.Duration {
+		return base
+	})
+}
+
+// Decorrelated picks each delay at random between base and three times the
+// previous one, as in AWS's "decorrelated jitter". It is random on its own, so
+// it is usually combined with NoJitter.
+func Decorrelated() BackoffStrategy {
+	return BackoffFunc(func(_ uint, base, prev time.Duration) time.Duration {
+		upper := max(prev, base)
+		if upper > maxDuration/3 {
+			upper = maxDuration
+		} else {
+			upper *= 3
+		}
+		return base + randDuration(upper-base)
+	})
+}
+
+// Jitter randomizes delays so that clients failing at the same moment don't
+// retry in lockstep.
+type Jitter int
+
+const (
+	// NoJitter waits exactly the delay of the strategy. It is the default.
+	NoJitter Jitter = iota
+	// FullJitter waits anywhere between zero and the delay.
+	FullJitter
+	// EqualJitter waits half the delay plus up to another half at random.
+	EqualJitter
+)
+
+func (j Jitter) apply(d time.Duration) time.Duration {
+	switch j {
+	case FullJitter:
+		return randDuration(d)
+	case EqualJitter:
+		return d/2 + randDuration(d-d/2)
+	default:
+		return d
+	}
+}
+
+const maxDuration = time.Duration(1<<63 - 1)
+
+// randDuration returns a random duration in [0, d].
+func randDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	if d == maxDuration {
+		return time.Duration(rand.Int64N(int64(d)))
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
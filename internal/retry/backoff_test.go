@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffStrategies(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	testCases := []struct {
+		name     string
+		strategy BackoffStrategy
+		expected []time.Duration
+	}{
+		{"Exponential", Exponential(), []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond}},
+		{"Linear", Linear(), []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 400 * time.Millisecond}},
+		{"Constant", Constant(), []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for attempt, expected := range tc.expected {
+				delay := tc.strategy.Delay(uint(attempt), base, 0)
+				if delay != expected {
+					t.Errorf("For attempt %d, expected delay %v, got %v", attempt, expected, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffStrategies_Overflow(t *testing.T) {
+	for _, s := range []BackoffStrategy{Exponential(), Linear(), Decorrelated()} {
+		if delay := s.Delay(200, time.Hour, maxDuration); delay <= 0 {
+			t.Errorf("Expected a positive delay on overflow, got %v", delay)
+		}
+	}
+}
+
+func TestDecorrelated_Bounds(t *testing.T) {
+	base := 100 * time.Millisecond
+	s := Decorrelated()
+
+	prev := time.Duration(0)
+	for attempt := range uint(50) {
+		upper := 3 * max(prev, base)
+		delay := s.Delay(attempt, base, prev)
+		if delay < base || delay > upper {
+			t.Fatalf("Expected delay in [%v, %v], got %v", base, upper, delay)
+		}
+		prev = delay
+	}
+}
+
+func TestJitter_Bounds(t *testing.T) {
+	d := 100 * time.Millisecond
+
+	testCases := []struct {
+		name   string
+		jitter Jitter
+		lower  time.Duration
+	}{
+		{"NoJitter", NoJitter, d},
+		{"FullJitter", FullJitter, 0},
+		{"EqualJitter", EqualJitter, d / 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for range 100 {
+				delay := tc.jitter.apply(d)
+				if delay < tc.lower || delay > d {
+					t.Fatalf("Expected delay in [%v, %v], got %v", tc.lower, d, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryer_CalculateBackoff_WithOptions(t *testing.T) {
+	retryer := NewRetryer(5, 100*time.Millisecond, 250*time.Millisecond,
+		WithBackoff(Linear()),
+		WithJitter(FullJitter),
+	)
+
+	for attempt := range uint(5) {
+		delay := retryer.calculateBackoff(attempt, 0)
+		if delay < 0 || delay > 250*time.Millisecond {
+			t.Errorf("For attempt %d, expected delay capped at 250ms, got %v", attempt, delay)
+		}
+	}
+}
+
+func TestNewRetryer_DefaultBackoff(t *testing.T) {
+	retryer := NewRetryer(3, 100*time.Millisecond, time.Second, WithBackoff(nil))
+
+	if delay := retryer.calculateBackoff(2, 0); delay != 400*time.Millisecond {
+		t.Errorf("Expected exponential delay 400ms, got %v", delay)
+	}
+}
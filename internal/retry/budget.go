@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget caps how many retries every Retryer sharing it may make within a
+// sliding window, so that a burst of failing requests doesn't multiply the
+// load on a struggling server by the attempts each of them may make. First
+// attempts are never held back.
+type Budget struct {
+	mu      sync.Mutex
+	window  time.Duration
+	retries []time.Time // of the last n retries, oldest first
+	next    int
+	now     func() time.Time
+}
+
+var (
+	budgetsMu sync.Mutex
+	budgets   = make(map[budgetKey]*Budget)
+)
+
+type budgetKey struct {
+	name   string
+	n      int
+	window time.Duration
+}
+
+// NewBudget returns a Budget of n retries per window.
+func NewBudget(n int, window time.Duration) *Budget {
+	return &Budget{
+		window:  window,
+		retries: make([]time.Time, max(n, 0)),
+		now:     time.Now,
+	}
+}
+
+// SharedBudget returns the process-wide budget named name of n retries per
+// window, creating it on first use.
+func SharedBudget(name string, n int, window time.Duration) *Budget {
+	key := budgetKey{name, n, window}
+
+	budgetsMu.Lock()
+	defer budgetsMu.Unlock()
+
+	if b, ok := budgets[key]; ok {
+		return b
+	}
+
+	b := NewBudget(n, window)
+	budgets[key] = b
+	return b
+}
+
+// Take reports whether a retry may be made now, counting it against the
+// budget if so.
+func (b *Budget) Take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.retries) == 0 {
+		return false
+	}
+
+	// The slot to reuse holds the oldest retry, which must have left the
+	// window.
+	now := b.now()
+	if oldest := b.retries[b.next]; !oldest.IsZero() && now.Sub(oldest) < b.window {
+		return false
+	}
+	b.retries[b.next] = now
+	b.next = (b.next + 1) % len(b.retries)
+	return true
+}
+
+// Remaining returns how many more retries the budget allows now.
+func (b *Budget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	n := 0
+	for _, t := range b.retries {
+		if t.IsZero() || now.Sub(t) >= b.window {
+			n++
+		}
+	}
+	return n
+}
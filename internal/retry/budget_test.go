@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBudget_Take(t *testing.T) {
+	now := time.Date(2024, 1, 2, 9, 15, 0, 0, time.UTC)
+	b := NewBudget(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	if !b.Take() || !b.Take() {
+		t.Fatal("Expected the first two retries to be allowed")
+	}
+	if b.Take() {
+		t.Error("Expected a third retry within the window to be refused")
+	}
+	if b.Remaining() != 0 {
+		t.Errorf("Expected 0 retries left, got %d", b.Remaining())
+	}
+
+	now = now.Add(time.Minute)
+	if b.Remaining() != 2 {
+		t.Errorf("Expected 2 retries left once the window has passed, got %d", b.Remaining())
+	}
+	if !b.Take() {
+		t.Error("Expected a retry to be allowed once the window has passed")
+	}
+}
+
+func TestBudget_Zero(t *testing.T) {
+	if NewBudget(0, time.Minute).Take() {
+		t.Error("Expected a zero budget to allow no retry")
+	}
+}
+
+func TestSharedBudget(t *testing.T) {
+	a := SharedBudget("test", 5, time.Minute)
+	if b := SharedBudget("test", 5, time.Minute); a != b {
+		t.Error("Expected the same budget for the same name and limits")
+	}
+	if b := SharedBudget("test", 10, time.Minute); a == b {
+		t.Error("Expected another budget for other limits")
+	}
+}
+
+func TestRetryer_Do_Budget(t *testing.T) {
+	budget := NewBudget(3, time.Minute)
+	retryer := NewRetryer(5, time.Millisecond, time.Millisecond, WithBudget(budget))
+	failing := errors.New("unavailable")
+
+	attempts := 0
+	for range 2 {
+		err := retryer.Do(context.Background(), func() (bool, error) {
+			attempts++
+			return true, failing
+		})
+		if err != failing {
+			t.Errorf("Expected the last error, got %v", err)
+		}
+	}
+
+	// The first request retries 3 times, after which neither retries.
+	if attempts != 5 {
+		t.Errorf("Expected 5 attempts across both requests, got %d", attempts)
+	}
+}
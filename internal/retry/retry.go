@@ -9,45 +9,131 @@ type Retryer struct {
 	maxRetries uint
 	baseDelay  time.Duration
 	maxDelay   time.Duration
+	backoff    BackoffStrategy
+	jitter     Jitter
+	budget     *Budget
+	onRetry    func(Attempt)
+	onGiveUp   func(Attempt)
 }
 
-func NewRetryer(maxRetries uint, baseDelay time.Duration, maxDelay time.Duration) *Retryer {
-	return &Retryer{
+// Attempt describes a failed attempt to the hooks of a Retryer.
+type Attempt struct {
+	// Number counts the attempts of a call from 1.
+	Number uint
+	// Delay is the wait before the next attempt, zero when giving up.
+	Delay time.Duration
+	// Err is the error the attempt failed with.
+	Err error
+}
+
+// Option configures a Retryer.
+type Option func(*Retryer)
+
+// WithBackoff sets the strategy deciding the delay between attempts. The
+// default, also used when s is nil, is Exponential.
+func WithBackoff(s BackoffStrategy) Option {
+	return func(r *Retryer) {
+		r.backoff = s
+	}
+}
+
+// WithJitter randomizes every delay with j. The default is NoJitter.
+func WithJitter(j Jitter) Option {
+	return func(r *Retryer) {
+		r.jitter = j
+	}
+}
+
+// WithBudget draws every retry from b, giving up once it is used up.
+func WithBudget(b *Budget) Option {
+	return func(r *Retryer) {
+		r.budget = b
+	}
+}
+
+// WithOnRetry calls fn before waiting for every retry.
+func WithOnRetry(fn func(Attempt)) Option {
+	return func(r *Retryer) {
+		r.onRetry = fn
+	}
+}
+
+// WithOnGiveUp calls fn when a failed attempt isn't retried because the
+// retries or the budget are used up.
+func WithOnGiveUp(fn func(Attempt)) Option {
+	return func(r *Retryer) {
+		r.onGiveUp = fn
+	}
+}
+
+func NewRetryer(maxRetries uint, baseDelay time.Duration, maxDelay time.Duration, opts ...Option) *Retryer {
+	r := &Retryer{
 		maxRetries: maxRetries,
 		baseDelay:  baseDelay,
 		maxDelay:   maxDelay,
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.backoff == nil {
+		r.backoff = Exponential()
+	}
+
+	return r
 }
 
 func (r *Retryer) Do(ctx context.Context, fn func() (shouldRetry bool, err error)) error {
-	var lastErr error
+	return r.DoWithDelay(ctx, func() (bool, time.Duration, error) {
+		shouldRetry, err := fn()
+		return shouldRetry, 0, err
+	})
+}
+
+// DoWithDelay is like Do, but an attempt can ask to wait at least after
+// before the next one, such as when a server says when to come back.
+func (r *Retryer) DoWithDelay(ctx context.Context, fn func() (shouldRetry bool, after time.Duration, err error)) error {
+	var (
+		lastErr error
+		prev    time.Duration
+	)
 
 	for attempt := range r.maxRetries + 1 {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		shouldRetry, err := fn()
+		shouldRetry, after, err := fn()
 		if !shouldRetry {
 			return err
 		}
 		lastErr = err
 
-		if attempt < r.maxRetries {
-			delay := r.calculateBackoff(attempt)
-			select {
-			case <-time.After(delay):
-			case <-ctx.Done():
-				return ctx.Err()
+		failed := Attempt{Number: attempt + 1, Err: err}
+		if attempt == r.maxRetries || (r.budget != nil && !r.budget.Take()) {
+			if r.onGiveUp != nil {
+				r.onGiveUp(failed)
 			}
+			return lastErr
 		}
 
+		prev = r.calculateBackoff(attempt, prev)
+		failed.Delay = max(prev, after)
+		if r.onRetry != nil {
+			r.onRetry(failed)
+		}
+		select {
+		case <-time.After(failed.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	return lastErr
 }
 
-func (r *Retryer) calculateBackoff(attempt uint) time.Duration {
-	delay := r.baseDelay * (1 << attempt)
-	return min(delay, r.maxDelay)
+func (r *Retryer) calculateBackoff(attempt uint, prev time.Duration) time.Duration {
+	delay := min(r.backoff.Delay(attempt, r.baseDelay, prev), r.maxDelay)
+	return r.jitter.apply(delay)
 }
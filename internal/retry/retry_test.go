@@ -196,7 +196,7 @@ func TestRetryer_CalculateBackoff(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			delay := retryer.calculateBackoff(tc.attempt)
+			delay := retryer.calculateBackoff(tc.attempt, 0)
 			if delay != tc.expected {
 				t.Errorf("For attempt %d, expected delay %v, got %v", tc.attempt, tc.expected, delay)
 			}
@@ -207,7 +207,7 @@ func TestRetryer_CalculateBackoff(t *testing.T) {
 func TestRetryer_CalculateBackoff_ZeroBaseDelay(t *testing.T) {
 	retryer := NewRetryer(3, 0, 1*time.Second)
 
-	delay := retryer.calculateBackoff(2)
+	delay := retryer.calculateBackoff(2, 0)
 	if delay != 0 {
 		t.Errorf("Expected 0 delay with zero base delay, got %v", delay)
 	}
@@ -237,3 +237,59 @@ func TestRetryer_Do_BackoffTiming(t *testing.T) {
 		t.Errorf("Expected elapsed time to be at least %v, got %v", minExpected, elapsed)
 	}
 }
+
+func TestRetryer_DoWithDelay_HonoursAfter(t *testing.T) {
+	retryer := NewRetryer(3, 10*time.Millisecond, 20*time.Millisecond)
+
+	start := time.Now()
+	attempts := 0
+
+	err := retryer.DoWithDelay(context.Background(), func() (bool, time.Duration, error) {
+		attempts++
+		if attempts < 2 {
+			return true, 150 * time.Millisecond, errors.New("rate limited")
+		}
+		return false, 0, nil
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("Expected to wait at least the requested delay, waited %v", elapsed)
+	}
+}
+
+func TestRetryer_Hooks(t *testing.T) {
+	var retries, giveUps []Attempt
+	retryer := NewRetryer(2, 10*time.Millisecond, 10*time.Millisecond,
+		WithOnRetry(func(a Attempt) { retries = append(retries, a) }),
+		WithOnGiveUp(func(a Attempt) { giveUps = append(giveUps, a) }),
+	)
+	failing := errors.New("unavailable")
+
+	retryer.Do(context.Background(), func() (bool, error) {
+		return true, failing
+	})
+
+	if len(retries) != 2 || retries[0].Number != 1 || retries[1].Number != 2 {
+		t.Fatalf("Expected retries after attempts 1 and 2, got %+v", retries)
+	}
+	if retries[0].Delay != 10*time.Millisecond || retries[0].Err != failing {
+		t.Errorf("Expected the delay and error of the attempt, got %+v", retries[0])
+	}
+	if len(giveUps) != 1 || giveUps[0].Number != 3 || giveUps[0].Delay != 0 || giveUps[0].Err != failing {
+		t.Errorf("Expected to give up after attempt 3, got %+v", giveUps)
+	}
+
+	retries, giveUps = nil, nil
+	retryer.Do(context.Background(), func() (bool, error) {
+		return false, failing
+	})
+	if len(retries) != 0 || len(giveUps) != 0 {
+		t.Errorf("Expected no hooks for an error not to retry, got %+v %+v", retries, giveUps)
+	}
+}
@@ -0,0 +1,51 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// FetchCorporateActions returns the dividends and splits of symbol between
+// start and end from the first provider in the chain that serves them. A zero
+// end means up to now.
+func (m *MarketData) FetchCorporateActions(ctx context.Context, symbol string, start, end time.Time, opts ...FetchOption) (types.CorporateActions, error) {
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+
+	symbol, err := m.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return types.CorporateActions{}, err
+	}
+
+	loc := m.timezone()
+	start = start.In(loc)
+	if !end.IsZero() {
+		end = end.In(loc)
+	}
+
+	var errs []error
+	for _, p := range m.providers {
+		cp, ok := p.(provider.CorporateActionsProvider)
+		if !ok {
+			continue
+		}
+
+		actions, err := cp.CorporateActions(ctx, m.symbols.resolve(symbol, p.Name()), m.exchange, start, end)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		return actions, nil
+	}
+
+	if len(errs) > 0 {
+		return types.CorporateActions{}, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+
+	return types.CorporateActions{}, fmt.Errorf("%w: no provider serves corporate actions", provider.ErrProviderUnavailable)
+}
@@ -0,0 +1,58 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type actionsProvider struct {
+	mockProvider
+	actions types.CorporateActions
+	err     error
+}
+
+func (a *actionsProvider) CorporateActions(ctx context.Context, symbol string, exchange types.Exchange, start, end time.Time) (types.CorporateActions, error) {
+	return a.actions, a.err
+}
+
+func TestMarketData_FetchCorporateActions(t *testing.T) {
+	failing := &actionsProvider{mockProvider: mockProvider{name: "failing"}, err: provider.ErrRateLimited}
+	serving := &actionsProvider{
+		mockProvider: mockProvider{name: "serving"},
+		actions:      types.CorporateActions{Dividends: []types.Dividend{{Symbol: "RELIANCE", Amount: 10}}},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}, failing, serving))
+
+	actions, err := md.FetchCorporateActions(context.Background(), "RELIANCE", time.Now().AddDate(-1, 0, 0), time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(actions.Dividends) != 1 || actions.Dividends[0].Amount != 10 {
+		t.Errorf("Expected dividend from serving provider, got %+v", actions)
+	}
+}
+
+func TestMarketData_FetchCorporateActions_NoProvider(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}))
+
+	_, err := md.FetchCorporateActions(context.Background(), "RELIANCE", time.Now().AddDate(-1, 0, 0), time.Time{})
+	if !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
+
+func TestMarketData_FetchCorporateActions_AllFail(t *testing.T) {
+	failing := &actionsProvider{mockProvider: mockProvider{name: "failing"}, err: provider.ErrRateLimited}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing))
+
+	_, err := md.FetchCorporateActions(context.Background(), "RELIANCE", time.Now().AddDate(-1, 0, 0), time.Time{})
+	if !errors.Is(err, provider.ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type adjustingProvider struct {
+	mockProvider
+	adjustedCalls int
+}
+
+func (a *adjustingProvider) ProvideAdjusted(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+	a.adjustedCalls++
+	return []types.OHLCV{{DateTime: start, Close: 50, AdjustmentFactor: 0.5}}, nil
+}
+
+func TestMarketData_Fetch_WithAdjusted(t *testing.T) {
+	raw := &mockProvider{
+		name: "raw",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			t.Error("Expected raw provider not to be asked for adjusted prices")
+			return nil, nil
+		},
+	}
+	adjusting := &adjustingProvider{mockProvider: mockProvider{name: "adjusting"}}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(raw, adjusting), WithAdjusted(true))
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if adjusting.adjustedCalls != 1 {
+		t.Errorf("Expected 1 adjusted call, got %d", adjusting.adjustedCalls)
+	}
+	if len(data) != 1 || data[0].AdjustmentFactor != 0.5 {
+		t.Errorf("Expected adjusted candle, got %+v", data)
+	}
+}
+
+func TestMarketData_Fetch_WithAdjusted_Unsupported(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "raw"}), WithAdjusted(true))
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if !errors.Is(err, provider.ErrAdjustedUnsupported) {
+		t.Errorf("Expected ErrAdjustedUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const defaultConcurrency = 4
+
+// BatchError reports the symbols that could not be fetched by FetchMany.
+type BatchError struct {
+	Errors map[string]error
+}
+
+func (e *BatchError) Error() string {
+	symbols := make([]string, 0, len(e.Errors))
+	for symbol := range e.Errors {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+
+	msgs := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", symbol, e.Errors[symbol]))
+	}
+
+	return fmt.Sprintf("failed to fetch %d symbol(s): %s", len(symbols), strings.Join(msgs, "; "))
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// FetchMany fetches symbols concurrently using a pool of workers sized by
+// WithConcurrency. Workers share the MarketData providers and so their rate
+// limiters. Data for every symbol that succeeded is returned even when others
+// fail, in which case the error is a *BatchError. With WithPartialResults, a
+// symbol fetched only in part has both its candles and its *PartialError.
+// opts apply to every symbol.
+func (m *MarketData) FetchMany(
+	ctx context.Context,
+	symbols []string,
+	interval types.Interval,
+	start, end time.Time,
+	opts ...FetchOption,
+) (map[string][]types.OHLCV, error) {
+	return m.fetchEach(symbols, func(symbol string) ([]types.OHLCV, error) {
+		return m.Fetch(ctx, symbol, interval, start, end, opts...)
+	})
+}
+
+// fetchEach calls fetch for every distinct symbol on a pool of workers sized
+// by WithConcurrency and collects the results as FetchMany returns them.
+func (m *MarketData) fetchEach(
+	symbols []string,
+	fetch func(symbol string) ([]types.OHLCV, error),
+) (map[string][]types.OHLCV, error) {
+	symbols = slices.Compact(slices.Sorted(slices.Values(symbols)))
+
+	jobs := make(chan string)
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string][]types.OHLCV, len(symbols))
+		errs    = make(map[string]error)
+	)
+
+	workers := min(max(m.concurrency, 1), len(symbols))
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range jobs {
+				data, err := fetch(symbol)
+
+				mu.Lock()
+				if err != nil {
+					errs[symbol] = err
+				}
+				if err == nil || data != nil {
+					results[symbol] = data
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, symbol := range symbols {
+		jobs <- symbol
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, &BatchError{Errors: errs}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,110 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestMarketData_FetchMany(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			if symbol == "BAD" {
+				return nil, errors.New("unknown symbol")
+			}
+			return []types.OHLCV{{Symbol: symbol}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithConcurrency(2))
+	symbols := []string{"RELIANCE", "INFY", "TCS", "BAD", "INFY"}
+
+	results, err := md.FetchMany(context.Background(), symbols, types.Interval1d, time.Now().Add(-48*time.Hour), time.Time{})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected *BatchError, got %v", err)
+	}
+	if len(batchErr.Errors) != 1 || batchErr.Errors["BAD"] == nil {
+		t.Errorf("Expected only BAD to fail, got %v", batchErr.Errors)
+	}
+	if !strings.Contains(err.Error(), "BAD: ") || !strings.Contains(err.Error(), "unknown symbol") {
+		t.Errorf("Expected error message to name the failed symbol, got %q", err.Error())
+	}
+
+	if len(results) != 3 {
+		t.Errorf("Expected 3 results, got %d", len(results))
+	}
+	for _, symbol := range []string{"RELIANCE", "INFY", "TCS"} {
+		if data := results[symbol]; len(data) != 1 || data[0].Symbol != symbol {
+			t.Errorf("Unexpected result for %s: %v", symbol, data)
+		}
+	}
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent fetches, got %d", peak.Load())
+	}
+}
+
+func TestMarketData_FetchMany_AllSucceed(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{{Symbol: symbol}}, nil
+		},
+	}))
+
+	results, err := md.FetchMany(context.Background(), []string{"A", "B"}, types.Interval1d, time.Now().Add(-48*time.Hour), time.Time{})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(results))
+	}
+}
+
+func TestMarketData_FetchMany_Empty(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "mock"}))
+
+	results, err := md.FetchMany(context.Background(), nil, types.Interval1d, time.Time{}, time.Time{})
+
+	if err != nil || len(results) != 0 {
+		t.Errorf("Expected empty result, got %v %v", results, err)
+	}
+}
+
+func TestMarketData_FetchMany_ContextCancelled(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, ctx.Err()
+		},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := md.FetchMany(ctx, []string{"A", "B"}, types.Interval1d, time.Now().Add(-48*time.Hour), time.Time{})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package marketdata
+
+import (
+	"context"
+	"time"
+)
+
+// providerTimeout returns how long a provider of the chain may take when left
+// providers, itself included, are still to be tried: at most the timeout of
+// WithProviderTimeout and, with WithDeadlineSplit, an even share of the time
+// left before ctx's deadline. Zero means no limit.
+func (m *MarketData) providerTimeout(ctx context.Context, left int) time.Duration {
+	timeout := m.providerTimeoutCap
+
+	if deadline, ok := ctx.Deadline(); ok && m.deadlineSplit && left > 1 {
+		share := max(time.Until(deadline)/time.Duration(left), time.Nanosecond)
+		if timeout <= 0 || share < timeout {
+			timeout = share
+		}
+	}
+
+	return timeout
+}
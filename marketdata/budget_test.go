@@ -0,0 +1,98 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func hangingProvider(name string) *mockProvider {
+	return &mockProvider{
+		name: name,
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+}
+
+func TestMarketData_Fetch_WithDeadlineSplit(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	fallback := &mockProvider{
+		name: "fallback",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return []types.OHLCV{{DateTime: day, Close: 2}}, nil
+		},
+	}
+
+	for _, split := range []bool{false, true} {
+		md := NewMarketData(types.ExchangeNSE, WithProviders(hangingProvider("hanging"), fallback), WithDeadlineSplit(split))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		data, err := md.Fetch(ctx, "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+		cancel()
+
+		if !split {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Expected the hanging provider to use up the deadline, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected the fallback to get its share of the deadline, got %v", err)
+		}
+		if len(data) != 1 || data[0].Close != 2 {
+			t.Errorf("Expected the fallback's candle, got %+v", data)
+		}
+	}
+}
+
+func TestMarketData_Fetch_WithProviderTimeout(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE,
+		WithProviders(hangingProvider("first"), hangingProvider("second")),
+		WithProviderTimeout(20*time.Millisecond),
+	)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	begin := time.Now()
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected both providers to time out, got %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("Expected each provider to be given up on after 20ms, took %v", elapsed)
+	}
+
+	for _, s := range md.ProviderStatus() {
+		if s.LastError == nil {
+			t.Errorf("Expected the timeout to count against %s", s.Name)
+		}
+	}
+}
+
+func TestMarketData_ProviderTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(), WithDeadlineSplit(true))
+	if d := md.providerTimeout(ctx, 3); d > 20*time.Second || d < 19*time.Second {
+		t.Errorf("Expected a third of the minute left, got %v", d)
+	}
+	if d := md.providerTimeout(ctx, 1); d != 0 {
+		t.Errorf("Expected the last provider to get all the time left, got %v", d)
+	}
+	if d := md.providerTimeout(context.Background(), 3); d != 0 {
+		t.Errorf("Expected no limit without a deadline, got %v", d)
+	}
+
+	md = NewMarketData(types.ExchangeNSE, WithProviders(), WithDeadlineSplit(true), WithProviderTimeout(5*time.Second))
+	if d := md.providerTimeout(ctx, 3); d != 5*time.Second {
+		t.Errorf("Expected the shorter provider timeout, got %v", d)
+	}
+}
@@ -0,0 +1,50 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// CalendarEvents returns the upcoming earnings announcement and dividend
+// dates of symbol, oldest first, from the first provider in the chain that
+// serves them.
+func (m *MarketData) CalendarEvents(ctx context.Context, symbol string, opts ...FetchOption) ([]types.CalendarEvent, error) {
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+
+	symbol, err := m.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, p := range m.providers {
+		cp, ok := p.(provider.CalendarEventsProvider)
+		if !ok {
+			continue
+		}
+
+		alias := m.symbols.resolve(symbol, p.Name())
+		events, err := cp.CalendarEvents(ctx, alias, m.exchange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if alias != symbol {
+			for i := range events {
+				events[i].Symbol = symbol
+			}
+		}
+		return events, nil
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+
+	return nil, fmt.Errorf("%w: no provider serves calendar events", provider.ErrProviderUnavailable)
+}
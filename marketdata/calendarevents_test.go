@@ -0,0 +1,47 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type calendarEventsProvider struct {
+	mockProvider
+	events []types.CalendarEvent
+	err    error
+}
+
+func (c *calendarEventsProvider) CalendarEvents(ctx context.Context, symbol string, exchange types.Exchange) ([]types.CalendarEvent, error) {
+	return c.events, c.err
+}
+
+func TestMarketData_CalendarEvents(t *testing.T) {
+	failing := &calendarEventsProvider{mockProvider: mockProvider{name: "failing"}, err: provider.ErrProviderUnavailable}
+	serving := &calendarEventsProvider{mockProvider: mockProvider{name: "serving"}, events: []types.CalendarEvent{
+		{Symbol: "RIL", Kind: types.EventEarnings},
+	}}
+
+	md := NewMarketData(types.ExchangeNSE,
+		WithProviders(&mockProvider{name: "candles-only"}, failing, serving),
+		WithSymbolMap(SymbolMap{"RELIANCE": {"serving": "RIL"}}))
+
+	events, err := md.CalendarEvents(context.Background(), "RELIANCE")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != types.EventEarnings || events[0].Symbol != "RELIANCE" {
+		t.Errorf("Expected a RELIANCE earnings event, got %+v", events)
+	}
+}
+
+func TestMarketData_CalendarEvents_NoProvider(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}))
+
+	if _, err := md.CalendarEvents(context.Background(), "RELIANCE"); !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
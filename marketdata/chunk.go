@@ -0,0 +1,186 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type window struct {
+	from time.Time
+	to   time.Time
+}
+
+// splitRange splits [start, end] into consecutive windows no longer than max.
+// Adjacent windows share their boundary; duplicates are removed when the
+// results are stitched together.
+func splitRange(start, end time.Time, max time.Duration) []window {
+	if max <= 0 || end.Sub(start) <= max {
+		return []window{{from: start, to: end}}
+	}
+
+	var windows []window
+	for from := start; from.Before(end); from = from.Add(max) {
+		windows = append(windows, window{from: from, to: minTime(from.Add(max), end)})
+	}
+
+	return windows
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// requestWindows returns the ranges p is asked for, one request each, to
+// cover [start, end].
+func requestWindows(p provider.OHLCVProvider, interval types.Interval, start, end time.Time) []window {
+	rl, ok := p.(provider.RangeLimiter)
+	if !ok {
+		return []window{{from: start, to: end}}
+	}
+
+	chunkEnd := end
+	if chunkEnd.IsZero() {
+		chunkEnd = time.Now().In(start.Location())
+	}
+	return splitRange(start, chunkEnd, rl.MaxRange(interval))
+}
+
+// provideChunked calls p once per window it can serve in a single request
+// and stitches the results. Any failed chunk fails the whole call so the next
+// provider in the chain gets a chance at the full range, unless
+// WithPartialResults is set: then the other chunks are still fetched and, if
+// any succeeded, returned with a *PartialError naming the failed windows.
+func (m *MarketData) provideChunked(
+	ctx context.Context,
+	p provider.OHLCVProvider,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) (_ []types.OHLCV, err error) {
+	windows := requestWindows(p, interval, start, end)
+	progress := m.tracker.attempt(len(windows))
+	defer func() { progress.finish(err) }()
+	if len(windows) == 1 {
+		data, err := m.call(ctx, p, symbol, interval, start, end)
+		progress.done()
+		return data, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]types.OHLCV, len(windows))
+	chunkErrs := make([]error, len(windows))
+	sem := make(chan struct{}, max(m.chunkConcurrency, 1))
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for i, w := range windows {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			data, err := m.call(ctx, p, symbol, interval, w.from, w.to)
+			progress.done()
+			if err != nil && m.partial {
+				chunkErrs[i] = err
+				return
+			}
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = data
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		data    []types.OHLCV
+		missing []Gap
+		errs    []error
+	)
+	for i, r := range results {
+		if err := chunkErrs[i]; err != nil {
+			missing = append(missing, Gap{From: windows[i].from, To: windows[i].to})
+			errs = append(errs, err)
+			continue
+		}
+		data = append(data, r...)
+	}
+
+	switch {
+	case len(errs) == len(windows):
+		return nil, errors.Join(errs...)
+	case len(errs) > 0:
+		return dedupeByTime(data), &PartialError{Missing: missing, Err: errors.Join(errs...)}
+	}
+
+	return dedupeByTime(data), nil
+}
+
+// call asks p for candles between start and end, adjusted for splits and
+// dividends if WithAdjusted is set. Identical calls made at the same time
+// share one request unless WithDeduplication(false) is set.
+func (m *MarketData) call(
+	ctx context.Context,
+	p provider.OHLCVProvider,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) ([]types.OHLCV, error) {
+	alias := m.symbols.resolve(symbol, p.Name())
+
+	var provide func() ([]types.OHLCV, error)
+	if !m.adjusted {
+		provide = func() ([]types.OHLCV, error) {
+			return p.Provide(ctx, alias, m.exchange, interval, start, end)
+		}
+	} else if ap, ok := p.(provider.AdjustedProvider); ok {
+		provide = func() ([]types.OHLCV, error) {
+			return ap.ProvideAdjusted(ctx, alias, m.exchange, interval, start, end)
+		}
+	} else {
+		return nil, provider.ErrAdjustedUnsupported
+	}
+
+	key := newFlightKey(p, alias, m.exchange, interval, start, end, m.adjusted)
+	data, err := m.flights.do(ctx, key, provide)
+
+	if alias != symbol {
+		for i := range data {
+			data[i].Symbol = symbol
+		}
+	}
+	return data, err
+}
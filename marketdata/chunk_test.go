@@ -0,0 +1,204 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type limitedProvider struct {
+	mockProvider
+	maxRange time.Duration
+}
+
+func (l *limitedProvider) MaxRange(interval types.Interval) time.Duration {
+	return l.maxRange
+}
+
+func TestSplitRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name    string
+		end     time.Time
+		max     time.Duration
+		windows int
+	}{
+		{"Uncapped", start.Add(100 * day), 0, 1},
+		{"WithinLimit", start.Add(5 * day), 10 * day, 1},
+		{"ExactMultiple", start.Add(30 * day), 10 * day, 3},
+		{"Remainder", start.Add(25 * day), 10 * day, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows := splitRange(start, tt.end, tt.max)
+
+			if len(windows) != tt.windows {
+				t.Fatalf("Expected %d windows, got %d", tt.windows, len(windows))
+			}
+			if !windows[0].from.Equal(start) {
+				t.Errorf("Expected first window to start at %v, got %v", start, windows[0].from)
+			}
+			if !windows[len(windows)-1].to.Equal(tt.end) {
+				t.Errorf("Expected last window to end at %v, got %v", tt.end, windows[len(windows)-1].to)
+			}
+			for i, w := range windows {
+				if tt.max > 0 && w.to.Sub(w.from) > tt.max {
+					t.Errorf("Window %d exceeds max: %v", i, w.to.Sub(w.from))
+				}
+				if i > 0 && !w.from.Equal(windows[i-1].to) {
+					t.Errorf("Window %d is not contiguous with the previous one", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMarketData_Fetch_ChunksLongRanges(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 25)
+
+	var (
+		mu       sync.Mutex
+		requests []window
+	)
+	p := &limitedProvider{
+		maxRange: 10 * 24 * time.Hour,
+		mockProvider: mockProvider{
+			name: "limited",
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+				mu.Lock()
+				requests = append(requests, window{from: from, to: to})
+				mu.Unlock()
+
+				var data []types.OHLCV
+				for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+					data = append(data, types.OHLCV{DateTime: d})
+				}
+				return data, nil
+			},
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p), WithChunkConcurrency(3))
+	ohlcvs, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, end)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("Expected 3 chunked requests, got %d", len(requests))
+	}
+	if len(ohlcvs) != 26 {
+		t.Fatalf("Expected 26 deduplicated candles, got %d", len(ohlcvs))
+	}
+	for i, c := range ohlcvs {
+		if !c.DateTime.Equal(start.AddDate(0, 0, i)) {
+			t.Errorf("Expected candle %d at %v, got %v", i, start.AddDate(0, 0, i), c.DateTime)
+		}
+	}
+}
+
+func TestMarketData_Fetch_ChunkProgress(t *testing.T) {
+	var calls int
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &limitedProvider{mockProvider: *countingProvider("limited", &calls), maxRange: 10 * 24 * time.Hour}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p), WithChunkConcurrency(3))
+
+	var (
+		mu       sync.Mutex
+		progress []int
+	)
+	fn := func(fetched, total int, symbol string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if total != 3 || symbol != "INFY" {
+			t.Errorf("Expected 3 windows of INFY, got %d of %s", total, symbol)
+		}
+		progress = append(progress, fetched)
+	}
+
+	if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 25), WithProgress(fn)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(progress) != 3 || progress[0] != 1 || progress[2] != 3 {
+		t.Errorf("Expected progress 1, 2, 3, got %v", progress)
+	}
+
+	progress = nil
+	if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 5)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(progress) != 0 {
+		t.Errorf("Expected no progress without the option, got %v", progress)
+	}
+}
+
+func TestMarketData_Fetch_ChunkFailureFallsBack(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 25)
+
+	failing := &limitedProvider{
+		maxRange: 10 * 24 * time.Hour,
+		mockProvider: mockProvider{
+			name: "failing",
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+				if from.After(start) {
+					return nil, errors.New("chunk failed")
+				}
+				return []types.OHLCV{{DateTime: from, Source: "failing"}}, nil
+			},
+		},
+	}
+	fallback := &mockProvider{
+		name: "fallback",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{{DateTime: from, Source: "fallback"}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing, fallback))
+	ohlcvs, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, end)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ohlcvs) != 1 || ohlcvs[0].Source != "fallback" {
+		t.Errorf("Expected fallback provider result, got %v", ohlcvs)
+	}
+}
+
+func TestMarketData_Fetch_ChunkFailureReturnsRootCause(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	rootCause := errors.New("root cause")
+
+	p := &limitedProvider{
+		maxRange: 24 * time.Hour,
+		mockProvider: mockProvider{
+			name: "failing",
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+				if from.Equal(start.AddDate(0, 0, 2)) {
+					return nil, rootCause
+				}
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p), WithChunkConcurrency(5))
+	_, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 5))
+
+	if !errors.Is(err, rootCause) {
+		t.Errorf("Expected root cause error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// flightKey identifies a provider request, so that identical requests made
+// at once can share one upstream call.
+type flightKey struct {
+	provider any
+	symbol   string
+	exchange types.Exchange
+	interval types.Interval
+	start    int64
+	end      int64
+	adjusted bool
+}
+
+func newFlightKey(p provider.OHLCVProvider, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time, adjusted bool) flightKey {
+	key := flightKey{provider: instanceOf(p), symbol: symbol, exchange: exchange, interval: interval, start: start.UnixNano(), adjusted: adjusted}
+	if !end.IsZero() {
+		key.end = end.UnixNano()
+	}
+	return key
+}
+
+// instanceOf returns a key identifying p itself rather than its name, which
+// two providers in a chain may share, such as two Kite accounts. Providers
+// that can't be compared, which only a type implemented on values holding a
+// slice, map or func can be, are keyed by name.
+func instanceOf(p provider.OHLCVProvider) any {
+	if !reflect.ValueOf(p).Comparable() {
+		return p.Name()
+	}
+	return p
+}
+
+type flight struct {
+	done chan struct{}
+	data []types.OHLCV
+	err  error
+}
+
+// flightGroup collapses identical provider requests in flight at the same
+// time into one. A nil group runs every request.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[flightKey]*flight
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[flightKey]*flight)}
+}
+
+// do runs fn, unless a request with the same key is already running, in
+// which case it waits for that one's result instead. Every caller gets its
+// own copy of the candles. A caller whose shared request was cancelled by the
+// context of the caller that started it, while its own context is still
+// live, runs the request again.
+func (g *flightGroup) do(ctx context.Context, key flightKey, fn func() ([]types.OHLCV, error)) ([]types.OHLCV, error) {
+	if g == nil {
+		return fn()
+	}
+
+	for {
+		g.mu.Lock()
+		f, ok := g.flights[key]
+		if !ok {
+			f = &flight{done: make(chan struct{})}
+			g.flights[key] = f
+			g.mu.Unlock()
+
+			g.run(key, f, fn)
+			return slices.Clone(f.data), f.err
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if isContextErr(f.err) && ctx.Err() == nil {
+			continue
+		}
+		return slices.Clone(f.data), f.err
+	}
+}
+
+// errFlightPanicked is what waiters get if the shared request panicked.
+var errFlightPanicked = errors.New("marketdata: shared provider request panicked")
+
+// run calls fn for f and releases f's waiters, even if fn panics.
+func (g *flightGroup) run(key flightKey, f *flight, fn func() ([]types.OHLCV, error)) {
+	f.err = errFlightPanicked
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+
+	f.data, f.err = fn()
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,157 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// blockingProvider counts its calls and holds each until release is closed.
+func blockingProvider(calls *atomic.Int32, release <-chan struct{}) *mockProvider {
+	return &mockProvider{
+		name: "blocking",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			calls.Add(1)
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return []types.OHLCV{{Symbol: symbol, DateTime: start, Close: 100}}, nil
+		},
+	}
+}
+
+func fetchConcurrently(md *MarketData, n int, start, end time.Time) ([][]types.OHLCV, []error) {
+	results := make([][]types.OHLCV, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = md.Fetch(context.Background(), "INFY", types.Interval1d, start, end)
+		}()
+	}
+	wg.Wait()
+	return results, errs
+}
+
+func TestMarketData_Fetch_Deduplicates(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	md := NewMarketData(types.ExchangeNSE, WithProviders(blockingProvider(&calls, release)))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	go func() {
+		for calls.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	results, errs := fetchConcurrently(md, 10, start, start.AddDate(0, 0, 1))
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", calls.Load())
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(results[i]) != 1 || results[i][0].Close != 100 {
+			t.Fatalf("Expected the shared candle, got %v", results[i])
+		}
+	}
+
+	results[0][0].Close = 1
+	if results[1][0].Close != 100 {
+		t.Error("Expected every caller to get its own copy of the candles")
+	}
+}
+
+func TestMarketData_Fetch_DeduplicationDisabled(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	close(release)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(blockingProvider(&calls, release)), WithDeduplication(false))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fetchConcurrently(md, 5, start, start.AddDate(0, 0, 1))
+
+	if calls.Load() != 5 {
+		t.Errorf("Expected 5 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestNewFlightKey_SameName(t *testing.T) {
+	a, b := &mockProvider{name: "kite"}, &mockProvider{name: "kite"}
+	start := time.Unix(0, 0)
+
+	if newFlightKey(a, "INFY", types.ExchangeNSE, types.Interval1d, start, time.Time{}, false) == newFlightKey(b, "INFY", types.ExchangeNSE, types.Interval1d, start, time.Time{}, false) {
+		t.Error("Expected providers sharing a name not to share requests")
+	}
+	if newFlightKey(a, "INFY", types.ExchangeNSE, types.Interval1d, start, time.Time{}, false) != newFlightKey(a, "INFY", types.ExchangeNSE, types.Interval1d, start, time.Time{}, false) {
+		t.Error("Expected the same provider to share requests")
+	}
+}
+
+func TestFlightGroup_Do_LeaderCancelled(t *testing.T) {
+	g := newFlightGroup()
+	key := newFlightKey(&mockProvider{name: "mock"}, "INFY", types.ExchangeNSE, types.Interval1d, time.Unix(0, 0), time.Time{}, false)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go g.do(leaderCtx, key, func() ([]types.OHLCV, error) {
+		close(started)
+		<-leaderCtx.Done()
+		return nil, leaderCtx.Err()
+	})
+	<-started
+
+	var calls int
+	done := make(chan error)
+	go func() {
+		_, err := g.do(context.Background(), key, func() ([]types.OHLCV, error) {
+			calls++
+			return []types.OHLCV{{Close: 1}}, nil
+		})
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Expected the waiter to run the request again, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 retried call, got %d", calls)
+	}
+}
+
+func TestFlightGroup_Do_WaiterCancelled(t *testing.T) {
+	g := newFlightGroup()
+	key := newFlightKey(&mockProvider{name: "mock"}, "INFY", types.ExchangeNSE, types.Interval1d, time.Unix(0, 0), time.Time{}, false)
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go g.do(context.Background(), key, func() ([]types.OHLCV, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.do(ctx, key, func() ([]types.OHLCV, error) { return nil, nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Depth returns a snapshot of the order book of symbol from the first
+// provider in the chain that serves market depth. BestBid and BestAsk of the
+// result give the top of the book; Bids and Asks hold every level reported.
+func (m *MarketData) Depth(ctx context.Context, symbol string, opts ...FetchOption) (types.Depth, error) {
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+
+	symbol, err := m.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return types.Depth{}, err
+	}
+
+	var errs []error
+	for _, p := range m.providers {
+		dp, ok := p.(provider.DepthProvider)
+		if !ok {
+			continue
+		}
+
+		alias := m.symbols.resolve(symbol, p.Name())
+		depth, err := dp.Depth(ctx, alias, m.exchange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if alias != symbol {
+			depth.Symbol = symbol
+		}
+		return depth, nil
+	}
+
+	if len(errs) > 0 {
+		return types.Depth{}, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+
+	return types.Depth{}, fmt.Errorf("%w: no provider serves market depth", provider.ErrProviderUnavailable)
+}
@@ -0,0 +1,47 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type depthProvider struct {
+	mockProvider
+	depth types.Depth
+	err   error
+}
+
+func (d *depthProvider) Depth(ctx context.Context, symbol string, exchange types.Exchange) (types.Depth, error) {
+	return d.depth, d.err
+}
+
+func TestMarketData_Depth(t *testing.T) {
+	failing := &depthProvider{mockProvider: mockProvider{name: "failing"}, err: provider.ErrProviderUnavailable}
+	serving := &depthProvider{mockProvider: mockProvider{name: "serving"}, depth: types.Depth{
+		Symbol: "RELIANCE",
+		Bids:   []types.DepthLevel{{Price: 1374.9, Quantity: 120}},
+		Asks:   []types.DepthLevel{{Price: 1375.1, Quantity: 80}},
+	}}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}, failing, serving))
+
+	d, err := md.Depth(context.Background(), "RELIANCE")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bid, ok := d.BestBid(); !ok || bid.Price != 1374.9 {
+		t.Errorf("Expected best bid 1374.9, got %+v", bid)
+	}
+}
+
+func TestMarketData_Depth_NoProvider(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}))
+
+	if _, err := md.Depth(context.Background(), "RELIANCE"); !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// earliestFloor is the earliest date EarliestAvailable considers.
+var earliestFloor = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// EarliestAvailable reports the time of the earliest candle of symbol at
+// interval that the provider chain can serve. It binary-searches history with
+// short probe requests, so it takes a dozen or so requests instead of fetching
+// decades of candles, and assumes that once data starts it doesn't stop. The
+// cache is bypassed. It fails with provider.ErrNoData if not even recent
+// candles exist.
+func (m *MarketData) EarliestAvailable(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	opts ...FetchOption,
+) (time.Time, error) {
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+	m = m.withFetchConfig(fetchConfig{noCache: true})
+	// Probes aren't the caller's fetch, so they report no progress.
+	m.tracker = nil
+
+	window := probeWindow(interval)
+	now := time.Now()
+
+	hi, ok, err := m.probe(ctx, symbol, interval, now.Add(-window), now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: no recent %s candles for %s", provider.ErrNoData, interval, symbol)
+	}
+
+	lo := earliestFloor
+	for hi.Sub(lo) > window {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		end := minTime(mid.Add(window), hi)
+
+		first, ok, err := m.probe(ctx, symbol, interval, mid, end)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			hi = first
+		} else {
+			lo = end
+		}
+	}
+
+	if lo.Before(hi) {
+		first, ok, err := m.probe(ctx, symbol, interval, lo, hi)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			hi = first
+		}
+	}
+
+	return hi.In(m.timezone()), nil
+}
+
+// probe returns the time of the first candle between start and end. ok is
+// false if there is none.
+func (m *MarketData) probe(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) (first time.Time, ok bool, err error) {
+	data, err := m.fetch(ctx, symbol, interval, start, end)
+	// Probes are never in the future, so an invalid range is one older than
+	// the providers keep.
+	if errors.Is(err, provider.ErrNoData) || errors.Is(err, provider.ErrInvalidRange) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("probing %s from %s: %w", symbol, start.Format(time.DateOnly), err)
+	}
+
+	for _, c := range data {
+		if !ok || c.DateTime.Before(first) {
+			first, ok = c.DateTime, true
+		}
+	}
+	return first, ok, nil
+}
+
+// probeWindow returns how long a range EarliestAvailable asks for at a time:
+// long enough that weekends and holidays can't leave a range with data empty.
+func probeWindow(interval types.Interval) time.Duration {
+	_, unit, _ := interval.Split()
+	switch {
+	case interval.IsIntraday():
+		return 5 * 24 * time.Hour
+	case unit == "d":
+		return 14 * 24 * time.Hour
+	case unit == "wk":
+		return 35 * 24 * time.Hour
+	case unit == "mo":
+		return 100 * 24 * time.Hour
+	default:
+		return 5 * 24 * time.Hour
+	}
+}
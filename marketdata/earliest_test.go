@@ -0,0 +1,71 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// listedProvider serves a daily candle for every day from listed on.
+func listedProvider(listed time.Time, calls *int) *mockProvider {
+	return &mockProvider{
+		name: "listed",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			*calls++
+			var data []types.OHLCV
+			for t := listed; t.Before(end); t = t.AddDate(0, 0, 1) {
+				if !t.Before(start) {
+					data = append(data, types.OHLCV{Symbol: symbol, DateTime: t, Freshness: types.FreshnessHistorical})
+				}
+			}
+			if len(data) == 0 {
+				return nil, provider.ErrNoData
+			}
+			return data, nil
+		},
+	}
+}
+
+func TestMarketData_EarliestAvailable(t *testing.T) {
+	listed := time.Date(2008, 6, 16, 0, 0, 0, 0, time.UTC)
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(listedProvider(listed, &calls)))
+
+	got, err := md.EarliestAvailable(context.Background(), "RELIANCE", types.Interval1d)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !got.Equal(listed) {
+		t.Errorf("Expected %v, got %v", listed, got)
+	}
+	if calls > 20 {
+		t.Errorf("Expected a binary search, got %d requests", calls)
+	}
+}
+
+func TestMarketData_EarliestAvailable_NoData(t *testing.T) {
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(listedProvider(time.Now().AddDate(1, 0, 0), &calls)))
+
+	if _, err := md.EarliestAvailable(context.Background(), "RELIANCE", types.Interval1d); !errors.Is(err, provider.ErrNoData) {
+		t.Errorf("Expected ErrNoData, got %v", err)
+	}
+}
+
+func TestMarketData_EarliestAvailable_ProviderError(t *testing.T) {
+	failing := &mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrRateLimited
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing))
+
+	if _, err := md.EarliestAvailable(context.Background(), "RELIANCE", types.Interval1d); !errors.Is(err, provider.ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package marketdata
+
+import (
+	"context"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// FetchOption changes the behavior of a single Fetch, FetchMany, FetchStream,
+// Quote or FetchCorporateActions call.
+type FetchOption interface {
+	applyFetch(c *fetchConfig)
+}
+
+// SharedOption is accepted both by NewMarketData, as the default for every
+// fetch, and by Fetch, for a single call.
+type SharedOption interface {
+	Option
+	FetchOption
+}
+
+type fetchConfig struct {
+	exchange  types.Exchange
+	providers []provider.OHLCVProvider
+	noCache   bool
+	timeout   time.Duration
+	adjusted  *bool
+	location  *time.Location
+	freshness types.DataFreshness
+	ranges    RangePolicy
+	progress  ProgressFunc
+	limit     int
+}
+
+type fetchOptionFunc func(*fetchConfig)
+
+func (f fetchOptionFunc) applyFetch(c *fetchConfig) {
+	f(c)
+}
+
+type sharedOption struct {
+	option func(*MarketData)
+	fetch  func(*fetchConfig)
+}
+
+func (o sharedOption) apply(m *MarketData) {
+	o.option(m)
+}
+
+func (o sharedOption) applyFetch(c *fetchConfig) {
+	o.fetch(c)
+}
+
+// WithExchange fetches from exchange instead of the one MarketData was
+// created for. The providers, and so their rate limits, are shared across
+// exchanges. Unless WithCalendar was given, the exchange's own calendar is
+// used.
+func WithExchange(exchange types.Exchange) FetchOption {
+	return fetchOptionFunc(func(c *fetchConfig) {
+		c.exchange = exchange
+	})
+}
+
+// WithProviderOverride fetches from providers, in the order given, instead of
+// the configured chain. The cache is bypassed since it doesn't record which
+// providers a result came from.
+func WithProviderOverride(providers ...provider.OHLCVProvider) FetchOption {
+	return fetchOptionFunc(func(c *fetchConfig) {
+		c.providers = append([]provider.OHLCVProvider{}, providers...)
+	})
+}
+
+// WithNoCache neither reads nor writes the cache.
+func WithNoCache() FetchOption {
+	return fetchOptionFunc(func(c *fetchConfig) {
+		c.noCache = true
+	})
+}
+
+// WithTimeout bounds the whole call, including every provider tried and
+// every retry.
+func WithTimeout(d time.Duration) FetchOption {
+	return fetchOptionFunc(func(c *fetchConfig) {
+		c.timeout = d
+	})
+}
+
+// WithLimit returns at most the n most recent candles of the range.
+func WithLimit(n int) FetchOption {
+	return fetchOptionFunc(func(c *fetchConfig) {
+		c.limit = n
+	})
+}
+
+// forCall applies opts and returns the MarketData and context a single call
+// should use, along with the settings only the caller can apply. cancel must
+// be called once the call is done.
+func (m *MarketData) forCall(ctx context.Context, opts []FetchOption) (*MarketData, context.Context, context.CancelFunc, fetchConfig) {
+	var c fetchConfig
+	for _, opt := range opts {
+		opt.applyFetch(&c)
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	return m.withFetchConfig(c), ctx, cancel, c
+}
+
+// withFetchConfig returns m, or a copy of m carrying the per-call settings of
+// c.
+func (m *MarketData) withFetchConfig(c fetchConfig) *MarketData {
+	if c.exchange == "" && c.providers == nil && !c.noCache && c.adjusted == nil && c.location == nil && c.freshness == "" &&
+		c.ranges == nil && c.progress == nil {
+		return m
+	}
+
+	cp := *m
+	if c.exchange != "" && c.exchange != m.exchange {
+		cp.exchange = c.exchange
+		if m.defaultCalendar {
+			cp.calendar = exchangeCalendar(c.exchange)
+		}
+	}
+	if c.providers != nil {
+		cp.providers = c.providers
+		cp.cache = nil
+	}
+	if c.noCache {
+		cp.cache = nil
+	}
+	if c.adjusted != nil {
+		cp.adjusted = *c.adjusted
+	}
+	if c.location != nil {
+		cp.location = c.location
+	}
+	if c.freshness != "" {
+		cp.minFreshness = c.freshness
+	}
+	if c.ranges != nil {
+		cp.rangePolicy = c.ranges
+	}
+	if c.progress != nil {
+		cp.progress = c.progress
+	}
+	return &cp
+}
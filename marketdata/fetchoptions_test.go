@@ -0,0 +1,197 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func countingProvider(name string, calls *int) *mockProvider {
+	return &mockProvider{
+		name: name,
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			*calls++
+			var data []types.OHLCV
+			for t := start; t.Before(end); t = t.AddDate(0, 0, 1) {
+				data = append(data, types.OHLCV{Symbol: symbol, DateTime: t, Source: name, Freshness: types.FreshnessHistorical})
+			}
+			return data, nil
+		},
+	}
+}
+
+func TestMarketData_Fetch_WithProviderOverride(t *testing.T) {
+	var defaultCalls, overrideCalls int
+	c := cache.NewLRU(10)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("default", &defaultCalls)), WithCache(c))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end, WithProviderOverride(countingProvider("override", &overrideCalls)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) == 0 || data[0].Source != "override" {
+		t.Errorf("Expected data from override, got %v", data)
+	}
+	if defaultCalls != 0 || overrideCalls != 1 {
+		t.Errorf("Expected only the override to be called, got default=%d override=%d", defaultCalls, overrideCalls)
+	}
+
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if defaultCalls != 1 {
+		t.Errorf("Expected the override result not to be cached, got %d default calls", defaultCalls)
+	}
+}
+
+func TestMarketData_Fetch_WithNoCache(t *testing.T) {
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("mock", &calls)), WithCache(cache.NewLRU(10)))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	for range 2 {
+		if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end, WithNoCache()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 provider calls, got %d", calls)
+	}
+
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected WithNoCache results not to be cached, got %d calls", calls)
+	}
+}
+
+func TestMarketData_Fetch_WithTimeout(t *testing.T) {
+	slow := &mockProvider{
+		name: "slow",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(slow))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1), WithTimeout(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestMarketData_Fetch_WithLimit(t *testing.T) {
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("mock", &calls)))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 5), WithLimit(2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(data))
+	}
+	if want := start.AddDate(0, 0, 4); !data[1].DateTime.Equal(want) {
+		t.Errorf("Expected the most recent candle at %v, got %v", want, data[1].DateTime)
+	}
+}
+
+func TestMarketData_Fetch_WithAdjustedPerCall(t *testing.T) {
+	adjusting := &adjustingProvider{mockProvider: mockProvider{name: "adjusting"}}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(adjusting))
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1), WithAdjusted(true))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if adjusting.adjustedCalls != 1 || len(data) != 1 || data[0].AdjustmentFactor != 0.5 {
+		t.Errorf("Expected adjusted data, got %v", data)
+	}
+
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if adjusting.adjustedCalls != 1 {
+		t.Errorf("Expected the per-call option not to stick, got %d adjusted calls", adjusting.adjustedCalls)
+	}
+}
+
+func TestMarketData_Fetch_WithTimezonePerCall(t *testing.T) {
+	var got *time.Location
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			got = start.Location()
+			return nil, nil
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1), WithTimezone(time.UTC)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != time.UTC {
+		t.Errorf("Expected times in UTC, got %v", got)
+	}
+}
+
+func TestMarketData_Fetch_WithExchange(t *testing.T) {
+	var exchanges []types.Exchange
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			exchanges = append(exchanges, exchange)
+			return []types.OHLCV{{Symbol: symbol, Exchange: exchange, DateTime: start, Freshness: types.FreshnessHistorical}}, nil
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithCache(cache.NewLRU(10)))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	bse, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end, WithExchange(types.ExchangeBSE))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	nse, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if bse[0].Exchange != types.ExchangeBSE || nse[0].Exchange != types.ExchangeNSE {
+		t.Errorf("Expected BSE then NSE data, got %s then %s", bse[0].Exchange, nse[0].Exchange)
+	}
+	if len(exchanges) != 2 {
+		t.Errorf("Expected each exchange to be cached separately, got calls for %v", exchanges)
+	}
+	if md.Exchange() != types.ExchangeNSE {
+		t.Errorf("Expected the per-call exchange not to stick, got %s", md.Exchange())
+	}
+}
+
+func TestMarketData_Quote_WithExchange(t *testing.T) {
+	qp := &quoteProvider{mockProvider: mockProvider{name: "quotes"}}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(qp))
+
+	if _, err := md.Quote(context.Background(), "RELIANCE", WithExchange(types.ExchangeBSE)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if qp.exchange != types.ExchangeBSE {
+		t.Errorf("Expected quote from BSE, got %s", qp.exchange)
+	}
+}
@@ -0,0 +1,48 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Fundamentals returns the key statistics of symbol, such as its market
+// capitalization, P/E ratio and 52-week range, from the first provider in the
+// chain that serves them.
+func (m *MarketData) Fundamentals(ctx context.Context, symbol string, opts ...FetchOption) (types.Fundamentals, error) {
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+
+	symbol, err := m.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return types.Fundamentals{}, err
+	}
+
+	var errs []error
+	for _, p := range m.providers {
+		fp, ok := p.(provider.FundamentalsProvider)
+		if !ok {
+			continue
+		}
+
+		alias := m.symbols.resolve(symbol, p.Name())
+		fundamentals, err := fp.Fundamentals(ctx, alias, m.exchange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if alias != symbol {
+			fundamentals.Symbol = symbol
+		}
+		return fundamentals, nil
+	}
+
+	if len(errs) > 0 {
+		return types.Fundamentals{}, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+
+	return types.Fundamentals{}, fmt.Errorf("%w: no provider serves fundamentals", provider.ErrProviderUnavailable)
+}
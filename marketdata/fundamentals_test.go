@@ -0,0 +1,43 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type fundamentalsProvider struct {
+	mockProvider
+	fundamentals types.Fundamentals
+	err          error
+}
+
+func (f *fundamentalsProvider) Fundamentals(ctx context.Context, symbol string, exchange types.Exchange) (types.Fundamentals, error) {
+	return f.fundamentals, f.err
+}
+
+func TestMarketData_Fundamentals(t *testing.T) {
+	failing := &fundamentalsProvider{mockProvider: mockProvider{name: "failing"}, err: provider.ErrProviderUnavailable}
+	serving := &fundamentalsProvider{mockProvider: mockProvider{name: "serving"}, fundamentals: types.Fundamentals{Symbol: "RELIANCE", TrailingPE: 27.4}}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}, failing, serving))
+
+	f, err := md.Fundamentals(context.Background(), "RELIANCE")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.TrailingPE != 27.4 {
+		t.Errorf("Expected trailing P/E 27.4, got %v", f.TrailingPE)
+	}
+}
+
+func TestMarketData_Fundamentals_NoProvider(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}))
+
+	if _, err := md.Fundamentals(context.Background(), "RELIANCE"); !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package marketdata
+
+import (
+	"context"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Gap is a window [From, To) in which candles were expected but missing.
+type Gap struct {
+	From time.Time
+	To   time.Time
+}
+
+// DetectGaps compares candles against the candles expected for interval
+// between start and end, one per session slot on every trading day of the
+// exchange calendar, and returns the missing windows. Ranges reaching into
+// the future are cut at the current time. Intervals longer than a day are
+// not checked.
+func (m *MarketData) DetectGaps(candles []types.OHLCV, interval types.Interval, start, end time.Time) []Gap {
+	step := interval.Duration()
+	if !interval.IsIntraday() && interval != types.Interval1d {
+		return nil
+	}
+
+	cal := m.calendarOrDefault()
+	if now := time.Now(); end.IsZero() || end.After(now) {
+		end = now
+	}
+
+	have := make(map[time.Time]bool, len(candles))
+	for _, c := range candles {
+		have[slotOf(c.DateTime, interval, step, cal)] = true
+	}
+
+	var (
+		gaps []Gap
+		open *Gap
+	)
+	for _, slot := range expectedSlots(interval, step, cal, start, end) {
+		if have[slot] {
+			open = nil
+			continue
+		}
+
+		if open != nil && open.To.Equal(slot) {
+			open.To = slotEnd(slot, interval, step)
+			continue
+		}
+
+		gaps = append(gaps, Gap{From: slot, To: slotEnd(slot, interval, step)})
+		open = &gaps[len(gaps)-1]
+	}
+
+	return gaps
+}
+
+// expectedSlots lists the start of every candle expected in [start, end).
+func expectedSlots(interval types.Interval, step time.Duration, cal *calendar.Calendar, start, end time.Time) []time.Time {
+	loc := cal.Location()
+	start, end = start.In(loc), end.In(loc)
+
+	var slots []time.Time
+	for day := startOfDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		open, close, ok := cal.SessionBounds(day)
+		if !ok {
+			continue
+		}
+
+		if interval == types.Interval1d {
+			slots = append(slots, day)
+			continue
+		}
+
+		for t := open; t.Before(close); t = t.Add(step) {
+			if !t.Before(start) && t.Before(end) {
+				slots = append(slots, t)
+			}
+		}
+	}
+
+	return slots
+}
+
+// slotOf maps a candle time onto the expected slot containing it. Daily
+// candles map to their date; intraday candles to the session-aligned slot, so
+// bars aligned to the hour still count for the slot they fall in.
+func slotOf(t time.Time, interval types.Interval, step time.Duration, cal *calendar.Calendar) time.Time {
+	t = t.In(cal.Location())
+	day := startOfDay(t)
+	if interval == types.Interval1d {
+		return day
+	}
+
+	open, _ := cal.SessionHours()
+	offset := max(t.Sub(day)-open, 0)
+	return day.Add(open + offset/step*step)
+}
+
+// slotEnd returns the end of the slot starting at slot. Session-end slots are
+// not clipped; a gap ends where the next expected candle would start.
+func slotEnd(slot time.Time, interval types.Interval, step time.Duration) time.Time {
+	if interval == types.Interval1d {
+		return slot.AddDate(0, 0, 1)
+	}
+	return slot.Add(step)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Backfill fills the gaps in candles by querying every provider in the chain
+// for each missing window, and returns the merged candles together with the
+// gaps no provider could fill. Provider errors only leave a gap unfilled.
+func (m *MarketData) Backfill(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	candles []types.OHLCV,
+	start, end time.Time,
+) ([]types.OHLCV, []Gap, error) {
+	return m.backfill(ctx, m.providers, symbol, interval, candles, start, end)
+}
+
+func (m *MarketData) backfill(
+	ctx context.Context,
+	providers []provider.OHLCVProvider,
+	symbol string,
+	interval types.Interval,
+	candles []types.OHLCV,
+	start, end time.Time,
+) ([]types.OHLCV, []Gap, error) {
+	gaps := m.DetectGaps(candles, interval, start, end)
+	if len(gaps) == 0 {
+		return candles, nil, nil
+	}
+
+	merged := append([]types.OHLCV{}, candles...)
+	var remaining []Gap
+
+	for _, gap := range gaps {
+		missing := []Gap{gap}
+
+		for _, p := range providers {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+
+			data, err := m.provide(ctx, p, symbol, interval, gap.From, gap.To)
+			if err != nil {
+				continue
+			}
+
+			for _, c := range data {
+				if !c.DateTime.Before(gap.From) && c.DateTime.Before(gap.To) {
+					merged = append(merged, c)
+				}
+			}
+
+			missing = m.DetectGaps(merged, interval, gap.From, gap.To)
+			if len(missing) == 0 {
+				break
+			}
+		}
+
+		remaining = append(remaining, missing...)
+	}
+
+	return dedupeByTime(merged), remaining, nil
+}
@@ -0,0 +1,111 @@
+package marketdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func minuteCandles(start time.Time, n int) []types.OHLCV {
+	var data []types.OHLCV
+	for i := range n {
+		data = append(data, types.OHLCV{DateTime: start.Add(time.Duration(i) * time.Minute), Close: 100, Volume: 1})
+	}
+	return data
+}
+
+func TestMarketData_DetectGaps_Intraday(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 9, 15, 0, 0, loc) // Tuesday
+	end := start.Add(10 * time.Minute)
+
+	candles := append(minuteCandles(start, 3), minuteCandles(start.Add(6*time.Minute), 4)...)
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "mock"}))
+	gaps := md.DetectGaps(candles, types.Interval1m, start, end)
+
+	if len(gaps) != 1 {
+		t.Fatalf("Expected 1 gap, got %d: %+v", len(gaps), gaps)
+	}
+	if !gaps[0].From.Equal(start.Add(3*time.Minute)) || !gaps[0].To.Equal(start.Add(6*time.Minute)) {
+		t.Errorf("Expected gap 09:18-09:21, got %v-%v", gaps[0].From, gaps[0].To)
+	}
+}
+
+func TestMarketData_DetectGaps_DailySkipsWeekends(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 5, 0, 0, 0, 0, loc) // Friday
+	end := time.Date(2024, 1, 10, 0, 0, 0, 0, loc)  // Wednesday
+
+	candles := []types.OHLCV{
+		{DateTime: time.Date(2024, 1, 5, 0, 0, 0, 0, loc)},
+		{DateTime: time.Date(2024, 1, 8, 9, 15, 0, 0, loc)},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "mock"}))
+	gaps := md.DetectGaps(candles, types.Interval1d, start, end)
+
+	if len(gaps) != 1 {
+		t.Fatalf("Expected 1 gap, got %d: %+v", len(gaps), gaps)
+	}
+	want := time.Date(2024, 1, 9, 0, 0, 0, 0, loc)
+	if !gaps[0].From.Equal(want) {
+		t.Errorf("Expected gap on %v, got %v", want, gaps[0].From)
+	}
+}
+
+func TestMarketData_Fetch_WithBackfill(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 9, 15, 0, 0, loc)
+	end := start.Add(10 * time.Minute)
+
+	primary := &mockProvider{
+		name: "primary",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+			return append(minuteCandles(start, 3), minuteCandles(start.Add(6*time.Minute), 4)...), nil
+		},
+	}
+
+	var backfillRange [2]time.Time
+	secondary := &mockProvider{
+		name: "secondary",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+			backfillRange = [2]time.Time{from, to}
+			return minuteCandles(start, 10), nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(primary, secondary), WithBackfill(true))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1m, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 10 {
+		t.Errorf("Expected 10 candles, got %d", len(data))
+	}
+	if !backfillRange[0].Equal(start.Add(3*time.Minute)) || !backfillRange[1].Equal(start.Add(6*time.Minute)) {
+		t.Errorf("Expected backfill of 09:18-09:21, got %v-%v", backfillRange[0], backfillRange[1])
+	}
+}
+
+func TestMarketData_Backfill_ReportsUnfillableGaps(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 9, 15, 0, 0, loc)
+	end := start.Add(5 * time.Minute)
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "empty"}))
+
+	data, gaps, err := md.Backfill(context.Background(), "RELIANCE", types.Interval1m, minuteCandles(start, 2), start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 2 {
+		t.Errorf("Expected 2 candles, got %d", len(data))
+	}
+	if len(gaps) != 1 || !gaps[0].From.Equal(start.Add(2*time.Minute)) || !gaps[0].To.Equal(end) {
+		t.Errorf("Expected one gap 09:17-09:20, got %+v", gaps)
+	}
+}
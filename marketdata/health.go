@@ -0,0 +1,209 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+)
+
+// healthWindow is how many of a provider's most recent requests its success
+// rate is computed over.
+const healthWindow = 50
+
+// CircuitState is the state of a provider's circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through. It is the state of every provider
+	// unless WithCircuitBreaker is given.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen skips the provider after too many failures in a row.
+	CircuitOpen
+	// CircuitHalfOpen lets requests through again once the cooldown is over;
+	// the next outcome closes or reopens the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// ProviderStatus describes the recent health of a provider in the chain.
+type ProviderStatus struct {
+	Name string
+	// Requests is how many requests SuccessRate is computed over, up to the
+	// last 50.
+	Requests int
+	// SuccessRate is the share of those requests that didn't fail. It is 1
+	// for a provider that hasn't been asked yet.
+	SuccessRate float64
+	LastError   error
+	LastErrorAt time.Time
+	Circuit     CircuitState
+	// RateLimit is the budget left in the current second, minute and hour,
+	// for providers implementing provider.RateLimitReporter; nil otherwise.
+	RateLimit *provider.RateLimits
+}
+
+// ProviderStatus reports the health of every provider in the chain, in chain
+// order, for surfacing data-source health on dashboards. Requests made through
+// Fetch and the calls built on it are counted; answers such as
+// provider.ErrNoData and cancellations by the caller don't count as failures.
+func (m *MarketData) ProviderStatus() []ProviderStatus {
+	statuses := make([]ProviderStatus, 0, len(m.providers))
+	for _, p := range m.providers {
+		s := m.health.status(p)
+		if r, ok := p.(provider.RateLimitReporter); ok {
+			if remaining, ok := r.RemainingRateLimit(); ok {
+				s.RateLimit = &remaining
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// healthTracker records the outcome of provider requests and runs their
+// circuit breakers. A nil tracker records nothing and lets every request
+// through.
+type healthTracker struct {
+	mu sync.Mutex
+	// providers is keyed by instanceOf, so that providers sharing a name
+	// have circuits of their own.
+	providers map[any]*providerHealth
+	// threshold is how many failures in a row open a circuit; zero disables
+	// circuit breaking.
+	threshold int
+	cooldown  time.Duration
+}
+
+type providerHealth struct {
+	outcomes    []bool
+	next        int
+	failures    int
+	lastErr     error
+	lastErrAt   time.Time
+	openedAt    time.Time
+	circuitOpen bool
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{providers: make(map[any]*providerHealth)}
+}
+
+func (h *healthTracker) get(p provider.OHLCVProvider) *providerHealth {
+	key := instanceOf(p)
+	ph, ok := h.providers[key]
+	if !ok {
+		ph = &providerHealth{}
+		h.providers[key] = ph
+	}
+	return ph
+}
+
+// record counts the outcome of a request to p.
+func (h *healthTracker) record(ctx context.Context, p provider.OHLCVProvider, err error) {
+	if h == nil || (err != nil && !isFailure(ctx, err)) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ph := h.get(p)
+	if len(ph.outcomes) < healthWindow {
+		ph.outcomes = append(ph.outcomes, err == nil)
+	} else {
+		ph.outcomes[ph.next] = err == nil
+		ph.next = (ph.next + 1) % healthWindow
+	}
+
+	if err == nil {
+		ph.failures = 0
+		ph.circuitOpen = false
+		return
+	}
+
+	now := time.Now()
+	ph.failures++
+	ph.lastErr, ph.lastErrAt = err, now
+	if h.threshold > 0 && (ph.failures >= h.threshold || ph.circuitOpen) {
+		ph.circuitOpen, ph.openedAt = true, now
+	}
+}
+
+// allowed returns the providers of chain whose circuit lets requests through.
+func (h *healthTracker) allowed(chain []provider.OHLCVProvider) []provider.OHLCVProvider {
+	if h == nil || h.threshold <= 0 {
+		return chain
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := make([]provider.OHLCVProvider, 0, len(chain))
+	for _, p := range chain {
+		if h.circuit(h.get(p)) != CircuitOpen {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (h *healthTracker) circuit(ph *providerHealth) CircuitState {
+	switch {
+	case !ph.circuitOpen:
+		return CircuitClosed
+	case time.Since(ph.openedAt) < h.cooldown:
+		return CircuitOpen
+	default:
+		return CircuitHalfOpen
+	}
+}
+
+func (h *healthTracker) status(p provider.OHLCVProvider) ProviderStatus {
+	s := ProviderStatus{Name: p.Name(), SuccessRate: 1}
+	if h == nil {
+		return s
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ph := h.get(p)
+	s.Requests = len(ph.outcomes)
+	if s.Requests > 0 {
+		var ok int
+		for _, o := range ph.outcomes {
+			if o {
+				ok++
+			}
+		}
+		s.SuccessRate = float64(ok) / float64(s.Requests)
+	}
+	s.LastError, s.LastErrorAt = ph.lastErr, ph.lastErrAt
+	s.Circuit = h.circuit(ph)
+	return s
+}
+
+// isFailure reports whether err says something about the provider's health
+// rather than about the request or the caller.
+func isFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return !errors.Is(err, provider.ErrNoData) &&
+		!errors.Is(err, provider.ErrSymbolNotFound) &&
+		!errors.Is(err, provider.ErrUnknownInterval) &&
+		!errors.Is(err, provider.ErrAdjustedUnsupported)
+}
@@ -0,0 +1,148 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestMarketData_ProviderStatus(t *testing.T) {
+	failing := &mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrRateLimited
+		},
+	}
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing, countingProvider("mock", &calls)))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for range 2 {
+		if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	statuses := md.ProviderStatus()
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 statuses, got %d", len(statuses))
+	}
+
+	bad, good := statuses[0], statuses[1]
+	if bad.Name != "failing" || bad.Requests != 2 || bad.SuccessRate != 0 {
+		t.Errorf("Expected 2 failed requests, got %+v", bad)
+	}
+	if !errors.Is(bad.LastError, provider.ErrRateLimited) || bad.LastErrorAt.IsZero() {
+		t.Errorf("Expected the last error to be recorded, got %v", bad.LastError)
+	}
+	if good.SuccessRate != 1 || good.LastError != nil || good.Circuit != CircuitClosed {
+		t.Errorf("Expected a healthy provider, got %+v", good)
+	}
+	if good.RateLimit != nil {
+		t.Errorf("Expected no rate limit for a mock provider, got %+v", good.RateLimit)
+	}
+}
+
+func TestMarketData_ProviderStatus_IgnoresNoData(t *testing.T) {
+	empty := &mockProvider{
+		name: "empty",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrNoData
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(empty))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1))
+
+	if s := md.ProviderStatus()[0]; s.Requests != 0 || s.LastError != nil {
+		t.Errorf("Expected ErrNoData not to count against the provider, got %+v", s)
+	}
+}
+
+func TestMarketData_WithCircuitBreaker(t *testing.T) {
+	var failingCalls, calls int
+	failing := &mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			failingCalls++
+			return nil, provider.ErrProviderUnavailable
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE,
+		WithProviders(failing, countingProvider("mock", &calls)),
+		WithCircuitBreaker(2, time.Hour),
+	)
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for range 4 {
+		if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if failingCalls != 2 {
+		t.Errorf("Expected the failing provider to be skipped after 2 failures, got %d calls", failingCalls)
+	}
+	if calls != 4 {
+		t.Errorf("Expected the next provider to serve every fetch, got %d calls", calls)
+	}
+	if s := md.ProviderStatus()[0]; s.Circuit != CircuitOpen {
+		t.Errorf("Expected an open circuit, got %v", s.Circuit)
+	}
+}
+
+func TestMarketData_WithCircuitBreaker_SameName(t *testing.T) {
+	failing := &mockProvider{
+		name: "kite",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrProviderUnavailable
+		},
+	}
+	var calls int
+	md := NewMarketData(types.ExchangeNSE,
+		WithProviders(failing, countingProvider("kite", &calls)),
+		WithCircuitBreaker(1, time.Hour),
+	)
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for range 2 {
+		if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+			t.Fatalf("Expected the second kite to serve, got %v", err)
+		}
+	}
+
+	statuses := md.ProviderStatus()
+	if statuses[0].Circuit != CircuitOpen || statuses[1].Circuit != CircuitClosed || statuses[1].Requests != 2 {
+		t.Errorf("Expected only the failing kite's circuit to open, got %+v", statuses)
+	}
+}
+
+func TestHealthTracker_HalfOpen(t *testing.T) {
+	h := newHealthTracker()
+	h.threshold, h.cooldown = 1, time.Millisecond
+	p := &mockProvider{name: "p"}
+
+	h.record(context.Background(), p, provider.ErrProviderUnavailable)
+	time.Sleep(2 * time.Millisecond)
+	if s := h.status(p); s.Circuit != CircuitHalfOpen {
+		t.Fatalf("Expected a half-open circuit after the cooldown, got %v", s.Circuit)
+	}
+
+	h.record(context.Background(), p, nil)
+	if s := h.status(p); s.Circuit != CircuitClosed {
+		t.Errorf("Expected a success to close the circuit, got %v", s.Circuit)
+	}
+}
+
+func TestMarketData_ProviderStatus_ZeroValue(t *testing.T) {
+	md := &MarketData{providers: []provider.OHLCVProvider{&mockProvider{name: "mock"}}}
+
+	if s := md.ProviderStatus(); len(s) != 1 || s[0].SuccessRate != 1 {
+		t.Errorf("Expected a fresh status, got %+v", s)
+	}
+}
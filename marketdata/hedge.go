@@ -0,0 +1,98 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type hedgeResult struct {
+	index int
+	data  []types.OHLCV
+	err   error
+}
+
+// fetchHedged tries chain in order like fetchFromProviders, but doesn't wait
+// for a slow provider: every hedgeDelay without a result, the next provider is
+// asked as well, or with WithRace every provider is asked at once. It returns
+// the result of the first provider with data, cancelling the others, together
+// with the errors of the providers that failed. If none has the whole range,
+// the largest partial result is returned, its err a *PartialError, and if none
+// has data the result's index is -1.
+func (m *MarketData) fetchHedged(
+	ctx context.Context,
+	chain []provider.OHLCVProvider,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) (hedgeResult, []error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, len(chain))
+	next, running := 0, 0
+	launch := func() {
+		i, p := next, chain[next]
+		next++
+		running++
+
+		go func() {
+			data, err := m.tracedProvide(ctx, p, i+1, m.providerTimeout(ctx, 1), symbol, interval, start, end)
+			if len(data) > 0 && m.validation != 0 {
+				data = ohlcv.Clean(data, interval, ohlcv.SessionOf(m.calendarOrDefault()), m.validation)
+			}
+			results <- hedgeResult{index: i, data: data, err: err}
+		}()
+	}
+
+	timer := time.NewTimer(m.hedgeDelay)
+	defer timer.Stop()
+
+	var errs []error
+	best := hedgeResult{index: -1}
+	launch()
+	for m.race && next < len(chain) {
+		launch()
+	}
+	for running > 0 {
+		select {
+		case <-timer.C:
+			if next < len(chain) {
+				m.log().Debug("hedging request", "provider", chain[next].Name(), "symbol", symbol)
+				launch()
+				timer.Reset(m.hedgeDelay)
+			}
+		case r := <-results:
+			running--
+			name := chain[r.index].Name()
+
+			switch {
+			case r.err != nil:
+				m.log().Warn("provider failed", "provider", name, "symbol", symbol, "error", r.err)
+				err := r.err
+				if partial := asPartial(err); partial != nil {
+					err = partial.Err
+					if len(r.data) > len(best.data) {
+						best = r
+					}
+				}
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			case len(r.data) == 0:
+				m.log().Debug("provider returned no data", "provider", name, "symbol", symbol)
+			default:
+				return r, errs
+			}
+
+			if running == 0 && next < len(chain) {
+				launch()
+				timer.Reset(m.hedgeDelay)
+			}
+		}
+	}
+
+	return best, errs
+}
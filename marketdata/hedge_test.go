@@ -0,0 +1,196 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestMarketData_Fetch_WithHedging(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	cancelled := make(chan struct{})
+	slow := &mockProvider{
+		name: "slow",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			select {
+			case <-ctx.Done():
+				close(cancelled)
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return []types.OHLCV{{DateTime: day, Close: 1}}, nil
+			}
+		},
+	}
+	fast := &mockProvider{
+		name: "fast",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{{DateTime: day, Close: 2}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(slow, fast), WithHedging(20*time.Millisecond))
+
+	begin := time.Now()
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("Expected the hedged provider to answer quickly, took %v", elapsed)
+	}
+	if len(data) != 1 || data[0].Close != 2 {
+		t.Errorf("Expected the fast provider's candle, got %+v", data)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected the slow provider to be cancelled")
+	}
+}
+
+func TestMarketData_Fetch_WithHedging_FirstAnswers(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var secondCalls atomic.Int32
+	first := &mockProvider{
+		name: "first",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{{DateTime: day, Close: 1}}, nil
+		},
+	}
+	second := &mockProvider{
+		name: "second",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			secondCalls.Add(1)
+			return []types.OHLCV{{DateTime: day, Close: 2}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(first, second), WithHedging(time.Second))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 1 || data[0].Close != 1 {
+		t.Errorf("Expected the first provider's candle, got %+v", data)
+	}
+	if n := secondCalls.Load(); n != 0 {
+		t.Errorf("Expected the second provider not to be asked, got %d calls", n)
+	}
+}
+
+func TestMarketData_Fetch_WithHedging_FailsOverImmediately(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	failing := &mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrProviderUnavailable
+		},
+	}
+	second := &mockProvider{
+		name: "second",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{{DateTime: day, Close: 2}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing, second), WithHedging(5*time.Second))
+
+	begin := time.Now()
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("Expected to fail over without waiting for the hedge delay, took %v", elapsed)
+	}
+	if len(data) != 1 || data[0].Close != 2 {
+		t.Errorf("Expected the second provider's candle, got %+v", data)
+	}
+}
+
+func TestMarketData_Fetch_WithHedging_AllFail(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	unavailable := &mockProvider{
+		name: "unavailable",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrProviderUnavailable
+		},
+	}
+	limited := &mockProvider{
+		name: "limited",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			time.Sleep(30 * time.Millisecond)
+			return nil, provider.ErrRateLimited
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(limited, unavailable), WithHedging(10*time.Millisecond))
+
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if !errors.Is(err, provider.ErrRateLimited) || !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected both provider errors, got %v", err)
+	}
+}
+
+func TestMarketData_Fetch_WithRace(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// Each provider waits for the other to have started, which only happens
+	// if they are asked at once.
+	firstStarted, secondStarted := make(chan struct{}), make(chan struct{})
+	cancelled := make(chan struct{})
+	first := &mockProvider{
+		name: "first",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			close(firstStarted)
+			select {
+			case <-secondStarted:
+			case <-time.After(time.Second):
+				return nil, errors.New("second provider not started")
+			}
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	}
+	second := &mockProvider{
+		name: "second",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			close(secondStarted)
+			select {
+			case <-firstStarted:
+			case <-time.After(time.Second):
+				return nil, errors.New("first provider not started")
+			}
+			return []types.OHLCV{{DateTime: day, Close: 2}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(first, second), WithRace(true))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 1 || data[0].Close != 2 {
+		t.Errorf("Expected the second provider's candle, got %+v", data)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected the losing provider to be cancelled")
+	}
+}
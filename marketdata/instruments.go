@@ -0,0 +1,35 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shahid-2020/gohlcv/provider/upstox"
+	"github.com/shahid-2020/gohlcv/provider/yahoo"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var defaultInstruments = sync.OnceValue(func() *upstox.InstrumentStore {
+	return upstox.NewInstrumentStore()
+})
+
+var defaultYahoo = sync.OnceValue(func() *yahoo.YahooProvider {
+	return yahoo.NewYahooProvider()
+})
+
+// SearchSymbols finds instruments on exchange whose trading symbol, company
+// name or ISIN matches query, best matches first. An empty exchange searches
+// every exchange. It is backed by the embedded Upstox instrument list; use
+// upstox.InstrumentStore.Search to search a refreshed one.
+func SearchSymbols(query string, exchange types.Exchange) []types.Instrument {
+	return defaultInstruments().Search(query, string(exchange))
+}
+
+// SearchYahoo finds instruments on exchange matching a fuzzy query such as a
+// company name with Yahoo's search, best matches first. An empty exchange
+// searches every exchange Yahoo serves. Unlike SearchSymbols it covers US
+// listings and recent listings missing from the instrument list, but only
+// fills in the symbol, name, exchange and instrument type.
+func SearchYahoo(ctx context.Context, query string, exchange types.Exchange) ([]types.Instrument, error) {
+	return defaultYahoo().Search(ctx, query, exchange)
+}
@@ -0,0 +1,23 @@
+package marketdata
+
+import (
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestSearchSymbols(t *testing.T) {
+	results := SearchSymbols("reliance", types.ExchangeNSE)
+
+	if len(results) == 0 {
+		t.Fatal("Expected matches, got none")
+	}
+	if results[0].Symbol != "RELIANCE" || results[0].Exchange != types.ExchangeNSE {
+		t.Errorf("Expected RELIANCE on NSE first, got %+v", results[0])
+	}
+	for _, r := range results {
+		if r.Exchange != types.ExchangeNSE {
+			t.Errorf("Expected only NSE instruments, got %+v", r)
+		}
+	}
+}
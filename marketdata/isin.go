@@ -0,0 +1,136 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// isinCache remembers the trading symbols ISINs resolved to, keyed by
+// exchange and ISIN.
+type isinCache struct {
+	mu      sync.Mutex
+	symbols map[string]string
+}
+
+func newISINCache() *isinCache {
+	return &isinCache{symbols: make(map[string]string)}
+}
+
+func (c *isinCache) get(exchange types.Exchange, isin string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	symbol, ok := c.symbols[string(exchange)+":"+isin]
+	return symbol, ok
+}
+
+func (c *isinCache) put(exchange types.Exchange, isin, symbol string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.symbols[string(exchange)+":"+isin] = symbol
+}
+
+// resolveSymbol returns the trading symbol of symbol on m's exchange if it is
+// an ISIN, and symbol itself otherwise. ISINs are looked up in the Upstox
+// instrument list first, then with every provider in the chain implementing
+// provider.SymbolResolver.
+func (m *MarketData) resolveSymbol(ctx context.Context, symbol string) (string, error) {
+	if !IsISIN(symbol) {
+		return symbol, nil
+	}
+	if resolved, ok := m.isins.get(m.exchange, symbol); ok {
+		return resolved, nil
+	}
+
+	if m.exchange == types.ExchangeNSE || m.exchange == types.ExchangeBSE {
+		store := m.upstoxStore
+		if store == nil {
+			store = defaultInstruments()
+		}
+		if inst, ok := store.LookupISIN(symbol, string(m.exchange)); ok {
+			m.isins.put(m.exchange, symbol, inst.Symbol)
+			return inst.Symbol, nil
+		}
+	}
+
+	var errs []error
+	for _, p := range m.providers {
+		sr, ok := p.(provider.SymbolResolver)
+		if !ok {
+			continue
+		}
+
+		resolved, err := sr.ResolveISIN(ctx, symbol, m.exchange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		m.isins.put(m.exchange, symbol, resolved)
+		return resolved, nil
+	}
+
+	err := fmt.Errorf("%w: ISIN %s on exchange %s", provider.ErrSymbolNotFound, symbol, m.exchange)
+	if len(errs) > 0 {
+		return "", fmt.Errorf("%w: %w", err, errors.Join(errs...))
+	}
+	return "", err
+}
+
+// IsISIN reports whether s is a valid ISIN: two letters, nine letters or
+// digits and a check digit matching the rest.
+func IsISIN(s string) bool {
+	if len(s) != 12 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case i < 2 && (c < 'A' || c > 'Z'):
+			return false
+		case i == 11 && (c < '0' || c > '9'):
+			return false
+		case (c < 'A' || c > 'Z') && (c < '0' || c > '9'):
+			return false
+		}
+	}
+
+	// Letters expand to two digits (A is 10) and the digits are checked with
+	// the Luhn algorithm.
+	var digits []int
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' {
+			v := int(c-'A') + 10
+			digits = append(digits, v/10, v%10)
+		} else {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
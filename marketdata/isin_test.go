@@ -0,0 +1,94 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// resolverProvider resolves ISINs to symbol, counting the lookups.
+type resolverProvider struct {
+	mockProvider
+	symbol string
+	err    error
+	calls  int
+}
+
+func (r *resolverProvider) ResolveISIN(ctx context.Context, isin string, exchange types.Exchange) (string, error) {
+	r.calls++
+	return r.symbol, r.err
+}
+
+func TestIsISIN(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"INE002A01018", true},
+		{"US0378331005", true},
+		{"US0846707026", true},
+		{"INE002A01019", false},
+		{"ine002a01018", false},
+		{"RELIANCE", false},
+		{"1NE002A01018", false},
+		{"INE002A0101X", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsISIN(tt.s); got != tt.want {
+			t.Errorf("Expected IsISIN(%q) = %v, got %v", tt.s, tt.want, got)
+		}
+	}
+}
+
+func TestMarketData_Fetch_ISIN(t *testing.T) {
+	var asked string
+	md := NewMarketData(types.ExchangeNSE, WithProviders(symbolProvider("mock", "RELIANCE", &asked)))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, types.ExchangeNSE.Location())
+	data, err := md.Fetch(context.Background(), "INE002A01018", types.Interval1d, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asked != "RELIANCE" {
+		t.Errorf("Expected ISIN resolved from the instrument list, got %s", asked)
+	}
+	if len(data) != 1 || data[0].Symbol != "RELIANCE" {
+		t.Errorf("Expected RELIANCE candles, got %+v", data)
+	}
+}
+
+func TestMarketData_Fetch_ISINFromResolver(t *testing.T) {
+	var asked string
+	candles := symbolProvider("mock", "AAPL", &asked)
+	resolver := &resolverProvider{mockProvider: mockProvider{name: "resolver"}, symbol: "AAPL"}
+	md := NewMarketData(types.ExchangeNASDAQ, WithProviders(resolver, candles))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, types.ExchangeNASDAQ.Location())
+	for range 2 {
+		if _, err := md.Fetch(context.Background(), "US0378331005", types.Interval1d, start, start.Add(24*time.Hour)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if asked != "AAPL" {
+		t.Errorf("Expected AAPL to be fetched, got %s", asked)
+	}
+	if resolver.calls != 1 {
+		t.Errorf("Expected the resolution to be cached, got %d lookups", resolver.calls)
+	}
+}
+
+func TestMarketData_Fetch_ISINNotFound(t *testing.T) {
+	resolver := &resolverProvider{mockProvider: mockProvider{name: "resolver"}, err: provider.ErrSymbolNotFound}
+	md := NewMarketData(types.ExchangeNASDAQ, WithProviders(resolver))
+
+	_, err := md.Fetch(context.Background(), "US0378331005", types.Interval1d, time.Time{}, time.Time{})
+	if !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package marketdata
+
+import (
+	"context"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// fetchLastAttempts bounds how many times FetchLast widens its range when the
+// first one returns fewer candles than asked for.
+const fetchLastAttempts = 4
+
+// FetchLast returns the n most recent candles of symbol up to now. The range
+// to fetch is worked out from the exchange calendar, so weekends and holidays
+// don't eat into n; if the providers still return fewer candles, say because
+// of halts, the range is doubled a few times before giving up and returning
+// what there is. opts apply as they do to Fetch.
+func (m *MarketData) FetchLast(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	n int,
+	opts ...FetchOption,
+) ([]types.OHLCV, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+
+	cal := m.calendarOrDefault()
+	end := time.Now()
+
+	var data []types.OHLCV
+	for attempt, span := 0, n; attempt < fetchLastAttempts; attempt, span = attempt+1, span*2 {
+		var err error
+		data, err = m.Fetch(ctx, symbol, interval, lookback(cal, interval, span, end), end, WithLimit(n))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) >= n {
+			break
+		}
+	}
+
+	return data, nil
+}
+
+// lookback returns the start of a range ending at end that holds at least n
+// candles of interval on cal. The day of end counts as partial, so one more
+// trading day than strictly needed is included.
+func lookback(cal *calendar.Calendar, interval types.Interval, n int, end time.Time) time.Time {
+	var days int
+	switch interval {
+	case types.Interval1wk:
+		return end.AddDate(0, 0, -7*(n+1))
+	case types.Interval1mo:
+		return end.AddDate(0, -(n + 1), 0)
+	case types.Interval3mo:
+		return end.AddDate(0, -3*(n+1), 0)
+	case types.Interval5d:
+		days = 5 * n
+	case types.Interval1d:
+		days = n
+	default:
+		step := interval.Duration()
+		if !interval.IsIntraday() {
+			days = n
+			break
+		}
+		open, close := cal.SessionHours()
+		perDay := max(int((close-open+step-1)/step), 1)
+		days = (n + perDay - 1) / perDay
+	}
+
+	start := end
+	for range days {
+		start = cal.PreviousTradingDay(start)
+	}
+	return start
+}
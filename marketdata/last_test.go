@@ -0,0 +1,80 @@
+package marketdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestLookback(t *testing.T) {
+	cal, _ := calendar.ForExchange(types.ExchangeNSE)
+	ist := types.ExchangeNSE.Location()
+	// Monday 29 Jan 2024, after a weekend and Republic Day on Friday.
+	end := time.Date(2024, 1, 29, 11, 0, 0, 0, ist)
+
+	tests := []struct {
+		name     string
+		interval types.Interval
+		n        int
+		want     time.Time
+	}{
+		{"daily skips holidays", types.Interval1d, 2, time.Date(2024, 1, 24, 0, 0, 0, 0, ist)},
+		{"intraday within a day", types.Interval1m, 300, time.Date(2024, 1, 25, 0, 0, 0, 0, ist)},
+		{"intraday across days", types.Interval1h, 10, time.Date(2024, 1, 24, 0, 0, 0, 0, ist)},
+		{"weekly", types.Interval1wk, 2, end.AddDate(0, 0, -21)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lookback(cal, tt.interval, tt.n, end); !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMarketData_FetchLast(t *testing.T) {
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("mock", &calls)))
+
+	data, err := md.FetchLast(context.Background(), "RELIANCE", types.Interval1d, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 3 {
+		t.Fatalf("Expected 3 candles, got %d", len(data))
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single fetch, got %d", calls)
+	}
+}
+
+func TestMarketData_FetchLast_WidensSparseRange(t *testing.T) {
+	var starts []time.Time
+	weekly := &mockProvider{
+		name: "weekly",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			starts = append(starts, start)
+			var data []types.OHLCV
+			for t := start; t.Before(end); t = t.AddDate(0, 0, 7) {
+				data = append(data, types.OHLCV{Symbol: symbol, DateTime: t, Freshness: types.FreshnessHistorical})
+			}
+			return data, nil
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(weekly))
+
+	data, err := md.FetchLast(context.Background(), "RELIANCE", types.Interval1d, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 5 {
+		t.Fatalf("Expected 5 candles, got %d", len(data))
+	}
+	if len(starts) < 2 || !starts[1].Before(starts[0]) {
+		t.Errorf("Expected the range to be widened, got starts %v", starts)
+	}
+}
@@ -0,0 +1,24 @@
+package marketdata
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardHandler drops every record. It backs the logger used when
+// WithLogger isn't given.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+var discardLogger = slog.New(discardHandler{})
+
+func (m *MarketData) log() *slog.Logger {
+	if m.logger != nil {
+		return m.logger
+	}
+	return discardLogger
+}
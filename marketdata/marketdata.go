@@ -2,57 +2,528 @@ package marketdata
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
 	"time"
 
-	"github.com/shahid-2020/gohlcv/internal/provider"
-	"github.com/shahid-2020/gohlcv/internal/provider/upstox"
-	"github.com/shahid-2020/gohlcv/internal/provider/yahoo"
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/provider/upstox"
+	"github.com/shahid-2020/gohlcv/provider/yahoo"
 	"github.com/shahid-2020/gohlcv/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	upstoxPriority = 100
+	yahooPriority  = 200
 )
 
 type MarketData struct {
-	exchange types.Exchange
-	upstox   provider.OHLCVProvider
-	yahoo    provider.OHLCVProvider
+	exchange         types.Exchange
+	providers        []provider.OHLCVProvider
+	cache            cache.Cache
+	concurrency      int
+	chunkConcurrency int
+	autoResample     bool
+	validation       ohlcv.Policy
+	autoBackfill     bool
+	calendar         *calendar.Calendar
+	routing          RoutingStrategy
+	httpClient       *http.Client
+	transport        *provider.TransportConfig
+	retryHooks       *provider.RetryHooks
+	conditionalCache int64
+	rateLimits       map[string]provider.RateLimits
+	location         *time.Location
+	logger           *slog.Logger
+	upstoxToken      string
+	upstoxStore      *upstox.InstrumentStore
+	adjusted         bool
+	tracerProvider   trace.TracerProvider
+	merge            bool
+	hedgeDelay       time.Duration
+	race             bool
+	partial          bool
+	minFreshness     types.DataFreshness
+	rangePolicy      RangePolicy
+	progress         ProgressFunc
+	// tracker counts the windows of the Fetch call being made, for progress.
+	tracker *fetchProgress
+	// providerTimeoutCap and deadlineSplit bound each provider's time, see
+	// providerTimeout.
+	providerTimeoutCap time.Duration
+	deadlineSplit      bool
+	precision          *provider.Precision
+	pipeline           ohlcv.Pipeline
+	extendedHours      bool
+	health             *healthTracker
+	symbols            SymbolMap
+	isins              *isinCache
+	flights            *flightGroup
+	revalidating       *revalidations
+	revalidateTimeout  time.Duration
+	// planning is set on the copy Plan works with, so that range policies
+	// don't send requests.
+	planning bool
+	// defaultCalendar is set when calendar was derived from exchange rather
+	// than given with WithCalendar, so WithExchange can swap it.
+	defaultCalendar bool
+}
+
+// NewMarketData creates a MarketData for exchange. Unless WithProviders is
+// given, the fallback chain is made of the built-in upstox and yahoo providers
+// together with every provider added through RegisterProvider, ordered by
+// priority.
+func NewMarketData(exchange types.Exchange, opts ...Option) *MarketData {
+	m := &MarketData{
+		exchange:     exchange,
+		concurrency:  defaultConcurrency,
+		health:       newHealthTracker(),
+		symbols:      DefaultSymbolMap,
+		isins:        newISINCache(),
+		flights:      newFlightGroup(),
+		revalidating: newRevalidations(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(m)
+	}
+
+	if m.providers == nil {
+		m.providers = m.defaultProviders()
+	}
+
+	if m.calendar == nil {
+		m.calendar = exchangeCalendar(exchange)
+		m.defaultCalendar = true
+	}
+
+	if m.routing == nil {
+		m.routing = PreferFreshness()
+	}
+
+	return m
+}
+
+// Exchange returns the exchange m fetches from.
+func (m *MarketData) Exchange() types.Exchange {
+	return m.exchange
 }
 
-func NewMarketData(exchange types.Exchange) *MarketData {
-	return &MarketData{
-		exchange: exchange,
-		upstox:   upstox.NewUpstoxProvider(),
-		yahoo:    yahoo.NewYahooProvider(),
+func (m *MarketData) defaultProviders() []provider.OHLCVProvider {
+	var (
+		upstoxOpts []upstox.Option
+		yahooOpts  []yahoo.Option
+	)
+	if m.httpClient != nil {
+		upstoxOpts = append(upstoxOpts, upstox.WithHTTPClient(m.httpClient))
+		yahooOpts = append(yahooOpts, yahoo.WithHTTPClient(m.httpClient))
 	}
+	if m.transport != nil {
+		upstoxOpts = append(upstoxOpts, upstox.WithTransport(*m.transport))
+		yahooOpts = append(yahooOpts, yahoo.WithTransport(*m.transport))
+	}
+	if m.retryHooks != nil {
+		upstoxOpts = append(upstoxOpts, upstox.WithRetryHooks(*m.retryHooks))
+		yahooOpts = append(yahooOpts, yahoo.WithRetryHooks(*m.retryHooks))
+	}
+	if m.conditionalCache > 0 {
+		upstoxOpts = append(upstoxOpts, upstox.WithConditionalCache(m.conditionalCache))
+		yahooOpts = append(yahooOpts, yahoo.WithConditionalCache(m.conditionalCache))
+	}
+	if m.upstoxStore != nil {
+		upstoxOpts = append(upstoxOpts, upstox.WithInstrumentStore(m.upstoxStore))
+	}
+	if m.upstoxToken != "" {
+		upstoxOpts = append(upstoxOpts, upstox.WithAccessToken(m.upstoxToken))
+	}
+	if limits, ok := m.rateLimits["upstox"]; ok {
+		upstoxOpts = append(upstoxOpts, upstox.WithRateLimits(limits))
+	}
+	if limits, ok := m.rateLimits["yahoo"]; ok {
+		yahooOpts = append(yahooOpts, yahoo.WithRateLimits(limits))
+	}
+	if m.tracerProvider != nil {
+		upstoxOpts = append(upstoxOpts, upstox.WithTracerProvider(m.tracerProvider))
+		yahooOpts = append(yahooOpts, yahoo.WithTracerProvider(m.tracerProvider))
+	}
+	if m.extendedHours {
+		yahooOpts = append(yahooOpts, yahoo.WithExtendedHours(true))
+	}
+	if m.precision != nil {
+		upstoxOpts = append(upstoxOpts, upstox.WithPrecision(*m.precision))
+		yahooOpts = append(yahooOpts, yahoo.WithPrecision(*m.precision))
+	}
+
+	entries := append(registered(),
+		registration{provider: upstox.NewUpstoxProvider(upstoxOpts...), priority: upstoxPriority},
+		registration{provider: yahoo.NewYahooProvider(yahooOpts...), priority: yahooPriority},
+	)
+
+	return sortByPriority(entries)
 }
 
+// Fetch returns the candles of symbol between start and end from the first
+// provider of the chain that has them. opts apply to this call only.
 func (m *MarketData) Fetch(
 	ctx context.Context,
 	symbol string,
 	interval types.Interval,
 	start, end time.Time,
+	opts ...FetchOption,
+) ([]types.OHLCV, error) {
+	if err := validateRequest(symbol, interval); err != nil {
+		return nil, err
+	}
+
+	m, ctx, cancel, cfg := m.forCall(ctx, opts)
+	defer cancel()
+
+	symbol, err := m.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.progress != nil {
+		cp := *m
+		cp.tracker = newFetchProgress(m.progress, symbol)
+		m = &cp
+	}
+
+	ctx, span := m.tracer().Start(ctx, "MarketData.Fetch", trace.WithAttributes(
+		attribute.String("gohlcv.symbol", symbol),
+		attribute.String("gohlcv.exchange", string(m.exchange)),
+		attribute.String("gohlcv.interval", string(interval)),
+	))
+	defer span.End()
+
+	data, err := m.fetch(ctx, symbol, interval, start, end)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if asPartial(err) == nil {
+			return nil, err
+		}
+	}
+
+	if cfg.limit > 0 && len(data) > cfg.limit {
+		data = data[len(data)-cfg.limit:]
+	}
+	data = inLocation(data, m.timezone())
+	data = m.pipeline.Transform(data)
+
+	span.SetAttributes(attribute.Int("gohlcv.candles", len(data)))
+	return data, err
+}
+
+func (m *MarketData) fetch(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
 ) ([]types.OHLCV, error) {
-	loc, _ := time.LoadLocation("Asia/Kolkata")
-	now := time.Now().In(loc)
+	start, end, empty, err := m.fetchRange(ctx, symbol, interval, start, end)
+	if err != nil || empty {
+		return nil, err
+	}
+
+	if len(m.providers) == 0 {
+		return nil, fmt.Errorf("%w: no providers configured", provider.ErrProviderUnavailable)
+	}
+
+	key := cache.Key{Symbol: symbol, Exchange: m.exchange, Interval: interval, From: start, To: end, Adjusted: m.adjusted}
+	if rc, ok := m.cache.(cache.RangeCache); ok && !end.IsZero() {
+		return m.fetchMissing(ctx, rc, key)
+	}
+
+	if m.cache != nil {
+		if data, ok := m.getCached(ctx, key); ok {
+			return data, nil
+		}
+	}
+
+	data, err := m.fetchFromProviders(ctx, symbol, interval, start, end)
+	if err != nil {
+		// Partial results aren't cached, so the next fetch tries again.
+		return data, err
+	}
+
+	if m.cache != nil {
+		m.cache.Set(key, data)
+	}
+
+	return data, nil
+}
+
+// fetchMissing serves key from rc, fetching only the sub-ranges rc doesn't
+// hold and caching each of them.
+func (m *MarketData) fetchMissing(ctx context.Context, rc cache.RangeCache, key cache.Key) ([]types.OHLCV, error) {
+	data, missing := rc.GetRange(key)
+
+	var partial *PartialError
+	for _, r := range missing {
+		fetched, err := m.fetchFromProviders(ctx, key.Symbol, key.Interval, r.From, r.To)
+		if p := asPartial(err); p != nil {
+			partial = joinPartial(partial, p)
+			data = append(data, fetched...)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rangeKey := key
+		rangeKey.From, rangeKey.To = r.From, r.To
+		rc.Set(rangeKey, fetched)
 
-	if start.IsZero() {
-		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		data = append(data, fetched...)
+	}
+
+	if partial != nil {
+		return dedupeByTime(data), partial
+	}
+	return dedupeByTime(data), nil
+}
+
+// route returns the providers a fetch tries, in order: the chain as ordered
+// by the routing strategy, less those not fresh enough or whose circuit is
+// open. With preview, a routing strategy that is a RoutePreviewer is only
+// previewed, leaving its state as it was.
+func (m *MarketData) route(symbol string, interval types.Interval, start, end time.Time, preview bool) ([]provider.OHLCVProvider, error) {
+	routing := m.routing
+	if routing == nil {
+		routing = PreferFreshness()
+	}
+
+	req := Request{
+		Symbol:       symbol,
+		Exchange:     m.exchange,
+		Interval:     interval,
+		Start:        start,
+		End:          end,
+		Live:         m.isLive(start),
+		MinFreshness: m.minFreshness,
+	}
+	var routed []provider.OHLCVProvider
+	if p, ok := routing.(RoutePreviewer); ok && preview {
+		routed = p.Preview(req, m.providers)
 	} else {
-		start = start.In(loc)
+		routed = routing.Route(req, m.providers)
+	}
+	if m.minFreshness != "" {
+		routed = slices.DeleteFunc(slices.Clone(routed), func(p provider.OHLCVProvider) bool {
+			return !isFreshEnough(p, m.minFreshness)
+		})
+		if len(routed) == 0 {
+			return nil, fmt.Errorf("%w: no provider serves %s data", provider.ErrProviderUnavailable, m.minFreshness)
+		}
+	}
+	chain := m.health.allowed(routed)
+	if len(chain) == 0 && len(routed) > 0 {
+		return nil, fmt.Errorf("%w: every provider's circuit is open", provider.ErrProviderUnavailable)
+	}
+	return chain, nil
+}
+
+// fetchFromProviders walks the provider chain, as ordered by the routing
+// strategy, and returns the first non-empty result, or with WithMerge the
+// merged results of every provider. If none succeeds, the errors of every
+// provider are joined so callers can match any of them with errors.Is.
+func (m *MarketData) fetchFromProviders(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) ([]types.OHLCV, error) {
+	defer m.tracker.partDone()
+
+	chain, err := m.route(symbol, interval, start, end, false)
+	if err != nil {
+		return nil, err
 	}
 
-	if !end.IsZero() {
-		end = end.In(loc)
+	if (m.hedgeDelay > 0 || m.race) && !m.merge && len(chain) > 0 {
+		r, errs := m.fetchHedged(ctx, chain, symbol, interval, start, end)
+		if r.index < 0 {
+			if len(errs) > 0 {
+				return nil, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+			}
+			return nil, nil
+		}
+		if p := asPartial(r.err); p != nil {
+			return r.data, &PartialError{Missing: p.Missing, Err: errors.Join(errs...)}
+		}
+		return m.backfillFrom(ctx, chain, r.index, symbol, interval, r.data, start, end)
 	}
 
-	if start.Year() == now.Year() &&
-		start.Month() == now.Month() &&
-		start.Day() == now.Day() {
-		return m.yahoo.Provide(ctx, symbol, m.exchange, interval, start, end)
+	var (
+		errs    []error
+		results [][]types.OHLCV
+		// best is the largest partial result, returned if no provider has
+		// the whole range.
+		best        []types.OHLCV
+		bestMissing []Gap
+	)
+	for i, p := range chain {
+		timeout := m.providerTimeout(ctx, len(chain)-i)
+		data, err := m.tracedProvide(ctx, p, i+1, timeout, symbol, interval, start, end)
+		if err != nil {
+			m.log().Warn("provider failed", "provider", p.Name(), "symbol", symbol, "error", err)
+			partial := asPartial(err)
+			if partial != nil {
+				err = partial.Err
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			if partial != nil && len(data) > len(best) {
+				best, bestMissing = data, partial.Missing
+			}
+			continue
+		}
+		if m.validation != 0 {
+			data = ohlcv.Clean(data, interval, ohlcv.SessionOf(m.calendarOrDefault()), m.validation)
+		}
+		if len(data) == 0 {
+			m.log().Debug("provider returned no data", "provider", p.Name(), "symbol", symbol)
+			continue
+		}
+
+		if m.merge {
+			results = append(results, withSource(data, p.Name()))
+			continue
+		}
+
+		return m.backfillFrom(ctx, chain, i, symbol, interval, data, start, end)
+	}
+
+	if len(results) > 0 {
+		return mergeCandles(results), nil
+	}
+
+	if len(best) > 0 {
+		if m.validation != 0 {
+			best = ohlcv.Clean(best, interval, ohlcv.SessionOf(m.calendarOrDefault()), m.validation)
+		}
+		return best, &PartialError{Missing: bestMissing, Err: errors.Join(errs...)}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+
+	return nil, nil
+}
+
+// backfillFrom fills the gaps of data, fetched from chain[i], from the rest of
+// the chain when WithBackfill is set.
+func (m *MarketData) backfillFrom(
+	ctx context.Context,
+	chain []provider.OHLCVProvider,
+	i int,
+	symbol string,
+	interval types.Interval,
+	data []types.OHLCV,
+	start, end time.Time,
+) ([]types.OHLCV, error) {
+	if !m.autoBackfill {
+		return data, nil
 	}
 
-	data, err := m.upstox.Provide(ctx, symbol, m.exchange, interval, start, end)
-	if err != nil || len(data) == 0 {
-		return m.yahoo.Provide(ctx, symbol, m.exchange, interval, start, end)
+	others := slices.Delete(slices.Clone(chain), i, i+1)
+	data, _, err := m.backfill(ctx, others, symbol, interval, data, start, end)
+	if err != nil {
+		return nil, err
 	}
 
 	return data, nil
 }
+
+// isLive reports whether a request starting at start asks for the current
+// session, which historical-only providers can't serve yet. Without a
+// calendar any request for today counts; with one, only today's session once
+// it has opened does, which for crypto's Continuous calendar is always.
+func (m *MarketData) isLive(start time.Time) bool {
+	now := time.Now().In(start.Location())
+	today := start.Year() == now.Year() &&
+		start.Month() == now.Month() &&
+		start.Day() == now.Day()
+
+	if !today || m.calendar == nil {
+		return today
+	}
+
+	open, _, ok := m.calendar.SessionBounds(now)
+	return ok && !now.Before(open)
+}
+
+// timezone returns the location requests and candles are normalized to: the
+// exchange's time zone unless WithTimezone was given.
+func (m *MarketData) timezone() *time.Location {
+	if m.location != nil {
+		return m.location
+	}
+	return m.exchange.Location()
+}
+
+func (m *MarketData) calendarOrDefault() *calendar.Calendar {
+	if m.calendar != nil {
+		return m.calendar
+	}
+	return exchangeCalendar(m.exchange)
+}
+
+// exchangeCalendar returns the calendar of exchange or, for exchanges the
+// calendar package doesn't know, one trading every weekday around the clock
+// in the exchange's time zone, with no holidays.
+func exchangeCalendar(exchange types.Exchange) *calendar.Calendar {
+	if c, err := calendar.ForExchange(exchange); err == nil {
+		return c
+	}
+	return calendar.New(exchange.Location(), 0, 24*time.Hour)
+}
+
+// inLocation returns a copy of ohlcvs with every DateTime in loc, whatever
+// zone the provider reported it in. The copy keeps cached slices untouched.
+func inLocation(ohlcvs []types.OHLCV, loc *time.Location) []types.OHLCV {
+	if ohlcvs == nil {
+		return nil
+	}
+
+	out := make([]types.OHLCV, len(ohlcvs))
+	for i, c := range ohlcvs {
+		c.DateTime = c.DateTime.In(loc)
+		out[i] = c
+	}
+	return out
+}
+
+// dedupeByTime sorts ohlcvs by time and keeps the first candle seen for each
+// timestamp.
+func dedupeByTime(ohlcvs []types.OHLCV) []types.OHLCV {
+	slices.SortStableFunc(ohlcvs, func(a, b types.OHLCV) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+
+	return slices.CompactFunc(ohlcvs, func(a, b types.OHLCV) bool {
+		return a.DateTime.Equal(b.DateTime)
+	})
+}
+
+func isHistoricalOnly(p provider.OHLCVProvider) bool {
+	fr, ok := p.(provider.FreshnessReporter)
+	return ok && fr.Freshness() == types.FreshnessHistorical
+}
+
+// isFreshEnough reports whether p serves data at least as fresh as min.
+// Providers that don't report their freshness never are.
+func isFreshEnough(p provider.OHLCVProvider, min types.DataFreshness) bool {
+	fr, ok := p.(provider.FreshnessReporter)
+	return ok && freshnessRank[fr.Freshness()] >= freshnessRank[min]
+}
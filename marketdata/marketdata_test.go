@@ -3,14 +3,21 @@ package marketdata
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
 	"github.com/shahid-2020/gohlcv/types"
 )
 
 type mockProvider struct {
 	name        string
+	freshness   types.DataFreshness
 	provideFunc func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error)
 }
 
@@ -18,6 +25,10 @@ func (m *mockProvider) Name() string {
 	return m.name
 }
 
+func (m *mockProvider) Freshness() types.DataFreshness {
+	return m.freshness
+}
+
 func (m *mockProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
 	if m.provideFunc != nil {
 		return m.provideFunc(ctx, symbol, exchange, interval, start, end)
@@ -46,18 +57,20 @@ func TestNewMarketData(t *testing.T) {
 				t.Errorf("Expected exchange %v, got %v", tt.exchange, md.exchange)
 			}
 
-			if md.upstox == nil {
-				t.Error("Expected upstox provider to be initialized")
+			if len(md.providers) != 2 {
+				t.Fatalf("Expected 2 providers, got %d", len(md.providers))
 			}
-			if md.yahoo == nil {
-				t.Error("Expected yahoo provider to be initialized")
+			if md.providers[0].Name() != "upstox" {
+				t.Errorf("Expected upstox to be tried first, got %s", md.providers[0].Name())
 			}
-
-			if md.upstox.Name() == "" {
-				t.Error("Expected upstox provider to have a name")
+			if md.providers[1].Name() != "yahoo" {
+				t.Errorf("Expected yahoo to be tried second, got %s", md.providers[1].Name())
 			}
-			if md.yahoo.Name() == "" {
-				t.Error("Expected yahoo provider to have a name")
+			// Unknown exchanges don't get the Indian calendar.
+			if cal, err := calendar.ForExchange(tt.exchange); err == nil && md.calendar != cal {
+				t.Errorf("Expected the %s calendar", tt.exchange)
+			} else if err != nil && md.calendar.Location() != time.UTC {
+				t.Errorf("Expected a calendar in UTC, got %s", md.calendar.Location())
 			}
 		})
 	}
@@ -87,7 +100,8 @@ func TestMarketData_Fetch_CurrentDay_UsesYahoo(t *testing.T) {
 	}
 
 	mockUpstox := &mockProvider{
-		name: "upstox",
+		name:      "upstox",
+		freshness: types.FreshnessHistorical,
 		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
 			t.Error("Upstox should not be called for current day")
 			return nil, nil
@@ -95,9 +109,8 @@ func TestMarketData_Fetch_CurrentDay_UsesYahoo(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockYahoo,
-		upstox:   mockUpstox,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockUpstox, mockYahoo},
 	}
 
 	ctx := context.Background()
@@ -119,7 +132,8 @@ func TestMarketData_Fetch_HistoricalDay_UsesUpstoxFirst(t *testing.T) {
 	yesterday := time.Now().In(loc).Add(-24 * time.Hour)
 
 	mockUpstox := &mockProvider{
-		name: "upstox",
+		name:      "upstox",
+		freshness: types.FreshnessHistorical,
 		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
 			return []types.OHLCV{
 				{
@@ -146,9 +160,8 @@ func TestMarketData_Fetch_HistoricalDay_UsesUpstoxFirst(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockYahoo,
-		upstox:   mockUpstox,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockUpstox, mockYahoo},
 	}
 
 	ctx := context.Background()
@@ -170,7 +183,8 @@ func TestMarketData_Fetch_UpstoxFails_FallsBackToYahoo(t *testing.T) {
 	yesterday := time.Now().In(loc).Add(-24 * time.Hour)
 
 	mockUpstox := &mockProvider{
-		name: "upstox",
+		name:      "upstox",
+		freshness: types.FreshnessHistorical,
 		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
 			return nil, errors.New("upstox api error")
 		},
@@ -196,9 +210,8 @@ func TestMarketData_Fetch_UpstoxFails_FallsBackToYahoo(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockYahoo,
-		upstox:   mockUpstox,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockUpstox, mockYahoo},
 	}
 
 	ctx := context.Background()
@@ -220,7 +233,8 @@ func TestMarketData_Fetch_UpstoxEmpty_FallsBackToYahoo(t *testing.T) {
 	yesterday := time.Now().In(loc).Add(-24 * time.Hour)
 
 	mockUpstox := &mockProvider{
-		name: "upstox",
+		name:      "upstox",
+		freshness: types.FreshnessHistorical,
 		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
 			return []types.OHLCV{}, nil
 		},
@@ -246,9 +260,8 @@ func TestMarketData_Fetch_UpstoxEmpty_FallsBackToYahoo(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockYahoo,
-		upstox:   mockUpstox,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockUpstox, mockYahoo},
 	}
 
 	ctx := context.Background()
@@ -297,9 +310,8 @@ func TestMarketData_Fetch_TimeZoneHandling(t *testing.T) {
 			}
 
 			md := &MarketData{
-				exchange: types.ExchangeNSE,
-				yahoo:    mockProvider,
-				upstox:   mockProvider,
+				exchange:  types.ExchangeNSE,
+				providers: []provider.OHLCVProvider{mockProvider},
 			}
 
 			ctx := context.Background()
@@ -331,9 +343,8 @@ func TestMarketData_Fetch_DefaultStartTime(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockProvider,
-		upstox:   mockProvider,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockProvider},
 	}
 
 	ctx := context.Background()
@@ -349,7 +360,8 @@ func TestMarketData_Fetch_AllProvidersFail(t *testing.T) {
 	yesterday := time.Now().In(loc).Add(-24 * time.Hour)
 
 	mockUpstox := &mockProvider{
-		name: "upstox",
+		name:      "upstox",
+		freshness: types.FreshnessHistorical,
 		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
 			return nil, errors.New("upstox failed")
 		},
@@ -363,9 +375,8 @@ func TestMarketData_Fetch_AllProvidersFail(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockYahoo,
-		upstox:   mockUpstox,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockUpstox, mockYahoo},
 	}
 
 	ctx := context.Background()
@@ -393,7 +404,8 @@ func TestMarketData_Fetch_ProviderNames(t *testing.T) {
 	}
 
 	mockUpstox := &mockProvider{
-		name: "mock-upstox",
+		name:      "mock-upstox",
+		freshness: types.FreshnessHistorical,
 		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
 			return []types.OHLCV{
 				{
@@ -405,16 +417,15 @@ func TestMarketData_Fetch_ProviderNames(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockYahoo,
-		upstox:   mockUpstox,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockUpstox, mockYahoo},
 	}
 
-	if md.yahoo.Name() != "mock-yahoo" {
-		t.Errorf("Expected yahoo name 'mock-yahoo', got %s", md.yahoo.Name())
+	if md.providers[0].Name() != "mock-upstox" {
+		t.Errorf("Expected first provider name 'mock-upstox', got %s", md.providers[0].Name())
 	}
-	if md.upstox.Name() != "mock-upstox" {
-		t.Errorf("Expected upstox name 'mock-upstox', got %s", md.upstox.Name())
+	if md.providers[1].Name() != "mock-yahoo" {
+		t.Errorf("Expected second provider name 'mock-yahoo', got %s", md.providers[1].Name())
 	}
 
 	ctx := context.Background()
@@ -445,9 +456,8 @@ func TestMarketData_Fetch_ContextCancellation(t *testing.T) {
 	}
 
 	md := &MarketData{
-		exchange: types.ExchangeNSE,
-		yahoo:    mockProvider,
-		upstox:   mockProvider,
+		exchange:  types.ExchangeNSE,
+		providers: []provider.OHLCVProvider{mockProvider},
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -459,3 +469,302 @@ func TestMarketData_Fetch_ContextCancellation(t *testing.T) {
 		t.Error("Expected error with cancelled context")
 	}
 }
+
+func TestMarketData_Fetch_NoProviders(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders())
+
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, time.Time{}, time.Time{})
+
+	if !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable when no providers are configured, got %v", err)
+	}
+}
+
+func TestMarketData_Fetch_IteratesChainInOrder(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	yesterday := time.Now().In(loc).Add(-24 * time.Hour)
+
+	var calls []string
+	newProvider := func(name string, data []types.OHLCV, err error) *mockProvider {
+		return &mockProvider{
+			name: name,
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+				calls = append(calls, name)
+				return data, err
+			},
+		}
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(
+		newProvider("first", nil, errors.New("first failed")),
+		newProvider("second", []types.OHLCV{}, nil),
+		newProvider("third", []types.OHLCV{{Source: "third"}}, nil),
+		newProvider("fourth", []types.OHLCV{{Source: "fourth"}}, nil),
+	))
+
+	ohlcvs, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, yesterday, time.Time{})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(ohlcvs) != 1 || ohlcvs[0].Source != "third" {
+		t.Errorf("Expected data from third provider, got %v", ohlcvs)
+	}
+	if want := []string{"first", "second", "third"}; !slices.Equal(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+func TestMarketData_Fetch_WithCache(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, loc)
+
+	calls := 0
+	mock := &mockProvider{
+		name: "upstox",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			calls++
+			return []types.OHLCV{{Symbol: symbol, Freshness: types.FreshnessHistorical}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithCache(cache.NewLRU(10)))
+	ctx := context.Background()
+
+	for range 3 {
+		ohlcvs, err := md.Fetch(ctx, "RELIANCE", types.Interval1d, day, day)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(ohlcvs) != 1 {
+			t.Fatalf("Expected 1 OHLCV record, got %d", len(ohlcvs))
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected provider to be called once, got %d", calls)
+	}
+
+	if _, err := md.Fetch(ctx, "INFY", types.Interval1d, day, day); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected a different symbol to miss the cache, got %d calls", calls)
+	}
+}
+
+type fakeRangeCache struct {
+	cached  []types.OHLCV
+	missing []cache.Range
+	sets    []cache.Key
+}
+
+func (f *fakeRangeCache) Get(key cache.Key) ([]types.OHLCV, bool) {
+	return nil, false
+}
+
+func (f *fakeRangeCache) Set(key cache.Key, ohlcvs []types.OHLCV) {
+	f.sets = append(f.sets, key)
+}
+
+func (f *fakeRangeCache) GetRange(key cache.Key) ([]types.OHLCV, []cache.Range) {
+	return f.cached, f.missing
+}
+
+func TestMarketData_Fetch_RangeCacheFetchesOnlyMissing(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	day := func(n int) time.Time { return base.AddDate(0, 0, n) }
+
+	rc := &fakeRangeCache{
+		cached: []types.OHLCV{{DateTime: day(2), Source: "cache"}, {DateTime: day(3), Source: "cache"}},
+		missing: []cache.Range{
+			{From: day(0), To: day(1)},
+			{From: day(4), To: day(5)},
+		},
+	}
+
+	var requested []cache.Range
+	mock := &mockProvider{
+		name: "upstox",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			requested = append(requested, cache.Range{From: start, To: end})
+			// Overlaps the cached day 3 to exercise deduplication.
+			return []types.OHLCV{{DateTime: start, Source: "upstox"}, {DateTime: end, Source: "upstox"}, {DateTime: day(3), Source: "upstox"}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithCache(rc))
+	ohlcvs, err := md.Fetch(context.Background(), "INFY", types.Interval1d, day(0), day(5))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(requested) != 2 || !requested[0].From.Equal(day(0)) || !requested[1].To.Equal(day(5)) {
+		t.Errorf("Expected only missing ranges to be requested, got %v", requested)
+	}
+	if len(rc.sets) != 2 {
+		t.Errorf("Expected each fetched range to be cached, got %d", len(rc.sets))
+	}
+	if len(ohlcvs) != 6 {
+		t.Fatalf("Expected 6 deduplicated candles, got %d", len(ohlcvs))
+	}
+	for i, c := range ohlcvs {
+		if !c.DateTime.Equal(day(i)) {
+			t.Errorf("Expected candle %d at %v, got %v", i, day(i), c.DateTime)
+		}
+	}
+	if ohlcvs[3].Source != "cache" {
+		t.Errorf("Expected cached candle to win on duplicate timestamp, got %s", ohlcvs[3].Source)
+	}
+}
+
+func TestMarketData_Fetch_RangeCacheProviderError(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+
+	rc := &fakeRangeCache{missing: []cache.Range{{From: from, To: from.AddDate(0, 0, 1)}}}
+	mock := &mockProvider{
+		name: "upstox",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, errors.New("boom")
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithCache(rc))
+	_, err := md.Fetch(context.Background(), "INFY", types.Interval1d, from, from.AddDate(0, 0, 1))
+
+	if err == nil {
+		t.Error("Expected provider error to be returned")
+	}
+	if len(rc.sets) != 0 {
+		t.Error("Expected nothing to be cached on error")
+	}
+}
+
+func TestMarketData_Fetch_ErrorsMatchEveryProvider(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	yesterday := time.Now().In(loc).Add(-24 * time.Hour)
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(
+		&mockProvider{
+			name: "first",
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+				return nil, &provider.ProviderError{Provider: "first", StatusCode: 429}
+			},
+		},
+		&mockProvider{
+			name: "second",
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+				return nil, fmt.Errorf("%w: RELIANCE", provider.ErrSymbolNotFound)
+			},
+		},
+	))
+
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, yesterday, time.Time{})
+
+	if !errors.Is(err, provider.ErrRateLimited) {
+		t.Errorf("Expected error to match ErrRateLimited, got %v", err)
+	}
+	if !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected error to match ErrSymbolNotFound, got %v", err)
+	}
+
+	var pe *provider.ProviderError
+	if !errors.As(err, &pe) || pe.Provider != "first" {
+		t.Errorf("Expected ProviderError from first provider, got %v", err)
+	}
+}
+
+func TestMarketData_Fetch_WithValidation(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 9, 15, 0, 0, loc)
+
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{
+				{DateTime: start.Add(time.Minute), Open: 100, High: 98, Low: 101, Close: 100, Volume: 1},
+				{DateTime: start, Open: 100, High: 101, Low: 99, Close: 100, Volume: 1},
+				{DateTime: start.Add(-time.Hour), Open: 100, High: 101, Low: 99, Close: 100, Volume: 1},
+			}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithValidation(ohlcv.PolicyDrop))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1m, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 1 || !data[0].DateTime.Equal(start) {
+		t.Errorf("Expected only the valid candle at %v, got %+v", start, data)
+	}
+}
+
+func TestMarketData_Fetch_ClampsToTradingDays(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	saturday := time.Date(2024, 1, 6, 0, 0, 0, 0, loc)
+
+	var gotStart time.Time
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			gotStart = start
+			return []types.OHLCV{{DateTime: start}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock))
+
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, saturday, saturday.AddDate(0, 0, 3)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := saturday.AddDate(0, 0, 2); !gotStart.Equal(want) {
+		t.Errorf("Expected start clamped to %v, got %v", want, gotStart)
+	}
+
+	gotStart = time.Time{}
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, saturday, saturday.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 0 || !gotStart.IsZero() {
+		t.Error("Expected a weekend-only range not to reach the provider")
+	}
+}
+
+func TestMarketData_Fetch_Crypto(t *testing.T) {
+	var gotStart time.Time
+	live := &mockProvider{
+		name:      "live",
+		freshness: types.FreshnessRealtime,
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			gotStart = start
+			return []types.OHLCV{{DateTime: start}}, nil
+		},
+	}
+	historical := &mockProvider{
+		name:      "historical",
+		freshness: types.FreshnessHistorical,
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			t.Error("Historical provider should not be called for today, whatever the day")
+			return nil, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeBinance, WithProviders(historical, live))
+
+	if _, err := md.Fetch(context.Background(), "BTCUSDT", types.Interval1h, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	saturday := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+	md = NewMarketData(types.ExchangeBinance, WithProviders(live))
+	if _, err := md.Fetch(context.Background(), "BTCUSDT", types.Interval1d, saturday, saturday.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !gotStart.Equal(saturday) {
+		t.Errorf("Expected the weekend to be fetched from %v, got %v", saturday, gotStart)
+	}
+}
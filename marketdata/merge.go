@@ -0,0 +1,52 @@
+package marketdata
+
+import (
+	"slices"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// freshnessRank orders freshness from stalest to freshest; unknown freshness
+// ranks lowest.
+var freshnessRank = map[types.DataFreshness]int{
+	types.FreshnessHistorical: 1,
+	types.FreshnessEndOfDay:   2,
+	types.FreshnessDelayed:    3,
+	types.FreshnessRealtime:   4,
+}
+
+// mergeCandles merges the results of several providers, given in routing
+// order, into one series sorted by time. Where results overlap, the candle
+// with the higher freshness wins and ties go to the earlier result.
+func mergeCandles(results [][]types.OHLCV) []types.OHLCV {
+	best := make(map[int64]types.OHLCV)
+	for _, data := range results {
+		for _, c := range data {
+			key := c.DateTime.UnixNano()
+			if cur, ok := best[key]; ok && freshnessRank[c.Freshness] <= freshnessRank[cur.Freshness] {
+				continue
+			}
+			best[key] = c
+		}
+	}
+
+	merged := make([]types.OHLCV, 0, len(best))
+	for _, c := range best {
+		merged = append(merged, c)
+	}
+	slices.SortFunc(merged, func(a, b types.OHLCV) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+
+	return merged
+}
+
+// withSource sets the Source of candles that don't name their provider.
+func withSource(data []types.OHLCV, name string) []types.OHLCV {
+	for i := range data {
+		if data[i].Source == "" {
+			data[i].Source = name
+		}
+	}
+	return data
+}
@@ -0,0 +1,84 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestMarketData_Fetch_WithMerge(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	at := func(d int) time.Time { return day.AddDate(0, 0, d) }
+
+	first := &mockProvider{
+		name: "first",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{
+				{DateTime: at(0), Close: 10, Freshness: types.FreshnessHistorical},
+				{DateTime: at(1), Close: 11, Freshness: types.FreshnessHistorical},
+			}, nil
+		},
+	}
+	failing := &mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrRateLimited
+		},
+	}
+	second := &mockProvider{
+		name: "second",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{
+				{DateTime: at(0), Close: 20, Freshness: types.FreshnessHistorical},
+				{DateTime: at(1), Close: 21, Freshness: types.FreshnessRealtime},
+				{DateTime: at(2), Close: 22, Freshness: types.FreshnessHistorical},
+			}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(first, failing, second), WithRouting(PreferProvider()), WithMerge(true))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, at(3))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []struct {
+		close  float64
+		source string
+	}{
+		{10, "first"},
+		{21, "second"},
+		{22, "second"},
+	}
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d candles, got %d", len(want), len(data))
+	}
+	for i, w := range want {
+		if data[i].Close != w.close || data[i].Source != w.source {
+			t.Errorf("Candle %d: expected %v from %s, got %v from %s", i, w.close, w.source, data[i].Close, data[i].Source)
+		}
+		if !data[i].DateTime.Equal(at(i)) {
+			t.Errorf("Candle %d: expected %v, got %v", i, at(i), data[i].DateTime)
+		}
+	}
+}
+
+func TestMarketData_Fetch_WithMerge_AllFail(t *testing.T) {
+	failing := &mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrSymbolNotFound
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing), WithMerge(true))
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := md.Fetch(context.Background(), "UNKNOWN", types.Interval1d, day, day.AddDate(0, 0, 1)); !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,376 @@
+package marketdata
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/provider/upstox"
+	"github.com/shahid-2020/gohlcv/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures a MarketData in NewMarketData.
+type Option interface {
+	apply(m *MarketData)
+}
+
+type optionFunc func(*MarketData)
+
+func (f optionFunc) apply(m *MarketData) {
+	f(m)
+}
+
+// WithProviders replaces the fallback chain with providers, tried in the
+// order given. Registered and built-in providers are not used.
+func WithProviders(providers ...provider.OHLCVProvider) Option {
+	return optionFunc(func(m *MarketData) {
+		m.providers = append([]provider.OHLCVProvider{}, providers...)
+	})
+}
+
+// WithCache serves repeated fetches of the same range from c. How long a
+// result stays cached is decided by c, usually from the result's freshness.
+func WithCache(c cache.Cache) Option {
+	return optionFunc(func(m *MarketData) {
+		m.cache = c
+	})
+}
+
+// WithConcurrency sets how many symbols FetchMany fetches at once. The
+// default is 4.
+func WithConcurrency(n int) Option {
+	return optionFunc(func(m *MarketData) {
+		m.concurrency = n
+	})
+}
+
+// WithChunkConcurrency sets how many chunks of a long range are fetched at
+// once when a provider caps the range of a single request. Chunks are fetched
+// sequentially by default.
+func WithChunkConcurrency(n int) Option {
+	return optionFunc(func(m *MarketData) {
+		m.chunkConcurrency = n
+	})
+}
+
+// WithAutoResample serves intervals a provider doesn't support natively by
+// fetching a finer interval from it and aggregating the candles with
+// ohlcv.Resample.
+func WithAutoResample(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.autoResample = enabled
+	})
+}
+
+// WithValidation cleans fetched candles with ohlcv.Clean before they are
+// cached and returned: they are sorted, deduplicated and, per policy, bad
+// candles are dropped or repaired. Intraday candles outside the exchange's
+// trading session are always dropped.
+func WithValidation(policy ohlcv.Policy) Option {
+	return optionFunc(func(m *MarketData) {
+		m.validation = policy
+	})
+}
+
+// WithBackfill fills gaps in a provider's result from the other providers in
+// the chain. Gaps no provider can fill are left as they are; use Backfill to
+// find out which.
+func WithBackfill(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.autoBackfill = enabled
+	})
+}
+
+// WithMerge asks every provider in the chain instead of stopping at the first
+// with data, and merges their candles: for each timestamp the freshest candle
+// wins, ties going to the provider routed first. Each candle's Source names
+// the provider it came from. WithBackfill has no effect when merging.
+func WithMerge(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.merge = enabled
+	})
+}
+
+// WithHedging cuts tail latency by not waiting on a slow provider: if the
+// provider tried first hasn't answered after delay, the next one in the chain
+// is asked as well, and so on. The first provider with data wins and the
+// others are cancelled. Hedging is off by default and has no effect with
+// WithMerge.
+func WithHedging(delay time.Duration) Option {
+	return optionFunc(func(m *MarketData) {
+		m.hedgeDelay = delay
+	})
+}
+
+// WithRace asks every provider in the chain at once instead of one after the
+// other, so a failing or empty provider doesn't add its latency to the
+// fallback's. The first provider to return data wins, whatever its place in
+// the chain, and the others are cancelled. Racing costs a request to every
+// provider per fetch; it is off by default, overrides WithHedging and has no
+// effect with WithMerge.
+func WithRace(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.race = enabled
+	})
+}
+
+// WithPartialResults keeps what could be fetched when part of a range
+// fails, such as some chunks of a long range: if no provider in the chain has
+// the whole range, Fetch returns the largest partial result together with a
+// *PartialError naming the missing windows. Partial results aren't cached.
+// Without it, a fetch either returns the whole range or fails.
+func WithPartialResults(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.partial = enabled
+	})
+}
+
+// WithDeduplication sets whether identical provider requests made at the
+// same time, such as many dashboard widgets fetching the same chart, share a
+// single upstream call. Requests are identical if they go to the same
+// provider for the same symbol, interval and range. It is on by default.
+func WithDeduplication(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.flights = nil
+		if enabled {
+			m.flights = newFlightGroup()
+		}
+	})
+}
+
+// WithRevalidateTimeout bounds each background refresh of a stale cache
+// entry, 30 seconds by default, so that a provider that hangs doesn't keep
+// it, and the entry, from being retried.
+func WithRevalidateTimeout(d time.Duration) Option {
+	return optionFunc(func(m *MarketData) {
+		m.revalidateTimeout = d
+	})
+}
+
+// WithProviderTimeout bounds how long each provider of the chain may take,
+// so that a provider that hangs is given up on in favour of the next one
+// rather than using up the caller's deadline. A provider running out of time
+// counts as failed.
+func WithProviderTimeout(d time.Duration) Option {
+	return optionFunc(func(m *MarketData) {
+		m.providerTimeoutCap = d
+	})
+}
+
+// WithDeadlineSplit shares the deadline of a fetch's context between the
+// providers of the chain: each provider gets an even share of the time left
+// between itself and those after it, so the fallback still has a fair chance
+// once an earlier provider has timed out. Time a provider doesn't use passes
+// on to the rest. It combines with WithProviderTimeout, the shorter bound
+// winning.
+func WithDeadlineSplit(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.deadlineSplit = enabled
+	})
+}
+
+// WithCircuitBreaker skips a provider for cooldown once it has failed
+// failures times in a row. After the cooldown it is tried again, and a single
+// failure skips it for another cooldown. ProviderStatus reports the state of
+// each provider's circuit. Circuit breaking is off by default.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return optionFunc(func(m *MarketData) {
+		m.health.threshold = failures
+		m.health.cooldown = cooldown
+	})
+}
+
+// WithCalendar replaces the exchange's trading calendar, which decides the
+// trading days a range is clamped to, whether today's session is live, and
+// which candles gap detection expects.
+func WithCalendar(c *calendar.Calendar) Option {
+	return optionFunc(func(m *MarketData) {
+		m.calendar = c
+	})
+}
+
+// WithRouting sets the strategy that orders the provider chain for each
+// request. The default is PreferFreshness.
+func WithRouting(r RoutingStrategy) Option {
+	return optionFunc(func(m *MarketData) {
+		m.routing = r
+	})
+}
+
+// WithHTTPClient sets the HTTP client used by the built-in providers. It has
+// no effect on providers passed to WithProviders or RegisterProvider.
+func WithHTTPClient(c *http.Client) Option {
+	return optionFunc(func(m *MarketData) {
+		m.httpClient = c
+	})
+}
+
+// WithTransport tunes the connections of the built-in providers, unless the
+// client set with WithHTTPClient has a Transport of its own.
+func WithTransport(t provider.TransportConfig) Option {
+	return optionFunc(func(m *MarketData) {
+		m.transport = &t
+	})
+}
+
+// WithRetryHooks calls hooks on every retry of a request by the built-in
+// providers and when one is given up on.
+func WithRetryHooks(hooks provider.RetryHooks) Option {
+	return optionFunc(func(m *MarketData) {
+		m.retryHooks = &hooks
+	})
+}
+
+// WithConditionalCache lets each built-in provider keep up to maxBytes of
+// responses carrying an ETag or Last-Modified header and revalidate them
+// with conditional requests, see the providers' WithConditionalCache.
+func WithConditionalCache(maxBytes int64) Option {
+	return optionFunc(func(m *MarketData) {
+		m.conditionalCache = maxBytes
+	})
+}
+
+// WithRateLimits overrides the rate limits of the built-in provider named
+// name ("upstox" or "yahoo"). The budget is shared with every other provider
+// of that name using the same limits in the process.
+func WithRateLimits(name string, limits provider.RateLimits) Option {
+	return optionFunc(func(m *MarketData) {
+		if m.rateLimits == nil {
+			m.rateLimits = make(map[string]provider.RateLimits)
+		}
+		m.rateLimits[name] = limits
+	})
+}
+
+// WithPipeline runs every Fetch result through steps, in order, whichever
+// provider it came from. Steps see candles already in the request's time zone
+// and may modify them in place; the cached copy is not affected.
+func WithPipeline(steps ...ohlcv.Transformer) Option {
+	return optionFunc(func(m *MarketData) {
+		m.pipeline = append(m.pipeline, steps...)
+	})
+}
+
+// WithSymbolMap adds symbol aliases to DefaultSymbolMap, so tickers a
+// provider knows under another name resolve on every provider in the chain.
+// Entries in symbols replace the default ones for the same symbol and
+// provider.
+func WithSymbolMap(symbols SymbolMap) Option {
+	return optionFunc(func(m *MarketData) {
+		m.symbols = m.symbols.merge(symbols)
+	})
+}
+
+// WithExtendedHours includes pre-market and post-market candles from the
+// built-in Yahoo provider in intraday results; OHLCV.Session tells them apart.
+// Validation keeps them even though they fall outside the regular session.
+func WithExtendedHours(enabled bool) Option {
+	return optionFunc(func(m *MarketData) {
+		m.extendedHours = enabled
+	})
+}
+
+// WithPrecision sets how many decimal places the built-in providers round
+// prices to, for instruments quoted below a rupee or with more than two
+// decimals. It has no effect on providers passed to WithProviders or
+// RegisterProvider, which take their own precision option.
+func WithPrecision(p provider.Precision) Option {
+	return optionFunc(func(m *MarketData) {
+		m.precision = &p
+	})
+}
+
+// WithTimezone sets the location request times are converted to before they
+// reach providers and the location of returned candles' DateTime. The default
+// is the exchange's own time zone, Asia/Kolkata for NSE and BSE. Passed to
+// Fetch, it applies to that call only.
+func WithTimezone(loc *time.Location) SharedOption {
+	return sharedOption{
+		option: func(m *MarketData) { m.location = loc },
+		fetch:  func(c *fetchConfig) { c.location = loc },
+	}
+}
+
+// WithLogger logs provider failures and fallbacks to l. Nothing is logged by
+// default.
+func WithLogger(l *slog.Logger) Option {
+	return optionFunc(func(m *MarketData) {
+		m.logger = l
+	})
+}
+
+// WithUpstoxAccessToken authenticates the built-in upstox provider so it can
+// serve the current trading day from its intraday endpoint instead of leaving
+// it to Yahoo.
+func WithUpstoxAccessToken(token string) Option {
+	return optionFunc(func(m *MarketData) {
+		m.upstoxToken = token
+	})
+}
+
+// WithUpstoxInstruments makes the built-in upstox provider resolve symbols
+// through store instead of the embedded instrument list.
+func WithUpstoxInstruments(store *upstox.InstrumentStore) Option {
+	return optionFunc(func(m *MarketData) {
+		m.upstoxStore = store
+	})
+}
+
+// WithAdjusted fetches prices adjusted for splits and dividends. Only
+// providers implementing provider.AdjustedProvider are used; the others fail
+// with provider.ErrAdjustedUnsupported. Passed to Fetch, it applies to that
+// call only.
+func WithAdjusted(enabled bool) SharedOption {
+	return sharedOption{
+		option: func(m *MarketData) { m.adjusted = enabled },
+		fetch:  func(c *fetchConfig) { c.adjusted = &enabled },
+	}
+}
+
+// WithRangePolicy decides the range of fetches called with a zero start and
+// end, such as LastNDays(30) or TodaySession(), instead of Today. A zero
+// start with a non-zero end then fails with provider.ErrInvalidRange. Passed
+// to Fetch, it applies to that call only.
+func WithRangePolicy(p RangePolicy) SharedOption {
+	return sharedOption{
+		option: func(m *MarketData) { m.rangePolicy = p },
+		fetch:  func(c *fetchConfig) { c.ranges = p },
+	}
+}
+
+// WithProgress calls fn as the windows of a long range are fetched, see
+// ProgressFunc, so that a long fetch can show a progress bar. Passed to
+// Fetch, it applies to that call only.
+func WithProgress(fn ProgressFunc) SharedOption {
+	return sharedOption{
+		option: func(m *MarketData) { m.progress = fn },
+		fetch:  func(c *fetchConfig) { c.progress = fn },
+	}
+}
+
+// WithMinFreshness only fetches from providers serving data at least as fresh
+// as f, by their provider.FreshnessReporter, in the order realtime, delayed,
+// end of day, historical. Providers that don't report their freshness are
+// skipped, and if no provider is fresh enough the fetch fails right away with
+// provider.ErrProviderUnavailable. Cached results are still served. Passed to
+// Fetch, it applies to that call only.
+func WithMinFreshness(f types.DataFreshness) SharedOption {
+	return sharedOption{
+		option: func(m *MarketData) { m.minFreshness = f },
+		fetch:  func(c *fetchConfig) { c.freshness = f },
+	}
+}
+
+// WithTracerProvider records OpenTelemetry spans from tp: one per Fetch, one
+// per provider tried, and, for the built-in providers, one per HTTP request
+// attempt. Nothing is traced by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return optionFunc(func(m *MarketData) {
+		m.tracerProvider = tp
+	})
+}
@@ -0,0 +1,162 @@
+package marketdata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestNewMarketData_Options(t *testing.T) {
+	client := &http.Client{}
+	limits := provider.RateLimits{RequestsPerSecond: 1, RequestsPerMinute: 10, RequestsPerHour: 100}
+
+	md := NewMarketData(types.ExchangeNSE,
+		WithHTTPClient(client),
+		WithTransport(provider.TransportConfig{MaxIdleConnsPerHost: 16}),
+		WithRetryHooks(provider.RetryHooks{OnRetry: func(provider.RetryEvent) {}}),
+		WithConditionalCache(1<<20),
+		WithRateLimits("yahoo", limits),
+		WithTimezone(time.UTC),
+	)
+
+	if md.httpClient != client {
+		t.Error("Expected HTTP client to be set")
+	}
+	if md.transport == nil || md.transport.MaxIdleConnsPerHost != 16 {
+		t.Errorf("Expected the transport config to be set, got %+v", md.transport)
+	}
+	if md.retryHooks == nil || md.retryHooks.OnRetry == nil {
+		t.Error("Expected the retry hooks to be set")
+	}
+	if md.conditionalCache != 1<<20 {
+		t.Errorf("Expected a conditional cache of 1 MiB, got %d", md.conditionalCache)
+	}
+	if md.rateLimits["yahoo"] != limits {
+		t.Errorf("Expected yahoo rate limits %+v, got %+v", limits, md.rateLimits["yahoo"])
+	}
+	if md.location != time.UTC {
+		t.Errorf("Expected timezone UTC, got %v", md.location)
+	}
+	if len(md.providers) != 2 {
+		t.Errorf("Expected 2 built-in providers, got %d", len(md.providers))
+	}
+}
+
+func TestMarketData_Fetch_WithTimezone(t *testing.T) {
+	var gotLoc *time.Location
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			gotLoc = start.Location()
+			return []types.OHLCV{{DateTime: start}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithTimezone(time.UTC))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotLoc != time.UTC {
+		t.Errorf("Expected times in UTC, got %v", gotLoc)
+	}
+}
+
+func TestMarketData_Fetch_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	failing := &mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, errors.New("boom")
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing), WithLogger(logger))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1)); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if out := buf.String(); !strings.Contains(out, "provider=failing") || !strings.Contains(out, "boom") {
+		t.Errorf("Expected provider failure to be logged, got %q", out)
+	}
+}
+
+func TestMarketData_Fetch_NormalizesCandleTimezone(t *testing.T) {
+	day := time.Date(2024, 1, 2, 3, 45, 0, 0, time.UTC)
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{{DateTime: day}}, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loc := data[0].DateTime.Location(); loc != types.ExchangeNSE.Location() {
+		t.Errorf("Expected candles in the exchange's zone, got %v", loc)
+	}
+
+	data, err = md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1), WithTimezone(time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loc := data[0].DateTime.Location(); loc != time.UTC {
+		t.Errorf("Expected candles in UTC, got %v", loc)
+	}
+	if !data[0].DateTime.Equal(day) {
+		t.Errorf("Expected the same instant %v, got %v", day, data[0].DateTime)
+	}
+}
+
+func TestMarketData_Fetch_WithPipeline(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	cached := []types.OHLCV{
+		{DateTime: day, Open: 100, High: 101, Low: 99, Close: 100.123},
+		{DateTime: day.AddDate(0, 0, 1)},
+	}
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return cached, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithPipeline(
+		ohlcv.Round(provider.Decimals(1)),
+		ohlcv.DropNull(),
+		ohlcv.TransformerFunc(func(candles []types.OHLCV) []types.OHLCV {
+			for i := range candles {
+				candles[i].Source = "normalized"
+			}
+			return candles
+		}),
+	))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 1 || data[0].Close != 100.1 || data[0].Source != "normalized" {
+		t.Errorf("Expected one rounded, normalized candle, got %+v", data)
+	}
+	if cached[0].Close != 100.123 {
+		t.Errorf("Expected the provider's candles untouched, got %+v", cached[0])
+	}
+}
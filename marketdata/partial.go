@@ -0,0 +1,44 @@
+package marketdata
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PartialError is returned together with the candles that were fetched when
+// WithPartialResults is set and only part of a range could be fetched.
+// Missing lists the windows whose candles are missing and Err joins the
+// failures behind them.
+type PartialError struct {
+	Missing []Gap
+	Err     error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("partial result, %d window(s) missing: %v", len(e.Missing), e.Err)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// asPartial returns err as a *PartialError, or nil if it isn't one.
+func asPartial(err error) *PartialError {
+	var partial *PartialError
+	if errors.As(err, &partial) {
+		return partial
+	}
+	return nil
+}
+
+// joinPartial adds the missing windows and failures of other to p, which may
+// be nil.
+func joinPartial(p, other *PartialError) *PartialError {
+	if p == nil {
+		return other
+	}
+	return &PartialError{
+		Missing: append(append([]Gap{}, p.Missing...), other.Missing...),
+		Err:     errors.Join(p.Err, other.Err),
+	}
+}
@@ -0,0 +1,105 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// flakyChunks serves 10-day chunks with one candle each, failing the chunk
+// starting at broken.
+func flakyChunks(broken time.Time) *limitedProvider {
+	return &limitedProvider{
+		maxRange: 10 * 24 * time.Hour,
+		mockProvider: mockProvider{
+			name: "flaky",
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+				if from.Equal(broken) {
+					return nil, errors.New("upstream timeout")
+				}
+				return []types.OHLCV{{Symbol: symbol, DateTime: from, Close: 100}}, nil
+			},
+		},
+	}
+}
+
+func TestMarketData_Fetch_WithPartialResults(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 25)
+	broken := start.AddDate(0, 0, 10)
+
+	for _, race := range []bool{false, true} {
+		md := NewMarketData(types.ExchangeNSE, WithProviders(flakyChunks(broken)), WithPartialResults(true), WithRace(race))
+		data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end)
+
+		var partial *PartialError
+		if !errors.As(err, &partial) {
+			t.Fatalf("Expected a *PartialError, got %v", err)
+		}
+		if len(data) != 2 {
+			t.Errorf("Expected the candles of the 2 chunks that succeeded, got %d", len(data))
+		}
+		if len(partial.Missing) != 1 || !partial.Missing[0].From.Equal(broken) || !partial.Missing[0].To.Equal(broken.AddDate(0, 0, 10)) {
+			t.Errorf("Expected the failed chunk to be missing, got %+v", partial.Missing)
+		}
+		if partial.Err == nil || partial.Err.Error() != "flaky: upstream timeout" {
+			t.Errorf("Expected the chunk's failure, got %v", partial.Err)
+		}
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(flakyChunks(broken)))
+	if data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end); err == nil || data != nil {
+		t.Errorf("Expected all or nothing without partial results, got %d candles and %v", len(data), err)
+	}
+}
+
+func TestMarketData_Fetch_WithPartialResults_PrefersWholeRange(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 25)
+
+	whole := &mockProvider{
+		name: "whole",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+			return []types.OHLCV{{Symbol: symbol, DateTime: from, Close: 1}}, nil
+		},
+	}
+
+	for _, race := range []bool{false, true} {
+		md := NewMarketData(types.ExchangeNSE,
+			WithProviders(flakyChunks(start.AddDate(0, 0, 10)), whole),
+			WithPartialResults(true),
+			WithRace(race),
+		)
+		data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, end)
+		if err != nil {
+			t.Fatalf("Expected the whole range from the fallback, got %v", err)
+		}
+		if len(data) != 1 || data[0].Close != 1 {
+			t.Errorf("Expected the fallback's candle, got %+v", data)
+		}
+	}
+}
+
+func TestMarketData_FetchMany_WithPartialResults(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, loc)
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(flakyChunks(start.AddDate(0, 0, 10))), WithPartialResults(true))
+	results, err := md.FetchMany(context.Background(), []string{"INFY", "TCS"}, types.Interval1d, start, start.AddDate(0, 0, 25))
+
+	var batch *BatchError
+	if !errors.As(err, &batch) || len(batch.Errors) != 2 {
+		t.Fatalf("Expected both symbols in a *BatchError, got %v", err)
+	}
+	if asPartial(batch.Errors["INFY"]) == nil {
+		t.Errorf("Expected a *PartialError for INFY, got %v", batch.Errors["INFY"])
+	}
+	if len(results["INFY"]) != 2 || len(results["TCS"]) != 2 {
+		t.Errorf("Expected the partial candles of both symbols, got %v", results)
+	}
+}
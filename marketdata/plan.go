@@ -0,0 +1,180 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// FetchPlan describes what a Fetch would do, as returned by Plan.
+type FetchPlan struct {
+	Symbol   string
+	Exchange types.Exchange
+	Interval types.Interval
+	// Start and End are the range after range policies and the trading
+	// calendar have been applied. A zero End means up to now.
+	Start time.Time
+	End   time.Time
+	// StartUnknown is true if the range policy could only find the start by
+	// sending requests, as SinceListing does. Start is then zero, and the
+	// providers are listed without counting their requests.
+	StartUnknown bool
+	// Cached is true if the cache holds the whole range, so no request
+	// would be sent, other than to refresh candles served stale.
+	Cached bool
+	// Providers lists the providers that would be tried, in order, with the
+	// requests each would be sent if asked.
+	Providers []ProviderPlan
+	// Requests is how many requests the fetch would send if no provider
+	// fails: those of the first provider, or of every provider with
+	// WithMerge or WithRace.
+	Requests int
+}
+
+// ProviderPlan is the part of a FetchPlan served by one provider.
+type ProviderPlan struct {
+	Name string
+	// Requests is how many requests the provider would be sent, one per
+	// window of a long range and per range missing from the cache.
+	Requests int
+	// RateLimit is what is left of the provider's rate-limit budget, nil if
+	// the provider doesn't report it.
+	RateLimit *provider.RateLimits
+	// Throttled is true if Requests exceeds what is left of the budget for
+	// the current minute or hour, so the fetch would wait for it.
+	Throttled bool
+}
+
+// Plan reports which providers a Fetch with the same arguments would use,
+// how many requests it would send and how much of each provider's rate
+// limit they would take, without sending any. The cache is consulted but not
+// filled, and circuit breakers are as they are now.
+//
+// Plan is an estimate: a symbol given as an ISIN is only resolved if it has
+// been before, and the lookup isn't counted; intervals resampled from finer
+// ones under WithAutoResample are counted as a single attempt; and a start
+// found by probing, as with SinceListing, is reported as unknown. Routing
+// strategies are previewed, as RoutePreviewer describes. A range with no
+// trading day plans no requests.
+func (m *MarketData) Plan(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+	opts ...FetchOption,
+) (FetchPlan, error) {
+	if err := validateRequest(symbol, interval); err != nil {
+		return FetchPlan{}, err
+	}
+
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+
+	planning := *m
+	planning.planning = true
+	m = &planning
+
+	if IsISIN(symbol) {
+		if resolved, ok := m.isins.get(m.exchange, symbol); ok {
+			symbol = resolved
+		}
+	}
+
+	plan := FetchPlan{Symbol: symbol, Exchange: m.exchange, Interval: interval}
+	start, end, empty, err := m.fetchRange(ctx, symbol, interval, start, end)
+	switch {
+	case errors.Is(err, errStartUnknown):
+		plan.StartUnknown = true
+	case err != nil:
+		return FetchPlan{}, err
+	case empty:
+		plan.Start, plan.End = start, end
+		return plan, nil
+	default:
+		plan.Start, plan.End = start, end
+	}
+
+	if len(m.providers) == 0 {
+		return FetchPlan{}, fmt.Errorf("%w: no providers configured", provider.ErrProviderUnavailable)
+	}
+
+	ranges := []cache.Range{{From: plan.Start, To: plan.End}}
+	if !plan.StartUnknown {
+		key := cache.Key{Symbol: symbol, Exchange: m.exchange, Interval: interval, From: plan.Start, To: plan.End, Adjusted: m.adjusted}
+		ranges = m.uncachedRanges(key)
+	}
+	if len(ranges) == 0 {
+		plan.Cached = true
+		return plan, nil
+	}
+
+	index := make(map[string]int)
+	for _, r := range ranges {
+		chain, err := m.route(symbol, interval, r.From, r.To, true)
+		if err != nil {
+			return FetchPlan{}, err
+		}
+
+		first := true
+		for _, p := range chain {
+			if _, ok := p.(provider.AdjustedProvider); m.adjusted && !ok {
+				continue
+			}
+
+			var n int
+			if !plan.StartUnknown {
+				n = len(requestWindows(p, interval, r.From, r.To))
+			}
+			if first || m.merge || m.race {
+				plan.Requests += n
+			}
+			first = false
+
+			j, ok := index[p.Name()]
+			if !ok {
+				j = len(plan.Providers)
+				index[p.Name()] = j
+				plan.Providers = append(plan.Providers, ProviderPlan{Name: p.Name()})
+			}
+			plan.Providers[j].Requests += n
+		}
+	}
+
+	for i := range plan.Providers {
+		pp := &plan.Providers[i]
+		r, ok := m.providerNamed(pp.Name).(provider.RateLimitReporter)
+		if !ok {
+			continue
+		}
+		if remaining, ok := r.RemainingRateLimit(); ok {
+			pp.RateLimit = &remaining
+			pp.Throttled = pp.Requests > remaining.RequestsPerMinute || pp.Requests > remaining.RequestsPerHour
+		}
+	}
+
+	return plan, nil
+}
+
+// uncachedRanges returns the parts of key's range a fetch would request, as
+// the cache holds it now.
+func (m *MarketData) uncachedRanges(key cache.Key) []cache.Range {
+	if rc, ok := m.cache.(cache.RangeCache); ok && !key.To.IsZero() {
+		_, missing := rc.GetRange(key)
+		return missing
+	}
+	if sc, ok := m.cache.(cache.StaleCache); ok {
+		if _, _, ok := sc.GetStale(key); ok {
+			return nil
+		}
+	} else if m.cache != nil {
+		if _, ok := m.cache.Get(key); ok {
+			return nil
+		}
+	}
+	return []cache.Range{{From: key.From, To: key.To}}
+}
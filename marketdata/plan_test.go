@@ -0,0 +1,152 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type reportingProvider struct {
+	limitedProvider
+	remaining provider.RateLimits
+}
+
+func (r *reportingProvider) RemainingRateLimit() (provider.RateLimits, bool) {
+	return r.remaining, true
+}
+
+func TestMarketData_Plan(t *testing.T) {
+	var calls int
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 25)
+
+	limited := &limitedProvider{mockProvider: *countingProvider("limited", &calls), maxRange: 10 * 24 * time.Hour}
+	fallback := countingProvider("fallback", &calls)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(limited, fallback))
+
+	plan, err := md.Plan(context.Background(), "INFY", types.Interval1d, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no requests sent, got %d", calls)
+	}
+	if len(plan.Providers) != 2 || plan.Providers[0].Name != "limited" || plan.Providers[1].Name != "fallback" {
+		t.Fatalf("Expected both providers in chain order, got %+v", plan.Providers)
+	}
+	if plan.Providers[0].Requests != 3 || plan.Providers[1].Requests != 1 {
+		t.Errorf("Expected 3 and 1 requests, got %d and %d", plan.Providers[0].Requests, plan.Providers[1].Requests)
+	}
+	if plan.Requests != 3 || plan.Cached {
+		t.Errorf("Expected 3 requests from the first provider, got %d (cached %v)", plan.Requests, plan.Cached)
+	}
+	if !plan.Start.Equal(start) || !plan.End.Equal(end) || plan.Symbol != "INFY" {
+		t.Errorf("Unexpected plan range: %+v", plan)
+	}
+
+	md = NewMarketData(types.ExchangeNSE, WithProviders(limited, fallback), WithMerge(true))
+	merged, err := md.Plan(context.Background(), "INFY", types.Interval1d, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if merged.Requests != 4 {
+		t.Errorf("Expected every provider's requests with merging, got %d", merged.Requests)
+	}
+}
+
+func TestMarketData_Plan_Cached(t *testing.T) {
+	var calls int
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 5)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("mock", &calls)), WithCache(cache.NewLRU(10)))
+
+	if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, end); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	plan, err := md.Plan(context.Background(), "INFY", types.Interval1d, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !plan.Cached || plan.Requests != 0 || len(plan.Providers) != 0 {
+		t.Errorf("Expected a cached plan with no requests, got %+v", plan)
+	}
+	if calls != 1 {
+		t.Errorf("Expected only the fetch to send a request, got %d", calls)
+	}
+}
+
+func TestMarketData_Plan_RateLimit(t *testing.T) {
+	var calls int
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &reportingProvider{
+		limitedProvider: limitedProvider{mockProvider: *countingProvider("reporting", &calls), maxRange: 24 * time.Hour},
+		remaining:       provider.RateLimits{RequestsPerSecond: 5, RequestsPerMinute: 3, RequestsPerHour: 100},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p))
+
+	plan, err := md.Plan(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 5))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pp := plan.Providers[0]
+	if pp.RateLimit == nil || pp.RateLimit.RequestsPerMinute != 3 {
+		t.Fatalf("Expected the remaining budget, got %v", pp.RateLimit)
+	}
+	if pp.Requests != 5 || !pp.Throttled {
+		t.Errorf("Expected 5 requests to be throttled, got %d (throttled %v)", pp.Requests, pp.Throttled)
+	}
+}
+
+func TestMarketData_Plan_NoSideEffects(t *testing.T) {
+	var calls int
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("a", &calls), countingProvider("b", &calls)), WithRouting(RoundRobin()))
+
+	for range 2 {
+		plan, err := md.Plan(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 5))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if plan.Providers[0].Name != "a" {
+			t.Errorf("Expected planning not to advance the round robin, got %s first", plan.Providers[0].Name)
+		}
+	}
+
+	data, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 5))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data[0].Source != "a" {
+		t.Errorf("Expected the fetch to start at a, got %s", data[0].Source)
+	}
+
+	calls = 0
+	plan, err := md.Plan(context.Background(), "INFY", types.Interval1d, time.Time{}, time.Time{}, WithRangePolicy(SinceListing()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected SinceListing not to probe, got %d requests", calls)
+	}
+	if !plan.StartUnknown || !plan.Start.IsZero() || plan.Requests != 0 || len(plan.Providers) != 2 {
+		t.Errorf("Expected an unknown start with uncounted providers, got %+v", plan)
+	}
+}
+
+func TestMarketData_Plan_Invalid(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "mock"}))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := md.Plan(context.Background(), " ", types.Interval1d, start, time.Time{}); !errors.Is(err, provider.ErrInvalidSymbol) {
+		t.Errorf("Expected ErrInvalidSymbol, got %v", err)
+	}
+	if _, err := md.Plan(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, -1)); !errors.Is(err, provider.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/storage"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// errNoCache is the outcome of a Prefetch without a cache to warm.
+var errNoCache = errors.New("prefetch needs a cache, set with WithCache")
+
+// Prefetch warms the cache with the candles of symbols between start and end,
+// so that later fetches of the range are cache hits. It returns at once: the
+// symbols are fetched in the background by a worker pool sized by
+// WithConcurrency, and the returned channel receives the outcome, a
+// *BatchError if some symbols failed, before it is closed. Requests have
+// provider.PriorityLow unless ctx sets another priority, so interactive
+// fetches sharing the providers' rate limits go first. Cancelling ctx stops
+// the prefetch.
+func (m *MarketData) Prefetch(
+	ctx context.Context,
+	symbols []string,
+	interval types.Interval,
+	start, end time.Time,
+	opts ...FetchOption,
+) <-chan error {
+	if m.cache == nil {
+		done := make(chan error, 1)
+		done <- errNoCache
+		close(done)
+		return done
+	}
+
+	return m.prefetch(ctx, symbols, func(ctx context.Context, symbol string) error {
+		_, err := m.Fetch(ctx, symbol, interval, start, end, opts...)
+		return err
+	})
+}
+
+// PrefetchStore is Prefetch saving the candles of every symbol to store, as
+// FetchAndStore does, whether or not MarketData has a cache.
+func (m *MarketData) PrefetchStore(
+	ctx context.Context,
+	store storage.Store,
+	symbols []string,
+	interval types.Interval,
+	start, end time.Time,
+	opts ...FetchOption,
+) <-chan error {
+	return m.prefetch(ctx, symbols, func(ctx context.Context, symbol string) error {
+		_, err := m.FetchAndStore(ctx, store, symbol, interval, start, end, opts...)
+		return err
+	})
+}
+
+func (m *MarketData) prefetch(ctx context.Context, symbols []string, fetch func(ctx context.Context, symbol string) error) <-chan error {
+	if _, ok := provider.PriorityFromContext(ctx); !ok {
+		ctx = provider.WithPriority(ctx, provider.PriorityLow)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+
+		// The candles are only kept by the cache or store, not returned.
+		_, err := m.fetchEach(symbols, func(symbol string) ([]types.OHLCV, error) {
+			return nil, fetch(ctx, symbol)
+		})
+		done <- err
+	}()
+	return done
+}
@@ -0,0 +1,86 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// recordingProvider counts its calls and the priorities they were made with.
+type recordingProvider struct {
+	mockProvider
+	mu         sync.Mutex
+	priorities []provider.Priority
+}
+
+func newRecordingProvider() *recordingProvider {
+	r := &recordingProvider{}
+	r.name = "recording"
+	r.provideFunc = func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+		priority, _ := provider.PriorityFromContext(ctx)
+		r.mu.Lock()
+		r.priorities = append(r.priorities, priority)
+		r.mu.Unlock()
+		return []types.OHLCV{{Symbol: symbol, DateTime: start, Close: 100, Freshness: types.FreshnessHistorical}}, nil
+	}
+	return r
+}
+
+func TestMarketData_Prefetch(t *testing.T) {
+	p := newRecordingProvider()
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p), WithCache(cache.NewLRU(10)))
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 5)
+	symbols := []string{"INFY", "TCS", "RELIANCE"}
+
+	if err := <-md.Prefetch(context.Background(), symbols, types.Interval1d, start, end); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(p.priorities) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(p.priorities))
+	}
+	for _, priority := range p.priorities {
+		if priority != provider.PriorityLow {
+			t.Errorf("Expected prefetching at low priority, got %v", priority)
+		}
+	}
+
+	for _, symbol := range symbols {
+		if _, err := md.Fetch(context.Background(), symbol, types.Interval1d, start, end); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(p.priorities) != 3 {
+		t.Errorf("Expected later fetches to be cache hits, got %d requests", len(p.priorities))
+	}
+}
+
+func TestMarketData_Prefetch_NoCache(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(newRecordingProvider()))
+	if err := <-md.Prefetch(context.Background(), []string{"INFY"}, types.Interval1d, time.Time{}, time.Time{}); !errors.Is(err, errNoCache) {
+		t.Errorf("Expected an error without a cache, got %v", err)
+	}
+}
+
+func TestMarketData_PrefetchStore(t *testing.T) {
+	store := &memoryStore{}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(newRecordingProvider()), WithConcurrency(1))
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	done := md.PrefetchStore(context.Background(), store, []string{"INFY", "TCS"}, types.Interval1d, start, start.AddDate(0, 0, 5))
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(store.saved) != 2 {
+		t.Errorf("Expected the candles of both symbols to be stored, got %d", len(store.saved))
+	}
+	if _, open := <-done; open {
+		t.Error("Expected the channel to be closed")
+	}
+}
@@ -0,0 +1,161 @@
+package marketdata
+
+import "sync"
+
+// ProgressFunc is told how far a long operation has got: fetched of total
+// steps are done for symbol. For a fetch, the steps are the windows of its
+// range that a provider serves in one request each, and it is called as each
+// window is done, whether or not it succeeded.
+//
+// A fetch is counted as a whole: the parts of its range missing from a range
+// cache add their windows to total as each is started, and only the windows
+// of the attempt that served a part are kept once it is done. While providers
+// are tried, the count follows the attempt furthest along that hasn't
+// failed, so when a provider fails, or an interval is resampled from a finer
+// one, fetched and total move to the attempt replacing it and may go down.
+// Calls for one fetch don't overlap, even with WithRace or WithHedging, but
+// those for the symbols of a FetchMany may.
+type ProgressFunc func(fetched, total int, symbol string)
+
+// fetchProgress counts the windows of one Fetch call, across the parts of
+// its range and the attempts at each, and reports them to a ProgressFunc. A
+// nil fetchProgress reports nothing.
+type fetchProgress struct {
+	mu     sync.Mutex
+	fn     ProgressFunc
+	symbol string
+	// fetched and total count the windows of the parts done, by the
+	// attempts that served them.
+	fetched int
+	total   int
+	// part numbers the part being fetched, so that attempts outliving it
+	// are ignored.
+	part     int
+	attempts []*attemptProgress
+	// lastFetched and lastTotal are what was last reported.
+	lastFetched int
+	lastTotal   int
+}
+
+func newFetchProgress(fn ProgressFunc, symbol string) *fetchProgress {
+	if fn == nil {
+		return nil
+	}
+	return &fetchProgress{fn: fn, symbol: symbol}
+}
+
+// attemptProgress counts the windows of one provider's attempt at a part of
+// the range.
+type attemptProgress struct {
+	p       *fetchProgress
+	part    int
+	fetched int
+	total   int
+	failed  bool
+	served  bool
+}
+
+// attempt starts counting an attempt of total windows at the current part.
+func (p *fetchProgress) attempt(total int) *attemptProgress {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	a := &attemptProgress{p: p, part: p.part, total: total}
+	p.attempts = append(p.attempts, a)
+	return a
+}
+
+// partDone keeps the windows of the attempt that served the part being
+// fetched, if any, and starts counting the next.
+func (p *fetchProgress) partDone() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, a := range p.attempts {
+		if a.served {
+			p.fetched += a.fetched
+			p.total += a.total
+			break
+		}
+	}
+	p.attempts = nil
+	p.part++
+}
+
+// leader returns the attempt the count follows: the first that served the
+// part, or else the one furthest along that hasn't failed, or nil.
+func (p *fetchProgress) leader() *attemptProgress {
+	var lead *attemptProgress
+	for _, a := range p.attempts {
+		switch {
+		case a.served:
+			return a
+		case a.failed:
+		case lead == nil || a.fetched*lead.total > lead.fetched*a.total:
+			lead = a
+		}
+	}
+	return lead
+}
+
+// report calls the ProgressFunc if the count has changed.
+func (p *fetchProgress) report() {
+	lead := p.leader()
+	if lead == nil {
+		return
+	}
+
+	fetched, total := p.fetched+lead.fetched, p.total+lead.total
+	if fetched == p.lastFetched && total == p.lastTotal {
+		return
+	}
+	p.lastFetched, p.lastTotal = fetched, total
+	p.fn(fetched, total, p.symbol)
+}
+
+// done counts a window of the attempt as done.
+func (a *attemptProgress) done() {
+	if a == nil {
+		return
+	}
+
+	p := a.p
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if a.part != p.part {
+		return
+	}
+	a.fetched++
+	p.report()
+}
+
+// finish records whether the attempt served its part, as it does with no
+// error or a partial result.
+func (a *attemptProgress) finish(err error) {
+	if a == nil {
+		return
+	}
+
+	p := a.p
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if a.part != p.part {
+		return
+	}
+	if err == nil || asPartial(err) != nil {
+		a.served = true
+	} else {
+		a.failed = true
+	}
+	p.report()
+}
@@ -0,0 +1,105 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// progressRecorder records the calls of a ProgressFunc, noting any that
+// overlap.
+type progressRecorder struct {
+	mu       sync.Mutex
+	inFlight atomic.Int32
+	overlaps atomic.Int32
+	calls    [][2]int
+}
+
+func (r *progressRecorder) fn(fetched, total int, symbol string) {
+	if r.inFlight.Add(1) > 1 {
+		r.overlaps.Add(1)
+	}
+	defer r.inFlight.Add(-1)
+	time.Sleep(time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, [2]int{fetched, total})
+}
+
+func (r *progressRecorder) last() [2]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.calls) == 0 {
+		return [2]int{}
+	}
+	return r.calls[len(r.calls)-1]
+}
+
+func TestMarketData_Fetch_ProgressWithRace(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 25)
+
+	var calls int
+	fast := &limitedProvider{mockProvider: *countingProvider("fast", &calls), maxRange: 10 * 24 * time.Hour}
+	slow := &limitedProvider{maxRange: 5 * 24 * time.Hour, mockProvider: mockProvider{
+		name: "slow",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(slow, fast), WithRace(true), WithChunkConcurrency(5))
+
+	var r progressRecorder
+	data, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, end, WithProgress(r.fn))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data[0].Source != "fast" {
+		t.Fatalf("Expected the fast provider to win, got %s", data[0].Source)
+	}
+
+	// Windows of the losing attempt finishing late aren't reported.
+	time.Sleep(20 * time.Millisecond)
+	if got := r.last(); got != [2]int{3, 3} {
+		t.Errorf("Expected progress to end at the winner's 3 of 3 windows, got %v", r.calls)
+	}
+	if n := r.overlaps.Load(); n != 0 {
+		t.Errorf("Expected calls not to overlap, got %d overlapping", n)
+	}
+}
+
+func TestMarketData_Fetch_ProgressAcrossRanges(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return base.AddDate(0, 0, n) }
+	rc := &fakeRangeCache{missing: []cache.Range{{From: day(0), To: day(1)}, {From: day(4), To: day(5)}}}
+
+	failing := &limitedProvider{maxRange: 12 * time.Hour, mockProvider: mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, provider.ErrProviderUnavailable
+		},
+	}}
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing, countingProvider("fallback", &calls)), WithCache(rc))
+
+	var r progressRecorder
+	if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, day(0), day(5), WithProgress(r.fn)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Each missing range keeps the window of the provider that served it,
+	// not the two of the one that failed, and the totals add up over the
+	// fetch.
+	if got := r.last(); got != [2]int{2, 2} {
+		t.Errorf("Expected progress to end at 2 of 2 windows, got %v", r.calls)
+	}
+}
@@ -0,0 +1,47 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Quote returns the latest price of symbol from the first provider in the
+// chain that serves quotes.
+func (m *MarketData) Quote(ctx context.Context, symbol string, opts ...FetchOption) (types.Quote, error) {
+	m, ctx, cancel, _ := m.forCall(ctx, opts)
+	defer cancel()
+
+	symbol, err := m.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return types.Quote{}, err
+	}
+
+	var errs []error
+	for _, p := range m.providers {
+		qp, ok := p.(provider.QuoteProvider)
+		if !ok {
+			continue
+		}
+
+		alias := m.symbols.resolve(symbol, p.Name())
+		quote, err := qp.Quote(ctx, alias, m.exchange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if alias != symbol {
+			quote.Symbol = symbol
+		}
+		return quote, nil
+	}
+
+	if len(errs) > 0 {
+		return types.Quote{}, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+
+	return types.Quote{}, fmt.Errorf("%w: no provider serves quotes", provider.ErrProviderUnavailable)
+}
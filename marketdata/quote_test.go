@@ -0,0 +1,45 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type quoteProvider struct {
+	mockProvider
+	quote    types.Quote
+	err      error
+	exchange types.Exchange
+}
+
+func (q *quoteProvider) Quote(ctx context.Context, symbol string, exchange types.Exchange) (types.Quote, error) {
+	q.exchange = exchange
+	return q.quote, q.err
+}
+
+func TestMarketData_Quote(t *testing.T) {
+	failing := &quoteProvider{mockProvider: mockProvider{name: "failing"}, err: provider.ErrProviderUnavailable}
+	serving := &quoteProvider{mockProvider: mockProvider{name: "serving"}, quote: types.Quote{Symbol: "RELIANCE", LastPrice: 1375}}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}, failing, serving))
+
+	q, err := md.Quote(context.Background(), "RELIANCE")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if q.LastPrice != 1375 {
+		t.Errorf("Expected last price 1375, got %v", q.LastPrice)
+	}
+}
+
+func TestMarketData_Quote_NoProvider(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "candles-only"}))
+
+	if _, err := md.Quote(context.Background(), "RELIANCE"); !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
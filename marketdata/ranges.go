@@ -0,0 +1,189 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// RangePolicy decides the range of a fetch called with a zero start and end.
+// Resolve receives the MarketData making the fetch, for policies that look
+// data up, and a Request without Start and End. A zero end returned means up
+// to now.
+type RangePolicy interface {
+	Resolve(ctx context.Context, m *MarketData, req Request) (start, end time.Time, err error)
+}
+
+// RangeFunc adapts a function to RangePolicy.
+type RangeFunc func(ctx context.Context, m *MarketData, req Request) (start, end time.Time, err error)
+
+func (f RangeFunc) Resolve(ctx context.Context, m *MarketData, req Request) (time.Time, time.Time, error) {
+	return f(ctx, m, req)
+}
+
+// Today ranges from midnight today, in the MarketData's time zone, up to now.
+// It is what a zero start means without a policy.
+func Today() RangePolicy {
+	return RangeFunc(func(ctx context.Context, m *MarketData, req Request) (time.Time, time.Time, error) {
+		return m.midnight(time.Now()), time.Time{}, nil
+	})
+}
+
+// LastNDays ranges from midnight n days before today up to now, so that
+// LastNDays(1) covers yesterday and today so far.
+func LastNDays(n int) RangePolicy {
+	return RangeFunc(func(ctx context.Context, m *MarketData, req Request) (time.Time, time.Time, error) {
+		if n < 1 {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: LastNDays(%d) needs at least one day", provider.ErrInvalidRange, n)
+		}
+		return m.midnight(time.Now()).AddDate(0, 0, -n), time.Time{}, nil
+	})
+}
+
+// SinceListing ranges from the earliest candle the provider chain serves, as
+// found by EarliestAvailable, up to now. It takes a dozen or so requests more,
+// and is only accepted for daily and longer intervals, since providers keep
+// intraday history for months at most. Plan reports its start as unknown
+// rather than probing.
+func SinceListing() RangePolicy {
+	return RangeFunc(func(ctx context.Context, m *MarketData, req Request) (time.Time, time.Time, error) {
+		if req.Interval.IsIntraday() {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: SinceListing needs a daily or longer interval, got %s", provider.ErrInvalidRange, req.Interval)
+		}
+		if m.planning {
+			return time.Time{}, time.Time{}, errStartUnknown
+		}
+
+		start, err := m.EarliestAvailable(ctx, req.Symbol, req.Interval)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return start, time.Time{}, nil
+	})
+}
+
+// TodaySession ranges over today's regular session by the MarketData's
+// calendar, from the open to the close. It fails on days the exchange doesn't
+// trade.
+func TodaySession() RangePolicy {
+	return RangeFunc(func(ctx context.Context, m *MarketData, req Request) (time.Time, time.Time, error) {
+		now := time.Now()
+		open, close, ok := m.calendarOrDefault().SessionBounds(now)
+		if !ok {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: %s doesn't trade on %s", provider.ErrInvalidRange,
+				req.Exchange, now.In(m.timezone()).Format(time.DateOnly))
+		}
+		return open, close, nil
+	})
+}
+
+// errStartUnknown is returned by range policies asked to resolve a range for
+// Plan when they could only do so by sending requests.
+var errStartUnknown = errors.New("start unknown without sending requests")
+
+// fetchRange returns the range a fetch from start to end covers: resolved by
+// resolveRange, with a zero start taken as midnight today, in the
+// MarketData's time zone and, with a calendar and an end, starting on a
+// trading day. empty is true if the range holds no trading day. Ranges the
+// routed providers can't serve fail, as checkChain describes.
+func (m *MarketData) fetchRange(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) (time.Time, time.Time, bool, error) {
+	start, end, err := m.resolveRange(ctx, symbol, interval, start, end)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+
+	loc := m.timezone()
+	if start.IsZero() {
+		start = m.midnight(time.Now())
+	} else {
+		start = start.In(loc)
+	}
+
+	if !end.IsZero() {
+		end = end.In(loc)
+	}
+
+	if m.calendar != nil && !end.IsZero() && !m.calendar.IsTradingDay(start) {
+		start = m.calendar.NextTradingDay(start)
+		if !start.Before(end) {
+			return start, end, true, nil
+		}
+	}
+
+	if err := m.checkChain(symbol, interval, start, end); err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	return start, end, false, nil
+}
+
+// resolveRange applies the range policy to a fetch with a zero start, and
+// fails ranges that can't return candles before any request is made: a start
+// after the end or in the future.
+func (m *MarketData) resolveRange(ctx context.Context, symbol string, interval types.Interval, start, end time.Time) (time.Time, time.Time, error) {
+	if start.IsZero() && m.rangePolicy != nil {
+		if !end.IsZero() {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: a range policy needs both start and end zero, got an end of %s",
+				provider.ErrInvalidRange, end.Format(time.RFC3339))
+		}
+
+		req := Request{Symbol: symbol, Exchange: m.exchange, Interval: interval}
+		var err error
+		start, end, err = m.rangePolicy.Resolve(ctx, m, req)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if !start.IsZero() && !end.IsZero() && start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: start %s is after end %s", provider.ErrInvalidRange,
+			start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	if start.After(time.Now()) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: start %s is in the future", provider.ErrInvalidRange, start.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
+
+// checkHistory fails a range ending before the oldest candles of interval any
+// provider of chain keeps. It only applies when every provider reports how
+// far back it goes, as implementations of provider.HistoryLimiter.
+func (m *MarketData) checkHistory(chain []provider.OHLCVProvider, interval types.Interval, end time.Time) error {
+	if end.IsZero() || len(chain) == 0 {
+		return nil
+	}
+
+	var longest time.Duration
+	for _, p := range chain {
+		hl, ok := p.(provider.HistoryLimiter)
+		if !ok {
+			return nil
+		}
+		limit := hl.MaxHistory(interval)
+		if limit == 0 {
+			return nil
+		}
+		longest = max(longest, limit)
+	}
+
+	oldest := time.Now().Add(-longest)
+	if end.Before(oldest) {
+		return fmt.Errorf("%w: no provider keeps %s candles from before %s", provider.ErrInvalidRange,
+			interval, oldest.In(m.timezone()).Format(time.DateOnly))
+	}
+	return nil
+}
+
+// midnight returns the start of the day of t in the MarketData's time zone.
+func (m *MarketData) midnight(t time.Time) time.Time {
+	t = t.In(m.timezone())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,124 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// rangeProvider records the range of the last request and serves a daily
+// candle for every day of it from listed on. A zero end means up to now.
+type rangeProvider struct {
+	mockProvider
+	listed     time.Time
+	start, end time.Time
+	calls      int
+}
+
+func newRangeProvider(listed time.Time) *rangeProvider {
+	p := &rangeProvider{listed: listed}
+	p.mockProvider = mockProvider{
+		name: "ranges",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			p.start, p.end = start, end
+			p.calls++
+			if end.IsZero() {
+				end = time.Now()
+			}
+
+			var data []types.OHLCV
+			for t := p.listed; t.Before(end); t = t.AddDate(0, 0, 1) {
+				if !t.Before(start) {
+					data = append(data, types.OHLCV{Symbol: symbol, DateTime: t, Freshness: types.FreshnessHistorical})
+				}
+			}
+			if len(data) == 0 {
+				return nil, provider.ErrNoData
+			}
+			return data, nil
+		},
+	}
+	return p
+}
+
+func TestMarketData_Fetch_StartAfterEnd(t *testing.T) {
+	p := newRangeProvider(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p))
+
+	end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, end.AddDate(0, 0, 1), end)
+	if !errors.Is(err, provider.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+	if p.calls != 0 {
+		t.Errorf("Expected no request, got %d", p.calls)
+	}
+}
+
+func TestMarketData_Fetch_LastNDays(t *testing.T) {
+	p := newRangeProvider(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p), WithRangePolicy(LastNDays(3)))
+
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	now := time.Now().In(types.ExchangeNSE.Location())
+	expected := time.Date(now.Year(), now.Month(), now.Day()-3, 0, 0, 0, 0, now.Location())
+	if !p.start.Equal(expected) || !p.end.IsZero() {
+		t.Errorf("Expected %v up to now, got %v to %v", expected, p.start, p.end)
+	}
+
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, time.Time{}, time.Time{}, WithRangePolicy(LastNDays(0))); !errors.Is(err, provider.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange for LastNDays(0), got %v", err)
+	}
+}
+
+func TestMarketData_Fetch_SinceListing(t *testing.T) {
+	listed := time.Date(2019, 3, 4, 0, 0, 0, 0, time.UTC)
+	p := newRangeProvider(listed)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, time.Time{}, time.Time{}, WithRangePolicy(SinceListing()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) == 0 || !data[0].DateTime.Equal(listed) {
+		t.Errorf("Expected candles from %v, got %d candles", listed, len(data))
+	}
+
+	_, err = md.Fetch(context.Background(), "RELIANCE", types.Interval1m, time.Time{}, time.Time{}, WithRangePolicy(SinceListing()))
+	if !errors.Is(err, provider.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange for intraday candles since listing, got %v", err)
+	}
+}
+
+func TestMarketData_Fetch_TodaySession(t *testing.T) {
+	p := newRangeProvider(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	md := NewMarketData(types.ExchangeBinance, WithProviders(p), WithCalendar(calendar.Continuous(time.UTC)))
+
+	if _, err := md.Fetch(context.Background(), "BTCUSDT", types.Interval1h, time.Time{}, time.Time{}, WithRangePolicy(TodaySession())); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	now := time.Now().UTC()
+	open := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !p.start.Equal(open) || !p.end.Equal(open.AddDate(0, 0, 1)) {
+		t.Errorf("Expected today's session, got %v to %v", p.start, p.end)
+	}
+}
+
+func TestMarketData_Fetch_RangePolicyWithEnd(t *testing.T) {
+	p := newRangeProvider(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p), WithRangePolicy(LastNDays(5)))
+
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, time.Time{}, time.Now())
+	if !errors.Is(err, provider.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
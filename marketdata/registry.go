@@ -0,0 +1,52 @@
+package marketdata
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/shahid-2020/gohlcv/provider"
+)
+
+type registration struct {
+	provider provider.OHLCVProvider
+	priority int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registration
+)
+
+// RegisterProvider adds p to the providers used by every MarketData created
+// afterwards with NewMarketData. Providers are tried in ascending priority
+// order; the built-in upstox and yahoo providers use priorities 100 and 200.
+func RegisterProvider(p provider.OHLCVProvider, priority int) {
+	if p == nil {
+		panic("marketdata: RegisterProvider provider is nil")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry = append(registry, registration{provider: p, priority: priority})
+}
+
+func registered() []registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return slices.Clone(registry)
+}
+
+func sortByPriority(entries []registration) []provider.OHLCVProvider {
+	slices.SortStableFunc(entries, func(a, b registration) int {
+		return a.priority - b.priority
+	})
+
+	providers := make([]provider.OHLCVProvider, 0, len(entries))
+	for _, e := range entries {
+		providers = append(providers, e.provider)
+	}
+
+	return providers
+}
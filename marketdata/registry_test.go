@@ -0,0 +1,72 @@
+package marketdata
+
+import (
+	"testing"
+)
+
+func TestSortByPriority(t *testing.T) {
+	entries := []registration{
+		{provider: &mockProvider{name: "c"}, priority: 300},
+		{provider: &mockProvider{name: "a"}, priority: 100},
+		{provider: &mockProvider{name: "b1"}, priority: 200},
+		{provider: &mockProvider{name: "b2"}, priority: 200},
+	}
+
+	providers := sortByPriority(entries)
+
+	want := []string{"a", "b1", "b2", "c"}
+	if len(providers) != len(want) {
+		t.Fatalf("Expected %d providers, got %d", len(want), len(providers))
+	}
+	for i, name := range want {
+		if providers[i].Name() != name {
+			t.Errorf("Expected provider %d to be %s, got %s", i, name, providers[i].Name())
+		}
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	registryMu.Lock()
+	saved := registry
+	registry = nil
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+
+	RegisterProvider(&mockProvider{name: "custom"}, 150)
+
+	entries := registered()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 registered provider, got %d", len(entries))
+	}
+	if entries[0].provider.Name() != "custom" || entries[0].priority != 150 {
+		t.Errorf("Unexpected registration %+v", entries[0])
+	}
+}
+
+func TestRegisterProvider_NilPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for nil provider")
+		}
+	}()
+
+	RegisterProvider(nil, 0)
+}
+
+func TestWithProviders(t *testing.T) {
+	p1 := &mockProvider{name: "p1"}
+	p2 := &mockProvider{name: "p2"}
+
+	md := NewMarketData("NSE", WithProviders(p2, p1))
+
+	if len(md.providers) != 2 {
+		t.Fatalf("Expected 2 providers, got %d", len(md.providers))
+	}
+	if md.providers[0] != p2 || md.providers[1] != p1 {
+		t.Error("Expected providers in the order given")
+	}
+}
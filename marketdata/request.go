@@ -0,0 +1,67 @@
+package marketdata
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// validateRequest fails a fetch that no provider could serve, before any
+// request is made.
+func validateRequest(symbol string, interval types.Interval) error {
+	if strings.TrimSpace(symbol) == "" {
+		return fmt.Errorf("%w: empty symbol", provider.ErrInvalidSymbol)
+	}
+	if !interval.Valid() {
+		return fmt.Errorf("%w: %q", provider.ErrUnknownInterval, interval)
+	}
+	return nil
+}
+
+// checkChain fails a fetch that no provider of the chain routed for it could
+// serve, before any request is made: one for an interval or exchange every
+// provider reports it doesn't serve, as implementations of
+// provider.IntervalLimiter and provider.ExchangeLimiter, or for a range older
+// than any provider keeps. The chain is previewed for the whole range, and
+// routing errors are left for the fetch to report, since the cache may still
+// serve it.
+func (m *MarketData) checkChain(symbol string, interval types.Interval, start, end time.Time) error {
+	chain, err := m.route(symbol, interval, start, end, true)
+	if err != nil || len(chain) == 0 {
+		return nil
+	}
+
+	if !slices.ContainsFunc(chain, func(p provider.OHLCVProvider) bool { return supportsExchange(p, m.exchange) }) {
+		return fmt.Errorf("%w: no provider serves %s", provider.ErrUnsupportedExchange, m.exchange)
+	}
+	if !slices.ContainsFunc(chain, func(p provider.OHLCVProvider) bool { return m.supportsInterval(p, interval) }) {
+		return fmt.Errorf("%w: no provider serves %s candles", provider.ErrUnknownInterval, interval)
+	}
+	return m.checkHistory(chain, interval, end)
+}
+
+func supportsExchange(p provider.OHLCVProvider, exchange types.Exchange) bool {
+	el, ok := p.(provider.ExchangeLimiter)
+	return !ok || el.SupportsExchange(exchange)
+}
+
+// supportsInterval reports whether p serves interval, or with
+// WithAutoResample an interval it can be resampled from.
+func (m *MarketData) supportsInterval(p provider.OHLCVProvider, interval types.Interval) bool {
+	il, ok := p.(provider.IntervalLimiter)
+	if !ok || il.SupportsInterval(interval) {
+		return true
+	}
+	if !m.autoResample {
+		return false
+	}
+	return slices.ContainsFunc(resampleBases, func(base types.Interval) bool {
+		return ohlcv.CanResample(base, interval) && il.SupportsInterval(base)
+	})
+}
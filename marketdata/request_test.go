@@ -0,0 +1,147 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// historyProvider keeps intraday candles for history.
+type historyProvider struct {
+	mockProvider
+	history time.Duration
+}
+
+func (h *historyProvider) MaxHistory(interval types.Interval) time.Duration {
+	if !interval.IsIntraday() {
+		return 0
+	}
+	return h.history
+}
+
+// capableProvider only serves the intervals and exchanges listed.
+type capableProvider struct {
+	mockProvider
+	intervals []types.Interval
+	exchanges []types.Exchange
+}
+
+func (c *capableProvider) SupportsInterval(interval types.Interval) bool {
+	return slices.Contains(c.intervals, interval)
+}
+
+func (c *capableProvider) SupportsExchange(exchange types.Exchange) bool {
+	return slices.Contains(c.exchanges, exchange)
+}
+
+func TestMarketData_Fetch_RejectsInvalidRequests(t *testing.T) {
+	var calls int
+	counting := &mockProvider{
+		name: "counting",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			calls++
+			return []types.OHLCV{{Symbol: symbol, DateTime: start}}, nil
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(counting))
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, err := md.Fetch(ctx, " ", types.Interval1d, now.AddDate(0, 0, -5), now); !errors.Is(err, provider.ErrInvalidSymbol) {
+		t.Errorf("Expected ErrInvalidSymbol for a blank symbol, got %v", err)
+	}
+	if _, err := md.Fetch(ctx, "RELIANCE", "5x", now.AddDate(0, 0, -5), now); !errors.Is(err, provider.ErrUnknownInterval) {
+		t.Errorf("Expected ErrUnknownInterval, got %v", err)
+	}
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1d, now.Add(time.Hour), time.Time{}); !errors.Is(err, provider.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange for a start in the future, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request, got %d", calls)
+	}
+}
+
+func TestMarketData_Fetch_BeyondHistory(t *testing.T) {
+	var calls int
+	limited := &historyProvider{history: 30 * 24 * time.Hour, mockProvider: mockProvider{
+		name: "limited",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			calls++
+			return []types.OHLCV{{Symbol: symbol, DateTime: start}}, nil
+		},
+	}}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(limited))
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := md.Fetch(ctx, "RELIANCE", types.Interval1m, now.AddDate(0, 0, -90), now.AddDate(0, 0, -60))
+	if !errors.Is(err, provider.ErrInvalidRange) || calls != 0 {
+		t.Errorf("Expected ErrInvalidRange without a request, got %v after %d requests", err, calls)
+	}
+
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1m, now.AddDate(0, 0, -40), now.AddDate(0, 0, -20)); err != nil || calls != 1 {
+		t.Errorf("Expected a range partly within history fetched, got %v after %d requests", err, calls)
+	}
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1d, now.AddDate(-5, 0, 0), now.AddDate(-4, 0, 0)); err != nil {
+		t.Errorf("Expected daily candles to be unlimited, got %v", err)
+	}
+
+	unlimited := &mockProvider{name: "unlimited", provideFunc: limited.provideFunc}
+	md = NewMarketData(types.ExchangeNSE, WithProviders(limited, unlimited))
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1m, now.AddDate(0, 0, -90), now.AddDate(0, 0, -60)); err != nil {
+		t.Errorf("Expected a provider without a limit to be tried, got %v", err)
+	}
+
+	calls = 0
+	md = NewMarketData(types.ExchangeNSE, WithProviders(limited, unlimited), WithRouting(RoutingFunc(
+		func(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider {
+			return providers[:1]
+		})))
+	_, err = md.Fetch(ctx, "RELIANCE", types.Interval1m, now.AddDate(0, 0, -90), now.AddDate(0, 0, -60))
+	if !errors.Is(err, provider.ErrInvalidRange) || calls != 0 {
+		t.Errorf("Expected the routed chain's history to apply, got %v after %d requests", err, calls)
+	}
+}
+
+func TestMarketData_Fetch_UnsupportedByChain(t *testing.T) {
+	var calls int
+	bse := &capableProvider{
+		intervals: []types.Interval{types.Interval1m, types.Interval1d},
+		exchanges: []types.Exchange{types.ExchangeBSE},
+		mockProvider: mockProvider{
+			name: "bse",
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+				calls++
+				return []types.OHLCV{{Symbol: symbol, DateTime: start}}, nil
+			},
+		},
+	}
+	ctx := context.Background()
+	start := time.Now().AddDate(0, 0, -5)
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(bse))
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1d, start, time.Time{}); !errors.Is(err, provider.ErrUnsupportedExchange) {
+		t.Errorf("Expected ErrUnsupportedExchange, got %v", err)
+	}
+
+	md = NewMarketData(types.ExchangeBSE, WithProviders(bse))
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1h, start, time.Time{}); !errors.Is(err, provider.ErrUnknownInterval) {
+		t.Errorf("Expected ErrUnknownInterval, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request, got %d", calls)
+	}
+
+	md = NewMarketData(types.ExchangeBSE, WithProviders(bse), WithAutoResample(true))
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1h, start, time.Time{}); errors.Is(err, provider.ErrUnknownInterval) {
+		t.Errorf("Expected an interval resampled from 1m to be accepted, got %v", err)
+	}
+	if _, err := md.Fetch(ctx, "RELIANCE", types.Interval1d, start, time.Time{}, WithProviderOverride(bse, &mockProvider{name: "any"})); err != nil {
+		t.Errorf("Expected a chain with a capable provider to be tried, got %v", err)
+	}
+}
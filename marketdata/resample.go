@@ -0,0 +1,74 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// resampleBases lists the intervals tried, coarsest first, when a provider
+// doesn't support the requested interval natively.
+var resampleBases = []types.Interval{
+	types.Interval1d,
+	types.Interval1h,
+	types.Interval30m,
+	types.Interval15m,
+	types.Interval5m,
+	types.Interval1m,
+}
+
+// provide fetches from p, falling back to a finer interval resampled into
+// interval when auto-resampling is on and p reports the interval as unknown.
+func (m *MarketData) provide(
+	ctx context.Context,
+	p provider.OHLCVProvider,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) ([]types.OHLCV, error) {
+	data, err := m.provideChunked(ctx, p, symbol, interval, start, end)
+	if err == nil || !m.autoResample || !errors.Is(err, provider.ErrUnknownInterval) {
+		return data, err
+	}
+
+	for _, base := range resampleBases {
+		if !ohlcv.CanResample(base, interval) {
+			continue
+		}
+
+		baseData, baseErr := m.provideChunked(ctx, p, symbol, base, start, end)
+		if errors.Is(baseErr, provider.ErrUnknownInterval) {
+			continue
+		}
+		if baseErr != nil && asPartial(baseErr) == nil {
+			return nil, baseErr
+		}
+
+		data, err := ohlcv.Resample(baseData, base, interval, ohlcv.WithAnchor(m.sessionAnchor()))
+		if err != nil {
+			return nil, err
+		}
+		return data, baseErr
+	}
+
+	return nil, err
+}
+
+// sessionAnchor returns the session open of m's calendar as an offset from
+// midnight in the time zone candles are returned in, so that resampled
+// intraday bars count from the open, as the exchange counts its own.
+func (m *MarketData) sessionAnchor() time.Duration {
+	if m.calendar == nil {
+		return 0
+	}
+
+	open, _ := m.calendar.SessionHours()
+	loc := m.calendar.Location()
+	now := time.Now().In(loc)
+	t := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).Add(open).In(m.timezone())
+	return t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+}
@@ -0,0 +1,112 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestMarketData_Fetch_AutoResample(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 2, 9, 15, 0, 0, loc)
+	end := start.Add(10 * time.Minute)
+
+	var requested []types.Interval
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			requested = append(requested, interval)
+			if interval != types.Interval1m {
+				return nil, fmt.Errorf("%w: %s", provider.ErrUnknownInterval, interval)
+			}
+
+			var data []types.OHLCV
+			for i := range 10 {
+				data = append(data, types.OHLCV{
+					DateTime: start.Add(time.Duration(i) * time.Minute),
+					Open:     100, High: 100 + float64(i), Low: 100, Close: 100, Volume: 1,
+				})
+			}
+			return data, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithAutoResample(true))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval5m, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(data))
+	}
+	if data[0].High != 104 || data[0].Volume != 5 {
+		t.Errorf("Expected high 104 and volume 5, got %v and %d", data[0].High, data[0].Volume)
+	}
+
+	want := []types.Interval{types.Interval5m, types.Interval1m}
+	if fmt.Sprint(requested) != fmt.Sprint(want) {
+		t.Errorf("Expected intervals %v, got %v", want, requested)
+	}
+}
+
+func TestMarketData_Fetch_AutoResampleCustomInterval(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	open := time.Date(2024, 1, 2, 9, 15, 0, 0, loc)
+
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			if interval != types.Interval15m {
+				return nil, fmt.Errorf("%w: %s", provider.ErrUnknownInterval, interval)
+			}
+
+			var data []types.OHLCV
+			for i := range 10 {
+				data = append(data, types.OHLCV{
+					DateTime: open.Add(time.Duration(i) * 15 * time.Minute),
+					Open:     100, High: 101, Low: 99, Close: 100, Volume: 1,
+				})
+			}
+			return data, nil
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock), WithAutoResample(true))
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", "75m", open, open.Add(150*time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(data))
+	}
+	if !data[0].DateTime.Equal(open) || !data[1].DateTime.Equal(open.Add(75*time.Minute)) {
+		t.Errorf("Expected bars counted from the 09:15 open, got %v and %v", data[0].DateTime, data[1].DateTime)
+	}
+	if data[0].Volume != 5 || data[0].Interval != "75m" {
+		t.Errorf("Expected a 75m bar of volume 5, got %q of %d", data[0].Interval, data[0].Volume)
+	}
+}
+
+func TestMarketData_Fetch_AutoResampleDisabled(t *testing.T) {
+	mock := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			return nil, fmt.Errorf("%w: %s", provider.ErrUnknownInterval, interval)
+		},
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(mock))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := md.Fetch(context.Background(), "RELIANCE", types.Interval5m, start, start.Add(time.Hour))
+	if !errors.Is(err, provider.ErrUnknownInterval) {
+		t.Errorf("Expected ErrUnknownInterval, got %v", err)
+	}
+}
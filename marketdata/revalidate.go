@@ -0,0 +1,127 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// defaultRevalidateTimeout bounds a background refresh unless
+// WithRevalidateTimeout is given.
+const defaultRevalidateTimeout = 30 * time.Second
+
+// revalidations tracks the cache keys being refreshed in the background, so
+// that each is refreshed once at a time, and stops them all on close.
+type revalidations struct {
+	// ctx is cancelled by close, cancelling every refresh.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	keys   map[string]struct{}
+	closed bool
+}
+
+func newRevalidations() *revalidations {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &revalidations{ctx: ctx, cancel: cancel, keys: make(map[string]struct{})}
+}
+
+// start reports whether key wasn't being refreshed and refreshes haven't
+// been closed, and marks it as being refreshed.
+func (r *revalidations) start(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.keys[key]; ok || r.closed {
+		return false
+	}
+	r.keys[key] = struct{}{}
+	r.wg.Add(1)
+	return true
+}
+
+func (r *revalidations) done(key string) {
+	r.mu.Lock()
+	delete(r.keys, key)
+	r.mu.Unlock()
+
+	r.wg.Done()
+}
+
+// close cancels the refreshes running, waits for them to return and starts
+// no more.
+func (r *revalidations) close() {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Close stops the refreshes of stale cache entries running in the
+// background and waits for them to return. Fetches still work after Close,
+// serving stale entries without refreshing them.
+func (m *MarketData) Close() error {
+	m.revalidating.close()
+	return nil
+}
+
+// getCached returns key's candles from the cache. Candles a cache.StaleCache
+// reports as stale are returned too, and refreshed in the background.
+func (m *MarketData) getCached(ctx context.Context, key cache.Key) ([]types.OHLCV, bool) {
+	sc, ok := m.cache.(cache.StaleCache)
+	if !ok {
+		return m.cache.Get(key)
+	}
+
+	data, stale, ok := sc.GetStale(key)
+	if ok && stale {
+		m.revalidate(ctx, key)
+	}
+	return data, ok
+}
+
+// revalidate fetches key's range again in the background and caches the
+// result. The refresh outlives ctx, keeping its values, until the revalidate
+// timeout passes or Close is called, and has provider.PriorityLow unless ctx
+// sets another priority.
+func (m *MarketData) revalidate(ctx context.Context, key cache.Key) {
+	id := key.String()
+	if !m.revalidating.start(id) {
+		return
+	}
+
+	timeout := m.revalidateTimeout
+	if timeout <= 0 {
+		timeout = defaultRevalidateTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	stop := context.AfterFunc(m.revalidating.ctx, cancel)
+	if _, ok := provider.PriorityFromContext(ctx); !ok {
+		ctx = provider.WithPriority(ctx, provider.PriorityLow)
+	}
+
+	// The refresh outlives the fetch whose progress the caller follows.
+	bg := *m
+	bg.tracker = nil
+
+	go func() {
+		defer m.revalidating.done(id)
+		defer stop()
+		defer cancel()
+
+		data, err := bg.fetchFromProviders(ctx, key.Symbol, key.Interval, key.From, key.To)
+		if err != nil {
+			m.log().Warn("revalidating cached candles failed", "symbol", key.Symbol, "interval", key.Interval, "error", err)
+			return
+		}
+		m.cache.Set(key, data)
+	}()
+}
@@ -0,0 +1,164 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/cache"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestMarketData_Fetch_StaleWhileRevalidate(t *testing.T) {
+	var (
+		calls    atomic.Int32
+		priority atomic.Value
+	)
+	p := &mockProvider{
+		name: "mock",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			if pr, ok := provider.PriorityFromContext(ctx); ok {
+				priority.Store(pr)
+			}
+			n := calls.Add(1)
+			return []types.OHLCV{{Symbol: symbol, DateTime: start, Close: float64(n), Freshness: types.FreshnessHistorical}}, nil
+		},
+	}
+	lru := cache.NewLRU(10,
+		cache.WithTTLs(cache.TTLs{types.FreshnessHistorical: time.Nanosecond}),
+		cache.WithStaleWhileRevalidate(cache.TTLs{types.FreshnessHistorical: time.Hour}),
+	)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p), WithCache(lru))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, end); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stale, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(stale) != 1 || stale[0].Close != 1 {
+		t.Fatalf("Expected the stale candle served at once, got %v", stale)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if calls.Load() < 2 {
+		t.Fatal("Expected the stale entry to be refreshed in the background")
+	}
+	if pr, _ := priority.Load().(provider.Priority); pr != provider.PriorityLow {
+		t.Errorf("Expected the refresh to have low priority, got %v", pr)
+	}
+
+	for time.Now().Before(deadline) {
+		data, _, _ := lru.GetStale(cache.Key{Symbol: "INFY", Exchange: types.ExchangeNSE, Interval: types.Interval1d, From: start, To: end})
+		if len(data) == 1 && data[0].Close >= 2 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("Expected the refreshed candles to be cached")
+}
+
+func TestRevalidations_Start(t *testing.T) {
+	r := newRevalidations()
+
+	if !r.start("a") {
+		t.Error("Expected the first refresh to start")
+	}
+	if r.start("a") {
+		t.Error("Expected a second refresh of the same key not to start")
+	}
+	r.done("a")
+	if !r.start("a") {
+		t.Error("Expected a refresh to start once the previous one is done")
+	}
+}
+
+// blockingRefreshProvider answers the first request and blocks every later
+// one until its context is done, reporting the context's error on ended.
+func blockingRefreshProvider(ended chan<- error) *mockProvider {
+	var calls atomic.Int32
+	return &mockProvider{
+		name: "blocking",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			if calls.Add(1) == 1 {
+				return []types.OHLCV{{Symbol: symbol, DateTime: start, Freshness: types.FreshnessHistorical}}, nil
+			}
+			<-ctx.Done()
+			ended <- ctx.Err()
+			return nil, ctx.Err()
+		},
+	}
+}
+
+func staleLRU() *cache.LRU {
+	return cache.NewLRU(10,
+		cache.WithTTLs(cache.TTLs{types.FreshnessHistorical: time.Nanosecond}),
+		cache.WithStaleWhileRevalidate(cache.TTLs{types.FreshnessHistorical: time.Hour}),
+	)
+}
+
+func TestMarketData_Revalidate_Timeout(t *testing.T) {
+	ended := make(chan error, 1)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(blockingRefreshProvider(ended)), WithCache(staleLRU()),
+		WithRevalidateTimeout(20*time.Millisecond))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for range 2 {
+		if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	select {
+	case err := <-ended:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the refresh to time out, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a blocked refresh to be given up on")
+	}
+}
+
+func TestMarketData_Close(t *testing.T) {
+	ended := make(chan error, 1)
+	md := NewMarketData(types.ExchangeNSE, WithProviders(blockingRefreshProvider(ended)), WithCache(staleLRU()))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for range 2 {
+		if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		md.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to stop the blocked refresh")
+	}
+	if err := <-ended; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the refresh to be cancelled, got %v", err)
+	}
+
+	if _, err := md.Fetch(context.Background(), "INFY", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+		t.Errorf("Expected stale candles to be served after Close, got %v", err)
+	}
+	if md.revalidating.start("other") {
+		t.Error("Expected no refresh to start after Close")
+	}
+}
@@ -0,0 +1,126 @@
+package marketdata
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Request describes a fetch being routed.
+type Request struct {
+	Symbol   string
+	Exchange types.Exchange
+	Interval types.Interval
+	Start    time.Time
+	End      time.Time
+	// Live is set when the request covers today's session while it is
+	// trading or has traded.
+	Live bool
+	// MinFreshness is the freshness set with WithMinFreshness, if any.
+	// Providers serving staler data are dropped from the routed chain.
+	MinFreshness types.DataFreshness
+}
+
+// RoutingStrategy decides which providers are tried for a request and in
+// what order. Route receives the configured chain and must not modify it.
+type RoutingStrategy interface {
+	Route(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider
+}
+
+// RoutePreviewer is implemented by routing strategies that keep state between
+// requests. Preview returns the chain Route would return for req without
+// changing that state, so that Plan doesn't affect the fetches that follow.
+// Strategies that don't implement it are taken to be stateless.
+type RoutePreviewer interface {
+	Preview(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider
+}
+
+// RoutingFunc adapts a function to RoutingStrategy.
+type RoutingFunc func(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider
+
+func (f RoutingFunc) Route(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider {
+	return f(req, providers)
+}
+
+// PreferFreshness keeps the chain order but skips providers that only serve
+// historical data when the request is live. It is the default strategy.
+func PreferFreshness() RoutingStrategy {
+	return RoutingFunc(func(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider {
+		if !req.Live {
+			return providers
+		}
+
+		return slices.DeleteFunc(slices.Clone(providers), isHistoricalOnly)
+	})
+}
+
+// PreferProvider moves the named providers to the front of the chain in the
+// order given. The remaining providers keep their order.
+func PreferProvider(names ...string) RoutingStrategy {
+	rank := make(map[string]int, len(names))
+	for i, name := range names {
+		rank[name] = i
+	}
+
+	return RoutingFunc(func(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider {
+		ordered := slices.Clone(providers)
+		slices.SortStableFunc(ordered, func(a, b provider.OHLCVProvider) int {
+			return cmp.Compare(rankOf(rank, a.Name(), len(names)), rankOf(rank, b.Name(), len(names)))
+		})
+		return ordered
+	})
+}
+
+// RoundRobin starts each request at the provider after the one the previous
+// request started at, spreading load across the chain.
+func RoundRobin() RoutingStrategy {
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (r *roundRobin) Route(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider {
+	if len(providers) == 0 {
+		return providers
+	}
+	return rotate(providers, r.next.Add(1)-1)
+}
+
+// Preview returns the chain the next request would be routed to.
+func (r *roundRobin) Preview(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider {
+	if len(providers) == 0 {
+		return providers
+	}
+	return rotate(providers, r.next.Load())
+}
+
+// rotate returns providers starting at the n-th, wrapping around.
+func rotate(providers []provider.OHLCVProvider, n uint64) []provider.OHLCVProvider {
+	i := int(n % uint64(len(providers)))
+	return append(slices.Clone(providers[i:]), providers[:i]...)
+}
+
+// CostAware orders providers by cost, cheapest first, keeping the chain order
+// among providers of equal cost. Providers missing from costs are free.
+func CostAware(costs map[string]float64) RoutingStrategy {
+	return RoutingFunc(func(req Request, providers []provider.OHLCVProvider) []provider.OHLCVProvider {
+		ordered := slices.Clone(providers)
+		slices.SortStableFunc(ordered, func(a, b provider.OHLCVProvider) int {
+			return cmp.Compare(costs[a.Name()], costs[b.Name()])
+		})
+		return ordered
+	})
+}
+
+func rankOf(rank map[string]int, name string, fallback int) int {
+	if r, ok := rank[name]; ok {
+		return r
+	}
+	return fallback
+}
@@ -0,0 +1,161 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func names(providers []provider.OHLCVProvider) []string {
+	var out []string
+	for _, p := range providers {
+		out = append(out, p.Name())
+	}
+	return out
+}
+
+func chainOf(ps ...*mockProvider) []provider.OHLCVProvider {
+	var out []provider.OHLCVProvider
+	for _, p := range ps {
+		out = append(out, p)
+	}
+	return out
+}
+
+func TestPreferFreshness(t *testing.T) {
+	chain := chainOf(
+		&mockProvider{name: "upstox", freshness: types.FreshnessHistorical},
+		&mockProvider{name: "yahoo", freshness: types.FreshnessDelayed},
+	)
+
+	if got := names(PreferFreshness().Route(Request{}, chain)); !slices.Equal(got, []string{"upstox", "yahoo"}) {
+		t.Errorf("Expected chain order for historical requests, got %v", got)
+	}
+	if got := names(PreferFreshness().Route(Request{Live: true}, chain)); !slices.Equal(got, []string{"yahoo"}) {
+		t.Errorf("Expected historical-only providers skipped for live requests, got %v", got)
+	}
+	if len(chain) != 2 {
+		t.Error("Expected chain to be left untouched")
+	}
+}
+
+func TestPreferProvider(t *testing.T) {
+	chain := chainOf(&mockProvider{name: "a"}, &mockProvider{name: "b"}, &mockProvider{name: "c"})
+
+	got := names(PreferProvider("c", "b").Route(Request{}, chain))
+	if !slices.Equal(got, []string{"c", "b", "a"}) {
+		t.Errorf("Expected [c b a], got %v", got)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	chain := chainOf(&mockProvider{name: "a"}, &mockProvider{name: "b"}, &mockProvider{name: "c"})
+	rr := RoundRobin()
+
+	want := [][]string{{"a", "b", "c"}, {"b", "c", "a"}, {"c", "a", "b"}, {"a", "b", "c"}}
+	for i, w := range want {
+		if got := names(rr.Route(Request{}, chain)); !slices.Equal(got, w) {
+			t.Errorf("Request %d: expected %v, got %v", i, w, got)
+		}
+	}
+}
+
+func TestRoundRobin_Preview(t *testing.T) {
+	chain := chainOf(&mockProvider{name: "a"}, &mockProvider{name: "b"})
+	rr := RoundRobin()
+	rr.Route(Request{}, chain)
+
+	for range 2 {
+		if got := names(rr.(RoutePreviewer).Preview(Request{}, chain)); !slices.Equal(got, []string{"b", "a"}) {
+			t.Errorf("Expected [b a], got %v", got)
+		}
+	}
+	if got := names(rr.Route(Request{}, chain)); !slices.Equal(got, []string{"b", "a"}) {
+		t.Errorf("Expected the previewed chain to be routed, got %v", got)
+	}
+}
+
+func TestCostAware(t *testing.T) {
+	chain := chainOf(&mockProvider{name: "paid"}, &mockProvider{name: "cheap"}, &mockProvider{name: "free"})
+
+	got := names(CostAware(map[string]float64{"paid": 2, "cheap": 1}).Route(Request{}, chain))
+	if !slices.Equal(got, []string{"free", "cheap", "paid"}) {
+		t.Errorf("Expected [free cheap paid], got %v", got)
+	}
+}
+
+func TestMarketData_Fetch_WithRouting(t *testing.T) {
+	var calls []string
+	mock := func(name string) *mockProvider {
+		return &mockProvider{
+			name: name,
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+				calls = append(calls, name)
+				return []types.OHLCV{{Source: name}}, nil
+			},
+		}
+	}
+
+	md := NewMarketData(types.ExchangeNSE,
+		WithProviders(mock("first"), mock("second")),
+		WithRouting(PreferProvider("second")),
+	)
+
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, loc)
+
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data[0].Source != "second" || !slices.Equal(calls, []string{"second"}) {
+		t.Errorf("Expected only the preferred provider to be called, got %v", calls)
+	}
+}
+
+func TestMarketData_Fetch_WithMinFreshness(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	served := func(name string, freshness types.DataFreshness) *mockProvider {
+		return &mockProvider{
+			name:      name,
+			freshness: freshness,
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+				return []types.OHLCV{{DateTime: day, Close: 1, Source: name}}, nil
+			},
+		}
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(
+		served("historical", types.FreshnessHistorical),
+		served("delayed", types.FreshnessDelayed),
+		served("realtime", types.FreshnessRealtime),
+	))
+
+	tests := []struct {
+		freshness types.DataFreshness
+		source    string
+	}{
+		{"", "historical"},
+		{types.FreshnessEndOfDay, "delayed"},
+		{types.FreshnessDelayed, "delayed"},
+		{types.FreshnessRealtime, "realtime"},
+	}
+	for _, tt := range tests {
+		data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1), WithMinFreshness(tt.freshness))
+		if err != nil {
+			t.Fatalf("Expected no error for %q, got %v", tt.freshness, err)
+		}
+		if len(data) != 1 || data[0].Source != tt.source {
+			t.Errorf("Expected %s data for %q, got %+v", tt.source, tt.freshness, data)
+		}
+	}
+
+	md = NewMarketData(types.ExchangeNSE, WithProviders(served("delayed", types.FreshnessDelayed)), WithMinFreshness(types.FreshnessRealtime))
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1)); !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable without a fresh enough provider, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/storage"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// FetchAndStore fetches the candles of symbol between start and end, as Fetch
+// does, and saves them to store before returning them. Nothing is saved if
+// the fetch fails.
+func (m *MarketData) FetchAndStore(
+	ctx context.Context,
+	store storage.Store,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+	opts ...FetchOption,
+) ([]types.OHLCV, error) {
+	data, err := m.Fetch(ctx, symbol, interval, start, end, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Save(ctx, interval, data); err != nil {
+		return nil, fmt.Errorf("failed to store %s candles: %w", symbol, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,67 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type memoryStore struct {
+	saved    []types.OHLCV
+	interval types.Interval
+	err      error
+}
+
+func (s *memoryStore) Save(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.interval = interval
+	s.saved = append(s.saved, candles...)
+	return nil
+}
+
+func (s *memoryStore) Load(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	return s.saved, nil
+}
+
+func (s *memoryStore) Latest(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval) (time.Time, bool, error) {
+	if len(s.saved) == 0 {
+		return time.Time{}, false, nil
+	}
+	return s.saved[len(s.saved)-1].DateTime, true, nil
+}
+
+func TestMarketData_FetchAndStore(t *testing.T) {
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("mock", &calls)))
+	store := &memoryStore{}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data, err := md.FetchAndStore(context.Background(), store, "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(data) == 0 || len(store.saved) != len(data) {
+		t.Errorf("Expected the %d fetched candles to be stored, got %d", len(data), len(store.saved))
+	}
+	if store.interval != types.Interval1d {
+		t.Errorf("Expected interval 1d, got %s", store.interval)
+	}
+}
+
+func TestMarketData_FetchAndStore_SaveError(t *testing.T) {
+	var calls int
+	md := NewMarketData(types.ExchangeNSE, WithProviders(countingProvider("mock", &calls)))
+	saveErr := errors.New("disk full")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := md.FetchAndStore(context.Background(), &memoryStore{err: saveErr}, "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 3))
+	if !errors.Is(err, saveErr) {
+		t.Errorf("Expected the save error, got %v", err)
+	}
+}
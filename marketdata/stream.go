@@ -0,0 +1,73 @@
+package marketdata
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// streamWindow is how much data FetchStream requests at a time for interval.
+func streamWindow(interval types.Interval) time.Duration {
+	const day = 24 * time.Hour
+
+	switch d := interval.Duration(); {
+	case !interval.IsIntraday():
+		return 5 * 365 * day
+	case d < 5*time.Minute:
+		return 7 * day
+	case d < time.Hour:
+		return 30 * day
+	default:
+		return 90 * day
+	}
+}
+
+// FetchStream is like Fetch but fetches the range one window at a time and
+// yields candles as each window arrives, so only a window's worth of candles
+// is held in memory. Iteration stops after the first error. opts apply to
+// every window.
+func (m *MarketData) FetchStream(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+	opts ...FetchOption,
+) iter.Seq2[types.OHLCV, error] {
+	return func(yield func(types.OHLCV, error) bool) {
+		cm, _, cancel, _ := m.forCall(ctx, opts)
+		cancel()
+
+		loc := cm.timezone()
+		now := time.Now().In(loc)
+
+		if start.IsZero() {
+			start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		}
+		if end.IsZero() {
+			end = now
+		}
+
+		var last time.Time
+		for _, w := range splitRange(start.In(loc), end.In(loc), streamWindow(interval)) {
+			data, err := m.Fetch(ctx, symbol, interval, w.from, w.to, opts...)
+			if err != nil {
+				yield(types.OHLCV{}, err)
+				return
+			}
+
+			for _, c := range data {
+				// Windows share their boundaries, so skip what was already yielded.
+				if !last.IsZero() && !c.DateTime.After(last) {
+					continue
+				}
+				last = c.DateTime
+
+				if !yield(c, nil) {
+					return
+				}
+			}
+		}
+	}
+}
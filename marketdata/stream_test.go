@@ -0,0 +1,94 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func dailyProvider(calls *int) *mockProvider {
+	return &mockProvider{
+		name: "daily",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+			*calls++
+			var data []types.OHLCV
+			for d := from; !d.After(to); d = d.Add(24 * time.Hour) {
+				data = append(data, types.OHLCV{Symbol: symbol, DateTime: d})
+			}
+			return data, nil
+		},
+	}
+}
+
+func TestMarketData_FetchStream(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 20)
+
+	calls := 0
+	md := NewMarketData(types.ExchangeNSE, WithProviders(dailyProvider(&calls)))
+
+	var got []types.OHLCV
+	for c, err := range md.FetchStream(context.Background(), "INFY", types.Interval1m, start, end) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		got = append(got, c)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected the range to be fetched in 3 windows, got %d", calls)
+	}
+	if len(got) != 21 {
+		t.Fatalf("Expected 21 candles without duplicates, got %d", len(got))
+	}
+	for i, c := range got {
+		if !c.DateTime.Equal(start.AddDate(0, 0, i)) {
+			t.Errorf("Expected candle %d at %v, got %v", i, start.AddDate(0, 0, i), c.DateTime)
+		}
+	}
+}
+
+func TestMarketData_FetchStream_EarlyBreak(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+
+	calls := 0
+	md := NewMarketData(types.ExchangeNSE, WithProviders(dailyProvider(&calls)))
+
+	n := 0
+	for range md.FetchStream(context.Background(), "INFY", types.Interval1m, start, start.AddDate(0, 0, 30)) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected fetching to stop after the first window, got %d calls", calls)
+	}
+}
+
+func TestMarketData_FetchStream_Error(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{
+		name: "failing",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+			return nil, errors.New("boom")
+		},
+	}))
+
+	var errs int
+	for _, err := range md.FetchStream(context.Background(), "INFY", types.Interval1d, time.Now().AddDate(-1, 0, 0), time.Time{}) {
+		if err == nil {
+			t.Error("Expected only an error to be yielded")
+		}
+		errs++
+	}
+
+	if errs != 1 {
+		t.Errorf("Expected exactly one error, got %d", errs)
+	}
+}
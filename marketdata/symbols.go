@@ -0,0 +1,52 @@
+package marketdata
+
+import "maps"
+
+// AllProviders is the provider name under which a SymbolMap entry applies to
+// every provider without an entry of its own.
+const AllProviders = "*"
+
+// SymbolMap maps the symbols passed to Fetch to the symbols providers know
+// them by, keyed by symbol and then by provider name. Provider symbols are
+// passed to the provider's Provide as they are: the built-in providers also
+// accept their native identifiers, a Yahoo ticker with its suffix, an Upstox
+// instrument key or a Kite instrument token. Returned candles and quotes
+// carry the symbol passed to Fetch.
+type SymbolMap map[string]map[string]string
+
+// DefaultSymbolMap holds the aliases every MarketData starts with, for
+// tickers that were renamed but are still widely used under their old name.
+var DefaultSymbolMap = SymbolMap{
+	"L&TFH": {AllProviders: "LTF"},
+}
+
+// resolve returns the symbol the provider named name knows symbol by.
+func (s SymbolMap) resolve(symbol, name string) string {
+	aliases, ok := s[symbol]
+	if !ok {
+		return symbol
+	}
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	if alias, ok := aliases[AllProviders]; ok {
+		return alias
+	}
+	return symbol
+}
+
+// merge returns a copy of s with the entries of other added, other's provider
+// symbols taking precedence.
+func (s SymbolMap) merge(other SymbolMap) SymbolMap {
+	merged := make(SymbolMap, len(s)+len(other))
+	for symbol, aliases := range s {
+		merged[symbol] = maps.Clone(aliases)
+	}
+	for symbol, aliases := range other {
+		if merged[symbol] == nil {
+			merged[symbol] = make(map[string]string, len(aliases))
+		}
+		maps.Copy(merged[symbol], aliases)
+	}
+	return merged
+}
@@ -0,0 +1,119 @@
+package marketdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// symbolProvider records the symbol it was asked for and serves one daily
+// candle labelled with it, failing for symbols other than known.
+func symbolProvider(name, known string, asked *string) *mockProvider {
+	return &mockProvider{
+		name: name,
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			*asked = symbol
+			if symbol != known {
+				return nil, provider.ErrSymbolNotFound
+			}
+			return []types.OHLCV{{Symbol: symbol, Close: 100, DateTime: start, Freshness: types.FreshnessHistorical}}, nil
+		},
+	}
+}
+
+func TestSymbolMap_Resolve(t *testing.T) {
+	symbols := SymbolMap{
+		"M&M": {"yahoo": "M&M.NS", AllProviders: "MM"},
+	}
+
+	tests := []struct {
+		symbol   string
+		provider string
+		want     string
+	}{
+		{"M&M", "yahoo", "M&M.NS"},
+		{"M&M", "upstox", "MM"},
+		{"RELIANCE", "yahoo", "RELIANCE"},
+	}
+
+	for _, tt := range tests {
+		if got := symbols.resolve(tt.symbol, tt.provider); got != tt.want {
+			t.Errorf("Expected %s for %s on %s, got %s", tt.want, tt.symbol, tt.provider, got)
+		}
+	}
+}
+
+func TestSymbolMap_Merge(t *testing.T) {
+	base := SymbolMap{"L&TFH": {AllProviders: "LTF"}}
+	merged := base.merge(SymbolMap{"L&TFH": {"kite": "L&TFH"}, "M&M": {"yahoo": "M&M.NS"}})
+
+	if merged.resolve("L&TFH", "kite") != "L&TFH" || merged.resolve("L&TFH", "yahoo") != "LTF" {
+		t.Errorf("Expected overrides merged per provider, got %v", merged)
+	}
+	if merged.resolve("M&M", "yahoo") != "M&M.NS" {
+		t.Errorf("Expected new symbol added, got %v", merged)
+	}
+	if _, ok := base["L&TFH"]["kite"]; ok {
+		t.Error("Expected merge not to modify the base map")
+	}
+}
+
+func TestMarketData_Fetch_SymbolMap(t *testing.T) {
+	var yahooAsked, upstoxAsked string
+	upstox := symbolProvider("upstox", "NSE_EQ|INE101A01026", &upstoxAsked)
+	yahoo := symbolProvider("yahoo", "M&M.NS", &yahooAsked)
+
+	md := NewMarketData(types.ExchangeNSE,
+		WithProviders(upstox, yahoo),
+		WithSymbolMap(SymbolMap{"M&M": {"yahoo": "M&M.NS", "upstox": "NSE_EQ|INE101A01026"}}),
+	)
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, types.ExchangeNSE.Location())
+	data, err := md.Fetch(context.Background(), "M&M", types.Interval1d, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if upstoxAsked != "NSE_EQ|INE101A01026" {
+		t.Errorf("Expected upstox to be asked for its instrument key, got %s", upstoxAsked)
+	}
+	if yahooAsked != "" {
+		t.Errorf("Expected yahoo not to be asked, got %s", yahooAsked)
+	}
+	if len(data) != 1 || data[0].Symbol != "M&M" {
+		t.Errorf("Expected candles labelled with the requested symbol, got %+v", data)
+	}
+}
+
+func TestMarketData_Fetch_DefaultSymbolMap(t *testing.T) {
+	var asked string
+	md := NewMarketData(types.ExchangeNSE, WithProviders(symbolProvider("yahoo", "LTF", &asked)))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, types.ExchangeNSE.Location())
+	data, err := md.Fetch(context.Background(), "L&TFH", types.Interval1d, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asked != "LTF" || data[0].Symbol != "L&TFH" {
+		t.Errorf("Expected L&TFH to resolve to LTF, asked %s, got %+v", asked, data)
+	}
+}
+
+func TestMarketData_Quote_SymbolMap(t *testing.T) {
+	qp := &quoteProvider{mockProvider: mockProvider{name: "yahoo"}, quote: types.Quote{Symbol: "BAJAJ-AUTO.NS", LastPrice: 9000}}
+	md := NewMarketData(types.ExchangeNSE,
+		WithProviders(qp),
+		WithSymbolMap(SymbolMap{"BAJAJ-AUTO": {"yahoo": "BAJAJ-AUTO.NS"}}),
+	)
+
+	q, err := md.Quote(context.Background(), "BAJAJ-AUTO")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if q.Symbol != "BAJAJ-AUTO" {
+		t.Errorf("Expected quote labelled BAJAJ-AUTO, got %s", q.Symbol)
+	}
+}
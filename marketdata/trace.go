@@ -0,0 +1,87 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var noopTracer = noop.NewTracerProvider().Tracer(httpclient.TracerName)
+
+func (m *MarketData) tracer() trace.Tracer {
+	if m.tracerProvider != nil {
+		return m.tracerProvider.Tracer(httpclient.TracerName)
+	}
+	return noopTracer
+}
+
+// tracedProvide calls provide inside a span recording which provider of the
+// chain was tried and at which attempt. A positive timeout bounds the call;
+// running out of it counts against the provider's health, unlike ctx ending.
+func (m *MarketData) tracedProvide(
+	ctx context.Context,
+	p provider.OHLCVProvider,
+	attempt int,
+	timeout time.Duration,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+) ([]types.OHLCV, error) {
+	ctx, span := m.tracer().Start(ctx, "Provider.Provide", trace.WithAttributes(
+		attribute.String("gohlcv.provider", p.Name()),
+		attribute.String("gohlcv.symbol", symbol),
+		attribute.String("gohlcv.interval", string(interval)),
+		attribute.Int("gohlcv.attempt", attempt),
+	))
+	defer span.End()
+
+	provideCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		provideCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	data, err := m.provide(provideCtx, p, symbol, interval, start, end)
+	if err != nil && provideCtx.Err() != nil && ctx.Err() == nil {
+		err = fmt.Errorf("gave up after %v: %w", timeout, err)
+	}
+	m.health.record(ctx, p, err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if asPartial(err) == nil {
+			return nil, err
+		}
+	}
+
+	span.SetAttributes(attribute.Int("gohlcv.candles", len(data)))
+	return withInterval(data, interval), err
+}
+
+// withInterval returns data with the Interval of candles that don't carry
+// one set to interval. data is copied first if any is changed, since
+// providers may hand out candles they keep.
+func withInterval(data []types.OHLCV, interval types.Interval) []types.OHLCV {
+	i := slices.IndexFunc(data, func(c types.OHLCV) bool { return c.Interval == "" })
+	if i < 0 {
+		return data
+	}
+
+	data = slices.Clone(data)
+	for ; i < len(data); i++ {
+		if data[i].Interval == "" {
+			data[i].Interval = interval
+		}
+	}
+	return data
+}
@@ -0,0 +1,128 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMarketData_Fetch_Traces(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var providerSpan trace.SpanContext
+	failing := &mockProvider{name: "failing", provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+		return nil, provider.ErrRateLimited
+	}}
+	serving := &mockProvider{name: "serving", provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+		providerSpan = trace.SpanContextFromContext(ctx)
+		return []types.OHLCV{{Symbol: symbol, DateTime: start}}, nil
+	}}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing, serving), WithTracerProvider(tp))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+
+	fetch := spans[2]
+	if fetch.Name() != "MarketData.Fetch" {
+		t.Fatalf("Expected MarketData.Fetch to end last, got %s", fetch.Name())
+	}
+	if v := spanAttr(fetch, "gohlcv.symbol"); v.AsString() != "RELIANCE" {
+		t.Errorf("Expected symbol RELIANCE, got %s", v.AsString())
+	}
+	if v := spanAttr(fetch, "gohlcv.interval"); v.AsString() != "1d" {
+		t.Errorf("Expected interval 1d, got %s", v.AsString())
+	}
+
+	for i, want := range []string{"failing", "serving"} {
+		span := spans[i]
+		if span.Parent().SpanID() != fetch.SpanContext().SpanID() {
+			t.Errorf("Expected %s span to be a child of Fetch", want)
+		}
+		if v := spanAttr(span, "gohlcv.provider"); v.AsString() != want {
+			t.Errorf("Expected provider %s, got %s", want, v.AsString())
+		}
+		if v := spanAttr(span, "gohlcv.attempt"); v.AsInt64() != int64(i+1) {
+			t.Errorf("Expected attempt %d, got %d", i+1, v.AsInt64())
+		}
+	}
+
+	if spans[0].Status().Code != codes.Error {
+		t.Error("Expected failing provider span to be marked as error")
+	}
+	if providerSpan.SpanID() != spans[1].SpanContext().SpanID() {
+		t.Error("Expected provider to be called with its span in the context")
+	}
+}
+
+func TestMarketData_Fetch_TracesError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	failing := &mockProvider{name: "failing", provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+		return nil, provider.ErrSymbolNotFound
+	}}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(failing), WithTracerProvider(tp))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := md.Fetch(context.Background(), "UNKNOWN", types.Interval1d, start, start.AddDate(0, 0, 1))
+	if !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Fatalf("Expected ErrSymbolNotFound, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if fetch := spans[len(spans)-1]; fetch.Status().Code != codes.Error {
+		t.Errorf("Expected Fetch span to be marked as error, got %v", fetch.Status().Code)
+	}
+}
+
+func TestMarketData_Fetch_SetsInterval(t *testing.T) {
+	kept := []types.OHLCV{
+		{Symbol: "RELIANCE", DateTime: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Symbol: "RELIANCE", DateTime: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Interval: types.Interval1d},
+	}
+	p := &mockProvider{name: "mock", provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+		return kept, nil
+	}}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(p))
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, kept[0].DateTime, kept[1].DateTime.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, c := range data {
+		if c.Interval != types.Interval1d {
+			t.Errorf("Expected every candle marked 1d, got %q", c.Interval)
+		}
+	}
+	if kept[0].Interval != "" {
+		t.Error("Expected the provider's candles not to be modified")
+	}
+}
@@ -0,0 +1,50 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Verify fetches the same range from the providers named a and b, bypassing
+// the cache, and compares the results with ohlcv.Compare so the data can be
+// audited before it is relied on. Discrepancies are measured relative to a.
+func (m *MarketData) Verify(
+	ctx context.Context,
+	symbol string,
+	interval types.Interval,
+	start, end time.Time,
+	a, b string,
+	tol ohlcv.Tolerance,
+	opts ...FetchOption,
+) (ohlcv.ComparisonReport, error) {
+	var series [2][]types.OHLCV
+	for i, name := range []string{a, b} {
+		p := m.providerNamed(name)
+		if p == nil {
+			return ohlcv.ComparisonReport{}, fmt.Errorf("%w: no provider named %q", provider.ErrProviderUnavailable, name)
+		}
+
+		data, err := m.Fetch(ctx, symbol, interval, start, end, append(slices.Clone(opts), WithProviderOverride(p), WithNoCache())...)
+		if err != nil {
+			return ohlcv.ComparisonReport{}, fmt.Errorf("%s: %w", name, err)
+		}
+		series[i] = data
+	}
+
+	return ohlcv.Compare(series[0], series[1], tol), nil
+}
+
+func (m *MarketData) providerNamed(name string) provider.OHLCVProvider {
+	for _, p := range m.providers {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestMarketData_Verify(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	closing := func(name string, close float64) *mockProvider {
+		return &mockProvider{
+			name: name,
+			provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+				return []types.OHLCV{{DateTime: day, Open: 100, High: 110, Low: 90, Close: close, Volume: 1000}}, nil
+			},
+		}
+	}
+
+	md := NewMarketData(types.ExchangeNSE, WithProviders(closing("upstox", 105), closing("yahoo", 106), closing("other", 200)))
+
+	report, err := md.Verify(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1), "upstox", "yahoo", ohlcv.Tolerance{Price: 0.5})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Matched != 1 || len(report.Discrepancies) != 1 {
+		t.Fatalf("Expected 1 matched candle with 1 discrepancy, got %+v", report)
+	}
+	if d := report.Discrepancies[0]; d.Field != ohlcv.FieldClose || d.A != 105 || d.B != 106 {
+		t.Errorf("Unexpected discrepancy: %+v", d)
+	}
+
+	report, err = md.Verify(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1), "upstox", "yahoo", ohlcv.Tolerance{Price: 1})
+	if err != nil || !report.Consistent() {
+		t.Errorf("Expected consistent report within 1%%, got %+v, %v", report, err)
+	}
+}
+
+func TestMarketData_Verify_UnknownProvider(t *testing.T) {
+	md := NewMarketData(types.ExchangeNSE, WithProviders(&mockProvider{name: "upstox"}))
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := md.Verify(context.Background(), "RELIANCE", types.Interval1d, day, day.AddDate(0, 0, 1), "upstox", "missing", ohlcv.Tolerance{})
+	if !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Watchlist is an ordered set of symbols, such as the fixed universe of
+// stocks an app tracks. It is safe for concurrent use.
+type Watchlist struct {
+	mu      sync.RWMutex
+	symbols []string
+}
+
+// NewWatchlist creates a Watchlist holding symbols.
+func NewWatchlist(symbols ...string) *Watchlist {
+	w := &Watchlist{}
+	w.Add(symbols...)
+	return w
+}
+
+// LoadWatchlist reads a Watchlist saved with Save.
+func LoadWatchlist(path string) (*Watchlist, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchlist: %w", err)
+	}
+
+	var symbols []string
+	if err := json.Unmarshal(b, &symbols); err != nil {
+		return nil, fmt.Errorf("failed to parse watchlist %s: %w", path, err)
+	}
+	return NewWatchlist(symbols...), nil
+}
+
+// Add appends the symbols not already in w.
+func (w *Watchlist) Add(symbols ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, s := range symbols {
+		if s != "" && !slices.Contains(w.symbols, s) {
+			w.symbols = append(w.symbols, s)
+		}
+	}
+}
+
+// Remove removes symbols from w.
+func (w *Watchlist) Remove(symbols ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.symbols = slices.DeleteFunc(w.symbols, func(s string) bool {
+		return slices.Contains(symbols, s)
+	})
+}
+
+// Contains reports whether symbol is in w.
+func (w *Watchlist) Contains(symbol string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return slices.Contains(w.symbols, symbol)
+}
+
+// Symbols returns the symbols of w in the order they were added.
+func (w *Watchlist) Symbols() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return slices.Clone(w.symbols)
+}
+
+// Len returns how many symbols w holds.
+func (w *Watchlist) Len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return len(w.symbols)
+}
+
+// Save writes w to path as a JSON array of symbols. The file is replaced
+// atomically, so a crash never leaves it half-written.
+func (w *Watchlist) Save(path string) error {
+	b, err := json.MarshalIndent(w.Symbols(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watchlist: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	return nil
+}
+
+// RefreshWatchlist fetches the n most recent candles of every symbol in w, as
+// FetchLast does, on the worker pool of FetchMany: the workers share the
+// providers and their rate limiters, and the result and errors are those of
+// FetchMany.
+func (m *MarketData) RefreshWatchlist(
+	ctx context.Context,
+	w *Watchlist,
+	interval types.Interval,
+	n int,
+	opts ...FetchOption,
+) (map[string][]types.OHLCV, error) {
+	return m.fetchEach(w.Symbols(), func(symbol string) ([]types.OHLCV, error) {
+		return m.FetchLast(ctx, symbol, interval, n, opts...)
+	})
+}
@@ -0,0 +1,80 @@
+package marketdata
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestWatchlist_AddRemove(t *testing.T) {
+	w := NewWatchlist("INFY", "TCS", "INFY")
+	w.Add("RELIANCE", "TCS", "")
+	w.Remove("TCS", "HDFC")
+
+	if got := w.Symbols(); !slices.Equal(got, []string{"INFY", "RELIANCE"}) {
+		t.Errorf("Expected [INFY RELIANCE], got %v", got)
+	}
+	if !w.Contains("INFY") || w.Contains("TCS") {
+		t.Error("Expected INFY to be watched and TCS not")
+	}
+	if w.Len() != 2 {
+		t.Errorf("Expected 2 symbols, got %d", w.Len())
+	}
+}
+
+func TestWatchlist_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	if err := NewWatchlist("INFY", "TCS").Save(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	w, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := w.Symbols(); !slices.Equal(got, []string{"INFY", "TCS"}) {
+		t.Errorf("Expected [INFY TCS], got %v", got)
+	}
+
+	if _, err := LoadWatchlist(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestMarketData_RefreshWatchlist(t *testing.T) {
+	var calls atomic.Int32
+	daily := &mockProvider{
+		name: "daily",
+		provideFunc: func(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+			calls.Add(1)
+			var data []types.OHLCV
+			for t := start; t.Before(end); t = t.AddDate(0, 0, 1) {
+				data = append(data, types.OHLCV{Symbol: symbol, DateTime: t, Freshness: types.FreshnessHistorical})
+			}
+			return data, nil
+		},
+	}
+	md := NewMarketData(types.ExchangeNSE, WithProviders(daily))
+
+	results, err := md.RefreshWatchlist(context.Background(), NewWatchlist("INFY", "TCS", "RELIANCE"), types.Interval1d, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 symbols, got %d", len(results))
+	}
+	for symbol, data := range results {
+		if len(data) != 2 {
+			t.Errorf("Expected 2 candles for %s, got %d", symbol, len(data))
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected one fetch per symbol, got %d", calls.Load())
+	}
+}
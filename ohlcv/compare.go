@@ -0,0 +1,132 @@
+package ohlcv
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Field names a numeric field of a candle.
+type Field string
+
+const (
+	FieldOpen   Field = "open"
+	FieldHigh   Field = "high"
+	FieldLow    Field = "low"
+	FieldClose  Field = "close"
+	FieldVolume Field = "volume"
+)
+
+// Tolerance is the largest difference, in percent, that Compare accepts
+// between two candles' prices and volumes. The zero Tolerance reports every
+// difference.
+type Tolerance struct {
+	Price  float64
+	Volume float64
+}
+
+// Discrepancy is a field of the candles at DateTime whose values in the two
+// series differ by more than the tolerance. Diff is the difference in percent
+// of A.
+type Discrepancy struct {
+	DateTime time.Time
+	Field    Field
+	A, B     float64
+	Diff     float64
+}
+
+// ComparisonReport is the result of comparing two series.
+type ComparisonReport struct {
+	// Matched is the number of timestamps present in both series.
+	Matched       int
+	Discrepancies []Discrepancy
+	// OnlyInA and OnlyInB list timestamps missing from the other series.
+	OnlyInA []time.Time
+	OnlyInB []time.Time
+}
+
+// Consistent reports whether the series hold the same timestamps and no
+// discrepancies were found.
+func (r ComparisonReport) Consistent() bool {
+	return len(r.Discrepancies) == 0 && len(r.OnlyInA) == 0 && len(r.OnlyInB) == 0
+}
+
+// Compare matches the candles of a and b by timestamp and reports every
+// price or volume that differs by more than tol, along with the timestamps
+// only one of them has. Results are ordered by time.
+func Compare(a, b []types.OHLCV, tol Tolerance) ComparisonReport {
+	byTime := make(map[int64]types.OHLCV, len(b))
+	for _, c := range b {
+		byTime[c.DateTime.UnixNano()] = c
+	}
+
+	var report ComparisonReport
+	seen := make(map[int64]bool, len(a))
+	for _, ca := range sortedByTime(a) {
+		key := ca.DateTime.UnixNano()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		cb, ok := byTime[key]
+		if !ok {
+			report.OnlyInA = append(report.OnlyInA, ca.DateTime)
+			continue
+		}
+		report.Matched++
+
+		fields := []struct {
+			field Field
+			a, b  float64
+			tol   float64
+		}{
+			{FieldOpen, ca.Open, cb.Open, tol.Price},
+			{FieldHigh, ca.High, cb.High, tol.Price},
+			{FieldLow, ca.Low, cb.Low, tol.Price},
+			{FieldClose, ca.Close, cb.Close, tol.Price},
+			{FieldVolume, float64(ca.Volume), float64(cb.Volume), tol.Volume},
+		}
+		for _, f := range fields {
+			if diff := percentDiff(f.a, f.b); diff > f.tol {
+				report.Discrepancies = append(report.Discrepancies, Discrepancy{
+					DateTime: ca.DateTime,
+					Field:    f.field,
+					A:        f.a,
+					B:        f.b,
+					Diff:     diff,
+				})
+			}
+		}
+	}
+
+	for _, cb := range sortedByTime(b) {
+		if key := cb.DateTime.UnixNano(); !seen[key] {
+			seen[key] = true
+			report.OnlyInB = append(report.OnlyInB, cb.DateTime)
+		}
+	}
+
+	return report
+}
+
+// percentDiff returns the difference between a and b in percent of a.
+func percentDiff(a, b float64) float64 {
+	if a == b {
+		return 0
+	}
+	if a == 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(b-a) / math.Abs(a) * 100
+}
+
+func sortedByTime(candles []types.OHLCV) []types.OHLCV {
+	sorted := slices.Clone(candles)
+	slices.SortStableFunc(sorted, func(a, b types.OHLCV) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+	return sorted
+}
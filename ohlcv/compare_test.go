@@ -0,0 +1,61 @@
+package ohlcv
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestCompare(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	at := func(d int) time.Time { return day.AddDate(0, 0, d) }
+
+	a := []types.OHLCV{
+		candle(at(0), 100, 110, 90, 105, 1000),
+		candle(at(1), 105, 115, 100, 110, 1000),
+		candle(at(2), 110, 120, 105, 115, 1000),
+	}
+	b := []types.OHLCV{
+		candle(at(1), 105, 115, 100, 112, 1040),
+		candle(at(0), 100.05, 110, 90, 105, 1000),
+		candle(at(3), 115, 125, 110, 120, 1000),
+	}
+
+	report := Compare(a, b, Tolerance{Price: 0.1, Volume: 5})
+
+	if report.Consistent() {
+		t.Error("Expected report to be inconsistent")
+	}
+	if report.Matched != 2 {
+		t.Errorf("Expected 2 matched candles, got %d", report.Matched)
+	}
+	if len(report.Discrepancies) != 1 {
+		t.Fatalf("Expected 1 discrepancy, got %v", report.Discrepancies)
+	}
+
+	d := report.Discrepancies[0]
+	if !d.DateTime.Equal(at(1)) || d.Field != FieldClose || d.A != 110 || d.B != 112 {
+		t.Errorf("Unexpected discrepancy: %+v", d)
+	}
+	if math.Abs(d.Diff-1.818) > 0.001 {
+		t.Errorf("Expected diff of about 1.818%%, got %v", d.Diff)
+	}
+
+	if len(report.OnlyInA) != 1 || !report.OnlyInA[0].Equal(at(2)) {
+		t.Errorf("Expected %v only in a, got %v", at(2), report.OnlyInA)
+	}
+	if len(report.OnlyInB) != 1 || !report.OnlyInB[0].Equal(at(3)) {
+		t.Errorf("Expected %v only in b, got %v", at(3), report.OnlyInB)
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	a := []types.OHLCV{candle(time.Date(2024, 1, 2, 0, 0, 0, 0, ist), 100, 110, 90, 105, 0)}
+
+	report := Compare(a, a, Tolerance{})
+	if !report.Consistent() || report.Matched != 1 {
+		t.Errorf("Expected identical series to be consistent, got %+v", report)
+	}
+}
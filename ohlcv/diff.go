@@ -0,0 +1,59 @@
+package ohlcv
+
+import (
+	"slices"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Diff returns the candles of fetched that stored lacks: those at a timestamp
+// stored has no candle for, and those whose prices, volume or adjustment
+// factor differ from the stored candle's, as when a provider corrects a bar.
+// Candles are matched by timestamp and returned in fetched's order.
+func Diff(stored, fetched []types.OHLCV) []types.OHLCV {
+	byTime := make(map[int64]types.OHLCV, len(stored))
+	for _, c := range stored {
+		byTime[c.DateTime.UnixNano()] = c
+	}
+
+	var changes []types.OHLCV
+	for _, c := range fetched {
+		if s, ok := byTime[c.DateTime.UnixNano()]; ok && sameValues(s, c) {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// Patch returns stored with changes applied: each candle of changes replaces
+// the stored candle at its timestamp or is added. The result is sorted by
+// time; stored is not modified.
+func Patch(stored, changes []types.OHLCV) []types.OHLCV {
+	index := make(map[int64]int, len(stored))
+	patched := slices.Clone(stored)
+	for i, c := range patched {
+		index[c.DateTime.UnixNano()] = i
+	}
+
+	for _, c := range changes {
+		key := c.DateTime.UnixNano()
+		if i, ok := index[key]; ok {
+			patched[i] = c
+			continue
+		}
+		index[key] = len(patched)
+		patched = append(patched, c)
+	}
+
+	slices.SortStableFunc(patched, func(a, b types.OHLCV) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+	return patched
+}
+
+func sameValues(a, b types.OHLCV) bool {
+	return a.Open == b.Open && a.High == b.High && a.Low == b.Low && a.Close == b.Close &&
+		a.Volume == b.Volume && a.OpenInterest == b.OpenInterest && a.TradeCount == b.TradeCount &&
+		a.AdjustmentFactor == b.AdjustmentFactor
+}
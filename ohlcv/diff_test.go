@@ -0,0 +1,85 @@
+package ohlcv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestDiff(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	at := func(d int) time.Time { return day.AddDate(0, 0, d) }
+
+	stored := []types.OHLCV{
+		candle(at(0), 100, 110, 90, 105, 1000),
+		candle(at(1), 105, 115, 100, 110, 1000),
+		candle(at(2), 110, 120, 105, 115, 800),
+	}
+	fetched := []types.OHLCV{
+		candle(at(1), 105, 115, 100, 110, 1000),
+		candle(at(2), 110, 120, 105, 116, 1000),
+		candle(at(3), 115, 125, 110, 120, 1000),
+	}
+
+	changes := Diff(stored, fetched)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", changes)
+	}
+	if !changes[0].DateTime.Equal(at(2)) || changes[0].Close != 116 {
+		t.Errorf("Expected the corrected candle first, got %+v", changes[0])
+	}
+	if !changes[1].DateTime.Equal(at(3)) {
+		t.Errorf("Expected the new candle second, got %+v", changes[1])
+	}
+
+	if changes := Diff(stored, stored); len(changes) != 0 {
+		t.Errorf("Expected no changes against itself, got %+v", changes)
+	}
+}
+
+func TestDiff_AdjustmentFactor(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	stored := []types.OHLCV{candle(day, 100, 110, 90, 105, 1000)}
+	fetched := []types.OHLCV{candle(day, 100, 110, 90, 105, 1000)}
+	fetched[0].AdjustmentFactor = 0.5
+
+	if changes := Diff(stored, fetched); len(changes) != 1 {
+		t.Errorf("Expected a changed adjustment factor to count, got %+v", changes)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	at := func(d int) time.Time { return day.AddDate(0, 0, d) }
+
+	stored := []types.OHLCV{
+		candle(at(0), 100, 110, 90, 105, 1000),
+		candle(at(2), 110, 120, 105, 115, 800),
+	}
+	changes := []types.OHLCV{
+		candle(at(3), 115, 125, 110, 120, 1000),
+		candle(at(2), 110, 120, 105, 116, 1000),
+		candle(at(1), 105, 115, 100, 110, 1000),
+	}
+
+	patched := Patch(stored, changes)
+	if len(patched) != 4 {
+		t.Fatalf("Expected 4 candles, got %+v", patched)
+	}
+	for i, c := range patched {
+		if !c.DateTime.Equal(at(i)) {
+			t.Errorf("Expected candle %d at %v, got %v", i, at(i), c.DateTime)
+		}
+	}
+	if patched[2].Close != 116 {
+		t.Errorf("Expected the stored candle replaced, got %+v", patched[2])
+	}
+	if stored[1].Close != 115 || len(stored) != 2 {
+		t.Error("Expected stored not to be modified")
+	}
+
+	if got := Diff(patched, Patch(patched, Diff(patched, changes))); len(got) != 0 {
+		t.Errorf("Expected a patched series to have no diff, got %+v", got)
+	}
+}
@@ -0,0 +1,107 @@
+package ohlcv
+
+import (
+	"math"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Transformer is one step of a normalization pipeline. Transform may modify
+// candles in place and returns the transformed slice.
+type Transformer interface {
+	Transform(candles []types.OHLCV) []types.OHLCV
+}
+
+// TransformerFunc adapts a function to a Transformer.
+type TransformerFunc func(candles []types.OHLCV) []types.OHLCV
+
+func (f TransformerFunc) Transform(candles []types.OHLCV) []types.OHLCV {
+	return f(candles)
+}
+
+// Pipeline is a Transformer running its steps in order.
+type Pipeline []Transformer
+
+func (p Pipeline) Transform(candles []types.OHLCV) []types.OHLCV {
+	for _, step := range p {
+		candles = step.Transform(candles)
+	}
+	return candles
+}
+
+// Round rounds open, high, low and close to precision.
+func Round(precision provider.Precision) Transformer {
+	return TransformerFunc(func(candles []types.OHLCV) []types.OHLCV {
+		for i := range candles {
+			c := &candles[i]
+			c.Open = precision.Round(c.Open)
+			c.High = precision.Round(c.High)
+			c.Low = precision.Round(c.Low)
+			c.Close = precision.Round(c.Close)
+		}
+		return candles
+	})
+}
+
+// InLocation converts every DateTime to loc.
+func InLocation(loc *time.Location) Transformer {
+	return TransformerFunc(func(candles []types.OHLCV) []types.OHLCV {
+		for i := range candles {
+			candles[i].DateTime = candles[i].DateTime.In(loc)
+		}
+		return candles
+	})
+}
+
+// DropNull removes candles with a missing price: NaN, or the zero a provider
+// decoded a null price to.
+func DropNull() Transformer {
+	return TransformerFunc(func(candles []types.OHLCV) []types.OHLCV {
+		out := candles[:0]
+		for _, c := range candles {
+			if isNull(c.Open) || isNull(c.High) || isNull(c.Low) || isNull(c.Close) {
+				continue
+			}
+			out = append(out, c)
+		}
+		return out
+	})
+}
+
+func isNull(v float64) bool {
+	return v == 0 || math.IsNaN(v)
+}
+
+// AdjustSplits adjusts candles before each of splits as if the split had
+// always been in effect: prices are scaled down and volumes up by the split
+// ratio, and AdjustmentFactor records the price factor applied.
+func AdjustSplits(splits []types.Split) Transformer {
+	return TransformerFunc(func(candles []types.OHLCV) []types.OHLCV {
+		for _, s := range splits {
+			if s.Numerator <= 0 || s.Denominator <= 0 {
+				continue
+			}
+			factor := s.Denominator / s.Numerator
+
+			for i := range candles {
+				c := &candles[i]
+				if !c.DateTime.Before(s.Date) {
+					continue
+				}
+
+				c.Open *= factor
+				c.High *= factor
+				c.Low *= factor
+				c.Close *= factor
+				c.Volume = int64(math.Round(float64(c.Volume) / factor))
+				if c.AdjustmentFactor == 0 {
+					c.AdjustmentFactor = 1
+				}
+				c.AdjustmentFactor *= factor
+			}
+		}
+		return candles
+	})
+}
@@ -0,0 +1,62 @@
+package ohlcv
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestPipeline_Transform(t *testing.T) {
+	day := time.Date(2024, 1, 2, 3, 45, 0, 0, time.UTC)
+	ist := types.ExchangeNSE.Location()
+
+	candles := []types.OHLCV{
+		{DateTime: day, Open: 100.123, High: 101.456, Low: 99.111, Close: 100.999},
+		{DateTime: day.AddDate(0, 0, 1), Open: math.NaN(), High: 0, Low: 0, Close: 0},
+	}
+
+	var seen int
+	count := TransformerFunc(func(candles []types.OHLCV) []types.OHLCV {
+		seen = len(candles)
+		return candles
+	})
+
+	out := Pipeline{DropNull(), Round(provider.Decimals(1)), InLocation(ist), count}.Transform(candles)
+
+	if len(out) != 1 || seen != 1 {
+		t.Fatalf("Expected 1 candle after dropping nulls, got %d", len(out))
+	}
+	if out[0].Open != 100.1 || out[0].High != 101.5 || out[0].Low != 99.1 || out[0].Close != 101 {
+		t.Errorf("Expected prices rounded to 1 place, got %+v", out[0])
+	}
+	if out[0].DateTime.Location() != ist || !out[0].DateTime.Equal(day) {
+		t.Errorf("Expected %v in IST, got %v", day, out[0].DateTime)
+	}
+}
+
+func TestAdjustSplits(t *testing.T) {
+	split := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	candles := []types.OHLCV{
+		{DateTime: split.AddDate(0, 0, -1), Open: 200, High: 210, Low: 190, Close: 200, Volume: 100},
+		{DateTime: split, Open: 100, High: 105, Low: 95, Close: 100, Volume: 200},
+	}
+
+	out := AdjustSplits([]types.Split{{Date: split, Numerator: 2, Denominator: 1}}).Transform(candles)
+
+	before := out[0]
+	if before.Open != 100 || before.High != 105 || before.Low != 95 || before.Close != 100 {
+		t.Errorf("Expected prices halved before the split, got %+v", before)
+	}
+	if before.Volume != 200 {
+		t.Errorf("Expected volume doubled before the split, got %d", before.Volume)
+	}
+	if before.AdjustmentFactor != 0.5 {
+		t.Errorf("Expected adjustment factor 0.5, got %v", before.AdjustmentFactor)
+	}
+	if out[1].Close != 100 || out[1].AdjustmentFactor != 0 {
+		t.Errorf("Expected the candle on the split date untouched, got %+v", out[1])
+	}
+}
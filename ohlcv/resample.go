@@ -0,0 +1,154 @@
+package ohlcv
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// dayLength is the approximate length of a day, used only to order
+// intervals.
+const dayLength = 24 * time.Hour
+
+// length returns the approximate length of interval, for ordering intervals
+// from finest to coarsest, and false if interval can't be bucketed: intraday
+// intervals up to a day, a day, a week, and months dividing a year.
+func length(interval types.Interval) (time.Duration, bool) {
+	if d := interval.Duration(); d > 0 {
+		return d, d <= dayLength
+	}
+
+	n, unit, ok := interval.Split()
+	switch {
+	case !ok:
+		return 0, false
+	case unit == "d" && n == 1:
+		return dayLength, true
+	case unit == "wk" && n == 1:
+		return 7 * dayLength, true
+	case unit == "mo" && 12%n == 0:
+		return time.Duration(n) * 30 * dayLength, true
+	default:
+		return 0, false
+	}
+}
+
+// CanResample reports whether candles of interval from can be aggregated into
+// candles of interval to. Besides the Interval constants, to may be any
+// multiple of minutes or hours up to a day, such as "75m" or "4h", or of
+// months dividing a year, as long as it is a multiple of from.
+func CanResample(from, to types.Interval) bool {
+	fromLen, ok1 := length(from)
+	toLen, ok2 := length(to)
+	if !ok1 || !ok2 || fromLen >= toLen {
+		return false
+	}
+
+	fromDur, toDur := from.Duration(), to.Duration()
+	if fromDur > 0 && toDur > 0 {
+		return toDur%fromDur == 0
+	}
+
+	fromN, fromUnit, _ := from.Split()
+	toN, toUnit, _ := to.Split()
+	if fromUnit == "mo" && toUnit == "mo" {
+		return toN%fromN == 0
+	}
+	return true
+}
+
+// Resample aggregates candles of interval from into candles of interval to.
+// Intraday buckets are aligned to the start of the day in each candle's
+// location, or WithAnchor past it, weeks start on Monday, and months on the
+// first of the month, counted from January for multiples of months. Each
+// bucket opens at its first candle's open, closes at its last candle's
+// close, spans the highest high and lowest low, sums the volume and trade
+// count, and keeps the open interest of its last candle. The result is
+// sorted by time; candles are not modified.
+func Resample(candles []types.OHLCV, from, to types.Interval, opts ...ResampleOption) ([]types.OHLCV, error) {
+	if from == to {
+		return slices.Clone(candles), nil
+	}
+	if !CanResample(from, to) {
+		return nil, fmt.Errorf("cannot resample %s candles into %s", from, to)
+	}
+
+	cfg := newResampleConfig(opts)
+	sorted := slices.Clone(candles)
+	slices.SortStableFunc(sorted, func(a, b types.OHLCV) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+
+	var out []types.OHLCV
+	for _, c := range sorted {
+		start := bucketStart(c.DateTime, to, cfg)
+
+		if n := len(out); n > 0 && out[n-1].DateTime.Equal(start) {
+			bar := &out[n-1]
+			bar.High = max(bar.High, c.High)
+			bar.Low = min(bar.Low, c.Low)
+			bar.Close = c.Close
+			bar.Volume += c.Volume
+			bar.OpenInterest = c.OpenInterest
+			bar.TradeCount += c.TradeCount
+			continue
+		}
+
+		c.DateTime = start
+		c.Interval = to
+		out = append(out, c)
+	}
+
+	return out, nil
+}
+
+// Bucket returns the bounds of the interval bar containing t, aligned the same
+// way Resample aligns its buckets. The bar spans [start, end).
+func Bucket(t time.Time, interval types.Interval, opts ...ResampleOption) (start, end time.Time) {
+	start = bucketStart(t, interval, newResampleConfig(opts))
+	return start, types.OHLCV{DateTime: start, Interval: interval}.EndTime()
+}
+
+func bucketStart(t time.Time, interval types.Interval, cfg resampleConfig) time.Time {
+	if cfg.anchor == 0 || !interval.IsIntraday() {
+		return interval.Truncate(t)
+	}
+
+	d := interval.Duration()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	anchor := day.Add(cfg.anchor)
+	offset := t.Sub(anchor)
+	if offset < 0 {
+		// Floor rather than truncate toward zero, for times before the
+		// anchor.
+		offset -= d - 1
+	}
+	return anchor.Add(offset / d * d)
+}
+
+// ResampleOption configures Resample and Bucket.
+type ResampleOption func(*resampleConfig)
+
+type resampleConfig struct {
+	anchor time.Duration
+}
+
+func newResampleConfig(opts []ResampleOption) resampleConfig {
+	var cfg resampleConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithAnchor aligns intraday buckets to offset past the start of the day
+// rather than to midnight, such as 9h15m for bars counted from the NSE open,
+// where 75m bars run 09:15-10:30, 10:30-11:45 and so on. It makes no
+// difference to intervals that divide offset.
+func WithAnchor(offset time.Duration) ResampleOption {
+	return func(c *resampleConfig) {
+		c.anchor = offset
+	}
+}
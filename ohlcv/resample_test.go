@@ -0,0 +1,250 @@
+package ohlcv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var ist = time.FixedZone("IST", 5*3600+1800)
+
+func candle(t time.Time, o, h, l, c float64, v int64) types.OHLCV {
+	return types.OHLCV{DateTime: t, Open: o, High: h, Low: l, Close: c, Volume: v}
+}
+
+func TestResample_MinutesToFiveMinutes(t *testing.T) {
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, ist)
+	var candles []types.OHLCV
+	for i := range 10 {
+		f := float64(i)
+		candles = append(candles, candle(base.Add(time.Duration(i)*time.Minute), 100+f, 101+f, 99+f, 100.5+f, 10))
+	}
+
+	got, err := Resample(candles, types.Interval1m, types.Interval5m)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(got))
+	}
+
+	first := got[0]
+	if !first.DateTime.Equal(base) {
+		t.Errorf("Expected first bucket at %v, got %v", base, first.DateTime)
+	}
+	if first.Open != 100 || first.High != 105 || first.Low != 99 || first.Close != 104.5 || first.Volume != 50 {
+		t.Errorf("Unexpected first candle: %+v", first)
+	}
+	if first.Interval != types.Interval5m {
+		t.Errorf("Expected the candles marked 5m, got %q", first.Interval)
+	}
+	if !got[1].DateTime.Equal(base.Add(5 * time.Minute)) {
+		t.Errorf("Expected second bucket at %v, got %v", base.Add(5*time.Minute), got[1].DateTime)
+	}
+}
+
+func TestResample_OpenInterestAndTradeCount(t *testing.T) {
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, ist)
+	var candles []types.OHLCV
+	for i := range 5 {
+		c := candle(base.Add(time.Duration(i)*time.Minute), 100, 101, 99, 100, 10)
+		c.OpenInterest = int64(1000 + i)
+		c.TradeCount = 4
+		candles = append(candles, c)
+	}
+
+	got, err := Resample(candles, types.Interval1m, types.Interval5m)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 1 || got[0].OpenInterest != 1004 || got[0].TradeCount != 20 {
+		t.Errorf("Expected the last open interest 1004 and 20 trades, got %+v", got)
+	}
+}
+
+func TestResample_UnsortedInput(t *testing.T) {
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, ist)
+	candles := []types.OHLCV{
+		candle(base.Add(2*time.Minute), 3, 3, 3, 3, 1),
+		candle(base, 1, 1, 1, 1, 1),
+		candle(base.Add(time.Minute), 2, 2, 2, 2, 1),
+	}
+
+	got, err := Resample(candles, types.Interval1m, types.Interval15m)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 candle, got %d", len(got))
+	}
+	if got[0].Open != 1 || got[0].Close != 3 {
+		t.Errorf("Expected open 1 and close 3, got %v and %v", got[0].Open, got[0].Close)
+	}
+	if candles[0].Open != 3 {
+		t.Error("Expected input to be left untouched")
+	}
+}
+
+func TestResample_HourBucketsAlignToDayStart(t *testing.T) {
+	candles := []types.OHLCV{
+		candle(time.Date(2024, 1, 2, 9, 15, 0, 0, ist), 1, 1, 1, 1, 1),
+		candle(time.Date(2024, 1, 2, 9, 45, 0, 0, ist), 2, 2, 2, 2, 1),
+		candle(time.Date(2024, 1, 2, 10, 15, 0, 0, ist), 3, 3, 3, 3, 1),
+	}
+
+	got, err := Resample(candles, types.Interval15m, types.Interval1h)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(got))
+	}
+	want := time.Date(2024, 1, 2, 9, 0, 0, 0, ist)
+	if !got[0].DateTime.Equal(want) {
+		t.Errorf("Expected bucket at %v, got %v", want, got[0].DateTime)
+	}
+}
+
+func TestResample_DailyAndWeekly(t *testing.T) {
+	candles := []types.OHLCV{
+		candle(time.Date(2024, 1, 3, 9, 15, 0, 0, ist), 10, 12, 9, 11, 100),  // Wednesday
+		candle(time.Date(2024, 1, 3, 15, 0, 0, 0, ist), 11, 13, 10, 12, 100), // Wednesday
+		candle(time.Date(2024, 1, 5, 9, 15, 0, 0, ist), 12, 14, 8, 13, 100),  // Friday
+	}
+
+	daily, err := Resample(candles, types.Interval1h, types.Interval1d)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(daily) != 2 {
+		t.Fatalf("Expected 2 daily candles, got %d", len(daily))
+	}
+	if daily[0].High != 13 || daily[0].Low != 9 || daily[0].Volume != 200 {
+		t.Errorf("Unexpected daily candle: %+v", daily[0])
+	}
+
+	weekly, err := Resample(daily, types.Interval1d, types.Interval1wk)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(weekly) != 1 {
+		t.Fatalf("Expected 1 weekly candle, got %d", len(weekly))
+	}
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, ist)
+	if !weekly[0].DateTime.Equal(monday) {
+		t.Errorf("Expected week to start %v, got %v", monday, weekly[0].DateTime)
+	}
+	if weekly[0].Open != 10 || weekly[0].Close != 13 || weekly[0].Low != 8 || weekly[0].Volume != 300 {
+		t.Errorf("Unexpected weekly candle: %+v", weekly[0])
+	}
+}
+
+func TestResample_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to types.Interval
+	}{
+		{"coarser to finer", types.Interval1h, types.Interval5m},
+		{"not a multiple", types.Interval("2m"), types.Interval5m},
+		{"unknown interval", types.Interval("2x"), types.Interval5m},
+		{"multi-day", types.Interval1d, types.Interval("2d")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Resample(nil, tt.from, tt.to); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCanResample(t *testing.T) {
+	tests := []struct {
+		from, to types.Interval
+		want     bool
+	}{
+		{types.Interval1m, types.Interval5m, true},
+		{types.Interval5m, types.Interval15m, true},
+		{types.Interval15m, types.Interval1h, true},
+		{types.Interval1m, types.Interval1d, true},
+		{types.Interval1d, types.Interval1mo, true},
+		{types.Interval1h, types.Interval1m, false},
+		{types.Interval5m, types.Interval5m, false},
+		{types.Interval1m, "2m", true},
+		{types.Interval15m, "75m", true},
+		{types.Interval30m, "75m", false},
+		{types.Interval1h, "4h", true},
+		{types.Interval1mo, "6mo", true},
+		{types.Interval3mo, "6mo", true},
+		{"2mo", types.Interval3mo, false},
+		{types.Interval1h, "36h", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanResample(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanResample(%s, %s): expected %v, got %v", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
+
+func TestResample_CustomInterval(t *testing.T) {
+	open := time.Date(2024, 1, 2, 9, 15, 0, 0, ist)
+	var candles []types.OHLCV
+	for i := range 10 {
+		candles = append(candles, candle(open.Add(time.Duration(i)*15*time.Minute), 100, 101, 99, 100, 1))
+	}
+
+	midnight, err := Resample(candles, types.Interval15m, "75m")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(midnight) != 3 || !midnight[0].DateTime.Equal(time.Date(2024, 1, 2, 8, 45, 0, 0, ist)) {
+		t.Errorf("Expected 3 bars counted from midnight, got %d from %v", len(midnight), midnight[0].DateTime)
+	}
+
+	anchored, err := Resample(candles, types.Interval15m, "75m", WithAnchor(9*time.Hour+15*time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(anchored) != 2 || !anchored[1].DateTime.Equal(open.Add(75*time.Minute)) || anchored[1].Volume != 5 {
+		t.Errorf("Expected 2 bars counted from the open, got %+v", anchored)
+	}
+}
+
+func TestBucket_Anchor(t *testing.T) {
+	anchor := WithAnchor(9*time.Hour + 15*time.Minute)
+
+	start, end := Bucket(time.Date(2024, 1, 3, 9, 10, 0, 0, ist), "75m", anchor)
+	if !start.Equal(time.Date(2024, 1, 3, 8, 0, 0, 0, ist)) || !end.Equal(time.Date(2024, 1, 3, 9, 15, 0, 0, ist)) {
+		t.Errorf("Expected the bar before the anchor, got [%v, %v)", start, end)
+	}
+
+	start, end = Bucket(time.Date(2024, 8, 20, 0, 0, 0, 0, ist), "6mo")
+	if !start.Equal(time.Date(2024, 7, 1, 0, 0, 0, 0, ist)) || !end.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, ist)) {
+		t.Errorf("Expected the second half of 2024, got [%v, %v)", start, end)
+	}
+}
+
+func TestBucket(t *testing.T) {
+	at := time.Date(2024, 1, 3, 9, 17, 30, 0, ist)
+
+	tests := []struct {
+		interval   types.Interval
+		start, end time.Time
+	}{
+		{types.Interval1m, time.Date(2024, 1, 3, 9, 17, 0, 0, ist), time.Date(2024, 1, 3, 9, 18, 0, 0, ist)},
+		{types.Interval15m, time.Date(2024, 1, 3, 9, 15, 0, 0, ist), time.Date(2024, 1, 3, 9, 30, 0, 0, ist)},
+		{types.Interval1d, time.Date(2024, 1, 3, 0, 0, 0, 0, ist), time.Date(2024, 1, 4, 0, 0, 0, 0, ist)},
+		{types.Interval1wk, time.Date(2024, 1, 1, 0, 0, 0, 0, ist), time.Date(2024, 1, 8, 0, 0, 0, 0, ist)},
+		{types.Interval3mo, time.Date(2024, 1, 1, 0, 0, 0, 0, ist), time.Date(2024, 4, 1, 0, 0, 0, 0, ist)},
+	}
+
+	for _, tt := range tests {
+		start, end := Bucket(at, tt.interval)
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("Bucket(%s): expected [%v, %v), got [%v, %v)", tt.interval, tt.start, tt.end, start, end)
+		}
+	}
+}
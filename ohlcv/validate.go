@@ -0,0 +1,195 @@
+package ohlcv
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// IssueKind identifies a data-quality problem found in a candle.
+type IssueKind = types.IssueKind
+
+// The kinds of issues Validate reports, as reported by Series.Validate.
+const (
+	IssueHighBelowLow   = types.IssueHighBelowLow
+	IssueHighBelowBody  = types.IssueHighBelowBody
+	IssueLowAboveBody   = types.IssueLowAboveBody
+	IssueNegativePrice  = types.IssueNegativePrice
+	IssueNegativeVolume = types.IssueNegativeVolume
+	IssueDuplicate      = types.IssueDuplicate
+	IssueOutOfOrder     = types.IssueOutOfOrder
+)
+
+// IssueOutsideSession is an intraday candle outside the trading session.
+const IssueOutsideSession IssueKind = "outside_session"
+
+// Issue is a problem found in the candle at Index of the validated slice.
+type Issue = types.Issue
+
+// ValidationReport lists every issue found in a slice of candles.
+type ValidationReport struct {
+	Issues []Issue
+}
+
+// Valid reports whether no issues were found.
+func (r ValidationReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Count returns how many issues of kind were found.
+func (r ValidationReport) Count(kind IssueKind) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Session is the part of a trading day during which intraday candles are
+// expected, as offsets from midnight in Location. The zero Session disables
+// the trading-hours check.
+type Session struct {
+	Open     time.Duration
+	Close    time.Duration
+	Location *time.Location
+}
+
+// IndianEquitySession is the regular NSE and BSE equity session.
+var IndianEquitySession = SessionOf(mustCalendar(types.ExchangeNSE))
+
+// USEquitySession is the regular NASDAQ and NYSE equity session.
+var USEquitySession = SessionOf(mustCalendar(types.ExchangeNYSE))
+
+func mustCalendar(exchange types.Exchange) *calendar.Calendar {
+	c, err := calendar.ForExchange(exchange)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// SessionOf returns the regular session of c, or the zero Session if c is
+// nil.
+func SessionOf(c *calendar.Calendar) Session {
+	if c == nil {
+		return Session{}
+	}
+	open, close := c.SessionHours()
+	return Session{Open: open, Close: close, Location: c.Location()}
+}
+
+// contains reports whether a candle starting at t falls inside the session.
+func (s Session) contains(t time.Time) bool {
+	if s.Location == nil {
+		return true
+	}
+
+	t = t.In(s.Location)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.Location)
+	offset := t.Sub(day)
+	return offset >= s.Open && offset < s.Close
+}
+
+// isExtendedHours reports whether c is marked as a pre-market or post-market
+// candle.
+func isExtendedHours(c types.OHLCV) bool {
+	return c.Session == types.SessionPre || c.Session == types.SessionPost
+}
+
+// Validate checks each candle of interval as types.OHLCV.Validate does, for
+// duplicate and out-of-order timestamps and, for intraday intervals, for
+// candles outside session. Candles marked as pre-market or post-market are
+// expected outside it.
+func Validate(candles []types.OHLCV, interval types.Interval, session Session) ValidationReport {
+	var report ValidationReport
+	add := func(i int, kind IssueKind) {
+		report.Issues = append(report.Issues, Issue{Index: i, Kind: kind, Candle: candles[i]})
+	}
+
+	intraday := interval.IsIntraday()
+	seen := make(map[time.Time]bool, len(candles))
+
+	for i, c := range candles {
+		var verr *types.ValidationError
+		if errors.As(c.Validate(), &verr) {
+			for _, issue := range verr.Issues {
+				add(i, issue.Kind)
+			}
+		}
+
+		key := c.DateTime.UTC()
+		if seen[key] {
+			add(i, IssueDuplicate)
+		}
+		seen[key] = true
+
+		if i > 0 && c.DateTime.Before(candles[i-1].DateTime) {
+			add(i, IssueOutOfOrder)
+		}
+		if intraday && !isExtendedHours(c) && !session.contains(c.DateTime) {
+			add(i, IssueOutsideSession)
+		}
+	}
+
+	return report
+}
+
+// Policy decides what Clean does with candles that fail validation.
+type Policy int
+
+const (
+	// PolicyDrop removes every candle with an issue.
+	PolicyDrop Policy = iota + 1
+	// PolicyRepair fixes what can be fixed, by swapping an inverted high and
+	// low and widening the range to cover open and close, and drops the rest.
+	PolicyRepair
+)
+
+// Clean returns candles sorted by time with duplicates removed, keeping the
+// candle with the highest volume for each timestamp, and handles the
+// remaining issues according to policy. candles is not modified.
+func Clean(candles []types.OHLCV, interval types.Interval, session Session, policy Policy) []types.OHLCV {
+	sorted := slices.Clone(candles)
+	slices.SortStableFunc(sorted, func(a, b types.OHLCV) int {
+		if c := a.DateTime.Compare(b.DateTime); c != 0 {
+			return c
+		}
+		// Higher volume first so compaction keeps it.
+		return cmp.Compare(b.Volume, a.Volume)
+	})
+	sorted = slices.CompactFunc(sorted, func(a, b types.OHLCV) bool {
+		return a.DateTime.Equal(b.DateTime)
+	})
+
+	intraday := interval.IsIntraday()
+
+	out := sorted[:0]
+	for _, c := range sorted {
+		if c.Open < 0 || c.High < 0 || c.Low < 0 || c.Close < 0 || c.Volume < 0 {
+			continue
+		}
+		if intraday && !isExtendedHours(c) && !session.contains(c.DateTime) {
+			continue
+		}
+		if policy != PolicyRepair {
+			if c.Validate() != nil {
+				continue
+			}
+		} else {
+			if c.High < c.Low {
+				c.High, c.Low = c.Low, c.High
+			}
+			c.High = max(c.High, c.Open, c.Close)
+			c.Low = min(c.Low, c.Open, c.Close)
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
@@ -0,0 +1,143 @@
+package ohlcv
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestValidate_FindsIssues(t *testing.T) {
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, ist)
+	candles := []types.OHLCV{
+		candle(base, 100, 101, 99, 100, 10),
+		candle(base.Add(time.Minute), 100, 98, 101, 100, 10),  // high below low
+		candle(base.Add(2*time.Minute), -1, 101, 99, 100, 10), // negative price
+		candle(base.Add(2*time.Minute), 100, 101, 99, 100, 0), // duplicate
+		candle(base.Add(-time.Minute), 100, 101, 99, 100, 10), // out of order
+		candle(base.Add(8*time.Hour), 100, 101, 99, 100, 10),  // outside session
+	}
+
+	report := Validate(candles, types.Interval1m, IndianEquitySession)
+
+	if report.Valid() {
+		t.Fatal("Expected report to be invalid")
+	}
+
+	for _, kind := range []IssueKind{IssueHighBelowLow, IssueNegativePrice, IssueDuplicate, IssueOutOfOrder, IssueOutsideSession} {
+		if n := report.Count(kind); n != 1 {
+			t.Errorf("Expected 1 %s issue, got %d", kind, n)
+		}
+	}
+}
+
+func TestValidate_AgreesWithSeriesValidate(t *testing.T) {
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, ist)
+	candles := []types.OHLCV{
+		candle(base, 100, 101, 99, 100, 10),
+		candle(base.Add(time.Minute), 100, 98, 101, 100, 10),      // high below low and the body
+		candle(base.Add(2*time.Minute), 100, 99, 98, 100, 10),     // high below body
+		candle(base.Add(3*time.Minute), 100, 101, 100.5, 100, 10), // low above body
+		candle(base.Add(4*time.Minute), 100, 101, 99, 100, -5),    // negative volume
+		candle(base.Add(4*time.Minute), -1, 101, -2, 100, 10),     // negative price, duplicate
+		candle(base, 100, 101, 99, 100, 10),                       // out of order, duplicate
+	}
+
+	report := Validate(candles, types.Interval1d, Session{})
+
+	var verr *types.ValidationError
+	if !errors.As(types.Series(candles).Validate(), &verr) {
+		t.Fatal("Expected Series.Validate to fail")
+	}
+	if len(report.Issues) != len(verr.Issues) {
+		t.Fatalf("Expected %d issues, got %d", len(verr.Issues), len(report.Issues))
+	}
+	for i, want := range verr.Issues {
+		if got := report.Issues[i]; got.Index != want.Index || got.Kind != want.Kind {
+			t.Errorf("Expected issue %d to be %s, got %s", i, want, got)
+		}
+	}
+	if report.Count(IssueNegativeVolume) != 1 {
+		t.Errorf("Expected 1 negative volume issue, got %d", report.Count(IssueNegativeVolume))
+	}
+	if report.Count(IssueNegativePrice) != 1 {
+		t.Errorf("Expected 1 negative price issue, got %d", report.Count(IssueNegativePrice))
+	}
+}
+
+func TestValidate_CleanData(t *testing.T) {
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, ist)
+	candles := []types.OHLCV{
+		candle(base, 100, 101, 99, 100, 10),
+		candle(base.Add(time.Minute), 100, 101, 99, 100, 10),
+	}
+
+	if report := Validate(candles, types.Interval1m, IndianEquitySession); !report.Valid() {
+		t.Errorf("Expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestValidate_DailyIgnoresSession(t *testing.T) {
+	candles := []types.OHLCV{
+		candle(time.Date(2024, 1, 2, 0, 0, 0, 0, ist), 100, 101, 99, 100, 10),
+	}
+
+	if report := Validate(candles, types.Interval1d, IndianEquitySession); !report.Valid() {
+		t.Errorf("Expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestValidate_ExtendedHours(t *testing.T) {
+	pre := candle(time.Date(2024, 1, 2, 8, 0, 0, 0, ist), 100, 101, 99, 100, 10)
+	pre.Session = types.SessionPre
+	post := candle(time.Date(2024, 1, 2, 17, 0, 0, 0, ist), 100, 101, 99, 100, 10)
+	post.Session = types.SessionPost
+	candles := []types.OHLCV{pre, post}
+
+	if report := Validate(candles, types.Interval1m, IndianEquitySession); !report.Valid() {
+		t.Errorf("Expected no issues, got %+v", report.Issues)
+	}
+	if got := Clean(candles, types.Interval1m, IndianEquitySession, PolicyDrop); len(got) != 2 {
+		t.Errorf("Expected extended hours candles to be kept, got %d", len(got))
+	}
+}
+
+func TestClean(t *testing.T) {
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, ist)
+	candles := []types.OHLCV{
+		candle(base.Add(2*time.Minute), 100, 101, 99, 100, 10),
+		candle(base, 100, 101, 99, 100, 0),
+		candle(base, 100, 101, 99, 100, 10),
+		candle(base.Add(time.Minute), 100, 98, 101, 102, 10),
+		candle(base.Add(3*time.Minute), -1, 101, 99, 100, 10),
+		candle(base.Add(8*time.Hour), 100, 101, 99, 100, 10),
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		got := Clean(candles, types.Interval1m, IndianEquitySession, PolicyDrop)
+		if len(got) != 2 {
+			t.Fatalf("Expected 2 candles, got %d", len(got))
+		}
+		if got[0].Volume != 10 {
+			t.Errorf("Expected duplicate with volume to be kept, got volume %d", got[0].Volume)
+		}
+		if !got[1].DateTime.Equal(base.Add(2 * time.Minute)) {
+			t.Errorf("Expected sorted output, got %v", got[1].DateTime)
+		}
+	})
+
+	t.Run("repair", func(t *testing.T) {
+		got := Clean(candles, types.Interval1m, IndianEquitySession, PolicyRepair)
+		if len(got) != 3 {
+			t.Fatalf("Expected 3 candles, got %d", len(got))
+		}
+		if got[1].High != 102 || got[1].Low != 98 {
+			t.Errorf("Expected repaired range 98-102, got %v-%v", got[1].Low, got[1].High)
+		}
+	})
+
+	if candles[0].DateTime != base.Add(2*time.Minute) {
+		t.Error("Expected input to be left untouched")
+	}
+}
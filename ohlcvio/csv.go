@@ -0,0 +1,197 @@
+package ohlcvio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// WriteCSV writes candles to w as CSV, one row per candle.
+func WriteCSV(w io.Writer, candles []types.OHLCV, opts ...Option) error {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+
+	if cfg.header {
+		row := make([]string, len(cfg.columns))
+		for i, c := range cfg.columns {
+			row[i] = string(c)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+
+	row := make([]string, len(cfg.columns))
+	for _, candle := range candles {
+		for i, c := range cfg.columns {
+			row[i] = cfg.format(candle, c)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads candles written by WriteCSV, or any CSV file whose columns
+// are named after Column values. Columns with other names are ignored.
+func ReadCSV(r io.Reader, opts ...Option) ([]types.OHLCV, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	columns := cfg.columns
+	if cfg.header {
+		header, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+
+		columns = make([]Column, len(header))
+		for i, name := range header {
+			if c, ok := cfg.names[name]; ok {
+				columns[i] = c
+			} else if c := Column(name); knownColumns[c] {
+				columns[i] = c
+			}
+		}
+	}
+
+	var candles []types.OHLCV
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		var candle types.OHLCV
+		for i, value := range row {
+			if i >= len(columns) || columns[i] == "" {
+				continue
+			}
+			if err := cfg.parse(&candle, columns[i], value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		candles = append(candles, candle)
+	}
+
+	return candles, nil
+}
+
+func (cfg config) format(candle types.OHLCV, c Column) string {
+	switch c {
+	case ColumnDateTime:
+		t := candle.DateTime
+		if cfg.location != nil {
+			t = t.In(cfg.location)
+		}
+		return t.Format(cfg.timeFormat)
+	case ColumnSymbol:
+		return candle.Symbol
+	case ColumnExchange:
+		return string(candle.Exchange)
+	case ColumnOpen:
+		return formatFloat(candle.Open)
+	case ColumnHigh:
+		return formatFloat(candle.High)
+	case ColumnLow:
+		return formatFloat(candle.Low)
+	case ColumnClose:
+		return formatFloat(candle.Close)
+	case ColumnVolume:
+		return strconv.FormatInt(candle.Volume, 10)
+	case ColumnOpenInterest:
+		return strconv.FormatInt(candle.OpenInterest, 10)
+	case ColumnTradeCount:
+		return strconv.FormatInt(candle.TradeCount, 10)
+	case ColumnInterval:
+		return string(candle.Interval)
+	case ColumnSource:
+		return candle.Source
+	case ColumnFreshness:
+		return string(candle.Freshness)
+	case ColumnAdjustmentFactor:
+		return formatFloat(candle.AdjustmentFactor)
+	}
+	return ""
+}
+
+func (cfg config) parse(candle *types.OHLCV, c Column, value string) error {
+	var err error
+	switch c {
+	case ColumnDateTime:
+		loc := cfg.location
+		if loc == nil {
+			loc = time.UTC
+		}
+		candle.DateTime, err = time.ParseInLocation(cfg.timeFormat, value, loc)
+		if err == nil && cfg.location != nil {
+			candle.DateTime = candle.DateTime.In(cfg.location)
+		}
+	case ColumnSymbol:
+		candle.Symbol = value
+	case ColumnExchange:
+		candle.Exchange = types.Exchange(value)
+	case ColumnOpen:
+		candle.Open, err = strconv.ParseFloat(value, 64)
+	case ColumnHigh:
+		candle.High, err = strconv.ParseFloat(value, 64)
+	case ColumnLow:
+		candle.Low, err = strconv.ParseFloat(value, 64)
+	case ColumnClose:
+		candle.Close, err = strconv.ParseFloat(value, 64)
+	case ColumnVolume:
+		candle.Volume, err = strconv.ParseInt(value, 10, 64)
+	case ColumnOpenInterest:
+		if value != "" {
+			candle.OpenInterest, err = strconv.ParseInt(value, 10, 64)
+		}
+	case ColumnTradeCount:
+		if value != "" {
+			candle.TradeCount, err = strconv.ParseInt(value, 10, 64)
+		}
+	case ColumnInterval:
+		candle.Interval = types.Interval(value)
+	case ColumnSource:
+		candle.Source = value
+	case ColumnFreshness:
+		candle.Freshness = types.DataFreshness(value)
+	case ColumnAdjustmentFactor:
+		if value != "" {
+			candle.AdjustmentFactor, err = strconv.ParseFloat(value, 64)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", c, value, err)
+	}
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,161 @@
+package ohlcvio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var ist = time.FixedZone("IST", 5*3600+1800)
+
+func sampleCandles() []types.OHLCV {
+	return []types.OHLCV{
+		{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, Open: 2500, High: 2550.5, Low: 2490, Close: 2540.25, Volume: 1000, DateTime: time.Date(2024, 1, 2, 9, 15, 0, 0, ist), Source: "yahoo"},
+		{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, Open: 2540, High: 2560, Low: 2530, Close: 2555, Volume: 800, DateTime: time.Date(2024, 1, 2, 9, 16, 0, 0, ist), Source: "yahoo"},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sampleCandles()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := "datetime,open,high,low,close,volume\n" +
+		"2024-01-02T09:15:00+05:30,2500,2550.5,2490,2540.25,1000\n" +
+		"2024-01-02T09:16:00+05:30,2540,2560,2530,2555,800\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWriteCSV_ColumnsTimezoneNoHeader(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, sampleCandles()[:1],
+		WithColumns(ColumnSymbol, ColumnClose, ColumnDateTime),
+		WithTimezone(time.UTC),
+		WithTimeFormat("2006-01-02 15:04"),
+		WithHeader(false),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := "RELIANCE,2540.25,2024-01-02 03:45\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWriteCSV_UnknownColumn(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, nil, WithColumns("vwap")); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestReadCSV_RoundTrip(t *testing.T) {
+	candles := sampleCandles()
+	for i := range candles {
+		candles[i].Interval = types.Interval1m
+		candles[i].OpenInterest = int64(1000 * (i + 1))
+		candles[i].TradeCount = int64(10 + i)
+	}
+	columns := []Column{ColumnDateTime, ColumnSymbol, ColumnExchange, ColumnOpen, ColumnHigh, ColumnLow, ColumnClose, ColumnVolume, ColumnOpenInterest, ColumnTradeCount, ColumnInterval, ColumnSource}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, candles, WithColumns(columns...)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got, err := ReadCSV(&buf, WithTimezone(ist))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != len(candles) {
+		t.Fatalf("Expected %d candles, got %d", len(candles), len(got))
+	}
+	for i := range candles {
+		if !got[i].DateTime.Equal(candles[i].DateTime) || got[i].DateTime.Location() != ist {
+			t.Errorf("Candle %d: expected time %v, got %v", i, candles[i].DateTime, got[i].DateTime)
+		}
+		got[i].DateTime = candles[i].DateTime
+		if got[i] != candles[i] {
+			t.Errorf("Candle %d: expected %+v, got %+v", i, candles[i], got[i])
+		}
+	}
+}
+
+func TestReadCSV_HeaderOrderAndUnknownColumns(t *testing.T) {
+	in := "close,vwap,datetime\n101.5,100,2024-01-02T09:15:00Z\n"
+
+	got, err := ReadCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 1 || got[0].Close != 101.5 || !got[0].DateTime.Equal(time.Date(2024, 1, 2, 9, 15, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected candles: %+v", got)
+	}
+}
+
+func TestReadCSV_ColumnNames(t *testing.T) {
+	in := "Date,Close Price,No.of Shares,open\n2024-01-02,101.5,300,100\n"
+
+	got, err := ReadCSV(strings.NewReader(in),
+		WithColumnNames(map[string]Column{"Date": ColumnDateTime, "Close Price": ColumnClose, "No.of Shares": ColumnVolume}),
+		WithTimeFormat("2006-01-02"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 1 || got[0].Close != 101.5 || got[0].Volume != 300 || got[0].Open != 100 {
+		t.Errorf("Unexpected candles: %+v", got)
+	}
+}
+
+func TestReadCSV_UnknownColumnName(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader(""), WithColumnNames(map[string]Column{"VWAP": "vwap"}))
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestReadCSV_NoHeaderInLocation(t *testing.T) {
+	in := "2024-01-02 09:15,2500,2550,2490,2540,1000\n"
+
+	got, err := ReadCSV(strings.NewReader(in),
+		WithHeader(false),
+		WithTimeFormat("2006-01-02 15:04"),
+		WithTimezone(ist),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 candle, got %d", len(got))
+	}
+	if want := time.Date(2024, 1, 2, 9, 15, 0, 0, ist); !got[0].DateTime.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got[0].DateTime)
+	}
+	if got[0].Volume != 1000 {
+		t.Errorf("Expected volume 1000, got %d", got[0].Volume)
+	}
+}
+
+func TestReadCSV_InvalidValue(t *testing.T) {
+	in := "datetime,open\n2024-01-02T09:15:00Z,abc\n"
+
+	_, err := ReadCSV(strings.NewReader(in))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected error on line 2, got %v", err)
+	}
+}
+
+func TestReadCSV_Empty(t *testing.T) {
+	got, err := ReadCSV(strings.NewReader(""))
+	if err != nil || got != nil {
+		t.Errorf("Expected no candles and no error, got %v, %v", got, err)
+	}
+}
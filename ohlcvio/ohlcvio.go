@@ -0,0 +1,147 @@
+// Package ohlcvio reads and writes OHLCV series in file formats.
+package ohlcvio
+
+import (
+	"fmt"
+	"time"
+)
+
+// Column is a field of types.OHLCV. Its value is the name used in headers,
+// matching the field's JSON name.
+type Column string
+
+const (
+	ColumnDateTime         Column = "datetime"
+	ColumnSymbol           Column = "symbol"
+	ColumnExchange         Column = "exchange"
+	ColumnOpen             Column = "open"
+	ColumnHigh             Column = "high"
+	ColumnLow              Column = "low"
+	ColumnClose            Column = "close"
+	ColumnVolume           Column = "volume"
+	ColumnOpenInterest     Column = "open_interest"
+	ColumnTradeCount       Column = "trade_count"
+	ColumnInterval         Column = "interval"
+	ColumnSource           Column = "source"
+	ColumnFreshness        Column = "freshness"
+	ColumnAdjustmentFactor Column = "adjustment_factor"
+)
+
+// DefaultColumns is the column order used unless WithColumns is given.
+var DefaultColumns = []Column{ColumnDateTime, ColumnOpen, ColumnHigh, ColumnLow, ColumnClose, ColumnVolume}
+
+var knownColumns = map[Column]bool{
+	ColumnDateTime:         true,
+	ColumnSymbol:           true,
+	ColumnExchange:         true,
+	ColumnOpen:             true,
+	ColumnHigh:             true,
+	ColumnLow:              true,
+	ColumnClose:            true,
+	ColumnVolume:           true,
+	ColumnOpenInterest:     true,
+	ColumnTradeCount:       true,
+	ColumnInterval:         true,
+	ColumnSource:           true,
+	ColumnFreshness:        true,
+	ColumnAdjustmentFactor: true,
+}
+
+type config struct {
+	columns     []Column
+	names       map[string]Column
+	location    *time.Location
+	header      bool
+	timeFormat  string
+	compression Compression
+}
+
+type Option func(*config)
+
+// WithColumns sets the columns written, in order. When reading, it gives the
+// columns of a file without a header.
+func WithColumns(columns ...Column) Option {
+	return func(c *config) {
+		c.columns = append([]Column{}, columns...)
+	}
+}
+
+// WithColumnNames maps header names to columns when reading, for files whose
+// headers don't use Column values, such as vendor dumps with "Date" or
+// "Close Price". Names not in names are matched as Column values.
+func WithColumnNames(names map[string]Column) Option {
+	return func(c *config) {
+		c.names = names
+	}
+}
+
+// WithTimezone converts times to loc before they are written, and reads times
+// that carry no offset as times in loc. The default is to keep each time's
+// own location when writing and to read offset-less times as UTC. ReadParquet
+// returns every time in loc.
+func WithTimezone(loc *time.Location) Option {
+	return func(c *config) {
+		c.location = loc
+	}
+}
+
+// WithHeader sets whether the first row holds column names. It does by
+// default; when reading, its names override WithColumns.
+func WithHeader(enabled bool) Option {
+	return func(c *config) {
+		c.header = enabled
+	}
+}
+
+// WithTimeFormat sets the time layout of the datetime column. The default is
+// time.RFC3339.
+func WithTimeFormat(layout string) Option {
+	return func(c *config) {
+		c.timeFormat = layout
+	}
+}
+
+// WithCompression sets the codec Parquet columns are compressed with. The
+// default is CompressionZstd.
+func WithCompression(c Compression) Option {
+	return func(cfg *config) {
+		cfg.compression = c
+	}
+}
+
+func newConfig(opts []Option) (config, error) {
+	cfg := config{
+		columns:     DefaultColumns,
+		header:      true,
+		timeFormat:  time.RFC3339,
+		compression: CompressionZstd,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := checkColumns(cfg.columns); err != nil {
+		return config{}, err
+	}
+	for name, c := range cfg.names {
+		if !knownColumns[c] {
+			return config{}, fmt.Errorf("unknown column %q for %q", c, name)
+		}
+	}
+
+	return cfg, nil
+}
+
+func checkColumns(columns []Column) error {
+	seen := make(map[Column]bool, len(columns))
+	for _, c := range columns {
+		if !knownColumns[c] {
+			return fmt.Errorf("unknown column %q", c)
+		}
+		if seen[c] {
+			return fmt.Errorf("duplicate column %q", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
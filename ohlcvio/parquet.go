@@ -0,0 +1,159 @@
+package ohlcvio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// ParquetSchemaVersion is the version of the Parquet schema WriteParquet
+// writes. It is stored in the file's key-value metadata under
+// SchemaVersionKey and bumped whenever columns change incompatibly, so
+// archives written today can still be told apart from later ones.
+const ParquetSchemaVersion = 1
+
+// SchemaVersionKey is the Parquet metadata key holding the schema version.
+const SchemaVersionKey = "gohlcv.schema_version"
+
+// ErrUnsupportedSchema is returned by ReadParquet for files written with a
+// newer schema version than this package knows.
+var ErrUnsupportedSchema = errors.New("unsupported parquet schema version")
+
+// Compression is a Parquet column compression codec.
+type Compression string
+
+const (
+	CompressionNone   Compression = "none"
+	CompressionSnappy Compression = "snappy"
+	CompressionGzip   Compression = "gzip"
+	CompressionZstd   Compression = "zstd"
+)
+
+var codecs = map[Compression]compress.Codec{
+	CompressionNone:   &parquet.Uncompressed,
+	CompressionSnappy: &parquet.Snappy,
+	CompressionGzip:   &parquet.Gzip,
+	CompressionZstd:   &parquet.Zstd,
+}
+
+// parquetRow is version 1 of the schema. Times are stored as UTC nanoseconds;
+// string columns are dictionary encoded since they repeat on every row.
+type parquetRow struct {
+	DateTime         int64   `parquet:"datetime,timestamp(nanosecond:utc),delta"`
+	Symbol           string  `parquet:"symbol,dict"`
+	Exchange         string  `parquet:"exchange,dict"`
+	Open             float64 `parquet:"open"`
+	High             float64 `parquet:"high"`
+	Low              float64 `parquet:"low"`
+	Close            float64 `parquet:"close"`
+	Volume           int64   `parquet:"volume,delta"`
+	Source           string  `parquet:"source,dict"`
+	Freshness        string  `parquet:"freshness,dict"`
+	AdjustmentFactor float64 `parquet:"adjustment_factor"`
+}
+
+// WriteParquet writes candles to w as a single Parquet file with every field
+// of types.OHLCV as a column, compressed with the WithCompression codec.
+// Column, header and time format options don't apply.
+func WriteParquet(w io.Writer, candles []types.OHLCV, opts ...Option) error {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	codec, ok := codecs[cfg.compression]
+	if !ok {
+		return fmt.Errorf("unknown compression %q", cfg.compression)
+	}
+
+	pw := parquet.NewGenericWriter[parquetRow](w,
+		parquet.Compression(codec),
+		parquet.KeyValueMetadata(SchemaVersionKey, strconv.Itoa(ParquetSchemaVersion)),
+	)
+
+	rows := make([]parquetRow, len(candles))
+	for i, c := range candles {
+		rows[i] = parquetRow{
+			DateTime:         c.DateTime.UnixNano(),
+			Symbol:           c.Symbol,
+			Exchange:         string(c.Exchange),
+			Open:             c.Open,
+			High:             c.High,
+			Low:              c.Low,
+			Close:            c.Close,
+			Volume:           c.Volume,
+			Source:           c.Source,
+			Freshness:        string(c.Freshness),
+			AdjustmentFactor: c.AdjustmentFactor,
+		}
+	}
+
+	if _, err := pw.Write(rows); err != nil {
+		return fmt.Errorf("failed to write rows: %w", err)
+	}
+	if err := pw.Close(); err != nil {
+		return fmt.Errorf("failed to close parquet writer: %w", err)
+	}
+
+	return nil
+}
+
+// ReadParquet reads candles written by WriteParquet from the size bytes of r.
+// Times are returned in the WithTimezone location, UTC by default.
+func ReadParquet(r io.ReaderAt, size int64, opts ...Option) ([]types.OHLCV, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	if v, ok := f.Lookup(SchemaVersionKey); ok {
+		version, err := strconv.Atoi(v)
+		if err != nil || version > ParquetSchemaVersion {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedSchema, v)
+		}
+	}
+
+	rows := make([]parquetRow, f.NumRows())
+	pr := parquet.NewGenericReader[parquetRow](f)
+	defer pr.Close()
+
+	n, err := pr.Read(rows)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	loc := cfg.location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	candles := make([]types.OHLCV, n)
+	for i, row := range rows[:n] {
+		candles[i] = types.OHLCV{
+			Symbol:           row.Symbol,
+			Exchange:         types.Exchange(row.Exchange),
+			Open:             row.Open,
+			High:             row.High,
+			Low:              row.Low,
+			Close:            row.Close,
+			Volume:           row.Volume,
+			DateTime:         time.Unix(0, row.DateTime).In(loc),
+			Source:           row.Source,
+			Freshness:        types.DataFreshness(row.Freshness),
+			AdjustmentFactor: row.AdjustmentFactor,
+		}
+	}
+
+	return candles, nil
+}
@@ -0,0 +1,75 @@
+package ohlcvio
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestWriteParquet_RoundTrip(t *testing.T) {
+	candles := sampleCandles()
+	candles[1].AdjustmentFactor = 0.5
+	candles[1].Freshness = types.FreshnessHistorical
+
+	for _, c := range []Compression{CompressionNone, CompressionSnappy, CompressionGzip, CompressionZstd} {
+		var buf bytes.Buffer
+		if err := WriteParquet(&buf, candles, WithCompression(c)); err != nil {
+			t.Fatalf("%s: expected no error, got %v", c, err)
+		}
+
+		got, err := ReadParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()), WithTimezone(ist))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", c, err)
+		}
+		if len(got) != len(candles) {
+			t.Fatalf("%s: expected %d candles, got %d", c, len(candles), len(got))
+		}
+		for i := range candles {
+			if !got[i].DateTime.Equal(candles[i].DateTime) || got[i].DateTime.Location() != ist {
+				t.Errorf("%s: candle %d: expected time %v, got %v", c, i, candles[i].DateTime, got[i].DateTime)
+			}
+			got[i].DateTime = candles[i].DateTime
+			if got[i] != candles[i] {
+				t.Errorf("%s: candle %d: expected %+v, got %+v", c, i, candles[i], got[i])
+			}
+		}
+	}
+}
+
+func TestWriteParquet_SchemaVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, sampleCandles()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if v, ok := f.Lookup(SchemaVersionKey); !ok || v != "1" {
+		t.Errorf("Expected schema version 1, got %q", v)
+	}
+}
+
+func TestReadParquet_NewerSchema(t *testing.T) {
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[parquetRow](&buf, parquet.KeyValueMetadata(SchemaVersionKey, "2"))
+	w.Write([]parquetRow{{DateTime: time.Now().UnixNano()}})
+	w.Close()
+
+	_, err := ReadParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if !errors.Is(err, ErrUnsupportedSchema) {
+		t.Errorf("Expected ErrUnsupportedSchema, got %v", err)
+	}
+}
+
+func TestWriteParquet_UnknownCompression(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, nil, WithCompression("lzma")); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -0,0 +1,143 @@
+// Package patterns detects candlestick formations in a series. Detectors only
+// look at candle shapes, not at the trend they appear in, so a hammer in an
+// uptrend is still reported; filter events by trend if that matters.
+package patterns
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Pattern names a candlestick formation.
+type Pattern string
+
+const (
+	Doji             Pattern = "doji"
+	Hammer           Pattern = "hammer"
+	BullishEngulfing Pattern = "bullish_engulfing"
+	BearishEngulfing Pattern = "bearish_engulfing"
+	MorningStar      Pattern = "morning_star"
+)
+
+// Event is a pattern found in a series. Single-candle patterns have Start
+// equal to Index; multi-candle patterns start at Start and complete at Index.
+type Event struct {
+	Pattern  Pattern
+	Start    int
+	Index    int
+	DateTime time.Time
+}
+
+const (
+	// dojiBody is the largest body, as a fraction of the range, of a doji.
+	dojiBody = 0.1
+	// hammerShadow is how many bodies long a hammer's lower shadow must be.
+	hammerShadow = 2.0
+	// starBody is the largest body of a morning star's middle candle, as a
+	// fraction of the first candle's body.
+	starBody = 0.3
+)
+
+// Detect returns the events of every pattern in candles, ordered by the index
+// they complete at.
+func Detect(candles types.Series) []Event {
+	var events []Event
+	events = append(events, FindDoji(candles)...)
+	events = append(events, FindHammer(candles)...)
+	events = append(events, FindEngulfing(candles)...)
+	events = append(events, FindMorningStar(candles)...)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Index < events[j].Index
+	})
+	return events
+}
+
+// FindDoji returns the candles whose body is at most a tenth of their range.
+func FindDoji(candles types.Series) []Event {
+	var events []Event
+	for i, c := range candles {
+		if r := c.High - c.Low; r > 0 && body(c) <= dojiBody*r {
+			events = append(events, single(Doji, candles, i))
+		}
+	}
+	return events
+}
+
+// FindHammer returns the candles with a lower shadow at least twice their
+// body, an upper shadow no longer than their body and a non-zero body.
+func FindHammer(candles types.Series) []Event {
+	var events []Event
+	for i, c := range candles {
+		b := body(c)
+		if b == 0 {
+			continue
+		}
+
+		lower := math.Min(c.Open, c.Close) - c.Low
+		upper := c.High - math.Max(c.Open, c.Close)
+		if lower >= hammerShadow*b && upper <= b {
+			events = append(events, single(Hammer, candles, i))
+		}
+	}
+	return events
+}
+
+// FindEngulfing returns the candles whose body engulfs the previous candle's
+// opposite-coloured body: bullish after a down candle, bearish after an up one.
+func FindEngulfing(candles types.Series) []Event {
+	var events []Event
+	for i := 1; i < len(candles); i++ {
+		prev, c := candles[i-1], candles[i]
+		switch {
+		case bearish(prev) && bullish(c) && c.Open <= prev.Close && c.Close >= prev.Open && body(c) > body(prev):
+			events = append(events, multi(BullishEngulfing, candles, i-1, i))
+		case bullish(prev) && bearish(c) && c.Open >= prev.Close && c.Close <= prev.Open && body(c) > body(prev):
+			events = append(events, multi(BearishEngulfing, candles, i-1, i))
+		}
+	}
+	return events
+}
+
+// FindMorningStar returns the three-candle reversals of a down candle, a
+// small-bodied candle below its close and an up candle closing above the
+// middle of the first candle's body.
+func FindMorningStar(candles types.Series) []Event {
+	var events []Event
+	for i := 2; i < len(candles); i++ {
+		first, star, last := candles[i-2], candles[i-1], candles[i]
+		if !bearish(first) || !bullish(last) {
+			continue
+		}
+		if body(star) > starBody*body(first) || math.Max(star.Open, star.Close) > first.Close {
+			continue
+		}
+		if last.Close > (first.Open+first.Close)/2 {
+			events = append(events, multi(MorningStar, candles, i-2, i))
+		}
+	}
+	return events
+}
+
+func single(p Pattern, candles types.Series, i int) Event {
+	return multi(p, candles, i, i)
+}
+
+func multi(p Pattern, candles types.Series, start, i int) Event {
+	return Event{Pattern: p, Start: start, Index: i, DateTime: candles[i].DateTime}
+}
+
+func body(c types.OHLCV) float64 {
+	return math.Abs(c.Close - c.Open)
+}
+
+func bullish(c types.OHLCV) bool {
+	return c.Close > c.Open
+}
+
+func bearish(c types.OHLCV) bool {
+	return c.Close < c.Open
+}
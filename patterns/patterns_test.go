@@ -0,0 +1,114 @@
+package patterns
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func candle(open, high, low, close float64) types.OHLCV {
+	return types.OHLCV{Open: open, High: high, Low: low, Close: close}
+}
+
+func indices(events []Event) []int {
+	out := make([]int, len(events))
+	for i, e := range events {
+		out[i] = e.Index
+	}
+	return out
+}
+
+func TestFindDoji(t *testing.T) {
+	s := types.Series{
+		candle(100, 105, 95, 100.5),
+		candle(100, 105, 95, 104),
+		candle(100, 100, 100, 100),
+	}
+
+	if got := indices(FindDoji(s)); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("Expected a doji at 0, got %v", got)
+	}
+}
+
+func TestFindHammer(t *testing.T) {
+	s := types.Series{
+		candle(100, 102, 94, 101.5),
+		candle(100, 108, 94, 101.5),
+		candle(100, 101, 99, 100),
+		candle(100, 101, 99.5, 100.5),
+	}
+
+	if got := indices(FindHammer(s)); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("Expected a hammer at 0 only, got %v", got)
+	}
+}
+
+func TestFindEngulfing(t *testing.T) {
+	s := types.Series{
+		candle(102, 103, 99, 100),
+		candle(99, 105, 98, 104),
+		candle(105, 106, 96, 97),
+		candle(97.5, 98, 97, 97.2),
+	}
+
+	events := FindEngulfing(s)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", events)
+	}
+	if events[0].Pattern != BullishEngulfing || events[0].Start != 0 || events[0].Index != 1 {
+		t.Errorf("Expected bullish engulfing over 0-1, got %+v", events[0])
+	}
+	if events[1].Pattern != BearishEngulfing || events[1].Start != 1 || events[1].Index != 2 {
+		t.Errorf("Expected bearish engulfing over 1-2, got %+v", events[1])
+	}
+}
+
+func TestFindMorningStar(t *testing.T) {
+	s := types.Series{
+		candle(110, 111, 99, 100),
+		candle(98, 99, 96, 98.5),
+		candle(99, 108, 98, 107),
+		candle(107, 108, 103, 104),
+	}
+
+	events := FindMorningStar(s)
+	if len(events) != 1 || events[0].Start != 0 || events[0].Index != 2 {
+		t.Errorf("Expected a morning star over 0-2, got %+v", events)
+	}
+
+	s[2].Close = 104
+	if events := FindMorningStar(s); len(events) != 0 {
+		t.Errorf("Expected no morning star below the first body's midpoint, got %+v", events)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := types.Series{
+		candle(110, 111, 99, 100),
+		candle(98, 99, 96, 98.5),
+		candle(99, 108, 98, 107),
+	}
+	for i := range s {
+		s[i].DateTime = day.AddDate(0, 0, i)
+	}
+
+	events := Detect(s)
+	if got := indices(events); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("Expected events at 1 and 2, got %+v", events)
+	}
+	if events[0].Pattern != Hammer || events[1].Pattern != MorningStar {
+		t.Errorf("Expected a hammer then a morning star, got %+v", events)
+	}
+	if !events[1].DateTime.Equal(s[2].DateTime) {
+		t.Errorf("Expected the completing candle's time, got %v", events[1].DateTime)
+	}
+}
+
+func TestDetect_Empty(t *testing.T) {
+	if events := Detect(nil); len(events) != 0 {
+		t.Errorf("Expected no events, got %+v", events)
+	}
+}
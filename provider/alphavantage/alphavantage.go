@@ -0,0 +1,395 @@
+package alphavantage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/internal/retry"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const baseURL = "https://www.alphavantage.co/query"
+
+// alphaVantageResponse holds the raw response. The time series key depends on
+// the function and interval ("Time Series (5min)", "Time Series (Daily)"), so
+// the payload is decoded into a map and picked apart afterwards.
+type alphaVantageResponse map[string]json.RawMessage
+
+type alphaVantageCandle struct {
+	Open   string `json:"1. open"`
+	High   string `json:"2. high"`
+	Low    string `json:"3. low"`
+	Close  string `json:"4. close"`
+	Volume string `json:"5. volume"`
+}
+
+type config struct {
+	apiKey            string
+	httpClient        *http.Client
+	transport         provider.TransportConfig
+	retryHooks        provider.RetryHooks
+	conditionalCache  int64
+	maxResponseSize   int64
+	requestsPerMinute int
+	precision         provider.Precision
+}
+
+type Option func(*config)
+
+// WithAPIKey sets the Alpha Vantage API key sent with every request.
+func WithAPIKey(apiKey string) Option {
+	return func(c *config) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithRequestsPerMinute overrides the free-tier limit of 5 requests per minute
+// for premium API keys.
+func WithRequestsPerMinute(n int) Option {
+	return func(c *config) {
+		c.requestsPerMinute = n
+	}
+}
+
+// WithTransport tunes the connections of the default HTTP client, or of one
+// set with WithHTTPClient that has no Transport of its own.
+func WithTransport(t provider.TransportConfig) Option {
+	return func(c *config) {
+		c.transport = t
+	}
+}
+
+// WithRetryHooks calls hooks on every retry of a request and when one is
+// given up on.
+func WithRetryHooks(hooks provider.RetryHooks) Option {
+	return func(c *config) {
+		c.retryHooks = hooks
+	}
+}
+
+// WithConditionalCache keeps up to maxBytes of responses carrying an ETag or
+// Last-Modified header, and asks for them again conditionally; a 304 Not
+// Modified is then answered with the kept response.
+func WithConditionalCache(maxBytes int64) Option {
+	return func(c *config) {
+		c.conditionalCache = maxBytes
+	}
+}
+
+// WithMaxResponseSize caps the size of a response, after decompression, at n
+// bytes instead of provider.DefaultMaxResponseSize. Larger responses fail
+// with an error matching provider.ErrResponseTooLarge. Zero removes the
+// limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *config) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithPrecision sets how many decimal places prices are rounded to. The
+// default is provider.DefaultPrecision.
+func WithPrecision(p provider.Precision) Option {
+	return func(c *config) {
+		c.precision = p
+	}
+}
+
+type AlphaVantageProvider struct {
+	client    httpclient.Doer
+	apiKey    string
+	precision provider.Precision
+}
+
+func NewAlphaVantageProvider(opts ...Option) *AlphaVantageProvider {
+	cfg := config{
+		httpClient:        &http.Client{Timeout: 30 * time.Second},
+		maxResponseSize:   provider.DefaultMaxResponseSize,
+		requestsPerMinute: 5,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	clientConfig := httpclient.ClientConfig{
+		Name:                  "alphavantage",
+		HttpClient:            cfg.httpClient,
+		Transport:             httpclient.TransportConfig(cfg.transport),
+		ConditionalCacheBytes: cfg.conditionalCache,
+		MaxResponseBytes:      cfg.maxResponseSize,
+		RateLimitConfig: httpclient.RateLimitConfig{
+			RequestsPerSecond: min(cfg.requestsPerMinute, 50),
+			RequestsPerMinute: cfg.requestsPerMinute,
+			RequestsPerHour:   cfg.requestsPerMinute * 60,
+			Shared:            "alphavantage/" + cfg.apiKey,
+		},
+		RetryConfig: httpclient.RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     500 * time.Millisecond,
+			MaxDelay:      10 * time.Second,
+			RetryOnStatus: []uint{429, 500, 502, 503},
+			Jitter:        retry.EqualJitter,
+			Budget:        max(cfg.requestsPerMinute/5, 1),
+			BudgetWindow:  time.Minute,
+			Hooks:         cfg.retryHooks,
+		},
+	}
+
+	return &AlphaVantageProvider{
+		client:    httpclient.NewClient(clientConfig),
+		apiKey:    cfg.apiKey,
+		precision: cfg.precision,
+	}
+}
+
+func (a *AlphaVantageProvider) Name() string {
+	return "alphavantage"
+}
+
+func (a *AlphaVantageProvider) Freshness() types.DataFreshness {
+	return types.FreshnessDelayed
+}
+
+// RemainingRateLimit reports the requests left in the current second, minute
+// and hour of the provider's rate limit.
+func (a *AlphaVantageProvider) RemainingRateLimit() (provider.RateLimits, bool) {
+	c, ok := a.client.(*httpclient.Client)
+	if !ok {
+		return provider.RateLimits{}, false
+	}
+
+	perSecond, perMinute, perHour := c.Remaining()
+	return provider.RateLimits{
+		RequestsPerSecond: perSecond,
+		RequestsPerMinute: perMinute,
+		RequestsPerHour:   perHour,
+	}, true
+}
+
+func (a *AlphaVantageProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	if a.apiKey == "" {
+		return nil, errors.New("alphavantage api key is required")
+	}
+
+	avSymbol, err := a.formatSymbol(symbol, exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	query, seriesKey, err := a.intervalToQuery(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval: %w", err)
+	}
+	query.Set("symbol", avSymbol)
+	query.Set("outputsize", "full")
+	query.Set("apikey", a.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := a.client.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &provider.ProviderError{Provider: a.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	var data alphaVantageResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// Alpha Vantage reports errors and throttling with a 200 status.
+	if err := a.responseError(data, symbol, exchange); err != nil {
+		return nil, err
+	}
+
+	rawSeries, ok := data[seriesKey]
+	if !ok {
+		return nil, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+
+	var series map[string]alphaVantageCandle
+	if err := json.Unmarshal(rawSeries, &series); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal time series: %w", err)
+	}
+
+	srcLoc := a.seriesLocation(data)
+	loc := exchange.Location()
+	ohlcvs := make([]types.OHLCV, 0, len(series))
+
+	for ts, c := range series {
+		t, err := a.parseTimestamp(ts, srcLoc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp %q: %w", ts, err)
+		}
+		if t.Before(from) || (!to.IsZero() && t.After(to)) {
+			continue
+		}
+
+		ohlcv, err := a.toOHLCV(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse candle at %s: %w", ts, err)
+		}
+		ohlcv.Symbol = symbol
+		ohlcv.Exchange = exchange
+		ohlcv.DateTime = t.In(loc)
+		ohlcv.Interval = interval
+		ohlcv.Source = a.Name()
+		ohlcv.Freshness = a.Freshness()
+
+		ohlcvs = append(ohlcvs, ohlcv)
+	}
+
+	sort.Slice(ohlcvs, func(i, j int) bool {
+		return ohlcvs[i].DateTime.Before(ohlcvs[j].DateTime)
+	})
+
+	return a.normalizeOHLCVs(ohlcvs), nil
+}
+
+func (a *AlphaVantageProvider) responseError(data alphaVantageResponse, symbol string, exchange types.Exchange) error {
+	var msg string
+
+	if raw, ok := data["Error Message"]; ok {
+		_ = json.Unmarshal(raw, &msg)
+		return fmt.Errorf("%w: %s on exchange %s: %s", provider.ErrSymbolNotFound, symbol, exchange, msg)
+	}
+
+	for _, key := range []string{"Note", "Information"} {
+		if raw, ok := data[key]; ok {
+			_ = json.Unmarshal(raw, &msg)
+			return fmt.Errorf("%w: %s", provider.ErrRateLimited, msg)
+		}
+	}
+
+	return nil
+}
+
+// SupportsInterval reports whether Alpha Vantage serves candles of interval.
+func (a *AlphaVantageProvider) SupportsInterval(interval types.Interval) bool {
+	_, _, err := a.intervalToQuery(interval)
+	return err == nil
+}
+
+// SupportsExchange reports whether Alpha Vantage serves exchange; it doesn't
+// list NSE.
+func (a *AlphaVantageProvider) SupportsExchange(exchange types.Exchange) bool {
+	_, err := a.formatSymbol("", exchange)
+	return err == nil
+}
+
+func (a *AlphaVantageProvider) formatSymbol(symbol string, exchange types.Exchange) (string, error) {
+	switch exchange {
+	case types.ExchangeBSE:
+		return symbol + ".BSE", nil
+	case types.ExchangeNSE:
+		return "", fmt.Errorf("%w: %s is not supported by alphavantage", provider.ErrUnsupportedExchange, exchange)
+	default:
+		return symbol, nil
+	}
+}
+
+func (a *AlphaVantageProvider) intervalToQuery(i types.Interval) (query url.Values, seriesKey string, err error) {
+	query = url.Values{}
+
+	switch i {
+	case types.Interval1m, types.Interval5m, types.Interval15m, types.Interval30m:
+		avInterval := strings.TrimSuffix(string(i), "m") + "min"
+		query.Set("function", "TIME_SERIES_INTRADAY")
+		query.Set("interval", avInterval)
+		return query, "Time Series (" + avInterval + ")", nil
+	case types.Interval1h:
+		query.Set("function", "TIME_SERIES_INTRADAY")
+		query.Set("interval", "60min")
+		return query, "Time Series (60min)", nil
+	case types.Interval1d:
+		query.Set("function", "TIME_SERIES_DAILY")
+		return query, "Time Series (Daily)", nil
+	default:
+		return nil, "", fmt.Errorf("%w: %s", provider.ErrUnknownInterval, i)
+	}
+}
+
+// seriesLocation returns the time zone named in the response metadata, which
+// is the zone the time series keys are expressed in.
+func (a *AlphaVantageProvider) seriesLocation(data alphaVantageResponse) *time.Location {
+	var meta map[string]string
+	if err := json.Unmarshal(data["Meta Data"], &meta); err == nil {
+		for k, v := range meta {
+			if strings.HasSuffix(k, "Time Zone") {
+				if loc, err := time.LoadLocation(v); err == nil {
+					return loc
+				}
+			}
+		}
+	}
+
+	loc, _ := time.LoadLocation("US/Eastern")
+	return loc
+}
+
+func (a *AlphaVantageProvider) parseTimestamp(ts string, loc *time.Location) (time.Time, error) {
+	if len(ts) == len("2006-01-02") {
+		return time.ParseInLocation("2006-01-02", ts, loc)
+	}
+	return time.ParseInLocation("2006-01-02 15:04:05", ts, loc)
+}
+
+func (a *AlphaVantageProvider) toOHLCV(c alphaVantageCandle) (types.OHLCV, error) {
+	var (
+		ohlcv types.OHLCV
+		err   error
+	)
+
+	if ohlcv.Open, err = strconv.ParseFloat(c.Open, 64); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.High, err = strconv.ParseFloat(c.High, 64); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.Low, err = strconv.ParseFloat(c.Low, 64); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.Close, err = strconv.ParseFloat(c.Close, 64); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.Volume, err = strconv.ParseInt(c.Volume, 10, 64); err != nil {
+		return ohlcv, err
+	}
+
+	return ohlcv, nil
+}
+
+func (a *AlphaVantageProvider) normalizeOHLCVs(ohlcvs []types.OHLCV) []types.OHLCV {
+	return ohlcv.Round(a.precision).Transform(ohlcvs)
+}
@@ -0,0 +1,284 @@
+package alphavantage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type mockHTTPClient struct {
+	calledCount int
+	requests    []*http.Request
+	responses   []*http.Response
+}
+
+func NewMockHTTPClient(responses []*http.Response) *mockHTTPClient {
+	return &mockHTTPClient{
+		calledCount: 0,
+		requests:    []*http.Request{},
+		responses:   responses,
+	}
+}
+
+func (m *mockHTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	m.calledCount++
+	m.requests = append(m.requests, req)
+
+	if m.calledCount-1 >= len(m.responses) {
+		return nil, errors.New("no more mock responses")
+	}
+	return m.responses[m.calledCount-1], nil
+}
+
+func createResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+const intradayBody = `{
+	"Meta Data": {
+		"1. Information": "Intraday (5min) open, high, low, close prices and volume",
+		"2. Symbol": "RELIANCE.BSE",
+		"4. Interval": "5min",
+		"6. Time Zone": "US/Eastern"
+	},
+	"Time Series (5min)": {
+		"2024-01-05 00:05:00": {"1. open": "2590.1", "2. high": "2595.456", "3. low": "2588.0", "4. close": "2594.999", "5. volume": "2000"},
+		"2024-01-05 00:00:00": {"1. open": "2585.0", "2. high": "2591.0", "3. low": "2584.5", "4. close": "2590.1", "5. volume": "1000"}
+	}
+}`
+
+const dailyBody = `{
+	"Meta Data": {
+		"2. Symbol": "RELIANCE.BSE",
+		"5. Time Zone": "US/Eastern"
+	},
+	"Time Series (Daily)": {
+		"2024-01-03": {"1. open": "3", "2. high": "3", "3. low": "3", "4. close": "3", "5. volume": "3"},
+		"2024-01-02": {"1. open": "2", "2. high": "2", "3. low": "2", "4. close": "2", "5. volume": "2"},
+		"2024-01-01": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "1"}
+	}
+}`
+
+func newTestProvider(responses ...*http.Response) (*AlphaVantageProvider, *mockHTTPClient) {
+	mockClient := NewMockHTTPClient(responses)
+	provider := NewAlphaVantageProvider(WithAPIKey("demo"))
+	provider.client = mockClient
+	return provider, mockClient
+}
+
+func TestNewAlphaVantageProvider(t *testing.T) {
+	provider := NewAlphaVantageProvider(WithAPIKey("secret"), WithHTTPClient(&http.Client{}), WithRequestsPerMinute(75))
+
+	if provider == nil {
+		t.Fatal("Expected provider to be created")
+	}
+	if provider.apiKey != "secret" {
+		t.Errorf("Expected api key 'secret', got '%s'", provider.apiKey)
+	}
+	if provider.Name() != "alphavantage" {
+		t.Errorf("Expected name 'alphavantage', got '%s'", provider.Name())
+	}
+}
+
+func TestAlphaVantageProvider_Provide_Intraday(t *testing.T) {
+	provider, mockClient := newTestProvider(createResponse(200, intradayBody))
+
+	from := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	ohlcvs, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeBSE, types.Interval5m, from, time.Time{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ohlcvs) != 2 {
+		t.Fatalf("Expected 2 OHLCV records, got %d", len(ohlcvs))
+	}
+
+	query := mockClient.requests[0].URL.Query()
+	if query.Get("function") != "TIME_SERIES_INTRADAY" {
+		t.Errorf("Expected TIME_SERIES_INTRADAY, got %s", query.Get("function"))
+	}
+	if query.Get("interval") != "5min" {
+		t.Errorf("Expected interval 5min, got %s", query.Get("interval"))
+	}
+	if query.Get("symbol") != "RELIANCE.BSE" {
+		t.Errorf("Expected symbol RELIANCE.BSE, got %s", query.Get("symbol"))
+	}
+	if query.Get("apikey") != "demo" {
+		t.Errorf("Expected apikey demo, got %s", query.Get("apikey"))
+	}
+
+	first := ohlcvs[0]
+	if !first.DateTime.Before(ohlcvs[1].DateTime) {
+		t.Error("Expected candles sorted by time")
+	}
+	if first.Open != 2585 || first.Volume != 1000 {
+		t.Errorf("Unexpected first candle %+v", first)
+	}
+	if ohlcvs[1].High != 2595.46 || ohlcvs[1].Close != 2595 {
+		t.Errorf("Expected rounded prices, got %+v", ohlcvs[1])
+	}
+	if first.Source != "alphavantage" {
+		t.Errorf("Expected source alphavantage, got %s", first.Source)
+	}
+	if first.DateTime.Location().String() != "Asia/Kolkata" {
+		t.Errorf("Expected time in IST, got %v", first.DateTime.Location())
+	}
+
+	eastern, _ := time.LoadLocation("US/Eastern")
+	if want := time.Date(2024, 1, 5, 0, 0, 0, 0, eastern); !first.DateTime.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, first.DateTime)
+	}
+}
+
+func TestAlphaVantageProvider_Provide_DailyFiltersRange(t *testing.T) {
+	provider, mockClient := newTestProvider(createResponse(200, dailyBody))
+
+	eastern, _ := time.LoadLocation("US/Eastern")
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, eastern)
+	to := time.Date(2024, 1, 2, 23, 59, 0, 0, eastern)
+
+	ohlcvs, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeBSE, types.Interval1d, from, to)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ohlcvs) != 1 || ohlcvs[0].Close != 2 {
+		t.Errorf("Expected only the 2024-01-02 candle, got %+v", ohlcvs)
+	}
+	if fn := mockClient.requests[0].URL.Query().Get("function"); fn != "TIME_SERIES_DAILY" {
+		t.Errorf("Expected TIME_SERIES_DAILY, got %s", fn)
+	}
+}
+
+func TestAlphaVantageProvider_Provide_MissingAPIKey(t *testing.T) {
+	provider := NewAlphaVantageProvider()
+
+	_, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeBSE, types.Interval1d, time.Now(), time.Time{})
+
+	if err == nil {
+		t.Error("Expected error when api key is missing")
+	}
+}
+
+func TestAlphaVantageProvider_Provide_UnsupportedExchange(t *testing.T) {
+	av, mockClient := newTestProvider()
+
+	_, err := av.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Now(), time.Time{})
+
+	if !errors.Is(err, provider.ErrUnsupportedExchange) {
+		t.Errorf("Expected ErrUnsupportedExchange for NSE, got %v", err)
+	}
+	if mockClient.calledCount != 0 {
+		t.Error("Expected no request for unsupported exchange")
+	}
+	if av.SupportsExchange(types.ExchangeNSE) || !av.SupportsExchange(types.ExchangeBSE) {
+		t.Error("Expected BSE to be supported and NSE not")
+	}
+	if av.SupportsInterval(types.Interval1wk) || !av.SupportsInterval(types.Interval5m) {
+		t.Error("Expected 5m to be supported and 1wk not")
+	}
+}
+
+func TestAlphaVantageProvider_Provide_UnsupportedInterval(t *testing.T) {
+	provider, _ := newTestProvider()
+
+	_, err := provider.Provide(context.Background(), "IBM", types.Exchange("NYSE"), types.Interval1wk, time.Now(), time.Time{})
+
+	if err == nil || !strings.Contains(err.Error(), "invalid interval") {
+		t.Errorf("Expected invalid interval error, got %v", err)
+	}
+}
+
+func TestAlphaVantageProvider_Provide_APIErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"ErrorMessage", `{"Error Message": "Invalid API call."}`},
+		{"Note", `{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`},
+		{"Information", `{"Information": "This is a premium endpoint."}`},
+		{"MissingSeries", `{"Meta Data": {}}`},
+		{"InvalidJSON", `not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newTestProvider(createResponse(200, tt.body))
+
+			_, err := provider.Provide(context.Background(), "IBM", types.Exchange("NYSE"), types.Interval1d, time.Time{}, time.Time{})
+
+			if err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestAlphaVantageProvider_Provide_NonOKResponse(t *testing.T) {
+	provider, _ := newTestProvider(createResponse(503, "unavailable"))
+
+	_, err := provider.Provide(context.Background(), "IBM", types.Exchange("NYSE"), types.Interval1d, time.Time{}, time.Time{})
+
+	expectedError := "non-OK response: 503 unavailable"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error '%s', got '%v'", expectedError, err)
+	}
+}
+
+func TestAlphaVantageProvider_Provide_InvalidNumber(t *testing.T) {
+	body := `{"Time Series (Daily)": {"2024-01-01": {"1. open": "x", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "1"}}}`
+	provider, _ := newTestProvider(createResponse(200, body))
+
+	_, err := provider.Provide(context.Background(), "IBM", types.Exchange("NYSE"), types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error for invalid number")
+	}
+}
+
+func TestAlphaVantageProvider_Provide_RequestFailed(t *testing.T) {
+	provider, _ := newTestProvider()
+
+	_, err := provider.Provide(context.Background(), "IBM", types.Exchange("NYSE"), types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil || !strings.HasPrefix(err.Error(), "request failed") {
+		t.Errorf("Expected request failed error, got %v", err)
+	}
+}
+
+func TestAlphaVantageProvider_Provide_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		target error
+	}{
+		{"ErrorMessage", `{"Error Message": "Invalid API call."}`, provider.ErrSymbolNotFound},
+		{"Note", `{"Note": "Our standard API call frequency is 5 calls per minute."}`, provider.ErrRateLimited},
+		{"Information", `{"Information": "You have reached the daily rate limit."}`, provider.ErrRateLimited},
+		{"MissingSeries", `{"Meta Data": {}}`, provider.ErrNoData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProvider(createResponse(200, tt.body))
+
+			_, err := p.Provide(context.Background(), "IBM", types.Exchange("NYSE"), types.Interval1d, time.Time{}, time.Time{})
+
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,553 @@
+package bse
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/internal/retry"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const (
+	baseURL  = "https://api.bseindia.com/BseIndiaAPI/api"
+	referer  = "https://www.bseindia.com/"
+	agent    = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+	scripURL = baseURL + "/ListofScripData/w?Group=&Scripcode=&industry=&segment=Equity&status=Active"
+)
+
+// scrip is an entry of the BSE list of scrips.
+type scrip struct {
+	Code   string `json:"SCRIP_CD"`
+	Symbol string `json:"scrip_id"`
+}
+
+// graphResponse holds the intraday price graph. Data is itself a JSON encoded
+// array of price points.
+type graphResponse struct {
+	Data string `json:"Data"`
+}
+
+type graphPoint struct {
+	DateTime string `json:"dttm"`
+	Price    string `json:"vale1"`
+	Volume   string `json:"vole"`
+}
+
+type config struct {
+	httpClient       *http.Client
+	transport        provider.TransportConfig
+	retryHooks       provider.RetryHooks
+	conditionalCache int64
+	maxResponseSize  int64
+	rateLimits       provider.RateLimits
+	retryBudget      provider.RetryBudget
+	scripCodes       map[string]string
+	precision        provider.Precision
+}
+
+type Option func(*config)
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithRateLimits overrides the default limits of 2 requests per second, 60
+// per minute and 1000 per hour.
+func WithRateLimits(limits provider.RateLimits) Option {
+	return func(c *config) {
+		c.rateLimits = limits
+	}
+}
+
+// WithRetryBudget overrides the default budget of 12 retries per minute,
+// shared by every BSE provider in the process with the same budget.
+func WithRetryBudget(budget provider.RetryBudget) Option {
+	return func(c *config) {
+		c.retryBudget = budget
+	}
+}
+
+// WithScripCodes maps symbols to BSE scrip codes, taking precedence over the
+// BSE list of scrips. Symbols found in codes don't need the list downloaded.
+func WithScripCodes(codes map[string]string) Option {
+	return func(c *config) {
+		for symbol, code := range codes {
+			c.scripCodes[strings.ToUpper(symbol)] = code
+		}
+	}
+}
+
+// WithTransport tunes the connections of the default HTTP client, or of one
+// set with WithHTTPClient that has no Transport of its own.
+func WithTransport(t provider.TransportConfig) Option {
+	return func(c *config) {
+		c.transport = t
+	}
+}
+
+// WithRetryHooks calls hooks on every retry of a request and when one is
+// given up on.
+func WithRetryHooks(hooks provider.RetryHooks) Option {
+	return func(c *config) {
+		c.retryHooks = hooks
+	}
+}
+
+// WithConditionalCache keeps up to maxBytes of responses carrying an ETag or
+// Last-Modified header, and asks for them again conditionally; a 304 Not
+// Modified is then answered with the kept response.
+func WithConditionalCache(maxBytes int64) Option {
+	return func(c *config) {
+		c.conditionalCache = maxBytes
+	}
+}
+
+// WithMaxResponseSize caps the size of a response, after decompression, at n
+// bytes instead of provider.DefaultMaxResponseSize. Larger responses fail
+// with an error matching provider.ErrResponseTooLarge. Zero removes the
+// limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *config) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithPrecision sets how many decimal places prices are rounded to. The
+// default is provider.DefaultPrecision.
+func WithPrecision(p provider.Precision) Option {
+	return func(c *config) {
+		c.precision = p
+	}
+}
+
+// BSEProvider fetches candles from the BSE India website's API. Daily, weekly
+// and monthly candles cover the full history of a scrip; 1m candles are built
+// from the intraday price graph, which only covers the latest session.
+type BSEProvider struct {
+	client    httpclient.Doer
+	overrides map[string]string
+	precision provider.Precision
+
+	mu     sync.Mutex
+	scrips map[string]string
+}
+
+func NewBSEProvider(opts ...Option) *BSEProvider {
+	cfg := config{
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		maxResponseSize: provider.DefaultMaxResponseSize,
+		rateLimits: provider.RateLimits{
+			RequestsPerSecond: 2,
+			RequestsPerMinute: 60,
+			RequestsPerHour:   1000,
+		},
+		retryBudget: provider.RetryBudget{Retries: 12, Window: time.Minute},
+		scripCodes:  make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	clientConfig := httpclient.ClientConfig{
+		Name:                  "bse",
+		HttpClient:            cfg.httpClient,
+		Transport:             httpclient.TransportConfig(cfg.transport),
+		ConditionalCacheBytes: cfg.conditionalCache,
+		MaxResponseBytes:      cfg.maxResponseSize,
+		RateLimitConfig: httpclient.RateLimitConfig{
+			RequestsPerSecond: cfg.rateLimits.RequestsPerSecond,
+			RequestsPerMinute: cfg.rateLimits.RequestsPerMinute,
+			RequestsPerHour:   cfg.rateLimits.RequestsPerHour,
+			MaxQueue:          cfg.rateLimits.MaxQueue,
+			Shared:            "bse",
+		},
+		RetryConfig: httpclient.RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     500 * time.Millisecond,
+			MaxDelay:      10 * time.Second,
+			RetryOnStatus: []uint{429, 500, 502, 503},
+			Jitter:        retry.EqualJitter,
+			Budget:        cfg.retryBudget.Retries,
+			BudgetWindow:  cfg.retryBudget.Window,
+			Hooks:         cfg.retryHooks,
+		},
+	}
+
+	return &BSEProvider{
+		client:    httpclient.NewClient(clientConfig),
+		overrides: cfg.scripCodes,
+		precision: cfg.precision,
+	}
+}
+
+func (b *BSEProvider) Name() string {
+	return "bse"
+}
+
+func (b *BSEProvider) Freshness() types.DataFreshness {
+	return types.FreshnessDelayed
+}
+
+// RemainingRateLimit reports the requests left in the current second, minute
+// and hour of the provider's rate limit.
+func (b *BSEProvider) RemainingRateLimit() (provider.RateLimits, bool) {
+	c, ok := b.client.(*httpclient.Client)
+	if !ok {
+		return provider.RateLimits{}, false
+	}
+
+	perSecond, perMinute, perHour := c.Remaining()
+	return provider.RateLimits{
+		RequestsPerSecond: perSecond,
+		RequestsPerMinute: perMinute,
+		RequestsPerHour:   perHour,
+	}, true
+}
+
+// SupportsInterval reports whether BSE serves candles of interval.
+func (b *BSEProvider) SupportsInterval(interval types.Interval) bool {
+	switch interval {
+	case types.Interval1m, types.Interval1d, types.Interval1wk, types.Interval1mo:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsExchange reports whether exchange is BSE, the only one served.
+func (b *BSEProvider) SupportsExchange(exchange types.Exchange) bool {
+	return exchange == types.ExchangeBSE
+}
+
+func (b *BSEProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	if !b.SupportsExchange(exchange) {
+		return nil, fmt.Errorf("%w: %s is not supported by bse", provider.ErrUnsupportedExchange, exchange)
+	}
+	if !b.SupportsInterval(interval) {
+		return nil, fmt.Errorf("invalid interval: %w: %s", provider.ErrUnknownInterval, interval)
+	}
+
+	code, err := b.ScripCode(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var ohlcvs []types.OHLCV
+	if interval == types.Interval1m {
+		ohlcvs, err = b.provideIntraday(ctx, code, from, to)
+	} else {
+		ohlcvs, err = b.provideEOD(ctx, code, interval, from, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ohlcvs) == 0 {
+		return nil, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+
+	loc := exchange.Location()
+	for i := range ohlcvs {
+		ohlcvs[i].Symbol = symbol
+		ohlcvs[i].Exchange = exchange
+		ohlcvs[i].DateTime = ohlcvs[i].DateTime.In(loc)
+		ohlcvs[i].Interval = interval
+		ohlcvs[i].Source = b.Name()
+		ohlcvs[i].Freshness = b.Freshness()
+	}
+
+	sort.Slice(ohlcvs, func(i, j int) bool {
+		return ohlcvs[i].DateTime.Before(ohlcvs[j].DateTime)
+	})
+
+	return b.normalizeOHLCVs(ohlcvs), nil
+}
+
+// ScripCode resolves symbol to its BSE scrip code. Numeric symbols are taken
+// to be scrip codes already. Other symbols are looked up in the codes given to
+// WithScripCodes, then in the BSE list of scrips, downloaded on first use.
+func (b *BSEProvider) ScripCode(ctx context.Context, symbol string) (string, error) {
+	if _, err := strconv.Atoi(symbol); err == nil {
+		return symbol, nil
+	}
+
+	key := strings.ToUpper(symbol)
+	if code, ok := b.overrides[key]; ok {
+		return code, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.scrips == nil {
+		scrips, err := b.loadScrips(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to load scrips: %w", err)
+		}
+		b.scrips = scrips
+	}
+
+	code, ok := b.scrips[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, symbol, types.ExchangeBSE)
+	}
+
+	return code, nil
+}
+
+func (b *BSEProvider) loadScrips(ctx context.Context) (map[string]string, error) {
+	body, err := b.get(ctx, scripURL, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []scrip
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scrips: %w", err)
+	}
+	if len(list) == 0 {
+		return nil, errors.New("empty list of scrips")
+	}
+
+	scrips := make(map[string]string, len(list))
+	for _, s := range list {
+		if s.Symbol == "" || s.Code == "" {
+			continue
+		}
+		scrips[strings.ToUpper(strings.TrimSpace(s.Symbol))] = strings.TrimSpace(s.Code)
+	}
+
+	return scrips, nil
+}
+
+func (b *BSEProvider) provideEOD(ctx context.Context, code string, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	loc := types.ExchangeBSE.Location()
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 0)
+	}
+
+	query := url.Values{}
+	query.Set("pageType", "0")
+	query.Set("rbType", b.periodType(interval))
+	query.Set("Scode", code)
+	query.Set("FDates", from.In(loc).Format("02/01/2006"))
+	query.Set("TDates", to.In(loc).Format("02/01/2006"))
+
+	body, err := b.get(ctx, baseURL+"/StockPriceCSVDownload/w?"+query.Encode(), "text/csv")
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse price csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	cols := map[string]int{}
+	for i, col := range records[0] {
+		cols[strings.TrimSpace(col)] = i
+	}
+	for _, col := range []string{"Date", "Open Price", "High Price", "Low Price", "Close Price", "No.of Shares"} {
+		if _, ok := cols[col]; !ok {
+			return nil, fmt.Errorf("price csv is missing column %q", col)
+		}
+	}
+
+	ohlcvs := make([]types.OHLCV, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		if len(rec) < len(records[0]) {
+			continue
+		}
+
+		t, err := b.parseDate(rec[cols["Date"]], loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date %q: %w", rec[cols["Date"]], err)
+		}
+
+		var c types.OHLCV
+		c.DateTime = t
+		fields := []struct {
+			col string
+			dst *float64
+		}{
+			{"Open Price", &c.Open},
+			{"High Price", &c.High},
+			{"Low Price", &c.Low},
+			{"Close Price", &c.Close},
+		}
+		for _, f := range fields {
+			if *f.dst, err = b.parseNumber(rec[cols[f.col]]); err != nil {
+				return nil, fmt.Errorf("failed to parse %s at %s: %w", f.col, rec[cols["Date"]], err)
+			}
+		}
+		volume, err := b.parseNumber(rec[cols["No.of Shares"]])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse No.of Shares at %s: %w", rec[cols["Date"]], err)
+		}
+		c.Volume = int64(volume)
+
+		ohlcvs = append(ohlcvs, c)
+	}
+
+	return ohlcvs, nil
+}
+
+// provideIntraday builds 1m candles from the price points of the intraday
+// graph, which covers the latest session only.
+func (b *BSEProvider) provideIntraday(ctx context.Context, code string, from, to time.Time) ([]types.OHLCV, error) {
+	query := url.Values{}
+	query.Set("scripcode", code)
+	query.Set("flag", "0")
+	query.Set("fromdate", "")
+	query.Set("todate", "")
+	query.Set("seriesid", "")
+
+	body, err := b.get(ctx, baseURL+"/StockReachGraph/w?"+query.Encode(), "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp graphResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if resp.Data == "" {
+		return nil, nil
+	}
+
+	var points []graphPoint
+	if err := json.Unmarshal([]byte(resp.Data), &points); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal price points: %w", err)
+	}
+
+	loc := types.ExchangeBSE.Location()
+	var ohlcvs []types.OHLCV
+	for _, p := range points {
+		t, err := time.ParseInLocation("Mon Jan 02 2006 15:04:05", p.DateTime, loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp %q: %w", p.DateTime, err)
+		}
+		t = t.Truncate(time.Minute)
+		if (!from.IsZero() && t.Before(from)) || (!to.IsZero() && t.After(to)) {
+			continue
+		}
+
+		price, err := b.parseNumber(p.Price)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price at %s: %w", p.DateTime, err)
+		}
+		var volume float64
+		if p.Volume != "" {
+			if volume, err = b.parseNumber(p.Volume); err != nil {
+				return nil, fmt.Errorf("failed to parse volume at %s: %w", p.DateTime, err)
+			}
+		}
+
+		if n := len(ohlcvs); n > 0 && ohlcvs[n-1].DateTime.Equal(t) {
+			last := &ohlcvs[n-1]
+			last.High = max(last.High, price)
+			last.Low = min(last.Low, price)
+			last.Close = price
+			last.Volume += int64(volume)
+			continue
+		}
+
+		ohlcvs = append(ohlcvs, types.OHLCV{
+			Open:     price,
+			High:     price,
+			Low:      price,
+			Close:    price,
+			Volume:   int64(volume),
+			DateTime: t,
+		})
+	}
+
+	return ohlcvs, nil
+}
+
+func (b *BSEProvider) get(ctx context.Context, reqURL, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// The API rejects requests that don't look like they come from the
+	// BSE website.
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", agent)
+	req.Header.Set("Referer", referer)
+
+	res, err := b.client.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &provider.ProviderError{Provider: b.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	return body, nil
+}
+
+func (b *BSEProvider) periodType(i types.Interval) string {
+	switch i {
+	case types.Interval1wk:
+		return "W"
+	case types.Interval1mo:
+		return "M"
+	default:
+		return "D"
+	}
+}
+
+func (b *BSEProvider) parseDate(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	var err error
+	for _, layout := range []string{"02-January-2006", "2-January-2006", "02-Jan-2006", "02-Jan-06", "02/01/2006", "2006-01-02"} {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func (b *BSEProvider) parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+}
+
+func (b *BSEProvider) normalizeOHLCVs(ohlcvs []types.OHLCV) []types.OHLCV {
+	return ohlcv.Round(b.precision).Transform(ohlcvs)
+}
@@ -0,0 +1,288 @@
+package bse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type mockHTTPClient struct {
+	calledCount int
+	requests    []*http.Request
+	responses   []*http.Response
+}
+
+func NewMockHTTPClient(responses []*http.Response) *mockHTTPClient {
+	return &mockHTTPClient{
+		calledCount: 0,
+		requests:    []*http.Request{},
+		responses:   responses,
+	}
+}
+
+func (m *mockHTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	m.calledCount++
+	m.requests = append(m.requests, req)
+
+	if m.calledCount-1 >= len(m.responses) {
+		return nil, errors.New("no more mock responses")
+	}
+	return m.responses[m.calledCount-1], nil
+}
+
+func createResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+const scripsJSON = `[
+	{"SCRIP_CD": "500325", "scrip_id": "RELIANCE", "Scrip_Name": "Reliance Industries Ltd"},
+	{"SCRIP_CD": "500209", "scrip_id": "INFY", "Scrip_Name": "Infosys Ltd"}
+]`
+
+const pricesCSV = "\xef\xbb\xbfDate,Open Price,High Price,Low Price,Close Price,WAP,No.of Shares,No. of Trades\n" +
+	"05-January-2024,\"2,590.10\",2610.456,2580.00,2600.999,2598.12,\"1,20,000\",4000\n" +
+	"04-January-2024,2570.00,2595.00,2565.00,2590.10,2580.00,100000,3500\n"
+
+const graphJSON = `{"Data": "[{\"dttm\":\"Fri Jan 05 2024 09:15:10\",\"vale1\":\"2590.10\",\"vole\":\"100\"},{\"dttm\":\"Fri Jan 05 2024 09:15:40\",\"vale1\":\"2595.00\",\"vole\":\"50\"},{\"dttm\":\"Fri Jan 05 2024 09:15:55\",\"vale1\":\"2588.00\",\"vole\":\"25\"},{\"dttm\":\"Fri Jan 05 2024 09:16:05\",\"vale1\":\"2591.00\",\"vole\":\"10\"}]"}`
+
+func newTestProvider(opts []Option, responses ...*http.Response) (*BSEProvider, *mockHTTPClient) {
+	mockClient := NewMockHTTPClient(responses)
+	provider := NewBSEProvider(opts...)
+	provider.client = mockClient
+	return provider, mockClient
+}
+
+func TestNewBSEProvider(t *testing.T) {
+	provider := NewBSEProvider(WithHTTPClient(&http.Client{}), WithScripCodes(map[string]string{"reliance": "500325"}))
+
+	if provider == nil {
+		t.Fatal("Expected provider to be created")
+	}
+	if provider.Name() != "bse" {
+		t.Errorf("Expected name 'bse', got '%s'", provider.Name())
+	}
+	if provider.overrides["RELIANCE"] != "500325" {
+		t.Errorf("Expected scrip code override for RELIANCE, got %v", provider.overrides)
+	}
+}
+
+func TestBSEProvider_Provide_Daily(t *testing.T) {
+	provider, mockClient := newTestProvider(nil, createResponse(200, scripsJSON), createResponse(200, pricesCSV))
+
+	ist := types.ExchangeBSE.Location()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, ist)
+	to := time.Date(2024, 1, 5, 23, 59, 0, 0, ist)
+	ohlcvs, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeBSE, types.Interval1d, from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ohlcvs) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(ohlcvs))
+	}
+	if !ohlcvs[0].DateTime.Equal(time.Date(2024, 1, 4, 0, 0, 0, 0, ist)) {
+		t.Errorf("Expected candles sorted ascending, got first at %v", ohlcvs[0].DateTime)
+	}
+
+	last := ohlcvs[1]
+	if last.Open != 2590.1 || last.High != 2610.46 || last.Close != 2601 {
+		t.Errorf("Expected rounded prices, got %+v", last)
+	}
+	if last.Volume != 120000 {
+		t.Errorf("Expected volume 120000, got %d", last.Volume)
+	}
+	if last.Source != "bse" || last.Symbol != "RELIANCE" || last.Exchange != types.ExchangeBSE {
+		t.Errorf("Unexpected candle metadata: %+v", last)
+	}
+
+	query := mockClient.requests[1].URL.Query()
+	if query.Get("Scode") != "500325" || query.Get("rbType") != "D" {
+		t.Errorf("Unexpected query: %s", mockClient.requests[1].URL.RawQuery)
+	}
+	if query.Get("FDates") != "01/01/2024" || query.Get("TDates") != "05/01/2024" {
+		t.Errorf("Unexpected date range: %s", mockClient.requests[1].URL.RawQuery)
+	}
+	if mockClient.requests[1].Header.Get("Referer") == "" {
+		t.Error("Expected Referer header to be set")
+	}
+}
+
+func TestBSEProvider_Provide_Weekly(t *testing.T) {
+	provider, mockClient := newTestProvider(nil, createResponse(200, pricesCSV))
+
+	if _, err := provider.Provide(context.Background(), "500325", types.ExchangeBSE, types.Interval1wk, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockClient.calledCount != 1 {
+		t.Errorf("Expected numeric symbol to skip scrip lookup, got %d calls", mockClient.calledCount)
+	}
+	if got := mockClient.requests[0].URL.Query().Get("rbType"); got != "W" {
+		t.Errorf("Expected rbType W, got %s", got)
+	}
+}
+
+func TestBSEProvider_Provide_Intraday(t *testing.T) {
+	provider, _ := newTestProvider(
+		[]Option{WithScripCodes(map[string]string{"RELIANCE": "500325"})},
+		createResponse(200, graphJSON),
+	)
+
+	ohlcvs, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeBSE, types.Interval1m, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ohlcvs) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(ohlcvs))
+	}
+
+	first := ohlcvs[0]
+	if first.Open != 2590.1 || first.High != 2595 || first.Low != 2588 || first.Close != 2588 {
+		t.Errorf("Unexpected OHLC: %+v", first)
+	}
+	if first.Volume != 175 {
+		t.Errorf("Expected volume 175, got %d", first.Volume)
+	}
+	if !first.DateTime.Equal(time.Date(2024, 1, 5, 9, 15, 0, 0, types.ExchangeBSE.Location())) {
+		t.Errorf("Expected candle at 09:15, got %v", first.DateTime)
+	}
+}
+
+func TestBSEProvider_Provide_IntradayFiltersRange(t *testing.T) {
+	provider, _ := newTestProvider([]Option{WithScripCodes(map[string]string{"RELIANCE": "500325"})}, createResponse(200, graphJSON))
+
+	from := time.Date(2024, 1, 5, 9, 16, 0, 0, types.ExchangeBSE.Location())
+	ohlcvs, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeBSE, types.Interval1m, from, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ohlcvs) != 1 || ohlcvs[0].Close != 2591 {
+		t.Errorf("Expected only the 09:16 candle, got %+v", ohlcvs)
+	}
+}
+
+func TestBSEProvider_Provide_CachesScrips(t *testing.T) {
+	provider, mockClient := newTestProvider(nil,
+		createResponse(200, scripsJSON),
+		createResponse(200, pricesCSV),
+		createResponse(200, pricesCSV),
+	)
+
+	ctx := context.Background()
+	if _, err := provider.Provide(ctx, "RELIANCE", types.ExchangeBSE, types.Interval1d, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := provider.Provide(ctx, "infy", types.ExchangeBSE, types.Interval1d, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockClient.calledCount != 3 {
+		t.Errorf("Expected scrips to be downloaded once, got %d calls", mockClient.calledCount)
+	}
+	if got := mockClient.requests[2].URL.Query().Get("Scode"); got != "500209" {
+		t.Errorf("Expected INFY scrip code, got %s", got)
+	}
+}
+
+func TestBSEProvider_Provide_RetriesFailedScripLoad(t *testing.T) {
+	provider, mockClient := newTestProvider(nil,
+		createResponse(503, "unavailable"),
+		createResponse(200, scripsJSON),
+		createResponse(200, pricesCSV),
+	)
+
+	ctx := context.Background()
+	if _, err := provider.Provide(ctx, "RELIANCE", types.ExchangeBSE, types.Interval1d, time.Time{}, time.Time{}); err == nil {
+		t.Fatal("Expected error when scrips fail to load")
+	}
+	if _, err := provider.Provide(ctx, "RELIANCE", types.ExchangeBSE, types.Interval1d, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockClient.calledCount != 3 {
+		t.Errorf("Expected scrips to be downloaded again, got %d calls", mockClient.calledCount)
+	}
+}
+
+func TestBSEProvider_Provide_UnsupportedExchange(t *testing.T) {
+	bse, mockClient := newTestProvider(nil)
+
+	_, err := bse.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if !errors.Is(err, provider.ErrUnsupportedExchange) {
+		t.Errorf("Expected ErrUnsupportedExchange for NSE, got %v", err)
+	}
+	if mockClient.calledCount != 0 {
+		t.Errorf("Expected no requests, got %d", mockClient.calledCount)
+	}
+	if bse.SupportsExchange(types.ExchangeNSE) || !bse.SupportsExchange(types.ExchangeBSE) {
+		t.Error("Expected only BSE to be supported")
+	}
+	if bse.SupportsInterval(types.Interval5m) || !bse.SupportsInterval(types.Interval1m) {
+		t.Error("Expected 1m to be supported and 5m not")
+	}
+}
+
+func TestBSEProvider_Provide_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		symbol    string
+		interval  types.Interval
+		responses []*http.Response
+		target    error
+	}{
+		{"UnknownSymbol", "NOPE", types.Interval1d, []*http.Response{createResponse(200, scripsJSON)}, provider.ErrSymbolNotFound},
+		{"UnknownInterval", "500325", types.Interval5m, nil, provider.ErrUnknownInterval},
+		{"EmptyCSV", "500325", types.Interval1d, []*http.Response{createResponse(200, "Date,Open Price,High Price,Low Price,Close Price,No.of Shares\n")}, provider.ErrNoData},
+		{"EmptyGraph", "500325", types.Interval1m, []*http.Response{createResponse(200, `{"Data": ""}`)}, provider.ErrNoData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProvider(nil, tt.responses...)
+
+			_, err := p.Provide(context.Background(), tt.symbol, types.ExchangeBSE, tt.interval, time.Time{}, time.Time{})
+
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestBSEProvider_Provide_APIError(t *testing.T) {
+	p, _ := newTestProvider(nil, createResponse(403, "forbidden"))
+
+	_, err := p.Provide(context.Background(), "500325", types.ExchangeBSE, types.Interval1d, time.Time{}, time.Time{})
+
+	var perr *provider.ProviderError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected ProviderError, got %v", err)
+	}
+	if perr.StatusCode != 403 || perr.Provider != "bse" {
+		t.Errorf("Unexpected provider error: %+v", perr)
+	}
+}
+
+func TestBSEProvider_Provide_MissingColumn(t *testing.T) {
+	p, _ := newTestProvider(nil, createResponse(200, "Date,Open Price\n05-January-2024,1\n"))
+
+	_, err := p.Provide(context.Background(), "500325", types.ExchangeBSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error for missing columns")
+	}
+}
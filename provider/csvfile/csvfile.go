@@ -0,0 +1,184 @@
+// Package csvfile serves candles from local CSV files, so offline datasets
+// and vendor dumps can be fetched like any other provider.
+package csvfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcvio"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// DefaultLayout is the path of a symbol's file unless WithLayout is given.
+const DefaultLayout = "{symbol}/{interval}.csv"
+
+// intervals are tried when a file is missing, to tell an unknown symbol from
+// an interval the dataset doesn't have.
+var intervals = []types.Interval{
+	types.Interval1m, types.Interval5m, types.Interval15m, types.Interval30m, types.Interval1h,
+	types.Interval1d, types.Interval5d, types.Interval1wk, types.Interval1mo, types.Interval3mo,
+}
+
+type config struct {
+	name       string
+	layout     string
+	freshness  types.DataFreshness
+	csvOptions []ohlcvio.Option
+}
+
+type Option func(*config)
+
+// WithName sets the provider name reported by Name and stamped on candles as
+// their Source. The default is "csv".
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithLayout sets the path of the file holding a symbol's candles, relative
+// to the provider's file system. {symbol}, {exchange} and {interval} are
+// replaced by the request's values. Files holding several symbols or
+// exchanges are filtered by their symbol and exchange columns.
+func WithLayout(layout string) Option {
+	return func(c *config) {
+		c.layout = layout
+	}
+}
+
+// WithFreshness sets the freshness reported for the files' candles. The
+// default is types.FreshnessHistorical.
+func WithFreshness(f types.DataFreshness) Option {
+	return func(c *config) {
+		c.freshness = f
+	}
+}
+
+// WithCSVOptions sets how files are parsed: their columns, header names,
+// time format and time zone. Times without an offset are read in the
+// exchange's time zone unless ohlcvio.WithTimezone says otherwise.
+func WithCSVOptions(opts ...ohlcvio.Option) Option {
+	return func(c *config) {
+		c.csvOptions = append(c.csvOptions, opts...)
+	}
+}
+
+// CSVProvider serves candles from CSV files in a file system, one file per
+// symbol and interval by default.
+type CSVProvider struct {
+	fsys       fs.FS
+	name       string
+	layout     string
+	freshness  types.DataFreshness
+	csvOptions []ohlcvio.Option
+}
+
+// NewCSVProvider returns a provider reading files from fsys, usually
+// os.DirFS of the dataset's root directory.
+func NewCSVProvider(fsys fs.FS, opts ...Option) *CSVProvider {
+	cfg := config{
+		name:      "csv",
+		layout:    DefaultLayout,
+		freshness: types.FreshnessHistorical,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &CSVProvider{
+		fsys:       fsys,
+		name:       cfg.name,
+		layout:     cfg.layout,
+		freshness:  cfg.freshness,
+		csvOptions: cfg.csvOptions,
+	}
+}
+
+func (c *CSVProvider) Name() string {
+	return c.name
+}
+
+func (c *CSVProvider) Freshness() types.DataFreshness {
+	return c.freshness
+}
+
+func (c *CSVProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path := c.path(symbol, exchange, interval)
+	f, err := c.fsys.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if c.hasSymbol(symbol, exchange) {
+			return nil, fmt.Errorf("%w: %s for symbol %s", provider.ErrUnknownInterval, interval, symbol)
+		}
+		return nil, fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, symbol, exchange)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	opts := append([]ohlcvio.Option{ohlcvio.WithTimezone(exchange.Location())}, c.csvOptions...)
+	candles, err := ohlcvio.ReadCSV(f, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	ohlcvs := make([]types.OHLCV, 0, len(candles))
+	for _, candle := range candles {
+		if candle.Symbol != "" && !strings.EqualFold(candle.Symbol, symbol) {
+			continue
+		}
+		if candle.Exchange != "" && !strings.EqualFold(string(candle.Exchange), string(exchange)) {
+			continue
+		}
+		if (!from.IsZero() && candle.DateTime.Before(from)) || (!to.IsZero() && candle.DateTime.After(to)) {
+			continue
+		}
+
+		candle.Symbol = symbol
+		candle.Exchange = exchange
+		candle.Interval = interval
+		candle.Source = c.name
+		candle.Freshness = c.freshness
+		ohlcvs = append(ohlcvs, candle)
+	}
+
+	if len(ohlcvs) == 0 {
+		return nil, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+
+	sort.Slice(ohlcvs, func(i, j int) bool {
+		return ohlcvs[i].DateTime.Before(ohlcvs[j].DateTime)
+	})
+
+	return ohlcvs, nil
+}
+
+func (c *CSVProvider) path(symbol string, exchange types.Exchange, interval types.Interval) string {
+	return strings.NewReplacer(
+		"{symbol}", symbol,
+		"{exchange}", string(exchange),
+		"{interval}", string(interval),
+	).Replace(c.layout)
+}
+
+// hasSymbol reports whether the dataset has a file for symbol at any
+// interval.
+func (c *CSVProvider) hasSymbol(symbol string, exchange types.Exchange) bool {
+	for _, interval := range intervals {
+		if _, err := fs.Stat(c.fsys, c.path(symbol, exchange, interval)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package csvfile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcvio"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const dailyCSV = "datetime,open,high,low,close,volume\n" +
+	"2024-01-03T00:00:00+05:30,3,3,3,3,30\n" +
+	"2024-01-01T00:00:00+05:30,1,1,1,1,10\n" +
+	"2024-01-02T00:00:00+05:30,2,2,2,2,20\n"
+
+func newTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"RELIANCE/1d.csv": {Data: []byte(dailyCSV)},
+	}
+}
+
+func TestNewCSVProvider(t *testing.T) {
+	p := NewCSVProvider(newTestFS(), WithName("dump"), WithFreshness(types.FreshnessDelayed))
+
+	if p.Name() != "dump" {
+		t.Errorf("Expected name 'dump', got '%s'", p.Name())
+	}
+	if p.Freshness() != types.FreshnessDelayed {
+		t.Errorf("Expected delayed freshness, got %s", p.Freshness())
+	}
+	if p.layout != DefaultLayout {
+		t.Errorf("Expected default layout, got %s", p.layout)
+	}
+}
+
+func TestCSVProvider_Provide(t *testing.T) {
+	p := NewCSVProvider(newTestFS())
+
+	ohlcvs, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ohlcvs) != 3 {
+		t.Fatalf("Expected 3 candles, got %d", len(ohlcvs))
+	}
+	for i, c := range ohlcvs {
+		if c.Close != float64(i+1) {
+			t.Errorf("Expected candles sorted ascending, got close %v at %d", c.Close, i)
+		}
+		if c.Symbol != "RELIANCE" || c.Exchange != types.ExchangeNSE || c.Source != "csv" || c.Freshness != types.FreshnessHistorical {
+			t.Errorf("Unexpected candle metadata: %+v", c)
+		}
+	}
+}
+
+func TestCSVProvider_Provide_FiltersRange(t *testing.T) {
+	p := NewCSVProvider(newTestFS())
+	ist := types.ExchangeNSE.Location()
+
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	to := time.Date(2024, 1, 2, 23, 59, 0, 0, ist)
+	ohlcvs, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ohlcvs) != 1 || ohlcvs[0].Close != 2 {
+		t.Errorf("Expected only the Jan 2 candle, got %+v", ohlcvs)
+	}
+}
+
+func TestCSVProvider_Provide_LayoutAndColumnNames(t *testing.T) {
+	fsys := fstest.MapFS{
+		"BSE/daily/1d.csv": {Data: []byte("Ticker,Date,Open,High,Low,Close,Volume\n" +
+			"SMALLCO,2024-01-02,10,11,9,10.5,100\n" +
+			"OTHERCO,2024-01-02,20,21,19,20.5,200\n")},
+	}
+	p := NewCSVProvider(fsys,
+		WithLayout("{exchange}/daily/{interval}.csv"),
+		WithCSVOptions(
+			ohlcvio.WithColumnNames(map[string]ohlcvio.Column{
+				"Ticker": ohlcvio.ColumnSymbol,
+				"Date":   ohlcvio.ColumnDateTime,
+				"Open":   ohlcvio.ColumnOpen,
+				"High":   ohlcvio.ColumnHigh,
+				"Low":    ohlcvio.ColumnLow,
+				"Close":  ohlcvio.ColumnClose,
+				"Volume": ohlcvio.ColumnVolume,
+			}),
+			ohlcvio.WithTimeFormat("2006-01-02"),
+		),
+	)
+
+	ohlcvs, err := p.Provide(context.Background(), "SMALLCO", types.ExchangeBSE, types.Interval1d, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ohlcvs) != 1 || ohlcvs[0].Close != 10.5 || ohlcvs[0].Volume != 100 {
+		t.Fatalf("Expected only the SMALLCO candle, got %+v", ohlcvs)
+	}
+	want := time.Date(2024, 1, 2, 0, 0, 0, 0, types.ExchangeBSE.Location())
+	if !ohlcvs[0].DateTime.Equal(want) {
+		t.Errorf("Expected offset-less dates in the exchange's time zone, got %v", ohlcvs[0].DateTime)
+	}
+}
+
+func TestCSVProvider_Provide_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		symbol   string
+		interval types.Interval
+		from     time.Time
+		target   error
+	}{
+		{"UnknownSymbol", "INFY", types.Interval1d, time.Time{}, provider.ErrSymbolNotFound},
+		{"MissingInterval", "RELIANCE", types.Interval1m, time.Time{}, provider.ErrUnknownInterval},
+		{"OutOfRange", "RELIANCE", types.Interval1d, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), provider.ErrNoData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewCSVProvider(newTestFS())
+
+			_, err := p.Provide(context.Background(), tt.symbol, types.ExchangeNSE, tt.interval, tt.from, time.Time{})
+
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestCSVProvider_Provide_InvalidFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"RELIANCE/1d.csv": {Data: []byte("datetime,open\nnot-a-time,1\n")},
+	}
+	p := NewCSVProvider(fsys)
+
+	_, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error for invalid file")
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrSymbolNotFound      = errors.New("symbol not found")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrNoData              = errors.New("no data found")
+	ErrProviderUnavailable = errors.New("provider unavailable")
+	ErrUnknownInterval     = errors.New("unknown interval")
+	ErrAdjustedUnsupported = errors.New("adjusted prices not supported")
+	ErrResponseTooLarge    = errors.New("response too large")
+	ErrInvalidRange        = errors.New("invalid range")
+	ErrInvalidSymbol       = errors.New("invalid symbol")
+	ErrUnsupportedExchange = errors.New("unsupported exchange")
+)
+
+// ProviderError is returned when a provider answers with a non-OK status. It
+// matches ErrRateLimited, ErrProviderUnavailable or ErrSymbolNotFound with
+// errors.Is depending on the status code.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("non-OK response: %d %s", e.StatusCode, e.Body)
+}
+
+func (e *ProviderError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrProviderUnavailable:
+		return e.StatusCode >= http.StatusInternalServerError
+	case ErrSymbolNotFound:
+		return e.StatusCode == http.StatusNotFound
+	default:
+		return false
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestProviderError(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+		want   bool
+	}{
+		{429, ErrRateLimited, true},
+		{429, ErrProviderUnavailable, false},
+		{500, ErrProviderUnavailable, true},
+		{503, ErrProviderUnavailable, true},
+		{404, ErrSymbolNotFound, true},
+		{400, ErrSymbolNotFound, false},
+		{400, ErrNoData, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d_%v", tt.status, tt.target), func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", &ProviderError{Provider: "test", StatusCode: tt.status, Body: "body"})
+
+			if got := errors.Is(err, tt.target); got != tt.want {
+				t.Errorf("Expected errors.Is to be %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProviderError_Error(t *testing.T) {
+	err := &ProviderError{Provider: "yahoo", StatusCode: 429, Body: "slow down"}
+
+	if err.Error() != "non-OK response: 429 slow down" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	var pe *ProviderError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &pe) || pe.Provider != "yahoo" {
+		t.Error("Expected errors.As to find the ProviderError")
+	}
+}
@@ -0,0 +1,338 @@
+package finnhub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/internal/retry"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const baseURL = "https://finnhub.io/api/v1/stock/candle"
+
+type finnhubResponse struct {
+	Status    string    `json:"s"`
+	Error     string    `json:"error"`
+	Timestamp []int64   `json:"t"`
+	Open      []float64 `json:"o"`
+	High      []float64 `json:"h"`
+	Low       []float64 `json:"l"`
+	Close     []float64 `json:"c"`
+	Volume    []float64 `json:"v"`
+}
+
+type config struct {
+	apiKey           string
+	httpClient       *http.Client
+	transport        provider.TransportConfig
+	retryHooks       provider.RetryHooks
+	conditionalCache int64
+	maxResponseSize  int64
+	rateLimits       provider.RateLimits
+	retryBudget      provider.RetryBudget
+	precision        provider.Precision
+}
+
+type Option func(*config)
+
+// WithAPIKey sets the Finnhub API key sent with every request.
+func WithAPIKey(apiKey string) Option {
+	return func(c *config) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithRateLimits overrides the free-tier limits of 30 requests per second, 60
+// per minute and 3600 per hour.
+func WithRateLimits(limits provider.RateLimits) Option {
+	return func(c *config) {
+		c.rateLimits = limits
+	}
+}
+
+// WithRetryBudget overrides the default budget of 12 retries per minute,
+// shared by every Finnhub provider in the process with the same API key
+// and budget.
+func WithRetryBudget(budget provider.RetryBudget) Option {
+	return func(c *config) {
+		c.retryBudget = budget
+	}
+}
+
+// WithTransport tunes the connections of the default HTTP client, or of one
+// set with WithHTTPClient that has no Transport of its own.
+func WithTransport(t provider.TransportConfig) Option {
+	return func(c *config) {
+		c.transport = t
+	}
+}
+
+// WithRetryHooks calls hooks on every retry of a request and when one is
+// given up on.
+func WithRetryHooks(hooks provider.RetryHooks) Option {
+	return func(c *config) {
+		c.retryHooks = hooks
+	}
+}
+
+// WithConditionalCache keeps up to maxBytes of responses carrying an ETag or
+// Last-Modified header, and asks for them again conditionally; a 304 Not
+// Modified is then answered with the kept response.
+func WithConditionalCache(maxBytes int64) Option {
+	return func(c *config) {
+		c.conditionalCache = maxBytes
+	}
+}
+
+// WithMaxResponseSize caps the size of a response, after decompression, at n
+// bytes instead of provider.DefaultMaxResponseSize. Larger responses fail
+// with an error matching provider.ErrResponseTooLarge. Zero removes the
+// limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *config) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithPrecision sets how many decimal places prices are rounded to. The
+// default is provider.DefaultPrecision.
+func WithPrecision(p provider.Precision) Option {
+	return func(c *config) {
+		c.precision = p
+	}
+}
+
+type FinnhubProvider struct {
+	client    httpclient.Doer
+	apiKey    string
+	precision provider.Precision
+}
+
+func NewFinnhubProvider(opts ...Option) *FinnhubProvider {
+	cfg := config{
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		maxResponseSize: provider.DefaultMaxResponseSize,
+		rateLimits: provider.RateLimits{
+			RequestsPerSecond: 30,
+			RequestsPerMinute: 60,
+			RequestsPerHour:   3600,
+		},
+		retryBudget: provider.RetryBudget{Retries: 12, Window: time.Minute},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	clientConfig := httpclient.ClientConfig{
+		Name:                  "finnhub",
+		HttpClient:            cfg.httpClient,
+		Transport:             httpclient.TransportConfig(cfg.transport),
+		ConditionalCacheBytes: cfg.conditionalCache,
+		MaxResponseBytes:      cfg.maxResponseSize,
+		RateLimitConfig: httpclient.RateLimitConfig{
+			RequestsPerSecond: cfg.rateLimits.RequestsPerSecond,
+			RequestsPerMinute: cfg.rateLimits.RequestsPerMinute,
+			RequestsPerHour:   cfg.rateLimits.RequestsPerHour,
+			MaxQueue:          cfg.rateLimits.MaxQueue,
+			Shared:            "finnhub/" + cfg.apiKey,
+		},
+		RetryConfig: httpclient.RetryConfig{
+			MaxRetries:    3,
+			BaseDelay:     500 * time.Millisecond,
+			MaxDelay:      10 * time.Second,
+			RetryOnStatus: []uint{429, 500, 502, 503},
+			Jitter:        retry.EqualJitter,
+			Budget:        cfg.retryBudget.Retries,
+			BudgetWindow:  cfg.retryBudget.Window,
+			Hooks:         cfg.retryHooks,
+		},
+	}
+
+	return &FinnhubProvider{
+		client:    httpclient.NewClient(clientConfig),
+		apiKey:    cfg.apiKey,
+		precision: cfg.precision,
+	}
+}
+
+func (f *FinnhubProvider) Name() string {
+	return "finnhub"
+}
+
+func (f *FinnhubProvider) Freshness() types.DataFreshness {
+	return types.FreshnessDelayed
+}
+
+// RemainingRateLimit reports the requests left in the current second, minute
+// and hour of the provider's rate limit.
+func (f *FinnhubProvider) RemainingRateLimit() (provider.RateLimits, bool) {
+	c, ok := f.client.(*httpclient.Client)
+	if !ok {
+		return provider.RateLimits{}, false
+	}
+
+	perSecond, perMinute, perHour := c.Remaining()
+	return provider.RateLimits{
+		RequestsPerSecond: perSecond,
+		RequestsPerMinute: perMinute,
+		RequestsPerHour:   perHour,
+	}, true
+}
+
+func (f *FinnhubProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	if f.apiKey == "" {
+		return nil, errors.New("finnhub api key is required")
+	}
+
+	resolution, err := f.intervalToResolution(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval: %w", err)
+	}
+
+	loc := exchange.Location()
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		t := to.In(loc)
+		from = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+
+	query := url.Values{}
+	query.Set("symbol", f.formatSymbol(symbol, exchange))
+	query.Set("resolution", resolution)
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("to", strconv.FormatInt(to.Unix(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Finnhub-Token", f.apiKey)
+
+	res, err := f.client.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &provider.ProviderError{Provider: f.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	var data finnhubResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if data.Error != "" {
+		return nil, fmt.Errorf("finnhub error: %s", data.Error)
+	}
+	if data.Status == "no_data" || len(data.Timestamp) == 0 {
+		return nil, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+	if data.Status != "ok" {
+		return nil, fmt.Errorf("finnhub error: unexpected status %q", data.Status)
+	}
+
+	n := len(data.Timestamp)
+	if len(data.Open) != n || len(data.High) != n || len(data.Low) != n || len(data.Close) != n || len(data.Volume) != n {
+		return nil, errors.New("finnhub response has mismatched array lengths")
+	}
+
+	daily := resolution == "D" || resolution == "W" || resolution == "M"
+	ohlcvs := make([]types.OHLCV, 0, n)
+	for i, ts := range data.Timestamp {
+		t := time.Unix(ts, 0)
+		// Daily and longer candles are stamped at midnight UTC of their date.
+		if daily {
+			u := t.UTC()
+			t = time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, loc)
+		}
+
+		ohlcvs = append(ohlcvs, types.OHLCV{
+			Symbol:    symbol,
+			Exchange:  exchange,
+			Open:      data.Open[i],
+			High:      data.High[i],
+			Low:       data.Low[i],
+			Close:     data.Close[i],
+			Volume:    int64(data.Volume[i]),
+			DateTime:  t.In(loc),
+			Interval:  interval,
+			Source:    f.Name(),
+			Freshness: f.Freshness(),
+		})
+	}
+
+	return f.normalizeOHLCVs(ohlcvs), nil
+}
+
+// formatSymbol maps NSE and BSE symbols to Finnhub's suffixed form. Symbols on
+// other exchanges are passed through as they are.
+func (f *FinnhubProvider) formatSymbol(symbol string, exchange types.Exchange) string {
+	switch exchange {
+	case types.ExchangeNSE:
+		return symbol + ".NS"
+	case types.ExchangeBSE:
+		return symbol + ".BO"
+	default:
+		return symbol
+	}
+}
+
+// SupportsInterval reports whether Finnhub serves candles of interval.
+func (f *FinnhubProvider) SupportsInterval(interval types.Interval) bool {
+	_, err := f.intervalToResolution(interval)
+	return err == nil
+}
+
+func (f *FinnhubProvider) intervalToResolution(i types.Interval) (string, error) {
+	switch i {
+	case types.Interval1m:
+		return "1", nil
+	case types.Interval5m:
+		return "5", nil
+	case types.Interval15m:
+		return "15", nil
+	case types.Interval30m:
+		return "30", nil
+	case types.Interval1h:
+		return "60", nil
+	case types.Interval1d:
+		return "D", nil
+	case types.Interval1wk:
+		return "W", nil
+	case types.Interval1mo:
+		return "M", nil
+	default:
+		return "", fmt.Errorf("%w: %s", provider.ErrUnknownInterval, i)
+	}
+}
+
+func (f *FinnhubProvider) normalizeOHLCVs(ohlcvs []types.OHLCV) []types.OHLCV {
+	return ohlcv.Round(f.precision).Transform(ohlcvs)
+}
@@ -0,0 +1,232 @@
+package finnhub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type mockHTTPClient struct {
+	calledCount int
+	requests    []*http.Request
+	responses   []*http.Response
+}
+
+func NewMockHTTPClient(responses []*http.Response) *mockHTTPClient {
+	return &mockHTTPClient{
+		calledCount: 0,
+		requests:    []*http.Request{},
+		responses:   responses,
+	}
+}
+
+func (m *mockHTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	m.calledCount++
+	m.requests = append(m.requests, req)
+
+	if m.calledCount-1 >= len(m.responses) {
+		return nil, errors.New("no more mock responses")
+	}
+	return m.responses[m.calledCount-1], nil
+}
+
+func createResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+const intradayBody = `{
+	"s": "ok",
+	"t": [1704426300, 1704426600],
+	"o": [2585.0, 2590.1],
+	"h": [2591.0, 2595.456],
+	"l": [2584.5, 2588.0],
+	"c": [2590.1, 2594.999],
+	"v": [1000, 2000]
+}`
+
+const dailyBody = `{
+	"s": "ok",
+	"t": [1704326400, 1704412800],
+	"o": [1, 2],
+	"h": [1, 2],
+	"l": [1, 2],
+	"c": [1, 2],
+	"v": [10, 20]
+}`
+
+func newTestProvider(responses ...*http.Response) (*FinnhubProvider, *mockHTTPClient) {
+	mockClient := NewMockHTTPClient(responses)
+	provider := NewFinnhubProvider(WithAPIKey("demo"))
+	provider.client = mockClient
+	return provider, mockClient
+}
+
+func TestNewFinnhubProvider(t *testing.T) {
+	provider := NewFinnhubProvider(WithAPIKey("secret"), WithHTTPClient(&http.Client{}))
+
+	if provider == nil {
+		t.Fatal("Expected provider to be created")
+	}
+	if provider.apiKey != "secret" {
+		t.Errorf("Expected api key 'secret', got '%s'", provider.apiKey)
+	}
+	if provider.Name() != "finnhub" {
+		t.Errorf("Expected name 'finnhub', got '%s'", provider.Name())
+	}
+}
+
+func TestFinnhubProvider_Provide_Intraday(t *testing.T) {
+	provider, mockClient := newTestProvider(createResponse(200, intradayBody))
+
+	from := time.Unix(1704426000, 0)
+	to := time.Unix(1704430000, 0)
+	ohlcvs, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval5m, from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ohlcvs) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(ohlcvs))
+	}
+
+	last := ohlcvs[1]
+	if last.High != 2595.46 || last.Close != 2595 {
+		t.Errorf("Expected rounded prices, got %+v", last)
+	}
+	if last.Volume != 2000 || last.Source != "finnhub" {
+		t.Errorf("Unexpected candle: %+v", last)
+	}
+	if last.DateTime.Location().String() != "Asia/Kolkata" {
+		t.Errorf("Expected Asia/Kolkata location, got %s", last.DateTime.Location())
+	}
+
+	req := mockClient.requests[0]
+	query := req.URL.Query()
+	if query.Get("symbol") != "RELIANCE.NS" || query.Get("resolution") != "5" {
+		t.Errorf("Unexpected query: %s", req.URL.RawQuery)
+	}
+	if query.Get("from") != "1704426000" || query.Get("to") != "1704430000" {
+		t.Errorf("Unexpected range: %s", req.URL.RawQuery)
+	}
+	if req.Header.Get("X-Finnhub-Token") != "demo" {
+		t.Errorf("Expected token header, got '%s'", req.Header.Get("X-Finnhub-Token"))
+	}
+}
+
+func TestFinnhubProvider_Provide_DailyDates(t *testing.T) {
+	provider, mockClient := newTestProvider(createResponse(200, dailyBody))
+
+	ohlcvs, err := provider.Provide(context.Background(), "AAPL", types.Exchange("NASDAQ"), types.Interval1d, time.Unix(1704326400, 0), time.Unix(1704412800, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := mockClient.requests[0].URL.Query().Get("symbol"); got != "AAPL" {
+		t.Errorf("Expected symbol passed through, got %s", got)
+	}
+	if len(ohlcvs) != 2 || ohlcvs[0].DateTime.Day() != 4 || ohlcvs[0].DateTime.Hour() != 0 {
+		t.Errorf("Expected candles at midnight of their date, got %+v", ohlcvs)
+	}
+}
+
+func TestFinnhubProvider_Provide_Resolutions(t *testing.T) {
+	tests := []struct {
+		interval   types.Interval
+		resolution string
+	}{
+		{types.Interval1m, "1"},
+		{types.Interval15m, "15"},
+		{types.Interval30m, "30"},
+		{types.Interval1h, "60"},
+		{types.Interval1wk, "W"},
+		{types.Interval1mo, "M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.interval), func(t *testing.T) {
+			p, mockClient := newTestProvider(createResponse(200, dailyBody))
+
+			if _, err := p.Provide(context.Background(), "INFY", types.ExchangeBSE, tt.interval, time.Time{}, time.Time{}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			query := mockClient.requests[0].URL.Query()
+			if query.Get("resolution") != tt.resolution {
+				t.Errorf("Expected resolution %s, got %s", tt.resolution, query.Get("resolution"))
+			}
+			if query.Get("symbol") != "INFY.BO" {
+				t.Errorf("Expected symbol INFY.BO, got %s", query.Get("symbol"))
+			}
+		})
+	}
+}
+
+func TestFinnhubProvider_Provide_MissingAPIKey(t *testing.T) {
+	provider := NewFinnhubProvider()
+
+	_, err := provider.Provide(context.Background(), "AAPL", types.Exchange("NASDAQ"), types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error for missing api key")
+	}
+}
+
+func TestFinnhubProvider_Provide_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval types.Interval
+		response *http.Response
+		target   error
+	}{
+		{"NoData", types.Interval1d, createResponse(200, `{"s": "no_data"}`), provider.ErrNoData},
+		{"UnknownInterval", types.Interval5d, nil, provider.ErrUnknownInterval},
+		{"RateLimited", types.Interval1d, createResponse(429, `{"error": "API limit reached."}`), provider.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var responses []*http.Response
+			if tt.response != nil {
+				responses = append(responses, tt.response)
+			}
+			p, _ := newTestProvider(responses...)
+
+			_, err := p.Provide(context.Background(), "AAPL", types.Exchange("NASDAQ"), tt.interval, time.Time{}, time.Time{})
+
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestFinnhubProvider_Provide_ErrorBody(t *testing.T) {
+	p, _ := newTestProvider(createResponse(200, `{"error": "You don't have access to this resource."}`))
+
+	_, err := p.Provide(context.Background(), "AAPL", types.Exchange("NASDAQ"), types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error for error body")
+	}
+}
+
+func TestFinnhubProvider_Provide_MismatchedArrays(t *testing.T) {
+	p, _ := newTestProvider(createResponse(200, `{"s": "ok", "t": [1, 2], "o": [1], "h": [1], "l": [1], "c": [1], "v": [1]}`))
+
+	_, err := p.Provide(context.Background(), "AAPL", types.Exchange("NASDAQ"), types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error for mismatched arrays")
+	}
+}
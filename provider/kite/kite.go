@@ -0,0 +1,410 @@
+package kite
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/internal/retry"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const baseURL = "https://api.kite.trade"
+
+type kiteResponse struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	ErrorType string `json:"error_type"`
+	Data      struct {
+		Candles [][]any `json:"candles"`
+	} `json:"data"`
+}
+
+type config struct {
+	apiKey           string
+	accessToken      string
+	httpClient       *http.Client
+	transport        provider.TransportConfig
+	retryHooks       provider.RetryHooks
+	conditionalCache int64
+	maxResponseSize  int64
+	precision        provider.Precision
+}
+
+type Option func(*config)
+
+// WithAPIKey sets the Kite Connect app API key.
+func WithAPIKey(apiKey string) Option {
+	return func(c *config) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithAccessToken sets the access token obtained from the Kite login flow.
+// Tokens expire daily, so long-running processes should recreate the provider
+// with a fresh token.
+func WithAccessToken(accessToken string) Option {
+	return func(c *config) {
+		c.accessToken = accessToken
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithTransport tunes the connections of the default HTTP client, or of one
+// set with WithHTTPClient that has no Transport of its own.
+func WithTransport(t provider.TransportConfig) Option {
+	return func(c *config) {
+		c.transport = t
+	}
+}
+
+// WithRetryHooks calls hooks on every retry of a request and when one is
+// given up on.
+func WithRetryHooks(hooks provider.RetryHooks) Option {
+	return func(c *config) {
+		c.retryHooks = hooks
+	}
+}
+
+// WithConditionalCache keeps up to maxBytes of responses carrying an ETag or
+// Last-Modified header, and asks for them again conditionally; a 304 Not
+// Modified is then answered with the kept response.
+func WithConditionalCache(maxBytes int64) Option {
+	return func(c *config) {
+		c.conditionalCache = maxBytes
+	}
+}
+
+// WithMaxResponseSize caps the size of a response, after decompression, at n
+// bytes instead of provider.DefaultMaxResponseSize. Larger responses fail
+// with an error matching provider.ErrResponseTooLarge. Zero removes the
+// limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *config) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithPrecision sets how many decimal places prices are rounded to. The
+// default is provider.DefaultPrecision.
+func WithPrecision(p provider.Precision) Option {
+	return func(c *config) {
+		c.precision = p
+	}
+}
+
+type KiteProvider struct {
+	client      httpclient.Doer
+	apiKey      string
+	accessToken string
+
+	mu          sync.Mutex
+	instruments map[types.Exchange]map[string]string
+	precision   provider.Precision
+}
+
+func NewKiteProvider(opts ...Option) *KiteProvider {
+	cfg := config{
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		maxResponseSize: provider.DefaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	clientConfig := httpclient.ClientConfig{
+		Name:                  "kite",
+		HttpClient:            cfg.httpClient,
+		Transport:             httpclient.TransportConfig(cfg.transport),
+		ConditionalCacheBytes: cfg.conditionalCache,
+		MaxResponseBytes:      cfg.maxResponseSize,
+		RateLimitConfig: httpclient.RateLimitConfig{
+			RequestsPerSecond: 3,
+			RequestsPerMinute: 180,
+			RequestsPerHour:   10000,
+			Shared:            "kite/" + cfg.apiKey,
+		},
+		RetryConfig: httpclient.RetryConfig{
+			MaxRetries:    6,
+			BaseDelay:     100 * time.Millisecond,
+			MaxDelay:      5 * time.Second,
+			RetryOnStatus: []uint{429, 500, 502, 503},
+			Jitter:        retry.EqualJitter,
+			Budget:        36,
+			BudgetWindow:  time.Minute,
+			Hooks:         cfg.retryHooks,
+		},
+	}
+
+	return &KiteProvider{
+		client:      httpclient.NewClient(clientConfig),
+		apiKey:      cfg.apiKey,
+		accessToken: cfg.accessToken,
+		instruments: make(map[types.Exchange]map[string]string),
+		precision:   cfg.precision,
+	}
+}
+
+func (k *KiteProvider) Name() string {
+	return "kite"
+}
+
+func (k *KiteProvider) Freshness() types.DataFreshness {
+	return types.FreshnessDelayed
+}
+
+// MaxRange reports the largest range the historical API accepts per request
+// for interval.
+func (k *KiteProvider) MaxRange(interval types.Interval) time.Duration {
+	const day = 24 * time.Hour
+
+	switch interval {
+	case types.Interval1m:
+		return 60 * day
+	case types.Interval5m:
+		return 100 * day
+	case types.Interval15m, types.Interval30m:
+		return 200 * day
+	case types.Interval1h:
+		return 400 * day
+	case types.Interval1d:
+		return 2000 * day
+	default:
+		return 0
+	}
+}
+
+// RemainingRateLimit reports the requests left in the current second, minute
+// and hour of the provider's rate limit.
+func (k *KiteProvider) RemainingRateLimit() (provider.RateLimits, bool) {
+	c, ok := k.client.(*httpclient.Client)
+	if !ok {
+		return provider.RateLimits{}, false
+	}
+
+	perSecond, perMinute, perHour := c.Remaining()
+	return provider.RateLimits{
+		RequestsPerSecond: perSecond,
+		RequestsPerMinute: perMinute,
+		RequestsPerHour:   perHour,
+	}, true
+}
+
+func (k *KiteProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	if k.apiKey == "" || k.accessToken == "" {
+		return nil, errors.New("kite api key and access token are required")
+	}
+
+	kiteInterval, err := k.intervalToKiteInterval(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval: %w", err)
+	}
+
+	token, err := k.instrumentToken(ctx, symbol, exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := exchange.Location()
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		t := to.In(loc)
+		from = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+
+	query := url.Values{}
+	query.Set("from", from.In(loc).Format(time.DateTime))
+	query.Set("to", to.In(loc).Format(time.DateTime))
+	reqURL := fmt.Sprintf("%s/instruments/historical/%s/%s?%s", baseURL, token, kiteInterval, query.Encode())
+
+	body, err := k.get(ctx, reqURL, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp kiteResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("kite error: %s: %s", resp.ErrorType, resp.Message)
+	}
+
+	ohlcvs := make([]types.OHLCV, 0, len(resp.Data.Candles))
+	for _, c := range resp.Data.Candles {
+		if len(c) < 6 {
+			continue
+		}
+
+		ts, _ := c[0].(string)
+		t, err := time.Parse("2006-01-02T15:04:05-0700", ts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp %q: %w", ts, err)
+		}
+
+		open, _ := c[1].(float64)
+		high, _ := c[2].(float64)
+		low, _ := c[3].(float64)
+		closePrice, _ := c[4].(float64)
+		volume, _ := c[5].(float64)
+
+		ohlcvs = append(ohlcvs, types.OHLCV{
+			Symbol:    symbol,
+			Exchange:  exchange,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     closePrice,
+			Volume:    int64(volume),
+			DateTime:  t.In(loc),
+			Interval:  interval,
+			Source:    k.Name(),
+			Freshness: k.Freshness(),
+		})
+	}
+
+	return k.normalizeOHLCVs(ohlcvs), nil
+}
+
+// instrumentToken resolves symbol to its Kite instrument token, downloading
+// the instruments dump for exchange on first use. Numeric symbols are taken
+// to be instrument tokens already.
+func (k *KiteProvider) instrumentToken(ctx context.Context, symbol string, exchange types.Exchange) (string, error) {
+	if _, err := strconv.ParseUint(symbol, 10, 64); err == nil {
+		return symbol, nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	tokens, ok := k.instruments[exchange]
+	if !ok {
+		var err error
+		tokens, err = k.loadInstruments(ctx, exchange)
+		if err != nil {
+			return "", fmt.Errorf("failed to load instruments: %w", err)
+		}
+		k.instruments[exchange] = tokens
+	}
+
+	token, ok := tokens[symbol]
+	if !ok {
+		return "", fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, symbol, exchange)
+	}
+
+	return token, nil
+}
+
+func (k *KiteProvider) loadInstruments(ctx context.Context, exchange types.Exchange) (map[string]string, error) {
+	body, err := k.get(ctx, fmt.Sprintf("%s/instruments/%s", baseURL, exchange), "text/csv")
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse instruments csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty instruments dump")
+	}
+
+	tokenCol, symbolCol := -1, -1
+	for i, col := range records[0] {
+		switch col {
+		case "instrument_token":
+			tokenCol = i
+		case "tradingsymbol":
+			symbolCol = i
+		}
+	}
+	if tokenCol < 0 || symbolCol < 0 {
+		return nil, errors.New("instruments csv is missing instrument_token or tradingsymbol")
+	}
+
+	tokens := make(map[string]string, len(records)-1)
+	for _, rec := range records[1:] {
+		if len(rec) <= tokenCol || len(rec) <= symbolCol {
+			continue
+		}
+		tokens[rec[symbolCol]] = rec[tokenCol]
+	}
+
+	return tokens, nil
+}
+
+func (k *KiteProvider) get(ctx context.Context, reqURL, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("X-Kite-Version", "3")
+	req.Header.Set("Authorization", fmt.Sprintf("token %s:%s", k.apiKey, k.accessToken))
+
+	res, err := k.client.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &provider.ProviderError{Provider: k.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	return body, nil
+}
+
+// SupportsInterval reports whether Kite serves candles of interval.
+func (k *KiteProvider) SupportsInterval(interval types.Interval) bool {
+	_, err := k.intervalToKiteInterval(interval)
+	return err == nil
+}
+
+func (k *KiteProvider) intervalToKiteInterval(i types.Interval) (string, error) {
+	switch i {
+	case types.Interval1m:
+		return "minute", nil
+	case types.Interval5m:
+		return "5minute", nil
+	case types.Interval15m:
+		return "15minute", nil
+	case types.Interval30m:
+		return "30minute", nil
+	case types.Interval1h:
+		return "60minute", nil
+	case types.Interval1d:
+		return "day", nil
+	default:
+		return "", fmt.Errorf("%w: %s", provider.ErrUnknownInterval, i)
+	}
+}
+
+func (k *KiteProvider) normalizeOHLCVs(ohlcvs []types.OHLCV) []types.OHLCV {
+	return ohlcv.Round(k.precision).Transform(ohlcvs)
+}
@@ -0,0 +1,311 @@
+package kite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type mockHTTPClient struct {
+	calledCount int
+	requests    []*http.Request
+	responses   []*http.Response
+}
+
+func NewMockHTTPClient(responses []*http.Response) *mockHTTPClient {
+	return &mockHTTPClient{
+		calledCount: 0,
+		requests:    []*http.Request{},
+		responses:   responses,
+	}
+}
+
+func (m *mockHTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	m.calledCount++
+	m.requests = append(m.requests, req)
+
+	if m.calledCount-1 >= len(m.responses) {
+		return nil, errors.New("no more mock responses")
+	}
+	return m.responses[m.calledCount-1], nil
+}
+
+func createResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+const instrumentsCSV = `instrument_token,exchange_token,tradingsymbol,name,last_price,expiry,strike,tick_size,lot_size,instrument_type,segment,exchange
+738561,2885,RELIANCE,RELIANCE INDUSTRIES,0,,0,0.05,1,EQ,NSE,NSE
+408065,1594,INFY,INFOSYS,0,,0,0.05,1,EQ,NSE,NSE
+`
+
+const candlesJSON = `{"status":"success","data":{"candles":[
+	["2024-01-05T09:15:00+0530",2590.123,2595.456,2588,2594.999,2499],
+	["2024-01-05T09:16:00+0530",2595,2596,2594,2595.5,1000]
+]}}`
+
+func newTestProvider(responses ...*http.Response) (*KiteProvider, *mockHTTPClient) {
+	mockClient := NewMockHTTPClient(responses)
+	provider := NewKiteProvider(WithAPIKey("key"), WithAccessToken("token"))
+	provider.client = mockClient
+	return provider, mockClient
+}
+
+func TestNewKiteProvider(t *testing.T) {
+	provider := NewKiteProvider(WithAPIKey("key"), WithAccessToken("token"), WithHTTPClient(&http.Client{}))
+
+	if provider.apiKey != "key" || provider.accessToken != "token" {
+		t.Error("Expected credentials to be set from options")
+	}
+	if provider.Name() != "kite" {
+		t.Errorf("Expected name 'kite', got '%s'", provider.Name())
+	}
+}
+
+func TestKiteProvider_Provide_Success(t *testing.T) {
+	provider, mockClient := newTestProvider(
+		createResponse(200, instrumentsCSV),
+		createResponse(200, candlesJSON),
+	)
+
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	from := time.Date(2024, 1, 5, 9, 15, 0, 0, loc)
+	to := time.Date(2024, 1, 5, 9, 16, 0, 0, loc)
+
+	ohlcvs, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1m, from, to)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ohlcvs) != 2 {
+		t.Fatalf("Expected 2 OHLCV records, got %d", len(ohlcvs))
+	}
+
+	if got := mockClient.requests[0].URL.String(); got != "https://api.kite.trade/instruments/NSE" {
+		t.Errorf("Unexpected instruments URL %s", got)
+	}
+
+	expectedURL := "https://api.kite.trade/instruments/historical/738561/minute?from=2024-01-05+09%3A15%3A00&to=2024-01-05+09%3A16%3A00"
+	if got := mockClient.requests[1].URL.String(); got != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, got)
+	}
+
+	for _, req := range mockClient.requests {
+		if req.Header.Get("Authorization") != "token key:token" {
+			t.Errorf("Unexpected Authorization header %q", req.Header.Get("Authorization"))
+		}
+		if req.Header.Get("X-Kite-Version") != "3" {
+			t.Error("Expected X-Kite-Version header")
+		}
+	}
+
+	first := ohlcvs[0]
+	if first.Open != 2590.12 || first.High != 2595.46 || first.Close != 2595 || first.Volume != 2499 {
+		t.Errorf("Unexpected first candle %+v", first)
+	}
+	if !first.DateTime.Equal(from) {
+		t.Errorf("Expected %v, got %v", from, first.DateTime)
+	}
+	if first.DateTime.Location().String() != "Asia/Kolkata" {
+		t.Errorf("Expected time in IST, got %v", first.DateTime.Location())
+	}
+	if first.Source != "kite" {
+		t.Errorf("Expected source kite, got %s", first.Source)
+	}
+}
+
+func TestKiteProvider_Provide_CachesInstruments(t *testing.T) {
+	provider, mockClient := newTestProvider(
+		createResponse(200, instrumentsCSV),
+		createResponse(200, candlesJSON),
+		createResponse(200, candlesJSON),
+	)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	if _, err := provider.Provide(ctx, "RELIANCE", types.ExchangeNSE, types.Interval1d, from, to); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := provider.Provide(ctx, "INFY", types.ExchangeNSE, types.Interval1d, from, to); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockClient.calledCount != 3 {
+		t.Errorf("Expected instruments to be downloaded once, got %d calls", mockClient.calledCount)
+	}
+	if !strings.Contains(mockClient.requests[2].URL.Path, "/408065/day") {
+		t.Errorf("Expected INFY token in URL, got %s", mockClient.requests[2].URL.Path)
+	}
+}
+
+func TestKiteProvider_Provide_MissingCredentials(t *testing.T) {
+	provider := NewKiteProvider(WithAPIKey("key"))
+
+	_, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error without access token")
+	}
+}
+
+func TestKiteProvider_Provide_SymbolNotFound(t *testing.T) {
+	provider, _ := newTestProvider(createResponse(200, instrumentsCSV))
+
+	_, err := provider.Provide(context.Background(), "UNKNOWN", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil || !strings.Contains(err.Error(), "symbol not found") {
+		t.Errorf("Expected symbol not found error, got %v", err)
+	}
+}
+
+func TestKiteProvider_Provide_InstrumentToken(t *testing.T) {
+	provider, mockClient := newTestProvider(createResponse(200, candlesJSON))
+
+	from := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	if _, err := provider.Provide(context.Background(), "519937", types.ExchangeNSE, types.Interval1d, from, from.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockClient.calledCount != 1 {
+		t.Errorf("Expected numeric token to skip the instruments dump, got %d calls", mockClient.calledCount)
+	}
+	if !strings.Contains(mockClient.requests[0].URL.Path, "/519937/day") {
+		t.Errorf("Expected token in URL, got %s", mockClient.requests[0].URL.Path)
+	}
+}
+
+func TestKiteProvider_Provide_InvalidInterval(t *testing.T) {
+	provider, mockClient := newTestProvider()
+
+	_, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1wk, time.Time{}, time.Time{})
+
+	if err == nil || !strings.Contains(err.Error(), "invalid interval") {
+		t.Errorf("Expected invalid interval error, got %v", err)
+	}
+	if mockClient.calledCount != 0 {
+		t.Error("Expected no requests for invalid interval")
+	}
+}
+
+func TestKiteProvider_Provide_InstrumentsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *http.Response
+	}{
+		{"NonOK", createResponse(403, "forbidden")},
+		{"Empty", createResponse(200, "")},
+		{"MissingColumns", createResponse(200, "a,b\n1,2\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newTestProvider(tt.response)
+
+			_, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+			if err == nil || !strings.Contains(err.Error(), "failed to load instruments") {
+				t.Errorf("Expected instruments error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKiteProvider_Provide_APIError(t *testing.T) {
+	provider, _ := newTestProvider(
+		createResponse(200, instrumentsCSV),
+		createResponse(200, `{"status":"error","message":"Incorrect api_key or access_token.","error_type":"TokenException"}`),
+	)
+
+	_, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil || !strings.Contains(err.Error(), "TokenException") {
+		t.Errorf("Expected TokenException error, got %v", err)
+	}
+}
+
+func TestKiteProvider_Provide_InvalidTimestamp(t *testing.T) {
+	provider, _ := newTestProvider(
+		createResponse(200, instrumentsCSV),
+		createResponse(200, `{"status":"success","data":{"candles":[["bad",1,1,1,1,1]]}}`),
+	)
+
+	_, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if err == nil {
+		t.Error("Expected error for invalid timestamp")
+	}
+}
+
+func TestKiteProvider_Provide_DefaultRange(t *testing.T) {
+	provider, mockClient := newTestProvider(
+		createResponse(200, instrumentsCSV),
+		createResponse(200, `{"status":"success","data":{"candles":[]}}`),
+	)
+
+	_, err := provider.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	query := mockClient.requests[1].URL.Query()
+	if !strings.HasSuffix(query.Get("from"), "00:00:00") {
+		t.Errorf("Expected from to default to start of day, got %s", query.Get("from"))
+	}
+	if query.Get("to") == "" {
+		t.Error("Expected to to default to now")
+	}
+}
+
+func TestKiteProvider_MaxRange(t *testing.T) {
+	provider := &KiteProvider{}
+
+	if provider.MaxRange(types.Interval1m) != 60*24*time.Hour {
+		t.Errorf("Unexpected 1m max range %v", provider.MaxRange(types.Interval1m))
+	}
+	if provider.MaxRange(types.Interval1d) != 2000*24*time.Hour {
+		t.Errorf("Unexpected 1d max range %v", provider.MaxRange(types.Interval1d))
+	}
+	if provider.MaxRange(types.Interval1wk) != 0 {
+		t.Error("Expected unsupported interval to be uncapped")
+	}
+}
+
+func TestKiteProvider_Provide_TypedErrors(t *testing.T) {
+	t.Run("SymbolNotFound", func(t *testing.T) {
+		p, _ := newTestProvider(createResponse(200, instrumentsCSV))
+
+		_, err := p.Provide(context.Background(), "UNKNOWN", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+		if !errors.Is(err, provider.ErrSymbolNotFound) {
+			t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+		}
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		p, _ := newTestProvider(
+			createResponse(200, instrumentsCSV),
+			createResponse(429, "Too many requests"),
+		)
+
+		_, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+
+		if !errors.Is(err, provider.ErrRateLimited) {
+			t.Errorf("Expected ErrRateLimited, got %v", err)
+		}
+	})
+}
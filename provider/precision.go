@@ -0,0 +1,42 @@
+package provider
+
+import "math"
+
+// DefaultPrecision is the number of decimal places prices are rounded to
+// unless a provider is given another Precision.
+const DefaultPrecision = 2
+
+// Precision is how many decimal places a provider rounds prices to. The zero
+// value rounds to DefaultPrecision places.
+type Precision struct {
+	places int
+	set    bool
+}
+
+// Decimals rounds prices to n decimal places. A negative n keeps full
+// precision.
+func Decimals(n int) Precision {
+	return Precision{places: n, set: true}
+}
+
+// FullPrecision leaves prices as the provider reports them.
+var FullPrecision = Decimals(-1)
+
+// Places returns the number of decimal places, or -1 for full precision.
+func (p Precision) Places() int {
+	if !p.set {
+		return DefaultPrecision
+	}
+	return max(p.places, -1)
+}
+
+// Round rounds v to p's decimal places, halves away from zero.
+func (p Precision) Round(v float64) float64 {
+	places := p.Places()
+	if places < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+
+	pow := math.Pow10(places)
+	return math.Round(v*pow) / pow
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrecision_Round(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision Precision
+		input     float64
+		expected  float64
+	}{
+		{"default", Precision{}, 100.125, 100.13},
+		{"default negative", Precision{}, -100.125, -100.13},
+		{"zero places", Decimals(0), 99.5, 100},
+		{"four places", Decimals(4), 0.123456, 0.1235},
+		{"eight places", Decimals(8), 0.000012345678, 0.00001235},
+		{"full precision", FullPrecision, 0.123456789, 0.123456789},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.precision.Round(tc.input); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPrecision_Places(t *testing.T) {
+	if p := (Precision{}).Places(); p != DefaultPrecision {
+		t.Errorf("Expected %d, got %d", DefaultPrecision, p)
+	}
+	if p := Decimals(6).Places(); p != 6 {
+		t.Errorf("Expected 6, got %d", p)
+	}
+	if p := Decimals(-5).Places(); p != -1 {
+		t.Errorf("Expected -1 for full precision, got %d", p)
+	}
+}
+
+func TestPrecision_Round_NaN(t *testing.T) {
+	if got := Decimals(2).Round(math.NaN()); !math.IsNaN(got) {
+		t.Errorf("Expected NaN, got %v", got)
+	}
+}
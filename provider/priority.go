@@ -0,0 +1,35 @@
+package provider
+
+import "context"
+
+// Priority tells how urgent a request is. While a provider's rate limit holds
+// requests back, those of higher priority are sent first, though lower
+// priorities are never starved completely.
+type Priority int
+
+const (
+	// PriorityLow is for background work, such as backfills and ingestion.
+	PriorityLow Priority = -1
+	// PriorityNormal is the priority of requests that don't set one.
+	PriorityNormal Priority = 0
+	// PriorityHigh is for interactive requests a user is waiting on.
+	PriorityHigh Priority = 1
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx that gives the requests made with it
+// priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set on ctx with WithPriority, or
+// PriorityNormal and false if none is.
+func PriorityFromContext(ctx context.Context) (Priority, bool) {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok {
+		return PriorityNormal, false
+	}
+	return p, true
+}
@@ -0,0 +1,17 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	if p, ok := PriorityFromContext(context.Background()); ok || p != PriorityNormal {
+		t.Errorf("Expected PriorityNormal and false without a priority, got %v %v", p, ok)
+	}
+
+	ctx := WithPriority(context.Background(), PriorityLow)
+	if p, ok := PriorityFromContext(ctx); !ok || p != PriorityLow {
+		t.Errorf("Expected PriorityLow, got %v %v", p, ok)
+	}
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type OHLCVProvider interface {
+	Name() string
+	Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error)
+}
+
+// FreshnessReporter is optionally implemented by providers to report how fresh
+// the data they serve is. Providers reporting types.FreshnessHistorical are
+// only asked for completed trading days.
+type FreshnessReporter interface {
+	Freshness() types.DataFreshness
+}
+
+// RangeLimiter is optionally implemented by providers that cap the range a
+// single request may span. MaxRange returns zero when interval is uncapped.
+type RangeLimiter interface {
+	MaxRange(interval types.Interval) time.Duration
+}
+
+// HistoryLimiter is optionally implemented by providers that only keep
+// candles of some intervals for a while. MaxHistory returns how far before
+// now candles of interval go back, or zero when they go back indefinitely.
+type HistoryLimiter interface {
+	MaxHistory(interval types.Interval) time.Duration
+}
+
+// IntervalLimiter is optionally implemented by providers that only serve
+// some intervals, so that requests for others fail before any is sent.
+type IntervalLimiter interface {
+	SupportsInterval(interval types.Interval) bool
+}
+
+// ExchangeLimiter is optionally implemented by providers that only serve
+// some exchanges, so that requests for others fail before any is sent.
+type ExchangeLimiter interface {
+	SupportsExchange(exchange types.Exchange) bool
+}
+
+// AdjustedProvider is optionally implemented by providers that can serve
+// prices adjusted for splits and dividends. Adjusted candles carry the factor
+// applied in OHLCV.AdjustmentFactor.
+type AdjustedProvider interface {
+	ProvideAdjusted(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error)
+}
+
+// FrameProvider is optionally implemented by providers that can decode
+// candles straight into a types.Frame, without building a []types.OHLCV first.
+type FrameProvider interface {
+	ProvideFrame(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) (*types.Frame, error)
+}
+
+// ProvideFrame returns the candles of p as a frame, decoded directly if p is a
+// FrameProvider.
+func ProvideFrame(ctx context.Context, p OHLCVProvider, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) (*types.Frame, error) {
+	if fp, ok := p.(FrameProvider); ok {
+		return fp.ProvideFrame(ctx, symbol, exchange, interval, start, end)
+	}
+
+	candles, err := p.Provide(ctx, symbol, exchange, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return types.FrameOf(candles), nil
+}
+
+// CorporateActionsProvider is optionally implemented by providers that serve
+// dividends and splits.
+type CorporateActionsProvider interface {
+	CorporateActions(ctx context.Context, symbol string, exchange types.Exchange, start, end time.Time) (types.CorporateActions, error)
+}
+
+// QuoteProvider is optionally implemented by providers that serve the latest
+// quote of a symbol.
+type QuoteProvider interface {
+	Quote(ctx context.Context, symbol string, exchange types.Exchange) (types.Quote, error)
+}
+
+// FundamentalsProvider is optionally implemented by providers that serve key
+// statistics of a company, such as its market capitalization and P/E ratio.
+type FundamentalsProvider interface {
+	Fundamentals(ctx context.Context, symbol string, exchange types.Exchange) (types.Fundamentals, error)
+}
+
+// CalendarEventsProvider is optionally implemented by providers that serve
+// upcoming earnings and dividend dates.
+type CalendarEventsProvider interface {
+	CalendarEvents(ctx context.Context, symbol string, exchange types.Exchange) ([]types.CalendarEvent, error)
+}
+
+// DepthProvider is optionally implemented by providers that serve order book
+// snapshots of a symbol.
+type DepthProvider interface {
+	Depth(ctx context.Context, symbol string, exchange types.Exchange) (types.Depth, error)
+}
+
+// SymbolResolver is optionally implemented by providers that can find the
+// trading symbol of an ISIN.
+type SymbolResolver interface {
+	ResolveISIN(ctx context.Context, isin string, exchange types.Exchange) (string, error)
+}
+
+// RateLimitReporter is optionally implemented by providers that can report
+// how much of their rate-limit budget is left. ok is false if the budget is
+// unknown, as when the provider was given a custom client.
+type RateLimitReporter interface {
+	RemainingRateLimit() (remaining RateLimits, ok bool)
+}
+
+// RateLimits caps how many requests a provider sends per second, minute and
+// hour. All three must be set; a zero limit lets no request through.
+type RateLimits struct {
+	RequestsPerSecond int
+	RequestsPerMinute int
+	RequestsPerHour   int
+	// MaxQueue caps how many requests may wait for the budget at once; more
+	// fail with ErrRateLimited. Zero means no limit.
+	MaxQueue int
+}
+
+// RetryBudget caps how many times a provider retries failed requests within
+// Window, across all its requests, on top of the retries each request may
+// make. Once it is used up, failing requests give up after one attempt.
+type RetryBudget struct {
+	Retries int
+	Window  time.Duration
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type sliceProvider struct {
+	candles []types.OHLCV
+}
+
+func (p *sliceProvider) Name() string {
+	return "slice"
+}
+
+func (p *sliceProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+	return p.candles, nil
+}
+
+type frameProvider struct {
+	sliceProvider
+	frames int
+}
+
+func (p *frameProvider) ProvideFrame(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) (*types.Frame, error) {
+	p.frames++
+	return types.FrameOf(p.candles), nil
+}
+
+func TestProvideFrame(t *testing.T) {
+	candles := []types.OHLCV{
+		{Symbol: "INFY", Close: 1500, DateTime: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Symbol: "INFY", Close: 1510, DateTime: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	f, err := ProvideFrame(context.Background(), &sliceProvider{candles: candles}, "INFY", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.Len() != 2 || f.Symbol != "INFY" || f.Closes[1] != 1510 {
+		t.Errorf("Expected the candles as a frame, got %+v", f)
+	}
+
+	fp := &frameProvider{sliceProvider: sliceProvider{candles: candles}}
+	if _, err := ProvideFrame(context.Background(), fp, "INFY", types.ExchangeNSE, types.Interval1d, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fp.frames != 1 {
+		t.Errorf("Expected ProvideFrame to be called once, got %d", fp.frames)
+	}
+}
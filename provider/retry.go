@@ -0,0 +1,28 @@
+package provider
+
+import "time"
+
+// RetryEvent describes a failed request attempt of a built-in provider.
+type RetryEvent struct {
+	// Provider is the name of the provider, such as "yahoo".
+	Provider string
+	// Attempt counts the attempts of the request from 1.
+	Attempt int
+	// Delay is the wait before the next attempt, zero when giving up.
+	Delay time.Duration
+	// Err is the error the attempt failed with, nil if it got a response.
+	Err error
+	// StatusCode is the status of the response, zero if there was none.
+	StatusCode int
+}
+
+// RetryHooks let applications log or count the retries of a built-in
+// provider. Hooks are called from the goroutine making the request and
+// should return quickly.
+type RetryHooks struct {
+	// OnRetry is called before waiting for every retry.
+	OnRetry func(RetryEvent)
+	// OnGiveUp is called when a failed attempt isn't retried because the
+	// request's retries or the provider's retry budget are used up.
+	OnGiveUp func(RetryEvent)
+}
@@ -0,0 +1,76 @@
+package upstox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type upstoxResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Candles [][]any `json:"candles"`
+	} `json:"data"`
+}
+
+// candleFields is how many leading fields of a candle row are required:
+// time, open, high, low, close and volume. Upstox appends open interest,
+// which is read when it is a number.
+const candleFields = 6
+
+// decodeCandles decodes a historical candle response body into candles of
+// symbol, with times in the exchange's location. Malformed rows, such as
+// short rows, unparseable times or non-numeric values, fail the decode
+// rather than yield zero candles.
+func decodeCandles(body []byte, symbol string, exchange types.Exchange) ([]types.OHLCV, error) {
+	var resp upstoxResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	loc := exchange.Location()
+	ohlcvs := make([]types.OHLCV, 0, len(resp.Data.Candles))
+	for i, row := range resp.Data.Candles {
+		if len(row) < candleFields {
+			return nil, fmt.Errorf("malformed candle %d: %d fields, want %d", i, len(row), candleFields)
+		}
+
+		ts, ok := row[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("malformed candle %d: time is %T, want string", i, row[0])
+		}
+		t, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			return nil, fmt.Errorf("malformed candle %d: %w", i, err)
+		}
+
+		var values [candleFields - 1]float64
+		for j := range values {
+			v, ok := row[j+1].(float64)
+			if !ok {
+				return nil, fmt.Errorf("malformed candle %d: field %d is %T, want number", i, j+1, row[j+1])
+			}
+			values[j] = v
+		}
+
+		var oi float64
+		if len(row) > candleFields {
+			oi, _ = row[candleFields].(float64)
+		}
+
+		ohlcvs = append(ohlcvs, types.OHLCV{
+			Symbol:       symbol,
+			Exchange:     exchange,
+			Open:         values[0],
+			High:         values[1],
+			Low:          values[2],
+			Close:        values[3],
+			Volume:       int64(values[4]),
+			DateTime:     t.In(loc),
+			OpenInterest: int64(oi),
+		})
+	}
+	return ohlcvs, nil
+}
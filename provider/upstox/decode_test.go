@@ -0,0 +1,100 @@
+package upstox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const candlesBody = `{"status":"success","data":{"candles":[["2024-01-02T09:15:00+05:30",2580.9,2597.7,2566.4,2584.5,4474301,0],["2024-01-02T09:16:00+05:30",2584.5,2590,2580,2585,1200]]}}`
+
+func TestDecodeCandles(t *testing.T) {
+	candles, err := decodeCandles([]byte(candlesBody), "RELIANCE", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(candles) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(candles))
+	}
+
+	c := candles[0]
+	if c.Open != 2580.9 || c.Close != 2584.5 || c.Volume != 4474301 || c.Symbol != "RELIANCE" {
+		t.Errorf("Unexpected candle: %+v", c)
+	}
+	if c.OpenInterest != 0 || candles[1].OpenInterest != 0 {
+		t.Errorf("Expected no open interest, got %d and %d", c.OpenInterest, candles[1].OpenInterest)
+	}
+	if c.DateTime.Location().String() != "Asia/Kolkata" || c.DateTime.Hour() != 9 || c.DateTime.Minute() != 15 {
+		t.Errorf("Expected 09:15 IST, got %v", c.DateTime)
+	}
+}
+
+func TestDecodeCandles_OpenInterest(t *testing.T) {
+	body := `{"status":"success","data":{"candles":[["2024-01-25T15:29:00+05:30",21450,21455.5,21448,21452.05,112350,13426050],["2024-01-25T15:28:00+05:30",21449,21451,21447,21450,98500,null]]}}`
+	candles, err := decodeCandles([]byte(body), "NIFTY24JANFUT", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if candles[0].OpenInterest != 13426050 {
+		t.Errorf("Expected open interest 13426050, got %d", candles[0].OpenInterest)
+	}
+	if candles[1].OpenInterest != 0 {
+		t.Errorf("Expected a null open interest read as zero, got %d", candles[1].OpenInterest)
+	}
+}
+
+func TestDecodeCandles_Empty(t *testing.T) {
+	for _, body := range []string{`{"status":"success","data":{"candles":[]}}`, `{"status":"success","data":{}}`, `{}`} {
+		candles, err := decodeCandles([]byte(body), "RELIANCE", types.ExchangeNSE)
+		if err != nil || len(candles) != 0 {
+			t.Errorf("Expected no candles for %s, got %v, %v", body, candles, err)
+		}
+	}
+}
+
+func TestDecodeCandles_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"ShortRow", `{"data":{"candles":[["2024-01-02T09:15:00+05:30",1,2,3]]}}`, "4 fields"},
+		{"NumericTime", `{"data":{"candles":[[1704166500,1,2,3,4,5]]}}`, "time is float64"},
+		{"BadTime", `{"data":{"candles":[["yesterday",1,2,3,4,5]]}}`, "malformed candle 0"},
+		{"StringPrice", `{"data":{"candles":[["2024-01-02T09:15:00+05:30","1",2,3,4,5]]}}`, "field 1 is string"},
+		{"NullVolume", `{"data":{"candles":[["2024-01-02T09:15:00+05:30",1,2,3,4,null]]}}`, "field 5 is <nil>"},
+		{"NotArray", `{"data":{"candles":{}}}`, "failed to unmarshal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCandles([]byte(tt.body), "RELIANCE", types.ExchangeNSE)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func FuzzDecodeCandles(f *testing.F) {
+	f.Add([]byte(candlesBody))
+	f.Add([]byte(`{"data":{"candles":[[],[null],["x"]]}}`))
+	f.Add([]byte(`{"data":{"candles":[["2024-01-02T09:15:00Z",1,2,3,4,5,6,7]]}}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		candles, err := decodeCandles(body, "RELIANCE", types.ExchangeNSE)
+		if err != nil {
+			if candles != nil {
+				t.Fatalf("Expected no candles with an error, got %d", len(candles))
+			}
+			return
+		}
+		for _, c := range candles {
+			if c.DateTime.IsZero() {
+				t.Fatalf("Expected every decoded candle to have a time, got %+v", c)
+			}
+		}
+	})
+}
@@ -0,0 +1,358 @@
+package upstox
+
+import (
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// InstrumentsURL is the gzipped JSON instrument master Upstox publishes daily.
+const InstrumentsURL = "https://assets.upstox.com/market-quote/instruments/exchange/complete.json.gz"
+
+// DefaultMaxInstrumentsSize caps the decompressed size of a downloaded
+// instrument master, several times the size of the current one.
+const DefaultMaxInstrumentsSize = 512 << 20
+
+// InstrumentStore maps trading symbols to Upstox instrument keys. It starts
+// from the copy embedded in the module and can be refreshed from the latest
+// instrument master, optionally cached on disk.
+type InstrumentStore struct {
+	mu          sync.RWMutex
+	instruments map[string]instrument
+	updatedAt   time.Time
+
+	url        string
+	httpClient *http.Client
+	cacheFile  string
+	ttl        time.Duration
+	maxSize    int64
+}
+
+type StoreOption func(*InstrumentStore)
+
+// WithCacheFile caches the downloaded instrument master at path. Load reuses
+// the file while it is younger than ttl.
+func WithCacheFile(path string, ttl time.Duration) StoreOption {
+	return func(s *InstrumentStore) {
+		s.cacheFile = path
+		s.ttl = ttl
+	}
+}
+
+// WithSourceURL downloads the instrument master from url instead of
+// InstrumentsURL.
+func WithSourceURL(url string) StoreOption {
+	return func(s *InstrumentStore) {
+		s.url = url
+	}
+}
+
+// WithStoreHTTPClient sets the HTTP client used to download the instrument
+// master.
+func WithStoreHTTPClient(client *http.Client) StoreOption {
+	return func(s *InstrumentStore) {
+		s.httpClient = client
+	}
+}
+
+// WithMaxInstrumentsSize caps the decompressed size of a downloaded
+// instrument master at n bytes instead of DefaultMaxInstrumentsSize. Zero
+// removes the limit.
+func WithMaxInstrumentsSize(n int64) StoreOption {
+	return func(s *InstrumentStore) {
+		s.maxSize = n
+	}
+}
+
+// NewInstrumentStore creates a store holding the embedded instruments. It
+// panics if the embedded copy can't be parsed.
+func NewInstrumentStore(opts ...StoreOption) *InstrumentStore {
+	s := &InstrumentStore{
+		url:        InstrumentsURL,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		maxSize:    DefaultMaxInstrumentsSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	instruments, err := parseInstruments(instrumentsJSON)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load instruments: %v", err))
+	}
+	s.instruments = instruments
+
+	return s
+}
+
+// Lookup returns the instrument for symbol on exchange. A symbol that is
+// already an instrument key, such as "NSE_EQ|INE002A01018", is used as is.
+func (s *InstrumentStore) Lookup(symbol, exchange string) (instrument, bool) {
+	if strings.Contains(symbol, "|") {
+		return instrument{InstrumentKey: symbol, TradingSymbol: symbol, Exchange: exchange}, true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, ok := s.instruments[instrumentID(symbol, exchange)]
+	return inst, ok
+}
+
+// Key returns the Upstox instrument key of symbol on exchange, as used by the
+// market data feed and quote APIs.
+func (s *InstrumentStore) Key(symbol, exchange string) (string, bool) {
+	inst, ok := s.Lookup(symbol, exchange)
+	return inst.InstrumentKey, ok
+}
+
+// LookupISIN returns the instrument with isin on exchange. An empty exchange
+// matches any exchange. When several instruments share the ISIN, equity
+// shares are preferred, then the first by symbol.
+func (s *InstrumentStore) LookupISIN(isin, exchange string) (types.Instrument, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		best  instrument
+		found bool
+	)
+	for _, inst := range s.instruments {
+		if !strings.EqualFold(inst.ISIN, isin) || (exchange != "" && inst.Exchange != exchange) {
+			continue
+		}
+		if !found || isinRank(inst, best) < 0 {
+			best, found = inst, true
+		}
+	}
+	return best.toInstrument(), found
+}
+
+func isinRank(a, b instrument) int {
+	aEQ, bEQ := a.InstrumentType == "EQ", b.InstrumentType == "EQ"
+	if aEQ != bEQ {
+		if aEQ {
+			return -1
+		}
+		return 1
+	}
+	if c := cmp.Compare(a.TradingSymbol, b.TradingSymbol); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Exchange, b.Exchange)
+}
+
+// UpdatedAt returns when the instruments were last downloaded or read from
+// the cache file; it is zero while the embedded copy is in use.
+func (s *InstrumentStore) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.updatedAt
+}
+
+// Load brings the store up to date: from the cache file if it is fresh,
+// otherwise by downloading the instrument master. On failure the instruments
+// already held, initially the embedded copy, stay in use and the error is
+// returned.
+func (s *InstrumentStore) Load(ctx context.Context) error {
+	if s.cacheFile != "" {
+		if info, err := os.Stat(s.cacheFile); err == nil && time.Since(info.ModTime()) < s.ttl {
+			if err := s.loadFile(info.ModTime()); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return s.Refresh(ctx)
+}
+
+// Refresh downloads the latest instrument master, replaces the instruments
+// held and, if a cache file is configured, writes it there.
+func (s *InstrumentStore) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("non-OK response: %d", res.StatusCode)
+	}
+
+	gz, err := gzip.NewReader(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decompress instruments: %w", err)
+	}
+	defer gz.Close()
+
+	var r io.Reader = gz
+	if s.maxSize > 0 {
+		r = httpclient.LimitReader(gz, s.maxSize)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read instruments: %w", err)
+	}
+
+	instruments, err := parseInstruments(data)
+	if err != nil {
+		return err
+	}
+
+	if s.cacheFile != "" {
+		if err := writeFileAtomic(s.cacheFile, data); err != nil {
+			return fmt.Errorf("failed to cache instruments: %w", err)
+		}
+	}
+
+	s.replace(instruments, time.Now())
+	return nil
+}
+
+func (s *InstrumentStore) loadFile(modTime time.Time) error {
+	data, err := os.ReadFile(s.cacheFile)
+	if err != nil {
+		return err
+	}
+
+	instruments, err := parseInstruments(data)
+	if err != nil {
+		return err
+	}
+
+	s.replace(instruments, modTime)
+	return nil
+}
+
+func (s *InstrumentStore) replace(instruments map[string]instrument, updatedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.instruments = instruments
+	s.updatedAt = updatedAt
+}
+
+func parseInstruments(data []byte) (map[string]instrument, error) {
+	var instruments []instrument
+	if err := json.Unmarshal(data, &instruments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal instruments: %w", err)
+	}
+
+	byID := make(map[string]instrument, len(instruments))
+	for _, inst := range instruments {
+		byID[instrumentID(inst.TradingSymbol, inst.Exchange)] = inst
+	}
+
+	return byID, nil
+}
+
+func instrumentID(symbol, exchange string) string {
+	return fmt.Sprint(symbol, ":", exchange)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Search returns the instruments whose trading symbol, name or ISIN matches
+// query, ignoring case. An empty exchange searches every exchange. Exact
+// symbol and ISIN matches come first, then symbol prefixes, then symbols and
+// names containing query; ties are ordered by symbol.
+func (s *InstrumentStore) Search(query, exchange string) []types.Instrument {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	type match struct {
+		rank int
+		inst instrument
+	}
+
+	s.mu.RLock()
+	var matches []match
+	for _, inst := range s.instruments {
+		if exchange != "" && inst.Exchange != exchange {
+			continue
+		}
+
+		symbol := strings.ToUpper(inst.TradingSymbol)
+		switch {
+		case symbol == query || strings.ToUpper(inst.ISIN) == query:
+			matches = append(matches, match{0, inst})
+		case strings.HasPrefix(symbol, query):
+			matches = append(matches, match{1, inst})
+		case strings.Contains(symbol, query) || strings.Contains(strings.ToUpper(inst.Name), query):
+			matches = append(matches, match{2, inst})
+		}
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(matches, func(a, b match) int {
+		if c := cmp.Compare(a.rank, b.rank); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.inst.TradingSymbol, b.inst.TradingSymbol); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.inst.Exchange, b.inst.Exchange)
+	})
+
+	out := make([]types.Instrument, len(matches))
+	for i, m := range matches {
+		out[i] = m.inst.toInstrument()
+	}
+	return out
+}
+
+func (i instrument) toInstrument() types.Instrument {
+	return types.Instrument{
+		Symbol:         i.TradingSymbol,
+		Name:           i.Name,
+		Exchange:       types.Exchange(i.Exchange),
+		ISIN:           i.ISIN,
+		Segment:        i.Segment,
+		InstrumentType: i.InstrumentType,
+		LotSize:        i.LotSize,
+		TickSize:       i.TickSize,
+	}
+}
@@ -0,0 +1,239 @@
+package upstox
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const masterJSON = `[{"trading_symbol":"NEWCO","exchange":"NSE","instrument_key":"NSE_EQ|INE000000001"}]`
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func masterServer(t *testing.T, calls *int) *httptest.Server {
+	t.Helper()
+
+	body := gzipped(t, masterJSON)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestInstrumentStore_Refresh(t *testing.T) {
+	calls := 0
+	srv := masterServer(t, &calls)
+	cacheFile := filepath.Join(t.TempDir(), "instruments.json")
+
+	s := NewInstrumentStore(WithSourceURL(srv.URL), WithCacheFile(cacheFile, time.Hour))
+
+	if err := s.Refresh(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	inst, ok := s.Lookup("NEWCO", "NSE")
+	if !ok || inst.InstrumentKey != "NSE_EQ|INE000000001" {
+		t.Errorf("Expected downloaded instrument, got %+v (found %v)", inst, ok)
+	}
+	if s.UpdatedAt().IsZero() {
+		t.Error("Expected UpdatedAt to be set")
+	}
+
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		t.Fatalf("Expected cache file to be written, got %v", err)
+	}
+	if string(data) != masterJSON {
+		t.Errorf("Expected cache file to hold the instrument master, got %q", data)
+	}
+}
+
+func TestInstrumentStore_Load_UsesFreshCacheFile(t *testing.T) {
+	calls := 0
+	srv := masterServer(t, &calls)
+	cacheFile := filepath.Join(t.TempDir(), "instruments.json")
+	if err := os.WriteFile(cacheFile, []byte(`[{"trading_symbol":"CACHED","exchange":"BSE","instrument_key":"BSE_EQ|X"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewInstrumentStore(WithSourceURL(srv.URL), WithCacheFile(cacheFile, time.Hour))
+
+	if err := s.Load(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no download, got %d", calls)
+	}
+	if _, ok := s.Lookup("CACHED", "BSE"); !ok {
+		t.Error("Expected instrument from cache file")
+	}
+}
+
+func TestInstrumentStore_Load_StaleCacheFileDownloads(t *testing.T) {
+	calls := 0
+	srv := masterServer(t, &calls)
+	cacheFile := filepath.Join(t.TempDir(), "instruments.json")
+	if err := os.WriteFile(cacheFile, []byte(`[]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(cacheFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewInstrumentStore(WithSourceURL(srv.URL), WithCacheFile(cacheFile, 24*time.Hour))
+
+	if err := s.Load(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 download, got %d", calls)
+	}
+	if _, ok := s.Lookup("NEWCO", "NSE"); !ok {
+		t.Error("Expected downloaded instrument")
+	}
+}
+
+func TestInstrumentStore_Load_FallsBackToEmbedded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	s := NewInstrumentStore(WithSourceURL(srv.URL))
+
+	if err := s.Load(context.Background()); err == nil {
+		t.Error("Expected error, got nil")
+	}
+	if _, ok := s.Lookup("RELIANCE", "NSE"); !ok {
+		t.Error("Expected embedded instruments to stay in use")
+	}
+	if !s.UpdatedAt().IsZero() {
+		t.Error("Expected UpdatedAt to stay zero")
+	}
+}
+
+func TestNewUpstoxProvider_WithInstrumentStore(t *testing.T) {
+	store := &InstrumentStore{instruments: map[string]instrument{
+		"NEWCO:NSE": {InstrumentKey: "NSE_EQ|INE000000001", TradingSymbol: "NEWCO", Exchange: "NSE"},
+	}}
+
+	p := NewUpstoxProvider(WithInstrumentStore(store))
+
+	if p.instruments != store {
+		t.Error("Expected provider to use the given store")
+	}
+}
+
+func TestInstrumentStore_Lookup_InstrumentKey(t *testing.T) {
+	s := &InstrumentStore{instruments: map[string]instrument{}}
+
+	inst, ok := s.Lookup("NSE_EQ|INE101A01026", "NSE")
+	if !ok {
+		t.Fatal("Expected instrument key to resolve")
+	}
+	if inst.InstrumentKey != "NSE_EQ|INE101A01026" {
+		t.Errorf("Expected key NSE_EQ|INE101A01026, got %s", inst.InstrumentKey)
+	}
+}
+
+func TestInstrumentStore_LookupISIN(t *testing.T) {
+	s := &InstrumentStore{instruments: map[string]instrument{
+		"RELIANCE:NSE": {TradingSymbol: "RELIANCE", Exchange: "NSE", ISIN: "INE002A01018", InstrumentType: "EQ"},
+		"RELIANCE:BSE": {TradingSymbol: "RELIANCE", Exchange: "BSE", ISIN: "INE002A01018", InstrumentType: "A"},
+		"AAREL:NSE":    {TradingSymbol: "AAREL", Exchange: "NSE", ISIN: "INE002A01018", InstrumentType: "BE"},
+	}}
+
+	inst, ok := s.LookupISIN("ine002a01018", "NSE")
+	if !ok || inst.Symbol != "RELIANCE" || inst.Exchange != types.ExchangeNSE {
+		t.Errorf("Expected RELIANCE on NSE, got %+v, %v", inst, ok)
+	}
+
+	inst, ok = s.LookupISIN("INE002A01018", "BSE")
+	if !ok || inst.Exchange != types.ExchangeBSE {
+		t.Errorf("Expected RELIANCE on BSE, got %+v, %v", inst, ok)
+	}
+
+	if _, ok := s.LookupISIN("INE009A01021", "NSE"); ok {
+		t.Error("Expected unknown ISIN not to be found")
+	}
+}
+
+func TestInstrumentStore_Search(t *testing.T) {
+	s := &InstrumentStore{instruments: map[string]instrument{
+		"INFY:NSE":     {TradingSymbol: "INFY", Name: "INFOSYS LIMITED", Exchange: "NSE", ISIN: "INE009A01021"},
+		"INFY:BSE":     {TradingSymbol: "INFY", Name: "INFOSYS LIMITED", Exchange: "BSE", ISIN: "INE009A01021"},
+		"INFIBEAM:NSE": {TradingSymbol: "INFIBEAM", Name: "INFIBEAM AVENUES LTD", Exchange: "NSE", ISIN: "INE483S01020"},
+		"NAUKRI:NSE":   {TradingSymbol: "NAUKRI", Name: "INFO EDGE (INDIA) LTD", Exchange: "NSE", ISIN: "INE663F01024"},
+		"RELIANCE:NSE": {TradingSymbol: "RELIANCE", Name: "RELIANCE INDUSTRIES LTD", Exchange: "NSE", ISIN: "INE002A01018"},
+	}}
+
+	symbols := func(insts []types.Instrument) []string {
+		var out []string
+		for _, inst := range insts {
+			out = append(out, inst.Symbol+":"+string(inst.Exchange))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		exchange string
+		want     []string
+	}{
+		{"exact symbol first", "infy", "NSE", []string{"INFY:NSE"}},
+		{"prefix then name", "inf", "NSE", []string{"INFIBEAM:NSE", "INFY:NSE", "NAUKRI:NSE"}},
+		{"by name", "info edge", "", []string{"NAUKRI:NSE"}},
+		{"by isin", "ine002a01018", "", []string{"RELIANCE:NSE"}},
+		{"all exchanges", "INFY", "", []string{"INFY:BSE", "INFY:NSE"}},
+		{"empty query", " ", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := symbols(s.Search(tt.query, tt.exchange)); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInstrumentStore_Refresh_MaxSize(t *testing.T) {
+	calls := 0
+	srv := masterServer(t, &calls)
+
+	s := NewInstrumentStore(WithSourceURL(srv.URL), WithMaxInstrumentsSize(int64(len(masterJSON)-1)))
+
+	if err := s.Refresh(context.Background()); !errors.Is(err, provider.ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+	if _, ok := s.Lookup("NEWCO", "NSE"); ok {
+		t.Error("Expected the instruments held to stay in use")
+	}
+}
@@ -0,0 +1,148 @@
+package upstox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type upstoxQuoteResponse struct {
+	Status string                       `json:"status"`
+	Data   map[string]upstoxMarketQuote `json:"data"`
+}
+
+type upstoxMarketQuote struct {
+	LastPrice         float64 `json:"last_price"`
+	Volume            int64   `json:"volume"`
+	NetChange         float64 `json:"net_change"`
+	Timestamp         string  `json:"timestamp"`
+	TotalBuyQuantity  float64 `json:"total_buy_quantity"`
+	TotalSellQuantity float64 `json:"total_sell_quantity"`
+	OHLC              struct {
+		Open  float64 `json:"open"`
+		High  float64 `json:"high"`
+		Low   float64 `json:"low"`
+		Close float64 `json:"close"`
+	} `json:"ohlc"`
+	Depth struct {
+		Buy  []upstoxDepthLevel `json:"buy"`
+		Sell []upstoxDepthLevel `json:"sell"`
+	} `json:"depth"`
+}
+
+type upstoxDepthLevel struct {
+	Quantity int64   `json:"quantity"`
+	Price    float64 `json:"price"`
+	Orders   int     `json:"orders"`
+}
+
+// Quote returns the latest market quote of symbol. The market quote API
+// requires an access token.
+func (u *UpstoxProvider) Quote(ctx context.Context, symbol string, exchange types.Exchange) (types.Quote, error) {
+	q, err := u.marketQuote(ctx, symbol, exchange)
+	if err != nil {
+		return types.Quote{}, err
+	}
+
+	t, _ := time.Parse(time.RFC3339, q.Timestamp)
+	return types.Quote{
+		Symbol:        symbol,
+		Exchange:      exchange,
+		LastPrice:     u.round(q.LastPrice),
+		Open:          u.round(q.OHLC.Open),
+		High:          u.round(q.OHLC.High),
+		Low:           u.round(q.OHLC.Low),
+		PreviousClose: u.round(q.LastPrice - q.NetChange),
+		Volume:        q.Volume,
+		DateTime:      t.In(exchange.Location()),
+		Source:        u.Name(),
+	}, nil
+}
+
+// Depth returns the five best bids and asks of symbol from the same market
+// quote API as Quote, which requires an access token. Empty levels, which
+// Upstox reports as zeros, are left out.
+func (u *UpstoxProvider) Depth(ctx context.Context, symbol string, exchange types.Exchange) (types.Depth, error) {
+	q, err := u.marketQuote(ctx, symbol, exchange)
+	if err != nil {
+		return types.Depth{}, err
+	}
+
+	t, _ := time.Parse(time.RFC3339, q.Timestamp)
+	return types.Depth{
+		Symbol:           symbol,
+		Exchange:         exchange,
+		Bids:             u.depthLevels(q.Depth.Buy),
+		Asks:             u.depthLevels(q.Depth.Sell),
+		TotalBidQuantity: int64(q.TotalBuyQuantity),
+		TotalAskQuantity: int64(q.TotalSellQuantity),
+		DateTime:         t.In(exchange.Location()),
+		Source:           u.Name(),
+	}, nil
+}
+
+func (u *UpstoxProvider) depthLevels(levels []upstoxDepthLevel) []types.DepthLevel {
+	out := make([]types.DepthLevel, 0, len(levels))
+	for _, l := range levels {
+		if l.Quantity == 0 {
+			continue
+		}
+		out = append(out, types.DepthLevel{Price: u.round(l.Price), Quantity: l.Quantity, Orders: l.Orders})
+	}
+	return out
+}
+
+// marketQuote requests the full market quote of symbol.
+func (u *UpstoxProvider) marketQuote(ctx context.Context, symbol string, exchange types.Exchange) (upstoxMarketQuote, error) {
+	if u.accessToken == "" {
+		return upstoxMarketQuote{}, fmt.Errorf("%w: market quotes require an access token", provider.ErrProviderUnavailable)
+	}
+
+	inst, ok := u.instruments.Lookup(symbol, string(exchange))
+	if !ok {
+		return upstoxMarketQuote{}, fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, symbol, exchange)
+	}
+
+	endpoint := "https://api.upstox.com/v2/market-quote/quotes?instrument_key=" + url.QueryEscape(inst.InstrumentKey)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return upstoxMarketQuote{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+u.accessToken)
+
+	res, err := u.client.Do(ctx, req)
+	if err != nil {
+		return upstoxMarketQuote{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return upstoxMarketQuote{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return upstoxMarketQuote{}, &provider.ProviderError{Provider: u.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	var resp upstoxQuoteResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return upstoxMarketQuote{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// The data is keyed by segment and trading symbol, e.g. "NSE_EQ:RELIANCE",
+	// and holds the single instrument requested.
+	for _, q := range resp.Data {
+		return q, nil
+	}
+
+	return upstoxMarketQuote{}, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+}
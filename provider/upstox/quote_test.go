@@ -0,0 +1,98 @@
+package upstox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestUpstoxProvider_Quote(t *testing.T) {
+	body := `{"status":"success","data":{"NSE_EQ:INFY":{"last_price":1500.5,"volume":1000,"net_change":10.5,` +
+		`"timestamp":"2024-01-02T15:29:59+05:30","ohlc":{"open":1490,"high":1510,"low":1485,"close":1500.5}}}}`
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}})
+	p := &UpstoxProvider{
+		client:      mockClient,
+		accessToken: "token",
+		instruments: &InstrumentStore{instruments: map[string]instrument{
+			"INFY:NSE": {InstrumentKey: "NSE_EQ|INE009A01021", TradingSymbol: "INFY", Exchange: "NSE"},
+		}},
+	}
+
+	q, err := p.Quote(context.Background(), "INFY", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	req := mockClient.requests[0]
+	if got := req.URL.Query().Get("instrument_key"); got != "NSE_EQ|INE009A01021" {
+		t.Errorf("Expected instrument key in query, got %q", got)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Expected bearer token, got %q", got)
+	}
+	if q.LastPrice != 1500.5 || q.Open != 1490 || q.High != 1510 || q.Low != 1485 || q.PreviousClose != 1490 {
+		t.Errorf("Unexpected prices: %+v", q)
+	}
+	if q.Volume != 1000 || q.Source != "upstox" {
+		t.Errorf("Unexpected quote metadata: %+v", q)
+	}
+}
+
+func TestUpstoxProvider_Depth(t *testing.T) {
+	body := `{"status":"success","data":{"NSE_EQ:INFY":{"last_price":1500.5,"timestamp":"2024-01-02T15:29:59+05:30",` +
+		`"total_buy_quantity":52000,"total_sell_quantity":61000,"depth":{` +
+		`"buy":[{"quantity":100,"price":1500.4,"orders":3},{"quantity":250,"price":1500.3,"orders":5},{"quantity":0,"price":0,"orders":0}],` +
+		`"sell":[{"quantity":75,"price":1500.6,"orders":2}]}}}}`
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}})
+	p := &UpstoxProvider{
+		client:      mockClient,
+		accessToken: "token",
+		instruments: &InstrumentStore{instruments: map[string]instrument{
+			"INFY:NSE": {InstrumentKey: "NSE_EQ|INE009A01021", TradingSymbol: "INFY", Exchange: "NSE"},
+		}},
+	}
+
+	d, err := p.Depth(context.Background(), "INFY", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(d.Bids) != 2 || len(d.Asks) != 1 {
+		t.Fatalf("Expected 2 bids and 1 ask, got %+v", d)
+	}
+	if d.Bids[1] != (types.DepthLevel{Price: 1500.3, Quantity: 250, Orders: 5}) {
+		t.Errorf("Unexpected second bid: %+v", d.Bids[1])
+	}
+	if spread := d.Spread(); spread < 0.19 || spread > 0.21 {
+		t.Errorf("Expected a spread of 0.2, got %v", spread)
+	}
+	if d.TotalBidQuantity != 52000 || d.TotalAskQuantity != 61000 || d.Source != "upstox" {
+		t.Errorf("Unexpected depth metadata: %+v", d)
+	}
+	if d.DateTime.Hour() != 15 || d.DateTime.Location().String() != "Asia/Kolkata" {
+		t.Errorf("Expected 15:29 IST, got %v", d.DateTime)
+	}
+}
+
+func TestUpstoxProvider_Quote_RequiresAccessToken(t *testing.T) {
+	p := &UpstoxProvider{}
+
+	_, err := p.Quote(context.Background(), "INFY", types.ExchangeNSE)
+	if !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,385 @@
+package upstox
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/internal/retry"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//go:embed data/complete.json
+var instrumentsJSON []byte
+
+type instrument struct {
+	Segment          string  `json:"segment"`
+	Name             string  `json:"name"`
+	Exchange         string  `json:"exchange"`
+	ISIN             string  `json:"isin"`
+	InstrumentType   string  `json:"instrument_type"`
+	InstrumentKey    string  `json:"instrument_key"`
+	LotSize          int     `json:"lot_size"`
+	FreezeQuantity   float64 `json:"freeze_quantity"`
+	ExchangeToken    string  `json:"exchange_token"`
+	TickSize         float64 `json:"tick_size"`
+	TradingSymbol    string  `json:"trading_symbol"`
+	ShortName        string  `json:"short_name"`
+	QtyMultiplier    float64 `json:"qty_multiplier"`
+	IntradayMargin   float64 `json:"intraday_margin"`
+	IntradayLeverage float64 `json:"intraday_leverage"`
+}
+
+type UpstoxProvider struct {
+	client      httpclient.Doer
+	instruments *InstrumentStore
+	accessToken string
+	precision   provider.Precision
+}
+
+type config struct {
+	httpClient       *http.Client
+	transport        provider.TransportConfig
+	retryHooks       provider.RetryHooks
+	conditionalCache int64
+	maxResponseSize  int64
+	rateLimits       provider.RateLimits
+	retryBudget      provider.RetryBudget
+	accessToken      string
+	instruments      *InstrumentStore
+	tracerProvider   trace.TracerProvider
+	precision        provider.Precision
+}
+
+type Option func(*config)
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithRateLimits overrides the default limits of 50 requests per second, 500
+// per minute and 4000 per hour.
+func WithRateLimits(limits provider.RateLimits) Option {
+	return func(c *config) {
+		c.rateLimits = limits
+	}
+}
+
+// WithRetryBudget overrides the default budget of 100 retries per minute,
+// shared by every Upstox provider in the process with the same budget.
+func WithRetryBudget(budget provider.RetryBudget) Option {
+	return func(c *config) {
+		c.retryBudget = budget
+	}
+}
+
+// WithAccessToken authenticates requests with an Upstox access token, which
+// enables the intraday endpoint for the current trading day.
+func WithAccessToken(token string) Option {
+	return func(c *config) {
+		c.accessToken = token
+	}
+}
+
+// WithInstrumentStore resolves symbols through store, which callers can
+// refresh from the latest instrument master. By default the embedded copy is
+// used.
+func WithInstrumentStore(store *InstrumentStore) Option {
+	return func(c *config) {
+		c.instruments = store
+	}
+}
+
+// WithTracerProvider traces every HTTP request attempt as a span from tp.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithTransport tunes the connections of the default HTTP client, or of one
+// set with WithHTTPClient that has no Transport of its own.
+func WithTransport(t provider.TransportConfig) Option {
+	return func(c *config) {
+		c.transport = t
+	}
+}
+
+// WithRetryHooks calls hooks on every retry of a request and when one is
+// given up on.
+func WithRetryHooks(hooks provider.RetryHooks) Option {
+	return func(c *config) {
+		c.retryHooks = hooks
+	}
+}
+
+// WithConditionalCache keeps up to maxBytes of responses carrying an ETag or
+// Last-Modified header, and asks for them again conditionally; a 304 Not
+// Modified is then answered with the kept response.
+func WithConditionalCache(maxBytes int64) Option {
+	return func(c *config) {
+		c.conditionalCache = maxBytes
+	}
+}
+
+// WithMaxResponseSize caps the size of a response, after decompression, at n
+// bytes instead of provider.DefaultMaxResponseSize. Larger responses fail
+// with an error matching provider.ErrResponseTooLarge. Zero removes the
+// limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *config) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithPrecision sets how many decimal places prices are rounded to. The
+// default is provider.DefaultPrecision.
+func WithPrecision(p provider.Precision) Option {
+	return func(c *config) {
+		c.precision = p
+	}
+}
+
+func NewUpstoxProvider(opts ...Option) *UpstoxProvider {
+	cfg := config{
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		maxResponseSize: provider.DefaultMaxResponseSize,
+		rateLimits: provider.RateLimits{
+			RequestsPerSecond: 50,
+			RequestsPerMinute: 500,
+			RequestsPerHour:   4000,
+		},
+		retryBudget: provider.RetryBudget{Retries: 100, Window: time.Minute},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	clientConfig := httpclient.ClientConfig{
+		Name:                  "upstox",
+		HttpClient:            cfg.httpClient,
+		Transport:             httpclient.TransportConfig(cfg.transport),
+		ConditionalCacheBytes: cfg.conditionalCache,
+		MaxResponseBytes:      cfg.maxResponseSize,
+		RateLimitConfig: httpclient.RateLimitConfig{
+			RequestsPerSecond: cfg.rateLimits.RequestsPerSecond,
+			RequestsPerMinute: cfg.rateLimits.RequestsPerMinute,
+			RequestsPerHour:   cfg.rateLimits.RequestsPerHour,
+			MaxQueue:          cfg.rateLimits.MaxQueue,
+			Shared:            "upstox",
+		},
+		RetryConfig: httpclient.RetryConfig{
+			MaxRetries:    6,
+			BaseDelay:     100 * time.Millisecond,
+			MaxDelay:      5 * time.Second,
+			RetryOnStatus: []uint{429, 500, 502, 503},
+			Jitter:        retry.EqualJitter,
+			Budget:        cfg.retryBudget.Retries,
+			BudgetWindow:  cfg.retryBudget.Window,
+			Hooks:         cfg.retryHooks,
+		},
+		TracerProvider: cfg.tracerProvider,
+	}
+
+	instruments := cfg.instruments
+	if instruments == nil {
+		instruments = NewInstrumentStore()
+	}
+
+	return &UpstoxProvider{
+		client:      httpclient.NewClient(clientConfig),
+		instruments: instruments,
+		accessToken: cfg.accessToken,
+		precision:   cfg.precision,
+	}
+}
+
+func (u *UpstoxProvider) Name() string {
+	return "upstox"
+}
+
+// Freshness reports historical data unless an access token is set, in which
+// case the current day is served from the intraday endpoint.
+func (u *UpstoxProvider) Freshness() types.DataFreshness {
+	if u.accessToken != "" {
+		return types.FreshnessRealtime
+	}
+	return types.FreshnessHistorical
+}
+
+// MaxRange reports the largest range the v3 historical candle API accepts per
+// request for interval.
+func (u *UpstoxProvider) MaxRange(interval types.Interval) time.Duration {
+	const day = 24 * time.Hour
+
+	switch interval {
+	case types.Interval1m, types.Interval5m, types.Interval15m:
+		return 30 * day
+	case types.Interval30m, types.Interval1h:
+		return 90 * day
+	case types.Interval1d:
+		return 3650 * day
+	default:
+		return 0
+	}
+}
+
+// RemainingRateLimit reports the requests left in the current second, minute
+// and hour of the provider's rate limit.
+func (u *UpstoxProvider) RemainingRateLimit() (provider.RateLimits, bool) {
+	c, ok := u.client.(*httpclient.Client)
+	if !ok {
+		return provider.RateLimits{}, false
+	}
+
+	perSecond, perMinute, perHour := c.Remaining()
+	return provider.RateLimits{
+		RequestsPerSecond: perSecond,
+		RequestsPerMinute: perMinute,
+		RequestsPerHour:   perHour,
+	}, true
+}
+
+func (u *UpstoxProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	inst, ok := u.instruments.Lookup(symbol, string(exchange))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, symbol, exchange)
+	}
+
+	unit, unitInterval, err := u.intervalToUnitInterval(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval: %w", err)
+	}
+
+	loc := exchange.Location()
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	if u.accessToken == "" || (!to.IsZero() && to.Before(today)) {
+		return u.fetch(ctx, u.historicalURL(inst, unit, unitInterval, from, to), symbol, exchange, interval, types.FreshnessHistorical)
+	}
+
+	// The historical endpoint stops at the previous day, so today's candles
+	// come from the intraday endpoint and are placed ahead of the older ones,
+	// matching Upstox's newest-first order.
+	url := fmt.Sprintf(
+		"https://api.upstox.com/v3/historical-candle/intraday/%s/%s/%s",
+		inst.InstrumentKey, unit, unitInterval,
+	)
+	ohlcvs, err := u.fetch(ctx, url, symbol, exchange, interval, types.FreshnessRealtime)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.IsZero() || !from.Before(today) {
+		return ohlcvs, nil
+	}
+
+	past, err := u.fetch(ctx, u.historicalURL(inst, unit, unitInterval, from, today.AddDate(0, 0, -1)), symbol, exchange, interval, types.FreshnessHistorical)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ohlcvs, past...), nil
+}
+
+func (u *UpstoxProvider) historicalURL(inst instrument, unit, unitInterval string, from, to time.Time) string {
+	toDate := to.Format("2006-01-02")
+	if from.IsZero() {
+		return fmt.Sprintf(
+			"https://api.upstox.com/v3/historical-candle/%s/%s/%s/%s",
+			inst.InstrumentKey, unit, unitInterval, toDate,
+		)
+	}
+
+	fromDate := from.Format("2006-01-02")
+	return fmt.Sprintf(
+		"https://api.upstox.com/v3/historical-candle/%s/%s/%s/%s/%s",
+		inst.InstrumentKey, unit, unitInterval, toDate, fromDate,
+	)
+}
+
+// fetch requests url and labels the candles with freshness.
+func (u *UpstoxProvider) fetch(ctx context.Context, url, symbol string, exchange types.Exchange, interval types.Interval, freshness types.DataFreshness) ([]types.OHLCV, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if u.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+u.accessToken)
+	}
+	res, err := u.client.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &provider.ProviderError{Provider: u.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	ohlcvs, err := decodeCandles(body, symbol, exchange)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ohlcvs {
+		ohlcvs[i].Interval = interval
+		ohlcvs[i].Source = u.Name()
+		ohlcvs[i].Freshness = freshness
+	}
+
+	return u.normalizeOHLCVs(ohlcvs), nil
+}
+
+// SupportsInterval reports whether Upstox serves candles of interval.
+func (u *UpstoxProvider) SupportsInterval(interval types.Interval) bool {
+	_, _, err := u.intervalToUnitInterval(interval)
+	return err == nil
+}
+
+func (u *UpstoxProvider) intervalToUnitInterval(i types.Interval) (unit string, interval string, err error) {
+	switch i {
+	case types.Interval1m:
+		return "minutes", "1", nil
+	case types.Interval5m:
+		return "minutes", "5", nil
+	case types.Interval15m:
+		return "minutes", "15", nil
+	case types.Interval30m:
+		return "minutes", "30", nil
+	case types.Interval1h:
+		return "hours", "1", nil
+	case types.Interval1d:
+		return "days", "1", nil
+	case types.Interval1wk:
+		return "weeks", "1", nil
+	case types.Interval1mo:
+		return "months", "1", nil
+	default:
+		return "", "", fmt.Errorf("%w: %s", provider.ErrUnknownInterval, i)
+	}
+}
+
+func (u *UpstoxProvider) normalizeOHLCVs(ohlcvs []types.OHLCV) []types.OHLCV {
+	return ohlcv.Round(u.precision).Transform(ohlcvs)
+}
+
+func (u *UpstoxProvider) round(v float64) float64 {
+	return u.precision.Round(v)
+}
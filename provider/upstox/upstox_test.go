@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/shahid-2020/gohlcv/provider"
 	"github.com/shahid-2020/gohlcv/types"
 )
 
@@ -79,11 +81,11 @@ func TestNewUpstoxProvider(t *testing.T) {
 		if provider.Name() != "upstox" {
 			t.Errorf("Expected name 'upstox', got '%s'", provider.Name())
 		}
-		if len(provider.instrumentMap) == 0 {
+		if len(provider.instruments.instruments) == 0 {
 			t.Error("Expected instrument map to be populated")
 		}
 
-		if provider.instrumentMap["RELIANCE:NSE"].TradingSymbol != "RELIANCE" {
+		if inst, _ := provider.instruments.Lookup("RELIANCE", "NSE"); inst.TradingSymbol != "RELIANCE" {
 			t.Error("Expected RELIANCE:NSE to be in instrument map")
 		}
 	})
@@ -102,6 +104,102 @@ func TestNewUpstoxProvider(t *testing.T) {
 	})
 }
 
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewUpstoxProvider_WithHTTPClient(t *testing.T) {
+	called := false
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return createMockResponse([][]any{{"2025-09-25T15:25:00+05:30", 1374.5, 1375, 1373.5, 1374.8, 283572}}, 200), nil
+	})}
+
+	p := NewUpstoxProvider(WithHTTPClient(client))
+
+	from := time.Date(2025, 9, 25, 0, 0, 0, 0, time.UTC)
+	if _, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval5m, from, from); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !called {
+		t.Error("Expected the custom HTTP client to be used")
+	}
+}
+
+func TestUpstoxProvider_Provide_IntradayWithAccessToken(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	t.Run("TodayOnly", func(t *testing.T) {
+		mockClient := NewMockHTTPClient([]*http.Response{
+			createMockResponse([][]any{{today.Add(10 * time.Hour).Format(time.RFC3339), 100.0, 101.0, 99.0, 100.5, 1000.0}}, 200),
+		})
+		p := NewUpstoxProvider(WithAccessToken("token"))
+		p.client = mockClient
+
+		ohlcvs, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1m, today, time.Time{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if mockClient.calledCount != 1 {
+			t.Fatalf("Expected 1 request, got %d", mockClient.calledCount)
+		}
+
+		req := mockClient.requests[0]
+		if !strings.Contains(req.URL.Path, "/historical-candle/intraday/") {
+			t.Errorf("Expected intraday endpoint, got %s", req.URL.Path)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		if len(ohlcvs) != 1 || ohlcvs[0].Freshness != types.FreshnessRealtime {
+			t.Errorf("Expected 1 realtime candle, got %+v", ohlcvs)
+		}
+	})
+
+	t.Run("SpansHistory", func(t *testing.T) {
+		mockClient := NewMockHTTPClient([]*http.Response{
+			createMockResponse([][]any{{today.Add(10 * time.Hour).Format(time.RFC3339), 100.0, 101.0, 99.0, 100.5, 1000.0}}, 200),
+			createMockResponse([][]any{{today.Add(-14 * time.Hour).Format(time.RFC3339), 90.0, 91.0, 89.0, 90.5, 1000.0}}, 200),
+		})
+		p := NewUpstoxProvider(WithAccessToken("token"))
+		p.client = mockClient
+
+		ohlcvs, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1m, today.AddDate(0, 0, -1), time.Time{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if mockClient.calledCount != 2 {
+			t.Fatalf("Expected 2 requests, got %d", mockClient.calledCount)
+		}
+		if strings.Contains(mockClient.requests[1].URL.Path, "/intraday/") {
+			t.Errorf("Expected historical endpoint for past days, got %s", mockClient.requests[1].URL.Path)
+		}
+		if len(ohlcvs) != 2 || ohlcvs[1].Freshness != types.FreshnessHistorical {
+			t.Errorf("Expected today's candle then a historical one, got %+v", ohlcvs)
+		}
+	})
+
+	t.Run("WithoutToken", func(t *testing.T) {
+		mockClient := NewMockHTTPClient([]*http.Response{createMockResponse(nil, 200)})
+		p := NewUpstoxProvider()
+		p.client = mockClient
+
+		if _, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1m, today, time.Time{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if strings.Contains(mockClient.requests[0].URL.Path, "/intraday/") {
+			t.Error("Expected historical endpoint without an access token")
+		}
+		if p.Freshness() != types.FreshnessHistorical {
+			t.Errorf("Expected historical freshness, got %s", p.Freshness())
+		}
+	})
+}
+
 func TestUpstoxProvider_Name(t *testing.T) {
 	provider := &UpstoxProvider{}
 	if name := provider.Name(); name != "upstox" {
@@ -177,13 +275,13 @@ func TestUpstoxProvider_Provide_WithoutFromDate(t *testing.T) {
 
 	provider := NewUpstoxProvider()
 	provider.client = mockClient
-	provider.instrumentMap = map[string]instrument{
+	provider.instruments = &InstrumentStore{instruments: map[string]instrument{
 		"INFY:NSE": {
 			InstrumentKey: "NSE_EQ|INE009A01021",
 			TradingSymbol: "INFY",
 			Exchange:      "NSE",
 		},
-	}
+	}}
 
 	ctx := context.Background()
 	to := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
@@ -231,7 +329,7 @@ func TestUpstoxProvider_Provide_BSE_Exchange(t *testing.T) {
 
 func TestUpstoxProvider_Provide_SymbolNotFound(t *testing.T) {
 	provider := NewUpstoxProvider()
-	provider.instrumentMap = map[string]instrument{}
+	provider.instruments = &InstrumentStore{instruments: map[string]instrument{}}
 
 	ctx := context.Background()
 	from := time.Now().Add(-24 * time.Hour)
@@ -485,7 +583,7 @@ func TestUpstoxProvider_Round2(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(fmt.Sprintf("round2(%f)", tc.input), func(t *testing.T) {
-			result := provider.round2(tc.input)
+			result := provider.round(tc.input)
 			if result != tc.expected {
 				t.Errorf("round2(%f) = %f, expected %f", tc.input, result, tc.expected)
 			}
@@ -524,3 +622,47 @@ func TestUpstoxProvider_AllIntervals(t *testing.T) {
 		})
 	}
 }
+
+func TestUpstoxProvider_MaxRange(t *testing.T) {
+	provider := &UpstoxProvider{}
+
+	if provider.MaxRange(types.Interval1m) != 30*24*time.Hour {
+		t.Errorf("Unexpected 1m max range %v", provider.MaxRange(types.Interval1m))
+	}
+	if provider.MaxRange(types.Interval1h) != 90*24*time.Hour {
+		t.Errorf("Unexpected 1h max range %v", provider.MaxRange(types.Interval1h))
+	}
+	if provider.MaxRange(types.Interval1wk) != 0 {
+		t.Error("Expected weekly data to be uncapped")
+	}
+}
+
+func TestUpstoxProvider_Provide_TypedErrors(t *testing.T) {
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("SymbolNotFound", func(t *testing.T) {
+		p := &UpstoxProvider{instruments: &InstrumentStore{instruments: map[string]instrument{}}}
+
+		_, err := p.Provide(context.Background(), "UNKNOWN", types.ExchangeNSE, types.Interval1d, from, to)
+
+		if !errors.Is(err, provider.ErrSymbolNotFound) {
+			t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		p := &UpstoxProvider{
+			client: NewMockHTTPClient([]*http.Response{createErrorResponse(503, "maintenance")}),
+			instruments: &InstrumentStore{instruments: map[string]instrument{
+				"INFY:NSE": {InstrumentKey: "NSE_EQ|INE009A01021", TradingSymbol: "INFY", Exchange: "NSE"},
+			}},
+		}
+
+		_, err := p.Provide(context.Background(), "INFY", types.ExchangeNSE, types.Interval1d, from, to)
+
+		if !errors.Is(err, provider.ErrProviderUnavailable) {
+			t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package yahoo
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type yahooCalendarEvents struct {
+	CalendarEvents struct {
+		Earnings struct {
+			EarningsDate    []yahooValue `json:"earningsDate"`
+			EarningsAverage yahooValue   `json:"earningsAverage"`
+			RevenueAverage  yahooValue   `json:"revenueAverage"`
+		} `json:"earnings"`
+		ExDividendDate yahooValue `json:"exDividendDate"`
+		DividendDate   yahooValue `json:"dividendDate"`
+	} `json:"calendarEvents"`
+}
+
+// CalendarEvents returns the next earnings announcement and the latest
+// ex-dividend and dividend payment dates Yahoo reports for symbol, oldest
+// first. Earnings Yahoo hasn't confirmed come with the window they are
+// expected in. Dates are midnight in the exchange's location for dividends,
+// and the announcement time Yahoo gives for earnings.
+func (y *YahooProvider) CalendarEvents(ctx context.Context, symbol string, exchange types.Exchange) ([]types.CalendarEvent, error) {
+	var data yahooCalendarEvents
+	if err := y.quoteSummary(ctx, symbol, exchange, []string{"calendarEvents"}, &data); err != nil {
+		return nil, err
+	}
+
+	loc := exchange.Location()
+	event := func(kind types.EventKind, v yahooValue) types.CalendarEvent {
+		return types.CalendarEvent{
+			Symbol:   symbol,
+			Exchange: exchange,
+			Kind:     kind,
+			Date:     time.Unix(int64(v.Raw), 0).In(loc),
+			Source:   y.Name(),
+		}
+	}
+
+	var events []types.CalendarEvent
+	calendar := data.CalendarEvents
+	if dates := calendar.Earnings.EarningsDate; len(dates) > 0 {
+		e := event(types.EventEarnings, dates[0])
+		if len(dates) > 1 && dates[1].Raw != dates[0].Raw {
+			e.EndDate = time.Unix(int64(dates[1].Raw), 0).In(loc)
+		}
+		e.EPSEstimate = calendar.Earnings.EarningsAverage.Raw
+		e.RevenueEstimate = calendar.Earnings.RevenueAverage.Raw
+		events = append(events, e)
+	}
+	if calendar.ExDividendDate.Raw != 0 {
+		events = append(events, dividendEvent(event(types.EventExDividend, calendar.ExDividendDate)))
+	}
+	if calendar.DividendDate.Raw != 0 {
+		events = append(events, dividendEvent(event(types.EventDividendPayment, calendar.DividendDate)))
+	}
+
+	slices.SortStableFunc(events, func(a, b types.CalendarEvent) int {
+		return a.Date.Compare(b.Date)
+	})
+	return events, nil
+}
+
+// dividendEvent moves the date of e, which Yahoo gives as midnight UTC, to
+// midnight of that day in the exchange's location.
+func dividendEvent(e types.CalendarEvent) types.CalendarEvent {
+	d := e.Date.UTC()
+	e.Date = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, e.Date.Location())
+	return e
+}
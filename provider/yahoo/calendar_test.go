@@ -0,0 +1,53 @@
+package yahoo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestYahooProvider_CalendarEvents(t *testing.T) {
+	body := `{"quoteSummary":{"result":[{"calendarEvents":{` +
+		`"earnings":{"earningsDate":[{"raw":1736938800},{"raw":1737370800}],"earningsAverage":{"raw":11.2},"revenueAverage":{"raw":2390000000000}},` +
+		`"exDividendDate":{"raw":1723766400},"dividendDate":{"raw":1725494400}}}],"error":null}}`
+	mockClient := NewMockHTTPClient(append(sessionResponses("abc"), response(200, body)))
+	p := &YahooProvider{client: mockClient}
+
+	events, err := p.CalendarEvents(context.Background(), "RELIANCE", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := mockClient.requests[2].URL.Query().Get("modules"); got != "calendarEvents" {
+		t.Errorf("Expected the calendarEvents module, got %q", got)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+
+	loc := types.ExchangeNSE.Location()
+	exDiv, payment, earnings := events[0], events[1], events[2]
+	if exDiv.Kind != types.EventExDividend || !exDiv.Date.Equal(time.Date(2024, 8, 16, 0, 0, 0, 0, loc)) {
+		t.Errorf("Expected the ex-dividend date 2024-08-16, got %+v", exDiv)
+	}
+	if payment.Kind != types.EventDividendPayment || !payment.Date.Equal(time.Date(2024, 9, 5, 0, 0, 0, 0, loc)) {
+		t.Errorf("Expected the payment date 2024-09-05, got %+v", payment)
+	}
+	if earnings.Kind != types.EventEarnings || !earnings.Date.Equal(time.Unix(1736938800, 0)) || !earnings.EndDate.Equal(time.Unix(1737370800, 0)) {
+		t.Errorf("Expected an earnings window, got %+v", earnings)
+	}
+	if earnings.EPSEstimate != 11.2 || earnings.RevenueEstimate != 2390000000000 || earnings.Source != "yahoo" {
+		t.Errorf("Unexpected earnings estimates: %+v", earnings)
+	}
+}
+
+func TestYahooProvider_CalendarEvents_None(t *testing.T) {
+	body := `{"quoteSummary":{"result":[{"calendarEvents":{"earnings":{"earningsDate":[]}}}],"error":null}}`
+	p := &YahooProvider{client: NewMockHTTPClient(append(sessionResponses("abc"), response(200, body)))}
+
+	events, err := p.CalendarEvents(context.Background(), "RELIANCE", types.ExchangeNSE)
+	if err != nil || len(events) != 0 {
+		t.Errorf("Expected no events, got %v, %v", events, err)
+	}
+}
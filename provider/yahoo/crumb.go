@@ -0,0 +1,96 @@
+package yahoo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/shahid-2020/gohlcv/provider"
+)
+
+const (
+	// cookieURL sets the session cookie Yahoo's crumb is tied to. It answers
+	// 404, with the cookie.
+	cookieURL = "https://fc.yahoo.com"
+	crumbURL  = "https://query2.finance.yahoo.com/v1/test/getcrumb"
+)
+
+// session holds the cookie and crumb Yahoo's quoteSummary API requires, unlike
+// the chart API. They are fetched on first use and kept until Yahoo rejects
+// them.
+type session struct {
+	mu      sync.Mutex
+	crumb   string
+	cookies []*http.Cookie
+}
+
+// session returns the crumb and cookies to send with quoteSummary requests.
+func (y *YahooProvider) session(ctx context.Context) (string, []*http.Cookie, error) {
+	y.auth.mu.Lock()
+	defer y.auth.mu.Unlock()
+
+	if y.auth.crumb != "" {
+		return y.auth.crumb, y.auth.cookies, nil
+	}
+
+	agent := uuid.NewString()
+	req, err := http.NewRequestWithContext(ctx, "GET", cookieURL, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", agent)
+
+	res, err := y.client.Do(ctx, req)
+	if err != nil {
+		return "", nil, fmt.Errorf("cookie request failed: %w", err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	cookies := res.Cookies()
+
+	req, err = http.NewRequestWithContext(ctx, "GET", crumbURL, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", agent)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	res, err = y.client.Do(ctx, req)
+	if err != nil {
+		return "", nil, fmt.Errorf("crumb request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read crumb: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", nil, &provider.ProviderError{Provider: y.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	crumb := strings.TrimSpace(string(body))
+	if crumb == "" {
+		return "", nil, fmt.Errorf("%w: empty crumb", provider.ErrProviderUnavailable)
+	}
+
+	y.auth.crumb, y.auth.cookies = crumb, cookies
+	return crumb, cookies, nil
+}
+
+// resetSession forgets crumb, after Yahoo rejected it, unless it was already
+// replaced.
+func (y *YahooProvider) resetSession(crumb string) {
+	y.auth.mu.Lock()
+	defer y.auth.mu.Unlock()
+
+	if y.auth.crumb == crumb {
+		y.auth.crumb, y.auth.cookies = "", nil
+	}
+}
@@ -0,0 +1,398 @@
+package yahoo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// chartSeries holds the series of the first result of a chart response.
+// Prices are NaN and volumes -1 where Yahoo reports null, and the value
+// series may be shorter than timestamps; priceAt and volumeAt read them
+// safely.
+type chartSeries struct {
+	timestamps []int64
+	opens      []float64
+	highs      []float64
+	lows       []float64
+	closes     []float64
+	volumes    []int64
+	adjClose   []float64
+}
+
+// decodeChart decodes a chart response body, with its adjusted close series
+// if adjusted is set. It fails with provider.ErrNoData if the response has no
+// result or no quote series.
+func decodeChart(body []byte, adjusted bool) (chartSeries, error) {
+	var s chartSeries
+	err := s.decode(body, adjusted)
+	return s, err
+}
+
+// decode is decodeChart into s, reusing the capacity of its series. It reads
+// body a value at a time, skipping everything but the series it keeps, so
+// that decoding into a reused chartSeries doesn't allocate.
+func (s *chartSeries) decode(body []byte, adjusted bool) error {
+	s.timestamps = s.timestamps[:0]
+	s.opens = s.opens[:0]
+	s.highs = s.highs[:0]
+	s.lows = s.lows[:0]
+	s.closes = s.closes[:0]
+	s.volumes = s.volumes[:0]
+	s.adjClose = s.adjClose[:0]
+
+	sc := &chartScanner{data: body}
+	var results, quotes int
+	err := sc.object(func(key []byte) error {
+		if string(key) != "chart" {
+			return sc.skip()
+		}
+		return sc.object(func(key []byte) error {
+			if string(key) != "result" {
+				return sc.skip()
+			}
+			results = 0
+			return sc.array(func(i int) error {
+				results++
+				if i > 0 {
+					return sc.skip()
+				}
+				return s.decodeResult(sc, adjusted, &quotes)
+			})
+		})
+	})
+	if err == nil && sc.peek() != 0 {
+		err = sc.errorf("unexpected data after the response")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if results == 0 || quotes == 0 {
+		return provider.ErrNoData
+	}
+	return nil
+}
+
+func (s *chartSeries) decodeResult(sc *chartScanner, adjusted bool, quotes *int) error {
+	return sc.object(func(key []byte) error {
+		switch string(key) {
+		case "timestamp":
+			var err error
+			s.timestamps, err = sc.ints(s.timestamps, 0)
+			return err
+		case "indicators":
+			return sc.object(func(key []byte) error {
+				switch {
+				case string(key) == "quote":
+					*quotes = 0
+					return sc.array(func(i int) error {
+						*quotes++
+						if i > 0 {
+							return sc.skip()
+						}
+						return s.decodeQuote(sc)
+					})
+				case string(key) == "adjclose" && adjusted:
+					return sc.array(func(i int) error {
+						if i > 0 {
+							return sc.skip()
+						}
+						return sc.object(func(key []byte) error {
+							if string(key) != "adjclose" {
+								return sc.skip()
+							}
+							var err error
+							s.adjClose, err = sc.floats(s.adjClose)
+							return err
+						})
+					})
+				default:
+					return sc.skip()
+				}
+			})
+		default:
+			return sc.skip()
+		}
+	})
+}
+
+func (s *chartSeries) decodeQuote(sc *chartScanner) error {
+	return sc.object(func(key []byte) error {
+		var err error
+		switch string(key) {
+		case "open":
+			s.opens, err = sc.floats(s.opens)
+		case "high":
+			s.highs, err = sc.floats(s.highs)
+		case "low":
+			s.lows, err = sc.floats(s.lows)
+		case "close":
+			s.closes, err = sc.floats(s.closes)
+		case "volume":
+			s.volumes, err = sc.ints(s.volumes, -1)
+		default:
+			err = sc.skip()
+		}
+		return err
+	})
+}
+
+// candle returns the candle of the i-th timestamp. A candle missing a value
+// is marked incomplete, with the value zero.
+func (s *chartSeries) candle(i int, symbol string, exchange types.Exchange, source string, freshness types.DataFreshness) types.OHLCV {
+	open, okOpen := priceAt(s.opens, i)
+	high, okHigh := priceAt(s.highs, i)
+	low, okLow := priceAt(s.lows, i)
+	closePrice, okClose := priceAt(s.closes, i)
+	volume, okVolume := volumeAt(s.volumes, i)
+
+	return types.OHLCV{
+		Symbol:     symbol,
+		Exchange:   exchange,
+		Open:       open,
+		High:       high,
+		Low:        low,
+		Close:      closePrice,
+		Volume:     volume,
+		DateTime:   time.Unix(s.timestamps[i], 0).In(exchange.Location()),
+		Source:     source,
+		Freshness:  freshness,
+		Incomplete: !(okOpen && okHigh && okLow && okClose && okVolume),
+	}
+}
+
+// priceAt returns the i-th price of vs, or false if it is null or missing.
+func priceAt(vs []float64, i int) (float64, bool) {
+	if i >= len(vs) || math.IsNaN(vs[i]) {
+		return 0, false
+	}
+	return vs[i], true
+}
+
+// volumeAt returns the i-th volume of vs, or false if it is null or missing.
+func volumeAt(vs []int64, i int) (int64, bool) {
+	if i >= len(vs) || vs[i] < 0 {
+		return 0, false
+	}
+	return vs[i], true
+}
+
+// maxDepth caps how deeply nested the values chartScanner skips may be.
+const maxDepth = 10000
+
+// chartScanner reads JSON from data a value at a time. A null object or
+// array reads as an empty one, as it would with json.Unmarshal.
+type chartScanner struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (sc *chartScanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", sc.pos, fmt.Sprintf(format, args...))
+}
+
+// peek returns the next byte that isn't space, or 0 at the end of data.
+func (sc *chartScanner) peek() byte {
+	for sc.pos < len(sc.data) {
+		switch c := sc.data[sc.pos]; c {
+		case ' ', '\t', '\n', '\r':
+			sc.pos++
+		default:
+			return c
+		}
+	}
+	return 0
+}
+
+func (sc *chartScanner) consume(c byte) error {
+	if sc.peek() != c {
+		return sc.errorf("expected %q", c)
+	}
+	sc.pos++
+	return nil
+}
+
+// literal consumes lit if it comes next.
+func (sc *chartScanner) literal(lit string) bool {
+	sc.peek()
+	if len(sc.data)-sc.pos < len(lit) || string(sc.data[sc.pos:sc.pos+len(lit)]) != lit {
+		return false
+	}
+	sc.pos += len(lit)
+	return true
+}
+
+// str returns the next string, without its quotes and with its escapes left
+// as they are.
+func (sc *chartScanner) str() ([]byte, error) {
+	if err := sc.consume('"'); err != nil {
+		return nil, err
+	}
+
+	start := sc.pos
+	for sc.pos < len(sc.data) {
+		switch sc.data[sc.pos] {
+		case '\\':
+			sc.pos += 2
+		case '"':
+			sc.pos++
+			return sc.data[start : sc.pos-1], nil
+		default:
+			sc.pos++
+		}
+	}
+	return nil, sc.errorf("unterminated string")
+}
+
+// number returns the next number as it is written.
+func (sc *chartScanner) number() ([]byte, error) {
+	sc.peek()
+	start := sc.pos
+	for sc.pos < len(sc.data) {
+		c := sc.data[sc.pos]
+		if (c < '0' || c > '9') && c != '-' && c != '+' && c != '.' && c != 'e' && c != 'E' {
+			break
+		}
+		sc.pos++
+	}
+	if sc.pos == start {
+		return nil, sc.errorf("expected a value")
+	}
+	return sc.data[start:sc.pos], nil
+}
+
+// object calls field with the key of each member of the next object, with
+// the scanner at the member's value, which field must consume.
+func (sc *chartScanner) object(field func(key []byte) error) error {
+	if sc.literal("null") {
+		return nil
+	}
+	if err := sc.consume('{'); err != nil {
+		return err
+	}
+	if sc.depth++; sc.depth > maxDepth {
+		return sc.errorf("too deeply nested")
+	}
+
+	if sc.peek() != '}' {
+		for {
+			key, err := sc.str()
+			if err != nil {
+				return err
+			}
+			if err := sc.consume(':'); err != nil {
+				return err
+			}
+			if err := field(key); err != nil {
+				return err
+			}
+			if sc.peek() != ',' {
+				break
+			}
+			sc.pos++
+		}
+	}
+
+	sc.depth--
+	return sc.consume('}')
+}
+
+// array calls elem with the index of each element of the next array, with
+// the scanner at the element, which elem must consume.
+func (sc *chartScanner) array(elem func(i int) error) error {
+	if sc.literal("null") {
+		return nil
+	}
+	if err := sc.consume('['); err != nil {
+		return err
+	}
+	if sc.depth++; sc.depth > maxDepth {
+		return sc.errorf("too deeply nested")
+	}
+
+	if sc.peek() != ']' {
+		for i := 0; ; i++ {
+			if err := elem(i); err != nil {
+				return err
+			}
+			if sc.peek() != ',' {
+				break
+			}
+			sc.pos++
+		}
+	}
+
+	sc.depth--
+	return sc.consume(']')
+}
+
+// skip consumes the next value.
+func (sc *chartScanner) skip() error {
+	switch sc.peek() {
+	case '{':
+		return sc.object(func([]byte) error { return sc.skip() })
+	case '[':
+		return sc.array(func(int) error { return sc.skip() })
+	case '"':
+		_, err := sc.str()
+		return err
+	}
+
+	if sc.literal("null") || sc.literal("true") || sc.literal("false") {
+		return nil
+	}
+	_, err := sc.number()
+	return err
+}
+
+// floats reads the next array of numbers into vs[:0], with NaN for nulls.
+func (sc *chartScanner) floats(vs []float64) ([]float64, error) {
+	vs = vs[:0]
+	err := sc.array(func(int) error {
+		if sc.literal("null") {
+			vs = append(vs, math.NaN())
+			return nil
+		}
+
+		tok, err := sc.number()
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseFloat(string(tok), 64)
+		if err != nil {
+			return sc.errorf("%v", err)
+		}
+		vs = append(vs, v)
+		return nil
+	})
+	return vs, err
+}
+
+// ints reads the next array of integers into vs[:0], with null for nulls.
+func (sc *chartScanner) ints(vs []int64, null int64) ([]int64, error) {
+	vs = vs[:0]
+	err := sc.array(func(int) error {
+		if sc.literal("null") {
+			vs = append(vs, null)
+			return nil
+		}
+
+		tok, err := sc.number()
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseInt(string(tok), 10, 64)
+		if err != nil {
+			return sc.errorf("%v", err)
+		}
+		vs = append(vs, v)
+		return nil
+	})
+	return vs, err
+}
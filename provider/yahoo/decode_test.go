@@ -0,0 +1,316 @@
+package yahoo
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// yahooResponse and yahooAdjClose are the chart response as json.Unmarshal
+// decodes it, which decodeChart must agree with.
+type yahooResponse struct {
+	Chart struct {
+		Result []struct {
+			Timestamp  []int64 `json:"timestamp"`
+			Indicators struct {
+				Quote []struct {
+					Open   []*float64 `json:"open"`
+					High   []*float64 `json:"high"`
+					Low    []*float64 `json:"low"`
+					Close  []*float64 `json:"close"`
+					Volume []*int64   `json:"volume"`
+				} `json:"quote"`
+			} `json:"indicators"`
+		} `json:"result"`
+		Error interface{} `json:"error"`
+	} `json:"chart"`
+}
+
+type yahooAdjClose struct {
+	Chart struct {
+		Result []struct {
+			Indicators struct {
+				AdjClose []struct {
+					AdjClose []*float64 `json:"adjclose"`
+				} `json:"adjclose"`
+			} `json:"indicators"`
+		} `json:"result"`
+	} `json:"chart"`
+}
+
+// unmarshalChart is decodeChart with json.Unmarshal, as the provider decoded
+// responses before.
+func unmarshalChart(body []byte) (chartSeries, error) {
+	var data yahooResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return chartSeries{}, err
+	}
+	if len(data.Chart.Result) == 0 || len(data.Chart.Result[0].Indicators.Quote) == 0 {
+		return chartSeries{}, provider.ErrNoData
+	}
+	var adj yahooAdjClose
+	if err := json.Unmarshal(body, &adj); err != nil {
+		return chartSeries{}, err
+	}
+
+	prices := func(vs []*float64) []float64 {
+		out := make([]float64, len(vs))
+		for i, v := range vs {
+			out[i] = math.NaN()
+			if v != nil {
+				out[i] = *v
+			}
+		}
+		return out
+	}
+
+	result := data.Chart.Result[0]
+	quotes := result.Indicators.Quote[0]
+	series := chartSeries{
+		timestamps: result.Timestamp,
+		opens:      prices(quotes.Open),
+		highs:      prices(quotes.High),
+		lows:       prices(quotes.Low),
+		closes:     prices(quotes.Close),
+		volumes:    make([]int64, len(quotes.Volume)),
+	}
+	for i, v := range quotes.Volume {
+		series.volumes[i] = -1
+		if v != nil {
+			series.volumes[i] = *v
+		}
+	}
+	if len(adj.Chart.Result) > 0 && len(adj.Chart.Result[0].Indicators.AdjClose) > 0 {
+		series.adjClose = prices(adj.Chart.Result[0].Indicators.AdjClose[0].AdjClose)
+	}
+	return series, nil
+}
+
+// randomChart returns a chart response of n candles with some values null
+// and some series cut short.
+func randomChart(r *rand.Rand, n int) []byte {
+	values := func(value func() any) []any {
+		length := n
+		if r.IntN(10) == 0 {
+			length = r.IntN(n + 1)
+		}
+		out := make([]any, length)
+		for i := range out {
+			if r.IntN(20) != 0 {
+				out[i] = value()
+			}
+		}
+		return out
+	}
+	price := func() any { return math.Round(r.Float64()*1e6) / 100 }
+
+	timestamps := make([]int64, n)
+	for i := range timestamps {
+		timestamps[i] = 1704166200 + int64(i)*60
+	}
+
+	body, _ := json.MarshalIndent(map[string]any{
+		"chart": map[string]any{
+			"result": []any{map[string]any{
+				"meta":      map[string]any{"currency": "INR", "symbol": "INFY.NS"},
+				"timestamp": timestamps,
+				"indicators": map[string]any{
+					"quote": []any{map[string]any{
+						"open":   values(price),
+						"high":   values(price),
+						"low":    values(price),
+						"close":  values(price),
+						"volume": values(func() any { return r.Int64N(1e9) }),
+					}},
+					"adjclose": []any{map[string]any{"adjclose": values(price)}},
+				},
+			}},
+			"error": nil,
+		},
+	}, "", " ")
+	return body
+}
+
+const chartBody = `{"chart":{"result":[{"timestamp":[1704166200,1704166260,1704166320],"indicators":{"quote":[{"open":[100,101,null],"high":[102,103,104],"low":[99,100,101],"close":[101,102,103],"volume":[10,20,30]}],"adjclose":[{"adjclose":[50.5,null,51.5]}]}}],"error":null}}`
+
+func TestDecodeChart(t *testing.T) {
+	series, err := decodeChart([]byte(chartBody), true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	candles := seriesCandles(series)
+	if len(candles) != 3 {
+		t.Fatalf("Expected 3 candles, got %d", len(candles))
+	}
+	if candles[0].Open != 100 || candles[0].Volume != 10 || candles[0].Incomplete {
+		t.Errorf("Unexpected first candle: %+v", candles[0])
+	}
+	if !candles[2].Incomplete {
+		t.Error("Expected the candle with a null open to be incomplete")
+	}
+	if len(series.adjClose) != 3 || !math.IsNaN(series.adjClose[1]) {
+		t.Errorf("Expected the adjusted close with its null, got %v", series.adjClose)
+	}
+}
+
+func TestDecodeChart_Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		noData bool
+		count  int
+	}{
+		{"NoResult", `{"chart":{"result":[],"error":null}}`, true, 0},
+		{"NullResult", `{"chart":{"result":null}}`, true, 0},
+		{"NoQuotes", `{"chart":{"result":[{"timestamp":[1],"indicators":{"quote":[]}}]}}`, true, 0},
+		{"ShortQuotes", `{"chart":{"result":[{"timestamp":[1,2,3],"indicators":{"quote":[{"open":[1],"high":[1],"low":[1],"close":[1],"volume":[1]}]}}]}}`, false, 3},
+		{"MissingArrays", `{"chart":{"result":[{"timestamp":[1,2],"indicators":{"quote":[{}]}}]}}`, false, 2},
+		{"NoTimestamps", `{"chart":{"result":[{"indicators":{"quote":[{"open":[1,2]}]}}]}}`, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := decodeChart([]byte(tt.body), true)
+			if tt.noData {
+				if !errors.Is(err, provider.ErrNoData) {
+					t.Errorf("Expected ErrNoData, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := seriesCandles(series); len(got) != tt.count {
+				t.Errorf("Expected %d candles, got %d", tt.count, len(got))
+			}
+		})
+	}
+}
+
+func TestDecodeChart_WrongTypes(t *testing.T) {
+	bodies := []string{
+		`{"chart":{"result":[{"timestamp":["1"],"indicators":{"quote":[{}]}}]}}`,
+		`{"chart":{"result":[{"timestamp":[1],"indicators":{"quote":[{"open":"100"}]}}]}}`,
+		`{"chart":{"result":{}}}`,
+		`not json`,
+	}
+
+	for _, body := range bodies {
+		if _, err := decodeChart([]byte(body), false); err == nil || errors.Is(err, provider.ErrNoData) {
+			t.Errorf("Expected a decode error for %s, got %v", body, err)
+		}
+	}
+}
+
+func FuzzDecodeChart(f *testing.F) {
+	f.Add([]byte(chartBody))
+	f.Add([]byte(`{"chart":{"result":[{"timestamp":[1,2,3],"indicators":{"quote":[{"open":[1]}],"adjclose":[{}]}}]}}`))
+	f.Add([]byte(`{"chart":{"result":[{"timestamp":[],"indicators":{"quote":[{"close":[null,null]}]}}]}}`))
+	f.Add([]byte(`{}`))
+
+	policies := []*YahooProvider{{nullPolicy: NullDrop}, {nullPolicy: NullForwardFill}, {nullPolicy: NullKeep}}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		series, err := decodeChart(body, true)
+		if err != nil {
+			return
+		}
+
+		for _, y := range policies {
+			var kept int
+			y.eachCandle(&series, "INFY", types.ExchangeNSE, types.Interval1m, true, func(types.OHLCV) { kept++ })
+			if kept > len(series.timestamps) {
+				t.Fatalf("Expected at most a candle per timestamp, got %d for %d", kept, len(series.timestamps))
+			}
+		}
+	})
+}
+
+func seriesCandles(series chartSeries) []types.OHLCV {
+	var candles []types.OHLCV
+	for i := range series.timestamps {
+		candles = append(candles, series.candle(i, "INFY", types.ExchangeNSE, "yahoo", types.FreshnessDelayed))
+	}
+	return candles
+}
+
+func TestDecodeChart_MatchesUnmarshal(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var reused chartSeries
+	for i := 0; i < 200; i++ {
+		body := randomChart(r, r.IntN(50))
+
+		want, err := unmarshalChart(body)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := reused.decode(body, true); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if got, want := seriesCandles(reused), seriesCandles(want); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+		for j := range want.adjClose {
+			a, okA := priceAt(want.adjClose, j)
+			b, okB := priceAt(reused.adjClose, j)
+			if a != b || okA != okB {
+				t.Fatalf("Expected adjusted close %v, got %v", want.adjClose, reused.adjClose)
+			}
+		}
+	}
+}
+
+func TestDecodeChart_SkipsOtherValues(t *testing.T) {
+	body := `{"chart":{"result":[{"meta":{"currency":"INR","validRanges":["1d","5d"],"tradingPeriods":[[{"start":1,"gmtoffset":19800}]],"hasPrePostMarketData":false,"name":"a \"quoted\" \\ name"},` +
+		`"timestamp":[1704166200],"events":{"splits":{}},"indicators":{"quote":[{"open":[1.5],"high":[2e0],"low":[-1],"close":[1],"volume":[5]},{"open":[9]}]}},{"timestamp":[0]}],"error":null}}`
+
+	series, err := decodeChart([]byte(body), false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := seriesCandles(series); len(got) != 1 || got[0].Open != 1.5 || got[0].High != 2 || got[0].Volume != 5 || got[0].Incomplete {
+		t.Errorf("Unexpected candles: %+v", got)
+	}
+}
+
+func TestDecodeChart_TooDeep(t *testing.T) {
+	body := `{"chart":{"meta":` + strings.Repeat("[", maxDepth+1) + strings.Repeat("]", maxDepth+1) + `}}`
+
+	if _, err := decodeChart([]byte(body), false); err == nil || !strings.Contains(err.Error(), "too deeply nested") {
+		t.Errorf("Expected a nesting error, got %v", err)
+	}
+}
+
+func BenchmarkDecodeChart(b *testing.B) {
+	// A week of one-minute NSE candles.
+	body := randomChart(rand.New(rand.NewPCG(1, 2)), 5*375)
+
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for range b.N {
+			if _, err := unmarshalChart(body); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		var series chartSeries
+		for range b.N {
+			if err := series.decode(body, true); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
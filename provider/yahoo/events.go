@@ -0,0 +1,102 @@
+package yahoo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type yahooEventsResponse struct {
+	Chart struct {
+		Result []struct {
+			Events struct {
+				Dividends map[string]struct {
+					Amount float64 `json:"amount"`
+					Date   int64   `json:"date"`
+				} `json:"dividends"`
+				Splits map[string]struct {
+					Date        int64   `json:"date"`
+					Numerator   float64 `json:"numerator"`
+					Denominator float64 `json:"denominator"`
+				} `json:"splits"`
+			} `json:"events"`
+		} `json:"result"`
+	} `json:"chart"`
+}
+
+// CorporateActions returns the dividends and splits Yahoo reports between
+// from and to, oldest first.
+func (y *YahooProvider) CorporateActions(ctx context.Context, symbol string, exchange types.Exchange, from, to time.Time) (types.CorporateActions, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?interval=1d&period1=%d&period2=%d&events=div,splits",
+		y.formatSymbol(symbol, exchange), from.Unix(), to.Unix())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return types.CorporateActions{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", uuid.NewString())
+	req.Header.Set("Accept", "application/json")
+
+	res, err := y.client.Do(ctx, req)
+	if err != nil {
+		return types.CorporateActions{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return types.CorporateActions{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return types.CorporateActions{}, &provider.ProviderError{Provider: y.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	var data yahooEventsResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return types.CorporateActions{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if len(data.Chart.Result) == 0 {
+		return types.CorporateActions{}, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+
+	loc := exchange.Location()
+	events := data.Chart.Result[0].Events
+
+	var actions types.CorporateActions
+	for _, d := range events.Dividends {
+		actions.Dividends = append(actions.Dividends, types.Dividend{
+			Symbol:   symbol,
+			Exchange: exchange,
+			Date:     time.Unix(d.Date, 0).In(loc),
+			Amount:   d.Amount,
+		})
+	}
+	for _, s := range events.Splits {
+		actions.Splits = append(actions.Splits, types.Split{
+			Symbol:      symbol,
+			Exchange:    exchange,
+			Date:        time.Unix(s.Date, 0).In(loc),
+			Numerator:   s.Numerator,
+			Denominator: s.Denominator,
+		})
+	}
+
+	slices.SortFunc(actions.Dividends, func(a, b types.Dividend) int { return a.Date.Compare(b.Date) })
+	slices.SortFunc(actions.Splits, func(a, b types.Split) int { return a.Date.Compare(b.Date) })
+
+	return actions, nil
+}
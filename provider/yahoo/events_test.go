@@ -0,0 +1,62 @@
+package yahoo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestYahooProvider_CorporateActions(t *testing.T) {
+	body := `{"chart":{"result":[{"events":{` +
+		`"dividends":{"1718668800":{"amount":10,"date":1718668800},"1687132800":{"amount":9,"date":1687132800}},` +
+		`"splits":{"1698969600":{"date":1698969600,"numerator":2,"denominator":1,"splitRatio":"2:1"}}}}],"error":null}}`
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}})
+	p := &YahooProvider{client: mockClient}
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	actions, err := p.CorporateActions(context.Background(), "RELIANCE", types.ExchangeNSE, from, from.AddDate(2, 0, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if q := mockClient.requests[0].URL.Query(); q.Get("events") != "div,splits" {
+		t.Errorf("Expected events to be requested, got %s", mockClient.requests[0].URL.RawQuery)
+	}
+
+	if len(actions.Dividends) != 2 {
+		t.Fatalf("Expected 2 dividends, got %d", len(actions.Dividends))
+	}
+	if actions.Dividends[0].Amount != 9 || actions.Dividends[1].Amount != 10 {
+		t.Errorf("Expected dividends oldest first, got %+v", actions.Dividends)
+	}
+	if actions.Dividends[0].Symbol != "RELIANCE" || actions.Dividends[0].Date.Location().String() != "Asia/Kolkata" {
+		t.Errorf("Expected symbol and IST date, got %+v", actions.Dividends[0])
+	}
+
+	if len(actions.Splits) != 1 {
+		t.Fatalf("Expected 1 split, got %d", len(actions.Splits))
+	}
+	if s := actions.Splits[0]; s.Numerator != 2 || s.Denominator != 1 {
+		t.Errorf("Expected 2:1 split, got %+v", s)
+	}
+}
+
+func TestYahooProvider_CorporateActions_NonOKResponse(t *testing.T) {
+	p := &YahooProvider{client: NewMockHTTPClient([]*http.Response{createErrorResponse(404, "Not Found")})}
+
+	_, err := p.CorporateActions(context.Background(), "UNKNOWN", types.ExchangeNSE, time.Now().AddDate(-1, 0, 0), time.Time{})
+	if !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package yahoo
+
+import (
+	"context"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type yahooFundamentals struct {
+	Price struct {
+		Currency string `json:"currency"`
+	} `json:"price"`
+	SummaryDetail struct {
+		MarketCap        yahooValue `json:"marketCap"`
+		TrailingPE       yahooValue `json:"trailingPE"`
+		ForwardPE        yahooValue `json:"forwardPE"`
+		DividendYield    yahooValue `json:"dividendYield"`
+		Beta             yahooValue `json:"beta"`
+		FiftyTwoWeekHigh yahooValue `json:"fiftyTwoWeekHigh"`
+		FiftyTwoWeekLow  yahooValue `json:"fiftyTwoWeekLow"`
+	} `json:"summaryDetail"`
+	DefaultKeyStatistics struct {
+		TrailingEps       yahooValue `json:"trailingEps"`
+		ForwardEps        yahooValue `json:"forwardEps"`
+		SharesOutstanding yahooValue `json:"sharesOutstanding"`
+		PriceToBook       yahooValue `json:"priceToBook"`
+	} `json:"defaultKeyStatistics"`
+}
+
+// Fundamentals returns the key statistics of symbol from Yahoo's quoteSummary
+// API. Prices are rounded to the provider's precision; ratios are not.
+func (y *YahooProvider) Fundamentals(ctx context.Context, symbol string, exchange types.Exchange) (types.Fundamentals, error) {
+	var data yahooFundamentals
+	if err := y.quoteSummary(ctx, symbol, exchange, []string{"price", "summaryDetail", "defaultKeyStatistics"}, &data); err != nil {
+		return types.Fundamentals{}, err
+	}
+
+	detail, stats := data.SummaryDetail, data.DefaultKeyStatistics
+	return types.Fundamentals{
+		Symbol:            symbol,
+		Exchange:          exchange,
+		Currency:          data.Price.Currency,
+		MarketCap:         detail.MarketCap.Raw,
+		SharesOutstanding: int64(stats.SharesOutstanding.Raw),
+		TrailingPE:        detail.TrailingPE.Raw,
+		ForwardPE:         detail.ForwardPE.Raw,
+		TrailingEPS:       stats.TrailingEps.Raw,
+		ForwardEPS:        stats.ForwardEps.Raw,
+		PriceToBook:       stats.PriceToBook.Raw,
+		DividendYield:     detail.DividendYield.Raw,
+		Beta:              detail.Beta.Raw,
+		FiftyTwoWeekHigh:  y.round(detail.FiftyTwoWeekHigh.Raw),
+		FiftyTwoWeekLow:   y.round(detail.FiftyTwoWeekLow.Raw),
+		Source:            y.Name(),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package yahoo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+// sessionResponses answers the cookie and crumb requests of a new session.
+func sessionResponses(crumb string) []*http.Response {
+	cookie := response(404, "")
+	cookie.Header.Set("Set-Cookie", "A3=d=session; Domain=.yahoo.com; Path=/")
+	return []*http.Response{cookie, response(200, crumb)}
+}
+
+const fundamentalsBody = `{"quoteSummary":{"result":[{` +
+	`"price":{"currency":"INR"},` +
+	`"summaryDetail":{"marketCap":{"raw":18650000000000,"fmt":"18.65T"},"trailingPE":{"raw":27.41},"forwardPE":{},` +
+	`"dividendYield":{"raw":0.0036},"beta":{"raw":0.55},"fiftyTwoWeekHigh":{"raw":1608.8},"fiftyTwoWeekLow":{"raw":1114.85}},` +
+	`"defaultKeyStatistics":{"trailingEps":{"raw":50.23},"forwardEps":{"raw":58.1},"sharesOutstanding":{"raw":13532472634},"priceToBook":{"raw":2.31}}` +
+	`}],"error":null}}`
+
+func TestYahooProvider_Fundamentals(t *testing.T) {
+	mockClient := NewMockHTTPClient(append(sessionResponses("abc/def"), response(200, fundamentalsBody)))
+	p := &YahooProvider{client: mockClient}
+
+	f, err := p.Fundamentals(context.Background(), "RELIANCE", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	req := mockClient.requests[2]
+	if req.URL.Path != "/v10/finance/quoteSummary/RELIANCE.NS" || req.URL.Query().Get("crumb") != "abc/def" {
+		t.Errorf("Unexpected request: %s", req.URL)
+	}
+	if c, err := req.Cookie("A3"); err != nil || c.Value != "d=session" {
+		t.Errorf("Expected the session cookie, got %v", c)
+	}
+	if f.MarketCap != 18650000000000 || f.SharesOutstanding != 13532472634 || f.Currency != "INR" {
+		t.Errorf("Unexpected size: %+v", f)
+	}
+	if f.TrailingPE != 27.41 || f.ForwardPE != 0 || f.TrailingEPS != 50.23 || f.ForwardEPS != 58.1 || f.PriceToBook != 2.31 {
+		t.Errorf("Unexpected ratios: %+v", f)
+	}
+	if f.FiftyTwoWeekHigh != 1608.8 || f.FiftyTwoWeekLow != 1114.85 || f.DividendYield != 0.0036 || f.Beta != 0.55 {
+		t.Errorf("Unexpected range: %+v", f)
+	}
+	if f.Symbol != "RELIANCE" || f.Source != "yahoo" {
+		t.Errorf("Unexpected metadata: %+v", f)
+	}
+
+	if _, err := p.Fundamentals(context.Background(), "RELIANCE", types.ExchangeNSE); err == nil || mockClient.calledCount != 4 {
+		t.Errorf("Expected the session reused, got %d requests", mockClient.calledCount)
+	}
+}
+
+func TestYahooProvider_Fundamentals_RenewsRejectedCrumb(t *testing.T) {
+	responses := sessionResponses("stale")
+	responses = append(responses, response(401, `{"finance":{"error":{"code":"Unauthorized","description":"Invalid Crumb"}}}`))
+	responses = append(responses, sessionResponses("fresh")...)
+	responses = append(responses, response(200, fundamentalsBody))
+	mockClient := NewMockHTTPClient(responses)
+	p := &YahooProvider{client: mockClient}
+
+	if _, err := p.Fundamentals(context.Background(), "RELIANCE", types.ExchangeNSE); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := mockClient.requests[5].URL.Query().Get("crumb"); got != "fresh" {
+		t.Errorf("Expected the renewed crumb, got %q", got)
+	}
+}
+
+func TestYahooProvider_Fundamentals_NotFound(t *testing.T) {
+	body := `{"quoteSummary":{"result":null,"error":{"code":"Not Found","description":"Quote not found for symbol: NOPE.NS"}}}`
+	p := &YahooProvider{client: NewMockHTTPClient(append(sessionResponses("abc"), response(404, body)))}
+
+	if _, err := p.Fundamentals(context.Background(), "NOPE", types.ExchangeNSE); !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+}
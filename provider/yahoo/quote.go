@@ -0,0 +1,94 @@
+package yahoo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type yahooQuoteResponse struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				RegularMarketPrice   float64 `json:"regularMarketPrice"`
+				RegularMarketDayHigh float64 `json:"regularMarketDayHigh"`
+				RegularMarketDayLow  float64 `json:"regularMarketDayLow"`
+				RegularMarketVolume  int64   `json:"regularMarketVolume"`
+				RegularMarketTime    int64   `json:"regularMarketTime"`
+				ChartPreviousClose   float64 `json:"chartPreviousClose"`
+			} `json:"meta"`
+			Indicators struct {
+				Quote []struct {
+					Open []float64 `json:"open"`
+				} `json:"quote"`
+			} `json:"indicators"`
+		} `json:"result"`
+	} `json:"chart"`
+}
+
+// Quote returns the latest price of symbol from the chart endpoint's market
+// metadata, with the day's open taken from today's daily candle.
+func (y *YahooProvider) Quote(ctx context.Context, symbol string, exchange types.Exchange) (types.Quote, error) {
+	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?interval=1d&range=1d",
+		y.formatSymbol(symbol, exchange))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return types.Quote{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", uuid.NewString())
+	req.Header.Set("Accept", "application/json")
+
+	res, err := y.client.Do(ctx, req)
+	if err != nil {
+		return types.Quote{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return types.Quote{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return types.Quote{}, &provider.ProviderError{Provider: y.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	var data yahooQuoteResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return types.Quote{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if len(data.Chart.Result) == 0 {
+		return types.Quote{}, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+
+	result := data.Chart.Result[0]
+	meta := result.Meta
+
+	var open float64
+	if len(result.Indicators.Quote) > 0 && len(result.Indicators.Quote[0].Open) > 0 {
+		open = result.Indicators.Quote[0].Open[0]
+	}
+
+	loc := exchange.Location()
+	return types.Quote{
+		Symbol:        symbol,
+		Exchange:      exchange,
+		LastPrice:     y.round(meta.RegularMarketPrice),
+		Open:          y.round(open),
+		High:          y.round(meta.RegularMarketDayHigh),
+		Low:           y.round(meta.RegularMarketDayLow),
+		PreviousClose: y.round(meta.ChartPreviousClose),
+		Volume:        meta.RegularMarketVolume,
+		DateTime:      time.Unix(meta.RegularMarketTime, 0).In(loc),
+		Source:        y.Name(),
+	}, nil
+}
@@ -0,0 +1,38 @@
+package yahoo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestYahooProvider_Quote(t *testing.T) {
+	body := `{"chart":{"result":[{"meta":{"regularMarketPrice":1375.456,"regularMarketDayHigh":1380,` +
+		`"regularMarketDayLow":1360,"regularMarketVolume":123456,"regularMarketTime":1704187800,"chartPreviousClose":1370},` +
+		`"indicators":{"quote":[{"open":[1365]}]}}],"error":null}}`
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}})
+	p := &YahooProvider{client: mockClient}
+
+	q, err := p.Quote(context.Background(), "RELIANCE", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := mockClient.requests[0].URL.Path; got != "/v8/finance/chart/RELIANCE.NS" {
+		t.Errorf("Expected chart path for RELIANCE.NS, got %s", got)
+	}
+	if q.LastPrice != 1375.46 || q.Open != 1365 || q.High != 1380 || q.Low != 1360 || q.PreviousClose != 1370 {
+		t.Errorf("Unexpected prices: %+v", q)
+	}
+	if q.Volume != 123456 || q.Source != "yahoo" || q.DateTime.Unix() != 1704187800 {
+		t.Errorf("Unexpected quote metadata: %+v", q)
+	}
+}
@@ -0,0 +1,146 @@
+package yahoo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type yahooSearchQuote struct {
+	Symbol    string `json:"symbol"`
+	Exchange  string `json:"exchange"`
+	ShortName string `json:"shortname"`
+	LongName  string `json:"longname"`
+	QuoteType string `json:"quoteType"`
+}
+
+type yahooSearchResponse struct {
+	Quotes []yahooSearchQuote `json:"quotes"`
+}
+
+// searchExchanges maps Yahoo's exchange codes to the exchanges Provide
+// accepts.
+var searchExchanges = map[string]types.Exchange{
+	"NSI": types.ExchangeNSE,
+	"BSE": types.ExchangeBSE,
+	"NMS": types.ExchangeNASDAQ,
+	"NGM": types.ExchangeNASDAQ,
+	"NCM": types.ExchangeNASDAQ,
+	"NYQ": types.ExchangeNYSE,
+}
+
+// Search finds the instruments whose ticker or company name matches query,
+// such as "infosys", with Yahoo's search, best matches first. Only
+// instruments on exchanges Provide serves are returned; an empty exchange
+// returns all of them. Symbols are in the form Provide accepts, without
+// Yahoo's exchange suffix.
+func (y *YahooProvider) Search(ctx context.Context, query string, exchange types.Exchange) ([]types.Instrument, error) {
+	quotes, err := y.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var instruments []types.Instrument
+	for _, q := range quotes {
+		ex, ok := searchExchanges[q.Exchange]
+		if !ok || (exchange != "" && ex != exchange) {
+			continue
+		}
+		symbol, ok := y.parseSymbol(q.Symbol, ex)
+		if !ok {
+			continue
+		}
+
+		name := q.LongName
+		if name == "" {
+			name = q.ShortName
+		}
+		instruments = append(instruments, types.Instrument{
+			Symbol:         symbol,
+			Name:           name,
+			Exchange:       ex,
+			InstrumentType: q.QuoteType,
+		})
+	}
+
+	return instruments, nil
+}
+
+// ResolveISIN finds the trading symbol of isin on exchange with Yahoo's
+// search, returning it in the form Provide accepts.
+func (y *YahooProvider) ResolveISIN(ctx context.Context, isin string, exchange types.Exchange) (string, error) {
+	quotes, err := y.search(ctx, isin)
+	if err != nil {
+		return "", err
+	}
+
+	for _, q := range quotes {
+		if symbol, ok := y.parseSymbol(q.Symbol, exchange); ok {
+			return symbol, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: ISIN %s on exchange %s", provider.ErrSymbolNotFound, isin, exchange)
+}
+
+func (y *YahooProvider) search(ctx context.Context, q string) ([]yahooSearchQuote, error) {
+	query := url.Values{}
+	query.Set("q", q)
+	query.Set("quotesCount", "10")
+	query.Set("newsCount", "0")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://query2.finance.yahoo.com/v1/finance/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", uuid.NewString())
+	req.Header.Set("Accept", "application/json")
+
+	res, err := y.client.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &provider.ProviderError{Provider: y.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	var data yahooSearchResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return data.Quotes, nil
+}
+
+// parseSymbol undoes formatSymbol, reporting whether the Yahoo ticker is
+// listed on exchange.
+func (y *YahooProvider) parseSymbol(ticker string, exchange types.Exchange) (string, bool) {
+	switch exchange {
+	case types.ExchangeNSE:
+		return strings.CutSuffix(ticker, ".NS")
+	case types.ExchangeBSE:
+		return strings.CutSuffix(ticker, ".BO")
+	case types.ExchangeNASDAQ, types.ExchangeNYSE:
+		if strings.Contains(ticker, ".") {
+			return "", false
+		}
+		return strings.ReplaceAll(ticker, "-", "."), true
+	default:
+		return ticker, ticker != ""
+	}
+}
@@ -0,0 +1,130 @@
+package yahoo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const searchBody = `{"quotes":[` +
+	`{"symbol":"RELIANCE.BO","quoteType":"EQUITY"},` +
+	`{"symbol":"RELIANCE.NS","quoteType":"EQUITY"}]}`
+
+func newSearchProvider(body string) (*YahooProvider, *mockHTTPClient) {
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}})
+	return &YahooProvider{client: mockClient}, mockClient
+}
+
+func TestYahooProvider_ResolveISIN(t *testing.T) {
+	tests := []struct {
+		exchange types.Exchange
+		want     string
+	}{
+		{types.ExchangeNSE, "RELIANCE"},
+		{types.ExchangeBSE, "RELIANCE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.exchange), func(t *testing.T) {
+			p, mockClient := newSearchProvider(searchBody)
+
+			got, err := p.ResolveISIN(context.Background(), "INE002A01018", tt.exchange)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+			if q := mockClient.requests[0].URL.Query().Get("q"); q != "INE002A01018" {
+				t.Errorf("Expected ISIN in query, got %s", q)
+			}
+		})
+	}
+}
+
+func TestYahooProvider_ResolveISIN_US(t *testing.T) {
+	p, _ := newSearchProvider(`{"quotes":[{"symbol":"BRK-B.MX"},{"symbol":"BRK-B"}]}`)
+
+	got, err := p.ResolveISIN(context.Background(), "US0846707026", types.ExchangeNYSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != "BRK.B" {
+		t.Errorf("Expected BRK.B, got %s", got)
+	}
+}
+
+func TestYahooProvider_ResolveISIN_NotFound(t *testing.T) {
+	p, _ := newSearchProvider(`{"quotes":[{"symbol":"RELIANCE.BO"}]}`)
+
+	_, err := p.ResolveISIN(context.Background(), "INE002A01018", types.ExchangeNSE)
+	if !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+}
+
+func TestYahooProvider_Search(t *testing.T) {
+	body := `{"quotes":[` +
+		`{"symbol":"INFY.NS","exchange":"NSI","shortname":"INFOSYS LIMITED","longname":"Infosys Limited","quoteType":"EQUITY"},` +
+		`{"symbol":"INFY","exchange":"NYQ","shortname":"Infosys Limited","quoteType":"EQUITY"},` +
+		`{"symbol":"INFY.BO","exchange":"BSE","shortname":"INFOSYS LTD.","quoteType":"EQUITY"},` +
+		`{"symbol":"INFY.F","exchange":"FRA","shortname":"INFOSYS LTD","quoteType":"EQUITY"}]}`
+
+	p, mockClient := newSearchProvider(body)
+	got, err := p.Search(context.Background(), "infosys", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []types.Instrument{
+		{Symbol: "INFY", Name: "Infosys Limited", Exchange: types.ExchangeNSE, InstrumentType: "EQUITY"},
+		{Symbol: "INFY", Name: "Infosys Limited", Exchange: types.ExchangeNYSE, InstrumentType: "EQUITY"},
+		{Symbol: "INFY", Name: "INFOSYS LTD.", Exchange: types.ExchangeBSE, InstrumentType: "EQUITY"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d instruments, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], got[i])
+		}
+	}
+	if q := mockClient.requests[0].URL.Query().Get("q"); q != "infosys" {
+		t.Errorf("Expected query infosys, got %s", q)
+	}
+}
+
+func TestYahooProvider_Search_Exchange(t *testing.T) {
+	p, _ := newSearchProvider(`{"quotes":[{"symbol":"INFY.NS","exchange":"NSI"},{"symbol":"INFY.BO","exchange":"BSE"}]}`)
+
+	got, err := p.Search(context.Background(), "infy", types.ExchangeBSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 1 || got[0].Exchange != types.ExchangeBSE {
+		t.Errorf("Expected only the BSE listing, got %+v", got)
+	}
+}
+
+func TestYahooProvider_Search_APIError(t *testing.T) {
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 429,
+		Body:       io.NopCloser(bytes.NewBufferString("Too Many Requests")),
+		Header:     make(http.Header),
+	}})
+	p := &YahooProvider{client: mockClient}
+
+	if _, err := p.Search(context.Background(), "infosys", ""); !errors.Is(err, provider.ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
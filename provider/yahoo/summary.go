@@ -0,0 +1,97 @@
+package yahoo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// yahooValue is a number as quoteSummary reports it, raw and formatted. A
+// value Yahoo doesn't have is an empty object, leaving Raw zero.
+type yahooValue struct {
+	Raw float64 `json:"raw"`
+}
+
+type yahooSummaryResponse struct {
+	QuoteSummary struct {
+		Result []json.RawMessage `json:"result"`
+		Error  *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"quoteSummary"`
+}
+
+// quoteSummary requests modules of symbol from the quoteSummary API and
+// decodes the result into out. A crumb Yahoo rejects is replaced once.
+func (y *YahooProvider) quoteSummary(ctx context.Context, symbol string, exchange types.Exchange, modules []string, out any) error {
+	body, err := y.requestSummary(ctx, symbol, exchange, modules)
+	if err != nil {
+		return err
+	}
+
+	var resp yahooSummaryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(resp.QuoteSummary.Result) == 0 {
+		if e := resp.QuoteSummary.Error; e != nil {
+			return fmt.Errorf("%w for symbol %s on exchange %s: %s", provider.ErrNoData, symbol, exchange, e.Description)
+		}
+		return fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+	if err := json.Unmarshal(resp.QuoteSummary.Result[0], out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+func (y *YahooProvider) requestSummary(ctx context.Context, symbol string, exchange types.Exchange, modules []string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		crumb, cookies, err := y.session(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint := fmt.Sprintf("https://query2.finance.yahoo.com/v10/finance/quoteSummary/%s?modules=%s&crumb=%s",
+			y.formatSymbol(symbol, exchange), strings.Join(modules, ","), url.QueryEscape(crumb))
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("User-Agent", uuid.NewString())
+		req.Header.Set("Accept", "application/json")
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+
+		res, err := y.client.Do(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		switch {
+		case res.StatusCode == http.StatusUnauthorized && attempt == 0:
+			y.resetSession(crumb)
+			continue
+		case res.StatusCode == http.StatusNotFound:
+			return nil, fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, symbol, exchange)
+		case res.StatusCode != http.StatusOK:
+			return nil, &provider.ProviderError{Provider: y.Name(), StatusCode: res.StatusCode, Body: string(body)}
+		}
+		return body, nil
+	}
+}
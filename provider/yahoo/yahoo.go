@@ -0,0 +1,482 @@
+package yahoo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/internal/httpclient"
+	"github.com/shahid-2020/gohlcv/internal/retry"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type YahooProvider struct {
+	client        httpclient.Doer
+	precision     provider.Precision
+	nullPolicy    NullPolicy
+	extendedHours bool
+	auth          session
+}
+
+// NullPolicy decides what happens to candles Yahoo reports with null values,
+// as it does for minutes a symbol was halted.
+type NullPolicy int
+
+const (
+	// NullDrop leaves incomplete candles out. It is the default.
+	NullDrop NullPolicy = iota
+	// NullForwardFill fills the missing prices of a candle with the previous
+	// candle's close and its missing volume with zero. Leading incomplete
+	// candles, with nothing to fill from, are dropped.
+	NullForwardFill
+	// NullKeep keeps incomplete candles with their missing values zero.
+	NullKeep
+)
+
+type config struct {
+	httpClient       *http.Client
+	transport        provider.TransportConfig
+	retryHooks       provider.RetryHooks
+	conditionalCache int64
+	maxResponseSize  int64
+	rateLimits       provider.RateLimits
+	retryBudget      provider.RetryBudget
+	tracerProvider   trace.TracerProvider
+	precision        provider.Precision
+	nullPolicy       NullPolicy
+	extendedHours    bool
+}
+
+type Option func(*config)
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithRateLimits overrides the default limits of 50 requests per second, 500
+// per minute and 2000 per hour.
+func WithRateLimits(limits provider.RateLimits) Option {
+	return func(c *config) {
+		c.rateLimits = limits
+	}
+}
+
+// WithRetryBudget overrides the default budget of 100 retries per minute,
+// shared by every Yahoo provider in the process with the same budget.
+func WithRetryBudget(budget provider.RetryBudget) Option {
+	return func(c *config) {
+		c.retryBudget = budget
+	}
+}
+
+// WithTracerProvider traces every HTTP request attempt as a span from tp.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithTransport tunes the connections of the default HTTP client, or of one
+// set with WithHTTPClient that has no Transport of its own.
+func WithTransport(t provider.TransportConfig) Option {
+	return func(c *config) {
+		c.transport = t
+	}
+}
+
+// WithRetryHooks calls hooks on every retry of a request and when one is
+// given up on.
+func WithRetryHooks(hooks provider.RetryHooks) Option {
+	return func(c *config) {
+		c.retryHooks = hooks
+	}
+}
+
+// WithConditionalCache keeps up to maxBytes of responses carrying an ETag or
+// Last-Modified header, and asks for them again conditionally; a 304 Not
+// Modified is then answered with the kept response.
+func WithConditionalCache(maxBytes int64) Option {
+	return func(c *config) {
+		c.conditionalCache = maxBytes
+	}
+}
+
+// WithMaxResponseSize caps the size of a response, after decompression, at n
+// bytes instead of provider.DefaultMaxResponseSize. Larger responses fail
+// with an error matching provider.ErrResponseTooLarge. Zero removes the
+// limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *config) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithPrecision sets how many decimal places prices are rounded to. The
+// default is provider.DefaultPrecision.
+func WithPrecision(p provider.Precision) Option {
+	return func(c *config) {
+		c.precision = p
+	}
+}
+
+// WithNullPolicy sets how candles with null values are handled. Whatever the
+// policy, the candles kept are marked with OHLCV.Incomplete.
+func WithNullPolicy(p NullPolicy) Option {
+	return func(c *config) {
+		c.nullPolicy = p
+	}
+}
+
+// WithExtendedHours includes pre-market and post-market candles in intraday
+// results. Every intraday candle's Session tells which part of the day it
+// belongs to.
+func WithExtendedHours(enabled bool) Option {
+	return func(c *config) {
+		c.extendedHours = enabled
+	}
+}
+
+func NewYahooProvider(opts ...Option) *YahooProvider {
+	cfg := config{
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		maxResponseSize: provider.DefaultMaxResponseSize,
+		rateLimits: provider.RateLimits{
+			RequestsPerSecond: 50,
+			RequestsPerMinute: 500,
+			RequestsPerHour:   2000,
+		},
+		retryBudget: provider.RetryBudget{Retries: 100, Window: time.Minute},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	clientConfig := httpclient.ClientConfig{
+		Name:                  "yahoo",
+		HttpClient:            cfg.httpClient,
+		Transport:             httpclient.TransportConfig(cfg.transport),
+		ConditionalCacheBytes: cfg.conditionalCache,
+		MaxResponseBytes:      cfg.maxResponseSize,
+		RateLimitConfig: httpclient.RateLimitConfig{
+			RequestsPerSecond: cfg.rateLimits.RequestsPerSecond,
+			RequestsPerMinute: cfg.rateLimits.RequestsPerMinute,
+			RequestsPerHour:   cfg.rateLimits.RequestsPerHour,
+			MaxQueue:          cfg.rateLimits.MaxQueue,
+			Shared:            "yahoo",
+		},
+		RetryConfig: httpclient.RetryConfig{
+			MaxRetries:    6,
+			BaseDelay:     100 * time.Millisecond,
+			MaxDelay:      5 * time.Second,
+			RetryOnStatus: []uint{429, 500, 502, 503},
+			Jitter:        retry.EqualJitter,
+			Budget:        cfg.retryBudget.Retries,
+			BudgetWindow:  cfg.retryBudget.Window,
+			Hooks:         cfg.retryHooks,
+		},
+		TracerProvider: cfg.tracerProvider,
+	}
+
+	return &YahooProvider{
+		client:        httpclient.NewClient(clientConfig),
+		precision:     cfg.precision,
+		nullPolicy:    cfg.nullPolicy,
+		extendedHours: cfg.extendedHours,
+	}
+}
+
+func (y *YahooProvider) Name() string {
+	return "yahoo"
+}
+
+func (y *YahooProvider) Freshness() types.DataFreshness {
+	return types.FreshnessDelayed
+}
+
+// MaxRange reports how much intraday data Yahoo returns per request; longer
+// ranges are silently truncated.
+func (y *YahooProvider) MaxRange(interval types.Interval) time.Duration {
+	switch interval {
+	case types.Interval1m:
+		return 7 * 24 * time.Hour
+	case types.Interval5m, types.Interval15m, types.Interval30m:
+		return 59 * 24 * time.Hour
+	case types.Interval1h:
+		return 729 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// MaxHistory reports how far back Yahoo keeps the intraday intervals it
+// serves; it fails requests for older candles.
+func (y *YahooProvider) MaxHistory(interval types.Interval) time.Duration {
+	switch {
+	case !interval.IsIntraday() || !intervals[interval]:
+		return 0
+	case interval == types.Interval1m:
+		return 30 * 24 * time.Hour
+	case interval.Duration() == time.Hour:
+		return 730 * 24 * time.Hour
+	default:
+		return 60 * 24 * time.Hour
+	}
+}
+
+// SupportsInterval reports whether the chart API serves candles of interval.
+func (y *YahooProvider) SupportsInterval(interval types.Interval) bool {
+	return intervals[interval]
+}
+
+// intervals are the intervals the chart API serves.
+var intervals = map[types.Interval]bool{
+	"1m": true, "2m": true, "5m": true, "15m": true, "30m": true, "60m": true, "90m": true,
+	"1h": true, "1d": true, "5d": true, "1wk": true, "1mo": true, "3mo": true,
+}
+
+// RemainingRateLimit reports the requests left in the current second, minute
+// and hour of the provider's rate limit.
+func (y *YahooProvider) RemainingRateLimit() (provider.RateLimits, bool) {
+	c, ok := y.client.(*httpclient.Client)
+	if !ok {
+		return provider.RateLimits{}, false
+	}
+
+	perSecond, perMinute, perHour := c.Remaining()
+	return provider.RateLimits{
+		RequestsPerSecond: perSecond,
+		RequestsPerMinute: perMinute,
+		RequestsPerHour:   perHour,
+	}, true
+}
+
+func (y *YahooProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	return y.fetch(ctx, symbol, exchange, interval, from, to, false)
+}
+
+// ProvideAdjusted returns candles with open, high, low and close scaled by
+// the ratio of Yahoo's split- and dividend-adjusted close to the raw close.
+// Intervals Yahoo has no adjusted close for are returned with a factor of 1.
+func (y *YahooProvider) ProvideAdjusted(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	return y.fetch(ctx, symbol, exchange, interval, from, to, true)
+}
+
+// ProvideFrame is Provide decoding the response straight into a frame.
+func (y *YahooProvider) ProvideFrame(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) (*types.Frame, error) {
+	series := chartPool.Get().(*chartSeries)
+	defer chartPool.Put(series)
+	if err := y.chart(ctx, symbol, exchange, interval, from, to, false, series); err != nil {
+		return nil, err
+	}
+
+	frame := types.NewFrame(len(series.timestamps))
+	y.eachCandle(series, symbol, exchange, interval, false, frame.Append)
+	for i := range frame.Opens {
+		frame.Opens[i] = y.round(frame.Opens[i])
+		frame.Highs[i] = y.round(frame.Highs[i])
+		frame.Lows[i] = y.round(frame.Lows[i])
+		frame.Closes[i] = y.round(frame.Closes[i])
+	}
+	return frame, nil
+}
+
+func (y *YahooProvider) fetch(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time, adjusted bool) ([]types.OHLCV, error) {
+	series := chartPool.Get().(*chartSeries)
+	defer chartPool.Put(series)
+	if err := y.chart(ctx, symbol, exchange, interval, from, to, adjusted, series); err != nil {
+		return nil, err
+	}
+
+	ohlcvs := make([]types.OHLCV, 0, len(series.timestamps))
+	y.eachCandle(series, symbol, exchange, interval, adjusted, func(c types.OHLCV) {
+		ohlcvs = append(ohlcvs, c)
+	})
+	return y.normalizeOHLCVs(ohlcvs), nil
+}
+
+// chartPool and bodyPool keep the series and response bodies of finished
+// requests for reuse, so that bulk pulls don't allocate them afresh.
+var (
+	chartPool = sync.Pool{New: func() any { return new(chartSeries) }}
+	bodyPool  = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+)
+
+// chart requests the chart of symbol and decodes it into series.
+func (y *YahooProvider) chart(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time, adjusted bool, series *chartSeries) error {
+	if !intervals[interval] {
+		return fmt.Errorf("%w: %s", provider.ErrUnknownInterval, interval)
+	}
+
+	// A zero to means up to now, as it does for the other providers.
+	if to.IsZero() {
+		to = time.Now()
+	}
+	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?interval=%s&period1=%d&period2=%d",
+		y.formatSymbol(symbol, exchange), interval, from.Unix(), to.Unix())
+	if adjusted {
+		url += "&events=div,splits&includeAdjustedClose=true"
+	}
+	if y.extendedHours {
+		url += "&includePrePost=true"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", uuid.NewString())
+	req.Header.Set("Accept", "application/json")
+
+	res, err := y.client.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	buf := bodyPool.Get().(*bytes.Buffer)
+	defer bodyPool.Put(buf)
+	buf.Reset()
+	if _, err := buf.ReadFrom(res.Body); err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return &provider.ProviderError{Provider: y.Name(), StatusCode: res.StatusCode, Body: buf.String()}
+	}
+
+	err = series.decode(buf.Bytes(), adjusted)
+	if errors.Is(err, provider.ErrNoData) {
+		return fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+	return err
+}
+
+// eachCandle calls fn with every candle of series the provider's NullPolicy
+// keeps, adjusted if asked and with its session marked, in order. Prices are
+// left unrounded.
+func (y *YahooProvider) eachCandle(series *chartSeries, symbol string, exchange types.Exchange, interval types.Interval, adjusted bool, fn func(types.OHLCV)) {
+	// Sessions of exchanges without a calendar are left unmarked.
+	var cal *calendar.Calendar
+	if interval.IsIntraday() {
+		cal, _ = calendar.ForExchange(exchange)
+	}
+
+	var prevClose float64
+	hasPrev := false
+	for i := range series.timestamps {
+		c := series.candle(i, symbol, exchange, y.Name(), y.Freshness())
+		c.Interval = interval
+		if adjusted {
+			applyAdjustment(&c, series.adjClose, i)
+		}
+		if c.Incomplete && !y.applyNullPolicy(&c, series, i, prevClose, hasPrev) {
+			continue
+		}
+		prevClose, hasPrev = c.Close, true
+
+		if cal != nil {
+			markSession(&c, cal)
+		}
+		fn(c)
+	}
+}
+
+// applyNullPolicy fills the missing values of the i-th candle of series, c,
+// according to the provider's NullPolicy, and reports whether c is kept.
+// prevClose is the close of the previous candle kept, if hasPrev.
+func (y *YahooProvider) applyNullPolicy(c *types.OHLCV, series *chartSeries, i int, prevClose float64, hasPrev bool) bool {
+	switch y.nullPolicy {
+	case NullDrop:
+		return false
+	case NullForwardFill:
+		if !hasPrev {
+			return false
+		}
+		fill := func(v *float64, values []float64) {
+			if _, ok := priceAt(values, i); !ok {
+				*v = prevClose
+			}
+		}
+		fill(&c.Open, series.opens)
+		fill(&c.High, series.highs)
+		fill(&c.Low, series.lows)
+		fill(&c.Close, series.closes)
+		if _, ok := volumeAt(series.volumes, i); !ok {
+			c.Volume = 0
+		}
+	}
+	return true
+}
+
+// markSession sets the Session of c from the regular session of cal: candles
+// before it are pre-market and candles from its close on are post-market.
+func markSession(c *types.OHLCV, cal *calendar.Calendar) {
+	open, close, ok := cal.SessionBounds(c.DateTime)
+	switch {
+	case !ok:
+	case c.DateTime.Before(open):
+		c.Session = types.SessionPre
+	case !c.DateTime.Before(close):
+		c.Session = types.SessionPost
+	default:
+		c.Session = types.SessionRegular
+	}
+}
+
+// applyAdjustment scales the prices of c, the i-th candle, by adjClose/close.
+// A candle without an adjusted close keeps its prices and gets a factor of 1.
+func applyAdjustment(c *types.OHLCV, adjClose []float64, i int) {
+	factor := 1.0
+	if adj, ok := priceAt(adjClose, i); ok && c.Close != 0 && adj != 0 {
+		factor = adj / c.Close
+	}
+
+	c.Open *= factor
+	c.High *= factor
+	c.Low *= factor
+	c.Close *= factor
+	c.AdjustmentFactor = factor
+}
+
+// formatSymbol adds the exchange suffix Yahoo expects, unless symbol is
+// already a Yahoo ticker such as "RELIANCE.NS".
+func (y *YahooProvider) formatSymbol(symbol string, exchange types.Exchange) string {
+	switch exchange {
+	case types.ExchangeNSE:
+		if strings.HasSuffix(symbol, ".NS") {
+			return symbol
+		}
+		return symbol + ".NS"
+	case types.ExchangeBSE:
+		if strings.HasSuffix(symbol, ".BO") {
+			return symbol
+		}
+		return symbol + ".BO"
+	case types.ExchangeNASDAQ, types.ExchangeNYSE:
+		// Yahoo writes share classes with a dash: BRK.B is BRK-B.
+		return strings.ReplaceAll(symbol, ".", "-")
+	default:
+		return symbol
+	}
+}
+
+func (y *YahooProvider) normalizeOHLCVs(ohlcvs []types.OHLCV) []types.OHLCV {
+	return ohlcv.Round(y.precision).Transform(ohlcvs)
+}
+
+func (y *YahooProvider) round(v float64) float64 {
+	return y.precision.Round(v)
+}
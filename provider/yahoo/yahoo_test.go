@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/shahid-2020/gohlcv/provider"
 	"github.com/shahid-2020/gohlcv/types"
 )
 
@@ -51,11 +54,11 @@ func createMockYahooResponse(timestamps []int64, opens, highs, lows, closes []fl
 				Timestamp  []int64 `json:"timestamp"`
 				Indicators struct {
 					Quote []struct {
-						Open   []float64 `json:"open"`
-						High   []float64 `json:"high"`
-						Low    []float64 `json:"low"`
-						Close  []float64 `json:"close"`
-						Volume []int64   `json:"volume"`
+						Open   []*float64 `json:"open"`
+						High   []*float64 `json:"high"`
+						Low    []*float64 `json:"low"`
+						Close  []*float64 `json:"close"`
+						Volume []*int64   `json:"volume"`
 					} `json:"quote"`
 				} `json:"indicators"`
 			} `json:"result"`
@@ -65,11 +68,11 @@ func createMockYahooResponse(timestamps []int64, opens, highs, lows, closes []fl
 				Timestamp  []int64 `json:"timestamp"`
 				Indicators struct {
 					Quote []struct {
-						Open   []float64 `json:"open"`
-						High   []float64 `json:"high"`
-						Low    []float64 `json:"low"`
-						Close  []float64 `json:"close"`
-						Volume []int64   `json:"volume"`
+						Open   []*float64 `json:"open"`
+						High   []*float64 `json:"high"`
+						Low    []*float64 `json:"low"`
+						Close  []*float64 `json:"close"`
+						Volume []*int64   `json:"volume"`
 					} `json:"quote"`
 				} `json:"indicators"`
 			}{
@@ -77,26 +80,26 @@ func createMockYahooResponse(timestamps []int64, opens, highs, lows, closes []fl
 					Timestamp: timestamps,
 					Indicators: struct {
 						Quote []struct {
-							Open   []float64 `json:"open"`
-							High   []float64 `json:"high"`
-							Low    []float64 `json:"low"`
-							Close  []float64 `json:"close"`
-							Volume []int64   `json:"volume"`
+							Open   []*float64 `json:"open"`
+							High   []*float64 `json:"high"`
+							Low    []*float64 `json:"low"`
+							Close  []*float64 `json:"close"`
+							Volume []*int64   `json:"volume"`
 						} `json:"quote"`
 					}{
 						Quote: []struct {
-							Open   []float64 `json:"open"`
-							High   []float64 `json:"high"`
-							Low    []float64 `json:"low"`
-							Close  []float64 `json:"close"`
-							Volume []int64   `json:"volume"`
+							Open   []*float64 `json:"open"`
+							High   []*float64 `json:"high"`
+							Low    []*float64 `json:"low"`
+							Close  []*float64 `json:"close"`
+							Volume []*int64   `json:"volume"`
 						}{
 							{
-								Open:   opens,
-								High:   highs,
-								Low:    lows,
-								Close:  closes,
-								Volume: volumes,
+								Open:   ptrs(opens),
+								High:   ptrs(highs),
+								Low:    ptrs(lows),
+								Close:  ptrs(closes),
+								Volume: ptrs(volumes),
 							},
 						},
 					},
@@ -114,6 +117,14 @@ func createMockYahooResponse(timestamps []int64, opens, highs, lows, closes []fl
 	}
 }
 
+func ptrs[T any](vs []T) []*T {
+	out := make([]*T, len(vs))
+	for i := range vs {
+		out[i] = &vs[i]
+	}
+	return out
+}
+
 func createErrorResponse(statusCode int, errorMsg string) *http.Response {
 	return &http.Response{
 		StatusCode: statusCode,
@@ -134,6 +145,102 @@ func TestNewYahooProvider(t *testing.T) {
 	}
 }
 
+func TestYahooProvider_RemainingRateLimit(t *testing.T) {
+	p := NewYahooProvider(WithRateLimits(provider.RateLimits{
+		RequestsPerSecond: 3,
+		RequestsPerMinute: 4,
+		RequestsPerHour:   5,
+	}))
+
+	remaining, ok := p.RemainingRateLimit()
+	if !ok {
+		t.Fatal("Expected the budget to be known")
+	}
+	if remaining.RequestsPerSecond != 3 || remaining.RequestsPerMinute != 4 || remaining.RequestsPerHour != 5 {
+		t.Errorf("Expected the full budget, got %+v", remaining)
+	}
+
+	if _, ok := (&YahooProvider{client: NewMockHTTPClient(nil)}).RemainingRateLimit(); ok {
+		t.Error("Expected the budget of a custom client to be unknown")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewYahooProvider_WithHTTPClient(t *testing.T) {
+	called := false
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return createMockYahooResponse([]int64{1704167100}, []float64{100}, []float64{101}, []float64{99}, []float64{100.5}, []int64{1000}), nil
+	})}
+
+	p := NewYahooProvider(WithHTTPClient(client), WithRateLimits(provider.RateLimits{
+		RequestsPerSecond: 1,
+		RequestsPerMinute: 1,
+		RequestsPerHour:   1,
+	}))
+
+	if _, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Now().Add(-48*time.Hour), time.Now()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !called {
+		t.Error("Expected the custom HTTP client to be used")
+	}
+}
+
+func TestNewYahooProvider_WithMaxResponseSize(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return createMockYahooResponse([]int64{1704167100}, []float64{100}, []float64{101}, []float64{99}, []float64{100.5}, []int64{1000}), nil
+	})}
+
+	p := NewYahooProvider(WithHTTPClient(client), WithMaxResponseSize(64))
+
+	_, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, time.Now().Add(-48*time.Hour), time.Now())
+	if !errors.Is(err, provider.ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestYahooProvider_ProvideAdjusted(t *testing.T) {
+	body := `{"chart":{"result":[{"timestamp":[1704167100,1704253500],"indicators":{` +
+		`"quote":[{"open":[100,200],"high":[110,220],"low":[90,180],"close":[100,200],"volume":[10,20]}],` +
+		`"adjclose":[{"adjclose":[50,200]}]}}],"error":null}}`
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}})
+	p := &YahooProvider{client: mockClient}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ohlcvs, err := p.ProvideAdjusted(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, from, from.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if q := mockClient.requests[0].URL.Query(); q.Get("events") != "div,splits" || q.Get("includeAdjustedClose") != "true" {
+		t.Errorf("Expected events and adjusted close to be requested, got %s", mockClient.requests[0].URL.RawQuery)
+	}
+	if len(ohlcvs) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(ohlcvs))
+	}
+
+	first := ohlcvs[0]
+	if first.AdjustmentFactor != 0.5 || first.Open != 50 || first.High != 55 || first.Low != 45 || first.Close != 50 {
+		t.Errorf("Expected prices halved with factor 0.5, got %+v", first)
+	}
+	if first.Volume != 10 {
+		t.Errorf("Expected volume untouched, got %d", first.Volume)
+	}
+	if ohlcvs[1].AdjustmentFactor != 1 || ohlcvs[1].Close != 200 {
+		t.Errorf("Expected unadjusted candle with factor 1, got %+v", ohlcvs[1])
+	}
+}
+
 func TestYahooProvider_Name(t *testing.T) {
 	provider := &YahooProvider{}
 	if name := provider.Name(); name != "yahoo" {
@@ -191,6 +298,9 @@ func TestYahooProvider_Provide_Success_NSE(t *testing.T) {
 	if first.Exchange != types.ExchangeNSE {
 		t.Errorf("Expected exchange NSE, got %v", first.Exchange)
 	}
+	if first.Interval != types.Interval1m {
+		t.Errorf("Expected interval 1m, got %q", first.Interval)
+	}
 	if first.Open != 100.12 {
 		t.Errorf("Expected open 100.12, got %f", first.Open)
 	}
@@ -217,6 +327,44 @@ func TestYahooProvider_Provide_Success_NSE(t *testing.T) {
 	}
 }
 
+func TestYahooProvider_ProvideFrame(t *testing.T) {
+	timestamps := []int64{
+		time.Date(2023, 10, 3, 3, 45, 0, 0, time.UTC).Unix(),
+		time.Date(2023, 10, 3, 3, 46, 0, 0, time.UTC).Unix(),
+	}
+	response := func() *http.Response {
+		return createMockYahooResponse(timestamps, []float64{100.123, 102.456}, []float64{105.678, 107.891},
+			[]float64{95.111, 101.222}, []float64{102.999, 106.777}, []int64{1000, 2000})
+	}
+	p := NewYahooProvider()
+	p.client = NewMockHTTPClient([]*http.Response{response(), response()})
+
+	ctx := context.Background()
+	from := time.Unix(timestamps[0], 0)
+	frame, err := p.ProvideFrame(ctx, "RELIANCE", types.ExchangeNSE, types.Interval1m, from, from.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if frame.Len() != 2 || frame.Symbol != "RELIANCE" || frame.Source != "yahoo" {
+		t.Fatalf("Expected 2 RELIANCE candles from yahoo, got %d %s %s", frame.Len(), frame.Symbol, frame.Source)
+	}
+	if frame.Opens[0] != 100.12 || frame.Closes[1] != 106.78 || frame.Volumes[1] != 2000 {
+		t.Errorf("Expected rounded prices, got opens %v closes %v", frame.Opens, frame.Closes)
+	}
+	if frame.Sessions[0] != types.SessionRegular {
+		t.Errorf("Expected the regular session, got %q", frame.Sessions[0])
+	}
+
+	ohlcvs, err := p.Provide(ctx, "RELIANCE", types.ExchangeNSE, types.Interval1m, from, from.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := frame.Candles(); !reflect.DeepEqual([]types.OHLCV(got), ohlcvs) {
+		t.Errorf("Expected the frame to hold the candles of Provide, got %v and %v", got, ohlcvs)
+	}
+}
+
 func TestYahooProvider_Provide_Success_BSE(t *testing.T) {
 
 	timestamps := []int64{time.Date(2023, 10, 1, 9, 15, 0, 0, time.UTC).Unix()}
@@ -270,6 +418,7 @@ func TestYahooProvider_Provide_WithoutToDate(t *testing.T) {
 
 	ctx := context.Background()
 	from := time.Date(2023, 10, 1, 9, 15, 0, 0, time.UTC)
+	before := time.Now().Unix()
 
 	ohlcvs, err := provider.Provide(ctx, "RELIANCE", types.ExchangeNSE, types.Interval1m, from, time.Time{})
 
@@ -280,9 +429,12 @@ func TestYahooProvider_Provide_WithoutToDate(t *testing.T) {
 		t.Errorf("Expected 1 OHLCV record, got %d", len(ohlcvs))
 	}
 
-	expectedURL := "https://query2.finance.yahoo.com/v8/finance/chart/RELIANCE.NS?interval=1m&period1=1696151700&period2=1696151700"
-	if mockClient.requests[0].URL.String() != expectedURL {
-		t.Errorf("Expected URL %s, got %s", expectedURL, mockClient.requests[0].URL.String())
+	query := mockClient.requests[0].URL.Query()
+	if query.Get("period1") != "1696151700" {
+		t.Errorf("Expected period1 1696151700, got %s", query.Get("period1"))
+	}
+	if period2, _ := strconv.ParseInt(query.Get("period2"), 10, 64); period2 < before {
+		t.Errorf("Expected period2 to be now, got %s", query.Get("period2"))
 	}
 }
 
@@ -437,11 +589,11 @@ func TestYahooProvider_Provide_EmptyResult(t *testing.T) {
 				Timestamp  []int64 `json:"timestamp"`
 				Indicators struct {
 					Quote []struct {
-						Open   []float64 `json:"open"`
-						High   []float64 `json:"high"`
-						Low    []float64 `json:"low"`
-						Close  []float64 `json:"close"`
-						Volume []int64   `json:"volume"`
+						Open   []*float64 `json:"open"`
+						High   []*float64 `json:"high"`
+						Low    []*float64 `json:"low"`
+						Close  []*float64 `json:"close"`
+						Volume []*int64   `json:"volume"`
 					} `json:"quote"`
 				} `json:"indicators"`
 			} `json:"result"`
@@ -451,11 +603,11 @@ func TestYahooProvider_Provide_EmptyResult(t *testing.T) {
 				Timestamp  []int64 `json:"timestamp"`
 				Indicators struct {
 					Quote []struct {
-						Open   []float64 `json:"open"`
-						High   []float64 `json:"high"`
-						Low    []float64 `json:"low"`
-						Close  []float64 `json:"close"`
-						Volume []int64   `json:"volume"`
+						Open   []*float64 `json:"open"`
+						High   []*float64 `json:"high"`
+						Low    []*float64 `json:"low"`
+						Close  []*float64 `json:"close"`
+						Volume []*int64   `json:"volume"`
 					} `json:"quote"`
 				} `json:"indicators"`
 			}{},
@@ -493,11 +645,11 @@ func TestYahooProvider_Provide_ErrorInResponse(t *testing.T) {
 				Timestamp  []int64 `json:"timestamp"`
 				Indicators struct {
 					Quote []struct {
-						Open   []float64 `json:"open"`
-						High   []float64 `json:"high"`
-						Low    []float64 `json:"low"`
-						Close  []float64 `json:"close"`
-						Volume []int64   `json:"volume"`
+						Open   []*float64 `json:"open"`
+						High   []*float64 `json:"high"`
+						Low    []*float64 `json:"low"`
+						Close  []*float64 `json:"close"`
+						Volume []*int64   `json:"volume"`
 					} `json:"quote"`
 				} `json:"indicators"`
 			} `json:"result"`
@@ -507,11 +659,11 @@ func TestYahooProvider_Provide_ErrorInResponse(t *testing.T) {
 				Timestamp  []int64 `json:"timestamp"`
 				Indicators struct {
 					Quote []struct {
-						Open   []float64 `json:"open"`
-						High   []float64 `json:"high"`
-						Low    []float64 `json:"low"`
-						Close  []float64 `json:"close"`
-						Volume []int64   `json:"volume"`
+						Open   []*float64 `json:"open"`
+						High   []*float64 `json:"high"`
+						Low    []*float64 `json:"low"`
+						Close  []*float64 `json:"close"`
+						Volume []*int64   `json:"volume"`
 					} `json:"quote"`
 				} `json:"indicators"`
 			}{},
@@ -557,7 +709,10 @@ func TestYahooProvider_FormatSymbol(t *testing.T) {
 		{"INFY", types.ExchangeNSE, "INFY.NS"},
 		{"RELIANCE", types.ExchangeBSE, "RELIANCE.BO"},
 		{"TCS", types.ExchangeBSE, "TCS.BO"},
-		{"AAPL", types.Exchange("NASDAQ"), "AAPL"},
+		{"M&M.NS", types.ExchangeNSE, "M&M.NS"},
+		{"TCS.BO", types.ExchangeBSE, "TCS.BO"},
+		{"AAPL", types.ExchangeNASDAQ, "AAPL"},
+		{"BRK.B", types.ExchangeNYSE, "BRK-B"},
 		{"GOOGL", types.Exchange("UNKNOWN"), "GOOGL"},
 	}
 
@@ -638,7 +793,7 @@ func TestYahooProvider_Round2(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(fmt.Sprintf("round2(%f)", tc.input), func(t *testing.T) {
-			result := provider.round2(tc.input)
+			result := provider.round(tc.input)
 			if result != tc.expected {
 				t.Errorf("round2(%f) = %f, expected %f", tc.input, result, tc.expected)
 			}
@@ -681,3 +836,199 @@ func TestYahooProvider_AllIntervals(t *testing.T) {
 		})
 	}
 }
+
+func TestYahooProvider_MaxRange(t *testing.T) {
+	provider := &YahooProvider{}
+
+	if provider.MaxRange(types.Interval1m) != 7*24*time.Hour {
+		t.Errorf("Unexpected 1m max range %v", provider.MaxRange(types.Interval1m))
+	}
+	if provider.MaxRange(types.Interval15m) <= provider.MaxRange(types.Interval1m) {
+		t.Error("Expected 15m max range to exceed 1m")
+	}
+	if provider.MaxRange(types.Interval1d) != 0 {
+		t.Error("Expected daily data to be uncapped")
+	}
+}
+
+func TestYahooProvider_MaxHistory(t *testing.T) {
+	p := &YahooProvider{}
+
+	tests := []struct {
+		interval types.Interval
+		expected time.Duration
+	}{
+		{types.Interval1m, 30 * 24 * time.Hour},
+		{types.Interval15m, 60 * 24 * time.Hour},
+		{"90m", 60 * 24 * time.Hour},
+		{types.Interval1h, 730 * 24 * time.Hour},
+		{"4h", 0},
+		{types.Interval1d, 0},
+	}
+	for _, tc := range tests {
+		if got := p.MaxHistory(tc.interval); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.interval, tc.expected, got)
+		}
+	}
+}
+
+func TestYahooProvider_Provide_UnknownInterval(t *testing.T) {
+	mockClient := NewMockHTTPClient(nil)
+	p := &YahooProvider{client: mockClient}
+
+	_, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, "4h", time.Now().AddDate(0, 0, -5), time.Now())
+	if !errors.Is(err, provider.ErrUnknownInterval) {
+		t.Errorf("Expected ErrUnknownInterval, got %v", err)
+	}
+	if mockClient.calledCount != 0 {
+		t.Errorf("Expected no request, got %d", mockClient.calledCount)
+	}
+}
+
+func TestYahooProvider_Provide_TypedErrors(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour)
+	to := time.Now()
+
+	t.Run("RateLimited", func(t *testing.T) {
+		p := NewYahooProvider()
+		p.client = NewMockHTTPClient([]*http.Response{createErrorResponse(429, "Too Many Requests")})
+
+		_, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1m, from, to)
+
+		if !errors.Is(err, provider.ErrRateLimited) {
+			t.Errorf("Expected ErrRateLimited, got %v", err)
+		}
+		var pe *provider.ProviderError
+		if !errors.As(err, &pe) || pe.Provider != "yahoo" || pe.Body != "Too Many Requests" {
+			t.Errorf("Expected ProviderError from yahoo, got %#v", pe)
+		}
+	})
+
+	t.Run("NoData", func(t *testing.T) {
+		p := NewYahooProvider()
+		p.client = NewMockHTTPClient([]*http.Response{createErrorResponse(200, `{"chart":{"result":[],"error":null}}`)})
+
+		_, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1m, from, to)
+
+		if !errors.Is(err, provider.ErrNoData) {
+			t.Errorf("Expected ErrNoData, got %v", err)
+		}
+	})
+}
+
+func TestNewYahooProvider_WithPrecision(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return createMockYahooResponse([]int64{1704167100}, []float64{0.123456}, []float64{0.125}, []float64{0.12}, []float64{0.1234}, []int64{1000}), nil
+	})}
+
+	p := NewYahooProvider(WithHTTPClient(client), WithPrecision(provider.Decimals(4)))
+
+	data, err := p.Provide(context.Background(), "IDEA", types.ExchangeNSE, types.Interval1d, time.Now().Add(-48*time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data[0].Open != 0.1235 || data[0].Close != 0.1234 {
+		t.Errorf("Expected prices rounded to 4 places, got open %v close %v", data[0].Open, data[0].Close)
+	}
+}
+
+func TestYahooProvider_Provide_NullPolicy(t *testing.T) {
+	body := `{"chart":{"result":[{"timestamp":[1704167100,1704167160,1704167220,1704167280],"indicators":{` +
+		`"quote":[{"open":[null,100,null,102],"high":[null,101,null,103],"low":[null,99,null,101],` +
+		`"close":[null,100.5,null,102.5],"volume":[null,10,null,30]}]}}],"error":null}}`
+
+	tests := []struct {
+		name     string
+		policy   NullPolicy
+		expected []types.OHLCV
+	}{
+		{"Drop", NullDrop, []types.OHLCV{
+			{Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 10},
+			{Open: 102, High: 103, Low: 101, Close: 102.5, Volume: 30},
+		}},
+		{"ForwardFill", NullForwardFill, []types.OHLCV{
+			{Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 10},
+			{Open: 100.5, High: 100.5, Low: 100.5, Close: 100.5, Incomplete: true},
+			{Open: 102, High: 103, Low: 101, Close: 102.5, Volume: 30},
+		}},
+		{"Keep", NullKeep, []types.OHLCV{
+			{Incomplete: true},
+			{Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 10},
+			{Incomplete: true},
+			{Open: 102, High: 103, Low: 101, Close: 102.5, Volume: 30},
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := NewMockHTTPClient([]*http.Response{{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(body)),
+				Header:     make(http.Header),
+			}})
+			p := &YahooProvider{client: mockClient, nullPolicy: tc.policy}
+
+			from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+			ohlcvs, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1m, from, from.AddDate(0, 0, 1))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(ohlcvs) != len(tc.expected) {
+				t.Fatalf("Expected %d candles, got %d", len(tc.expected), len(ohlcvs))
+			}
+			for i, want := range tc.expected {
+				got := ohlcvs[i]
+				if got.Open != want.Open || got.High != want.High || got.Low != want.Low || got.Close != want.Close ||
+					got.Volume != want.Volume || got.IsComplete() == want.Incomplete {
+					t.Errorf("Candle %d: expected %+v, got %+v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestYahooProvider_Provide_MissingQuote(t *testing.T) {
+	body := `{"chart":{"result":[{"timestamp":[1704167100],"indicators":{"quote":[]}}],"error":null}}`
+	mockClient := NewMockHTTPClient([]*http.Response{{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}})
+	p := &YahooProvider{client: mockClient}
+
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := p.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, from, from.AddDate(0, 0, 1))
+	if !errors.Is(err, provider.ErrNoData) {
+		t.Errorf("Expected ErrNoData, got %v", err)
+	}
+}
+
+func TestYahooProvider_Provide_ExtendedHours(t *testing.T) {
+	// 08:00, 10:00 and 17:00 New York time on 2024-01-03.
+	timestamps := []int64{1704286800, 1704294000, 1704319200}
+	prices := []float64{100, 100, 100}
+	mockClient := NewMockHTTPClient([]*http.Response{
+		createMockYahooResponse(timestamps, prices, prices, prices, prices, []int64{10, 10, 10}),
+	})
+	p := &YahooProvider{client: mockClient, extendedHours: true}
+
+	from := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	ohlcvs, err := p.Provide(context.Background(), "AAPL", types.ExchangeNASDAQ, types.Interval1m, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if q := mockClient.requests[0].URL.Query(); q.Get("includePrePost") != "true" {
+		t.Errorf("Expected extended hours to be requested, got %s", mockClient.requests[0].URL.RawQuery)
+	}
+	if len(ohlcvs) != 3 {
+		t.Fatalf("Expected 3 candles, got %d", len(ohlcvs))
+	}
+	want := []types.TradingSession{types.SessionPre, types.SessionRegular, types.SessionPost}
+	for i, c := range ohlcvs {
+		if c.Session != want[i] {
+			t.Errorf("Expected candle %d in session %q, got %q", i, want[i], c.Session)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shahid-2020/gohlcv/provider"
+)
+
+// errorCodes names the errors a server sends its clients, in the JSON of
+// HTTPHandler and the status details of the gRPC service, so that remote
+// providers return the same sentinel the server's MarketData did. An error
+// matching several, such as errors joined over a provider chain, gets the
+// code of the first.
+var errorCodes = []struct {
+	code string
+	err  error
+}{
+	{"deadline_exceeded", context.DeadlineExceeded},
+	{"canceled", context.Canceled},
+	{"symbol_not_found", provider.ErrSymbolNotFound},
+	{"invalid_symbol", provider.ErrInvalidSymbol},
+	{"invalid_range", provider.ErrInvalidRange},
+	{"unknown_interval", provider.ErrUnknownInterval},
+	{"unsupported_exchange", provider.ErrUnsupportedExchange},
+	{"adjusted_unsupported", provider.ErrAdjustedUnsupported},
+	{"rate_limited", provider.ErrRateLimited},
+	{"no_data", provider.ErrNoData},
+	{"response_too_large", provider.ErrResponseTooLarge},
+	{"provider_unavailable", provider.ErrProviderUnavailable},
+}
+
+// errorDomain is the ErrorInfo domain of the codes sent over gRPC.
+const errorDomain = "gohlcv"
+
+// errorCode returns the code of err, or "" if it matches no known error.
+func errorCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ""
+}
+
+// errorOfCode returns an error matching the sentinel named by code, with
+// msg, the message the server sent. ok is false for unknown codes.
+func errorOfCode(code, msg string) (err error, ok bool) {
+	for _, c := range errorCodes {
+		if c.code == code {
+			return fmt.Errorf("%w: %s", c.err, msg), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// candlesResponse is the JSON body of GET /v1/candles.
+type candlesResponse struct {
+	Candles []types.OHLCV `json:"candles"`
+}
+
+// errorResponse is the JSON body of a failed request. Code names the
+// provider error it matches, as listed in errorCodes, if any.
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// HTTPHandler serves s's MarketData as JSON over plain HTTP, for clients
+// that can't speak gRPC, such as HTTPRemoteProvider behind an HTTP-only load
+// balancer:
+//
+//	GET /v1/candles?symbol=RELIANCE&interval=1d&start=...&end=...&exchange=NSE
+//	GET /v1/quote?symbol=RELIANCE&exchange=NSE
+//
+// start and end are RFC 3339 times and, like exchange, optional. Errors are
+// answered with {"error": "...", "code": "..."} and the status code of
+// httpStatus, the code naming the provider error matched, such as
+// "symbol_not_found".
+func (s *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/candles", s.serveCandles)
+	mux.HandleFunc("GET /v1/quote", s.serveQuote)
+	return mux
+}
+
+func (s *Server) serveCandles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("symbol") == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "symbol is required"})
+		return
+	}
+
+	start, err := queryTime(q.Get("start"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid start: " + err.Error()})
+		return
+	}
+	end, err := queryTime(q.Get("end"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid end: " + err.Error()})
+		return
+	}
+
+	data, err := s.md.Fetch(r.Context(), q.Get("symbol"), types.Interval(q.Get("interval")), start, end, exchangeOf(q.Get("exchange"))...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, candlesResponse{Candles: data})
+}
+
+func (s *Server) serveQuote(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("symbol") == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "symbol is required"})
+		return
+	}
+
+	quote, err := s.md.Quote(r.Context(), q.Get("symbol"), exchangeOf(q.Get("exchange"))...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quote)
+}
+
+// queryTime parses an RFC 3339 time, treating an empty one as the zero time.
+func queryTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, httpStatus(err), errorResponse{Error: err.Error(), Code: errorCode(err)})
+}
+
+// httpStatus maps MarketData errors to HTTP status codes, as toStatus does to
+// gRPC codes.
+func httpStatus(err error) int {
+	var pe *provider.ProviderError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, provider.ErrSymbolNotFound), errors.Is(err, provider.ErrNoData):
+		return http.StatusNotFound
+	case errors.Is(err, provider.ErrUnknownInterval), errors.Is(err, provider.ErrInvalidSymbol),
+		errors.Is(err, provider.ErrInvalidRange), errors.Is(err, provider.ErrUnsupportedExchange),
+		errors.Is(err, provider.ErrAdjustedUnsupported):
+		return http.StatusBadRequest
+	case errors.Is(err, provider.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, provider.ErrProviderUnavailable), errors.As(err, &pe):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,138 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// HTTPRemoteProvider is RemoteProvider over plain HTTP and JSON: a provider
+// backed by another gohlcv instance serving Server.HTTPHandler, for
+// deployments where gRPC doesn't reach the gateway.
+type HTTPRemoteProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type HTTPRemoteOption func(*HTTPRemoteProvider)
+
+// WithHTTPClient sets the client requests are sent with, by default one with
+// a 30 second timeout.
+func WithHTTPClient(c *http.Client) HTTPRemoteOption {
+	return func(h *HTTPRemoteProvider) {
+		h.httpClient = c
+	}
+}
+
+// NewHTTPRemoteProvider returns a provider fetching from the gohlcv instance
+// whose HTTPHandler is served at baseURL, such as "http://gateway:8080".
+func NewHTTPRemoteProvider(baseURL string, opts ...HTTPRemoteOption) *HTTPRemoteProvider {
+	h := &HTTPRemoteProvider{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: &http.Client{Timeout: 30 * time.Second}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *HTTPRemoteProvider) Name() string {
+	return "httpremote"
+}
+
+func (h *HTTPRemoteProvider) Freshness() types.DataFreshness {
+	return types.FreshnessDelayed
+}
+
+// Provide fetches the range from the remote instance, keeping the source and
+// freshness each candle had there.
+func (h *HTTPRemoteProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	q := url.Values{"symbol": {symbol}, "exchange": {string(exchange)}, "interval": {string(interval)}}
+	if !from.IsZero() {
+		q.Set("start", from.Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		q.Set("end", to.Format(time.RFC3339Nano))
+	}
+
+	var resp candlesResponse
+	if err := h.get(ctx, "/v1/candles", q, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Candles) == 0 {
+		return nil, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+
+	loc := exchange.Location()
+	for i := range resp.Candles {
+		resp.Candles[i].DateTime = resp.Candles[i].DateTime.In(loc)
+		resp.Candles[i].Interval = interval
+	}
+	return resp.Candles, nil
+}
+
+// Quote returns the remote instance's latest quote of symbol.
+func (h *HTTPRemoteProvider) Quote(ctx context.Context, symbol string, exchange types.Exchange) (types.Quote, error) {
+	var quote types.Quote
+	if err := h.get(ctx, "/v1/quote", url.Values{"symbol": {symbol}, "exchange": {string(exchange)}}, &quote); err != nil {
+		return types.Quote{}, err
+	}
+	quote.DateTime = quote.DateTime.In(exchange.Location())
+	return quote, nil
+}
+
+// get decodes the JSON answer to path into out, mapping errors back to the
+// provider errors the server answered them for.
+func (h *HTTPRemoteProvider) get(ctx context.Context, path string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", provider.ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body errorResponse
+		json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body)
+		return fromHTTPError(resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, provider.DefaultMaxResponseSize)).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode remote response: %w", err)
+	}
+	return nil
+}
+
+// fromHTTPError returns the provider error named by body's code or, from
+// servers that don't send one, the error its status code best matches.
+func fromHTTPError(code int, body errorResponse) error {
+	msg := body.Error
+	if err, ok := errorOfCode(body.Code, msg); ok {
+		return err
+	}
+
+	switch code {
+	case http.StatusGatewayTimeout:
+		return fmt.Errorf("%w: %s", context.DeadlineExceeded, msg)
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", provider.ErrNoData, msg)
+	case http.StatusBadRequest:
+		return fmt.Errorf("%w: %s", provider.ErrUnknownInterval, msg)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", provider.ErrRateLimited, msg)
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return fmt.Errorf("%w: %s", provider.ErrProviderUnavailable, msg)
+	default:
+		return fmt.Errorf("remote error: status %d: %s", code, msg)
+	}
+}
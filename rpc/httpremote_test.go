@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func newHTTPRemote(t *testing.T, p provider.OHLCVProvider) *HTTPRemoteProvider {
+	t.Helper()
+
+	server := httptest.NewServer(NewServer(marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p))).HTTPHandler())
+	t.Cleanup(server.Close)
+	return NewHTTPRemoteProvider(server.URL+"/", WithHTTPClient(server.Client()))
+}
+
+func TestHTTPRemoteProvider_Provide(t *testing.T) {
+	remote := newHTTPRemote(t, &mockProvider{data: sampleCandles()})
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	data, err := remote.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, start, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := sampleCandles()
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d candles, got %d", len(want), len(data))
+	}
+	for i := range want {
+		if !data[i].DateTime.Equal(want[i].DateTime) || data[i].Close != want[i].Close || data[i].Volume != want[i].Volume {
+			t.Errorf("Expected %+v, got %+v", want[i], data[i])
+		}
+		if data[i].Source != "mock" || data[i].Freshness != types.FreshnessHistorical || data[i].Interval != types.Interval1d {
+			t.Errorf("Expected remote source, freshness and the interval, got %+v", data[i])
+		}
+		if data[i].DateTime.Location().String() != "Asia/Kolkata" {
+			t.Errorf("Expected Asia/Kolkata location, got %s", data[i].DateTime.Location())
+		}
+	}
+}
+
+func TestHTTPRemoteProvider_Provide_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"SymbolNotFound", provider.ErrSymbolNotFound, provider.ErrSymbolNotFound},
+		{"NoData", provider.ErrNoData, provider.ErrNoData},
+		{"InvalidRange", provider.ErrInvalidRange, provider.ErrInvalidRange},
+		{"UnsupportedExchange", provider.ErrUnsupportedExchange, provider.ErrUnsupportedExchange},
+		{"RateLimited", provider.ErrRateLimited, provider.ErrRateLimited},
+		{"Unavailable", &provider.ProviderError{Provider: "mock", StatusCode: 503}, provider.ErrProviderUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newHTTPRemote(t, &mockProvider{err: tt.err})
+
+			start := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+			_, err := remote.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, start, start.AddDate(0, 0, 2))
+
+			// The error must be the sentinel itself, not one sharing its
+			// status code.
+			if !errors.Is(err, tt.target) || errorCode(err) != errorCode(tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestFromHTTPError_StatusOnly(t *testing.T) {
+	err := fromHTTPError(404, errorResponse{Error: "no candles"})
+	if !errors.Is(err, provider.ErrNoData) {
+		t.Errorf("Expected %v from a status without a code, got %v", provider.ErrNoData, err)
+	}
+}
+
+func TestHTTPRemoteProvider_Quote(t *testing.T) {
+	quote := types.Quote{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, LastPrice: 1375.5, Volume: 5000, DateTime: time.Date(2024, 1, 2, 15, 30, 0, 0, ist), Source: "mock"}
+	remote := newHTTPRemote(t, &mockProvider{quote: quote})
+
+	got, err := remote.Quote(context.Background(), "RELIANCE", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.LastPrice != 1375.5 || got.Volume != 5000 || got.Source != "mock" || !got.DateTime.Equal(quote.DateTime) {
+		t.Errorf("Unexpected quote: %+v", got)
+	}
+}
+
+func TestHTTPRemoteProvider_FallsBack(t *testing.T) {
+	remote := newHTTPRemote(t, &mockProvider{err: provider.ErrRateLimited})
+	local := &mockProvider{data: sampleCandles()}
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(remote, local))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 2 {
+		t.Errorf("Expected 2 candles from the fallback, got %d", len(data))
+	}
+}
+
+func TestHTTPHandler_BadRequest(t *testing.T) {
+	server := httptest.NewServer(NewServer(marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(&mockProvider{}))).HTTPHandler())
+	defer server.Close()
+
+	for _, path := range []string{"/v1/candles?interval=1d", "/v1/candles?symbol=INFY&start=yesterday", "/v1/quote"} {
+		resp, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 400 {
+			t.Errorf("Expected 400 for %s, got %d", path, resp.StatusCode)
+		}
+	}
+}
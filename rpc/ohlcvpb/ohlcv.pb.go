@@ -0,0 +1,605 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.29.3
+// source: rpc/ohlcvpb/ohlcv.proto
+
+package ohlcvpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FetchRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// exchange is a gohlcv exchange such as "NSE", "NASDAQ" or "BINANCE";
+	// empty means the server's default exchange.
+	Exchange string `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	// interval is a gohlcv interval such as "1m", "1d" or "1wk".
+	Interval string `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	// start defaults to the beginning of the current day.
+	Start *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	// end defaults to now.
+	End           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchRequest) Reset() {
+	*x = FetchRequest{}
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchRequest) ProtoMessage() {}
+
+func (x *FetchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchRequest.ProtoReflect.Descriptor instead.
+func (*FetchRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_ohlcvpb_ohlcv_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FetchRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *FetchRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *FetchRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *FetchRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *FetchRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type FetchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candles       []*Candle              `protobuf:"bytes,1,rep,name=candles,proto3" json:"candles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchResponse) Reset() {
+	*x = FetchResponse{}
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchResponse) ProtoMessage() {}
+
+func (x *FetchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchResponse.ProtoReflect.Descriptor instead.
+func (*FetchResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_ohlcvpb_ohlcv_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FetchResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type Candle struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Symbol           string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange         string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Open             float64                `protobuf:"fixed64,3,opt,name=open,proto3" json:"open,omitempty"`
+	High             float64                `protobuf:"fixed64,4,opt,name=high,proto3" json:"high,omitempty"`
+	Low              float64                `protobuf:"fixed64,5,opt,name=low,proto3" json:"low,omitempty"`
+	Close            float64                `protobuf:"fixed64,6,opt,name=close,proto3" json:"close,omitempty"`
+	Volume           int64                  `protobuf:"varint,7,opt,name=volume,proto3" json:"volume,omitempty"`
+	Datetime         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Source           string                 `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`
+	Freshness        string                 `protobuf:"bytes,10,opt,name=freshness,proto3" json:"freshness,omitempty"`
+	AdjustmentFactor float64                `protobuf:"fixed64,11,opt,name=adjustment_factor,json=adjustmentFactor,proto3" json:"adjustment_factor,omitempty"`
+	// open_interest is the number of derivative contracts outstanding at the
+	// close of the candle, or zero if unknown.
+	OpenInterest int64 `protobuf:"varint,12,opt,name=open_interest,json=openInterest,proto3" json:"open_interest,omitempty"`
+	// trade_count is the number of trades in the candle, or zero if unknown.
+	TradeCount int64 `protobuf:"varint,13,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	// interval is the bar size of the candle, such as "1m" or "1d".
+	Interval string `protobuf:"bytes,14,opt,name=interval,proto3" json:"interval,omitempty"`
+	// session is "pre", "regular" or "post" for intraday candles, or empty.
+	Session string `protobuf:"bytes,15,opt,name=session,proto3" json:"session,omitempty"`
+	// incomplete is set on candles the provider reported with missing values.
+	Incomplete    bool `protobuf:"varint,16,opt,name=incomplete,proto3" json:"incomplete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_rpc_ohlcvpb_ohlcv_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Candle) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Candle) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Candle) GetDatetime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Datetime
+	}
+	return nil
+}
+
+func (x *Candle) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Candle) GetFreshness() string {
+	if x != nil {
+		return x.Freshness
+	}
+	return ""
+}
+
+func (x *Candle) GetAdjustmentFactor() float64 {
+	if x != nil {
+		return x.AdjustmentFactor
+	}
+	return 0
+}
+
+func (x *Candle) GetOpenInterest() int64 {
+	if x != nil {
+		return x.OpenInterest
+	}
+	return 0
+}
+
+func (x *Candle) GetTradeCount() int64 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *Candle) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *Candle) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *Candle) GetIncomplete() bool {
+	if x != nil {
+		return x.Incomplete
+	}
+	return false
+}
+
+type QuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteRequest) Reset() {
+	*x = QuoteRequest{}
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteRequest) ProtoMessage() {}
+
+func (x *QuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteRequest.ProtoReflect.Descriptor instead.
+func (*QuoteRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_ohlcvpb_ohlcv_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QuoteRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *QuoteRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+type QuoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	LastPrice     float64                `protobuf:"fixed64,3,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
+	Open          float64                `protobuf:"fixed64,4,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,5,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,6,opt,name=low,proto3" json:"low,omitempty"`
+	PreviousClose float64                `protobuf:"fixed64,7,opt,name=previous_close,json=previousClose,proto3" json:"previous_close,omitempty"`
+	Volume        int64                  `protobuf:"varint,8,opt,name=volume,proto3" json:"volume,omitempty"`
+	Datetime      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Source        string                 `protobuf:"bytes,10,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteResponse) Reset() {
+	*x = QuoteResponse{}
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteResponse) ProtoMessage() {}
+
+func (x *QuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ohlcvpb_ohlcv_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteResponse.ProtoReflect.Descriptor instead.
+func (*QuoteResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_ohlcvpb_ohlcv_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QuoteResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *QuoteResponse) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *QuoteResponse) GetLastPrice() float64 {
+	if x != nil {
+		return x.LastPrice
+	}
+	return 0
+}
+
+func (x *QuoteResponse) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *QuoteResponse) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *QuoteResponse) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *QuoteResponse) GetPreviousClose() float64 {
+	if x != nil {
+		return x.PreviousClose
+	}
+	return 0
+}
+
+func (x *QuoteResponse) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *QuoteResponse) GetDatetime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Datetime
+	}
+	return nil
+}
+
+func (x *QuoteResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+var File_rpc_ohlcvpb_ohlcv_proto protoreflect.FileDescriptor
+
+const file_rpc_ohlcvpb_ohlcv_proto_rawDesc = "" +
+	"\n" +
+	"\x17rpc/ohlcvpb/ohlcv.proto\x12\tgohlcv.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbe\x01\n" +
+	"\fFetchRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\tR\binterval\x120\n" +
+	"\x05start\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"<\n" +
+	"\rFetchResponse\x12+\n" +
+	"\acandles\x18\x01 \x03(\v2\x11.gohlcv.v1.CandleR\acandles\"\xdb\x03\n" +
+	"\x06Candle\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x12\n" +
+	"\x04open\x18\x03 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x04 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x05 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x06 \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\a \x01(\x03R\x06volume\x126\n" +
+	"\bdatetime\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bdatetime\x12\x16\n" +
+	"\x06source\x18\t \x01(\tR\x06source\x12\x1c\n" +
+	"\tfreshness\x18\n" +
+	" \x01(\tR\tfreshness\x12+\n" +
+	"\x11adjustment_factor\x18\v \x01(\x01R\x10adjustmentFactor\x12#\n" +
+	"\ropen_interest\x18\f \x01(\x03R\fopenInterest\x12\x1f\n" +
+	"\vtrade_count\x18\r \x01(\x03R\n" +
+	"tradeCount\x12\x1a\n" +
+	"\binterval\x18\x0e \x01(\tR\binterval\x12\x18\n" +
+	"\asession\x18\x0f \x01(\tR\asession\x12\x1e\n" +
+	"\n" +
+	"incomplete\x18\x10 \x01(\bR\n" +
+	"incomplete\"B\n" +
+	"\fQuoteRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\"\xab\x02\n" +
+	"\rQuoteResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x1d\n" +
+	"\n" +
+	"last_price\x18\x03 \x01(\x01R\tlastPrice\x12\x12\n" +
+	"\x04open\x18\x04 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x05 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x06 \x01(\x01R\x03low\x12%\n" +
+	"\x0eprevious_close\x18\a \x01(\x01R\rpreviousClose\x12\x16\n" +
+	"\x06volume\x18\b \x01(\x03R\x06volume\x126\n" +
+	"\bdatetime\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bdatetime\x12\x16\n" +
+	"\x06source\x18\n" +
+	" \x01(\tR\x06source2\xc3\x01\n" +
+	"\fOHLCVService\x12:\n" +
+	"\x05Fetch\x12\x17.gohlcv.v1.FetchRequest\x1a\x18.gohlcv.v1.FetchResponse\x12;\n" +
+	"\vFetchStream\x12\x17.gohlcv.v1.FetchRequest\x1a\x11.gohlcv.v1.Candle0\x01\x12:\n" +
+	"\x05Quote\x12\x17.gohlcv.v1.QuoteRequest\x1a\x18.gohlcv.v1.QuoteResponseB+Z)github.com/shahid-2020/gohlcv/rpc/ohlcvpbb\x06proto3"
+
+var (
+	file_rpc_ohlcvpb_ohlcv_proto_rawDescOnce sync.Once
+	file_rpc_ohlcvpb_ohlcv_proto_rawDescData []byte
+)
+
+func file_rpc_ohlcvpb_ohlcv_proto_rawDescGZIP() []byte {
+	file_rpc_ohlcvpb_ohlcv_proto_rawDescOnce.Do(func() {
+		file_rpc_ohlcvpb_ohlcv_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_ohlcvpb_ohlcv_proto_rawDesc), len(file_rpc_ohlcvpb_ohlcv_proto_rawDesc)))
+	})
+	return file_rpc_ohlcvpb_ohlcv_proto_rawDescData
+}
+
+var file_rpc_ohlcvpb_ohlcv_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_rpc_ohlcvpb_ohlcv_proto_goTypes = []any{
+	(*FetchRequest)(nil),          // 0: gohlcv.v1.FetchRequest
+	(*FetchResponse)(nil),         // 1: gohlcv.v1.FetchResponse
+	(*Candle)(nil),                // 2: gohlcv.v1.Candle
+	(*QuoteRequest)(nil),          // 3: gohlcv.v1.QuoteRequest
+	(*QuoteResponse)(nil),         // 4: gohlcv.v1.QuoteResponse
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+}
+var file_rpc_ohlcvpb_ohlcv_proto_depIdxs = []int32{
+	5, // 0: gohlcv.v1.FetchRequest.start:type_name -> google.protobuf.Timestamp
+	5, // 1: gohlcv.v1.FetchRequest.end:type_name -> google.protobuf.Timestamp
+	2, // 2: gohlcv.v1.FetchResponse.candles:type_name -> gohlcv.v1.Candle
+	5, // 3: gohlcv.v1.Candle.datetime:type_name -> google.protobuf.Timestamp
+	5, // 4: gohlcv.v1.QuoteResponse.datetime:type_name -> google.protobuf.Timestamp
+	0, // 5: gohlcv.v1.OHLCVService.Fetch:input_type -> gohlcv.v1.FetchRequest
+	0, // 6: gohlcv.v1.OHLCVService.FetchStream:input_type -> gohlcv.v1.FetchRequest
+	3, // 7: gohlcv.v1.OHLCVService.Quote:input_type -> gohlcv.v1.QuoteRequest
+	1, // 8: gohlcv.v1.OHLCVService.Fetch:output_type -> gohlcv.v1.FetchResponse
+	2, // 9: gohlcv.v1.OHLCVService.FetchStream:output_type -> gohlcv.v1.Candle
+	4, // 10: gohlcv.v1.OHLCVService.Quote:output_type -> gohlcv.v1.QuoteResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_rpc_ohlcvpb_ohlcv_proto_init() }
+func file_rpc_ohlcvpb_ohlcv_proto_init() {
+	if File_rpc_ohlcvpb_ohlcv_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_ohlcvpb_ohlcv_proto_rawDesc), len(file_rpc_ohlcvpb_ohlcv_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_ohlcvpb_ohlcv_proto_goTypes,
+		DependencyIndexes: file_rpc_ohlcvpb_ohlcv_proto_depIdxs,
+		MessageInfos:      file_rpc_ohlcvpb_ohlcv_proto_msgTypes,
+	}.Build()
+	File_rpc_ohlcvpb_ohlcv_proto = out.File
+	file_rpc_ohlcvpb_ohlcv_proto_goTypes = nil
+	file_rpc_ohlcvpb_ohlcv_proto_depIdxs = nil
+}
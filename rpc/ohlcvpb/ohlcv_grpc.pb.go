@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: rpc/ohlcvpb/ohlcv.proto
+
+package ohlcvpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OHLCVService_Fetch_FullMethodName       = "/gohlcv.v1.OHLCVService/Fetch"
+	OHLCVService_FetchStream_FullMethodName = "/gohlcv.v1.OHLCVService/FetchStream"
+	OHLCVService_Quote_FullMethodName       = "/gohlcv.v1.OHLCVService/Quote"
+)
+
+// OHLCVServiceClient is the client API for OHLCVService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OHLCVService serves candles and quotes from a gohlcv MarketData.
+type OHLCVServiceClient interface {
+	// Fetch returns every candle of the requested range at once.
+	Fetch(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (*FetchResponse, error)
+	// FetchStream sends candles as they are fetched, a window at a time, so
+	// long ranges never have to be held in memory.
+	FetchStream(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Candle], error)
+	// Quote returns the latest quote of a symbol.
+	Quote(ctx context.Context, in *QuoteRequest, opts ...grpc.CallOption) (*QuoteResponse, error)
+}
+
+type oHLCVServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOHLCVServiceClient(cc grpc.ClientConnInterface) OHLCVServiceClient {
+	return &oHLCVServiceClient{cc}
+}
+
+func (c *oHLCVServiceClient) Fetch(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (*FetchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchResponse)
+	err := c.cc.Invoke(ctx, OHLCVService_Fetch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oHLCVServiceClient) FetchStream(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Candle], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OHLCVService_ServiceDesc.Streams[0], OHLCVService_FetchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FetchRequest, Candle]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OHLCVService_FetchStreamClient = grpc.ServerStreamingClient[Candle]
+
+func (c *oHLCVServiceClient) Quote(ctx context.Context, in *QuoteRequest, opts ...grpc.CallOption) (*QuoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuoteResponse)
+	err := c.cc.Invoke(ctx, OHLCVService_Quote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OHLCVServiceServer is the server API for OHLCVService service.
+// All implementations must embed UnimplementedOHLCVServiceServer
+// for forward compatibility.
+//
+// OHLCVService serves candles and quotes from a gohlcv MarketData.
+type OHLCVServiceServer interface {
+	// Fetch returns every candle of the requested range at once.
+	Fetch(context.Context, *FetchRequest) (*FetchResponse, error)
+	// FetchStream sends candles as they are fetched, a window at a time, so
+	// long ranges never have to be held in memory.
+	FetchStream(*FetchRequest, grpc.ServerStreamingServer[Candle]) error
+	// Quote returns the latest quote of a symbol.
+	Quote(context.Context, *QuoteRequest) (*QuoteResponse, error)
+	mustEmbedUnimplementedOHLCVServiceServer()
+}
+
+// UnimplementedOHLCVServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOHLCVServiceServer struct{}
+
+func (UnimplementedOHLCVServiceServer) Fetch(context.Context, *FetchRequest) (*FetchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Fetch not implemented")
+}
+func (UnimplementedOHLCVServiceServer) FetchStream(*FetchRequest, grpc.ServerStreamingServer[Candle]) error {
+	return status.Errorf(codes.Unimplemented, "method FetchStream not implemented")
+}
+func (UnimplementedOHLCVServiceServer) Quote(context.Context, *QuoteRequest) (*QuoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Quote not implemented")
+}
+func (UnimplementedOHLCVServiceServer) mustEmbedUnimplementedOHLCVServiceServer() {}
+func (UnimplementedOHLCVServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeOHLCVServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OHLCVServiceServer will
+// result in compilation errors.
+type UnsafeOHLCVServiceServer interface {
+	mustEmbedUnimplementedOHLCVServiceServer()
+}
+
+func RegisterOHLCVServiceServer(s grpc.ServiceRegistrar, srv OHLCVServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOHLCVServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OHLCVService_ServiceDesc, srv)
+}
+
+func _OHLCVService_Fetch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OHLCVServiceServer).Fetch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OHLCVService_Fetch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OHLCVServiceServer).Fetch(ctx, req.(*FetchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OHLCVService_FetchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OHLCVServiceServer).FetchStream(m, &grpc.GenericServerStream[FetchRequest, Candle]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OHLCVService_FetchStreamServer = grpc.ServerStreamingServer[Candle]
+
+func _OHLCVService_Quote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OHLCVServiceServer).Quote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OHLCVService_Quote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OHLCVServiceServer).Quote(ctx, req.(*QuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OHLCVService_ServiceDesc is the grpc.ServiceDesc for OHLCVService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OHLCVService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gohlcv.v1.OHLCVService",
+	HandlerType: (*OHLCVServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Fetch",
+			Handler:    _OHLCVService_Fetch_Handler,
+		},
+		{
+			MethodName: "Quote",
+			Handler:    _OHLCVService_Quote_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FetchStream",
+			Handler:       _OHLCVService_FetchStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/ohlcvpb/ohlcv.proto",
+}
@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/rpc/ohlcvpb"
+	"github.com/shahid-2020/gohlcv/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RemoteProvider is a provider backed by another gohlcv instance serving
+// OHLCVService, so edge services can fetch through a central caching gateway
+// with the same MarketData API.
+type RemoteProvider struct {
+	client ohlcvpb.OHLCVServiceClient
+}
+
+// NewRemoteProvider returns a provider fetching through the OHLCVService at
+// conn, usually from grpc.NewClient.
+func NewRemoteProvider(conn grpc.ClientConnInterface) *RemoteProvider {
+	return &RemoteProvider{client: ohlcvpb.NewOHLCVServiceClient(conn)}
+}
+
+func (r *RemoteProvider) Name() string {
+	return "remote"
+}
+
+func (r *RemoteProvider) Freshness() types.DataFreshness {
+	return types.FreshnessDelayed
+}
+
+// Provide streams the range from the remote instance, keeping the source and
+// freshness each candle had there.
+func (r *RemoteProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	stream, err := r.client.FetchStream(ctx, &ohlcvpb.FetchRequest{
+		Symbol:   symbol,
+		Exchange: string(exchange),
+		Interval: string(interval),
+		Start:    timestampOf(from),
+		End:      timestampOf(to),
+	})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	loc := exchange.Location()
+	var ohlcvs []types.OHLCV
+	for {
+		c, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fromStatus(err)
+		}
+		candle := fromCandle(c, loc)
+		if candle.Interval == "" {
+			candle.Interval = interval
+		}
+		ohlcvs = append(ohlcvs, candle)
+	}
+
+	if len(ohlcvs) == 0 {
+		return nil, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+
+	return ohlcvs, nil
+}
+
+// Quote returns the remote instance's latest quote of symbol.
+func (r *RemoteProvider) Quote(ctx context.Context, symbol string, exchange types.Exchange) (types.Quote, error) {
+	resp, err := r.client.Quote(ctx, &ohlcvpb.QuoteRequest{Symbol: symbol, Exchange: string(exchange)})
+	if err != nil {
+		return types.Quote{}, fromStatus(err)
+	}
+
+	return types.Quote{
+		Symbol:        resp.GetSymbol(),
+		Exchange:      types.Exchange(resp.GetExchange()),
+		LastPrice:     resp.GetLastPrice(),
+		Open:          resp.GetOpen(),
+		High:          resp.GetHigh(),
+		Low:           resp.GetLow(),
+		PreviousClose: resp.GetPreviousClose(),
+		Volume:        resp.GetVolume(),
+		DateTime:      inLocation(resp.GetDatetime(), exchange.Location()),
+		Source:        resp.GetSource(),
+	}, nil
+}
+
+// fromStatus maps gRPC statuses back to the provider errors toStatus
+// produced them from, so the fallback chain treats a remote failure like a
+// local one. Statuses without an ErrorInfo detail naming one are mapped by
+// code.
+func fromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain {
+			if err, ok := errorOfCode(info.GetReason(), s.Message()); ok {
+				return err
+			}
+		}
+	}
+
+	switch s.Code() {
+	case codes.Canceled:
+		return fmt.Errorf("%w: %s", context.Canceled, s.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", context.DeadlineExceeded, s.Message())
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", provider.ErrNoData, s.Message())
+	case codes.InvalidArgument:
+		return fmt.Errorf("%w: %s", provider.ErrUnknownInterval, s.Message())
+	case codes.ResourceExhausted:
+		return fmt.Errorf("%w: %s", provider.ErrRateLimited, s.Message())
+	case codes.Unavailable:
+		return fmt.Errorf("%w: %s", provider.ErrProviderUnavailable, s.Message())
+	default:
+		return fmt.Errorf("remote error: %w", err)
+	}
+}
+
+func fromCandle(c *ohlcvpb.Candle, loc *time.Location) types.OHLCV {
+	return types.OHLCV{
+		Symbol:           c.GetSymbol(),
+		Exchange:         types.Exchange(c.GetExchange()),
+		Open:             c.GetOpen(),
+		High:             c.GetHigh(),
+		Low:              c.GetLow(),
+		Close:            c.GetClose(),
+		Volume:           c.GetVolume(),
+		DateTime:         inLocation(c.GetDatetime(), loc),
+		Source:           c.GetSource(),
+		Freshness:        types.DataFreshness(c.GetFreshness()),
+		AdjustmentFactor: c.GetAdjustmentFactor(),
+		OpenInterest:     c.GetOpenInterest(),
+		TradeCount:       c.GetTradeCount(),
+		Interval:         types.Interval(c.GetInterval()),
+		Session:          types.TradingSession(c.GetSession()),
+		Incomplete:       c.GetIncomplete(),
+	}
+}
+
+func inLocation(ts *timestamppb.Timestamp, loc *time.Location) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime().In(loc)
+}
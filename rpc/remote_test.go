@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRemoteProvider_Provide(t *testing.T) {
+	remote := NewRemoteProvider(newConn(t, &mockProvider{data: sampleCandles()}))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	data, err := remote.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, start, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := sampleCandles()
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d candles, got %d", len(want), len(data))
+	}
+	for i := range want {
+		if !data[i].DateTime.Equal(want[i].DateTime) || data[i].Close != want[i].Close || data[i].Volume != want[i].Volume {
+			t.Errorf("Expected %+v, got %+v", want[i], data[i])
+		}
+		if data[i].Source != "mock" || data[i].Freshness != types.FreshnessHistorical {
+			t.Errorf("Expected remote source and freshness to be kept, got %+v", data[i])
+		}
+		if data[i].DateTime.Location().String() != "Asia/Kolkata" {
+			t.Errorf("Expected Asia/Kolkata location, got %s", data[i].DateTime.Location())
+		}
+	}
+}
+
+func TestRemoteProvider_Provide_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"SymbolNotFound", provider.ErrSymbolNotFound, provider.ErrSymbolNotFound},
+		{"NoData", provider.ErrNoData, provider.ErrNoData},
+		{"InvalidRange", provider.ErrInvalidRange, provider.ErrInvalidRange},
+		{"RateLimited", provider.ErrRateLimited, provider.ErrRateLimited},
+		{"Unavailable", &provider.ProviderError{Provider: "mock", StatusCode: 503}, provider.ErrProviderUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := NewRemoteProvider(newConn(t, &mockProvider{err: tt.err}))
+
+			start := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+			_, err := remote.Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, start, start.AddDate(0, 0, 2))
+
+			if !errors.Is(err, tt.target) || errorCode(err) != errorCode(tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestFromStatus_RoundTrip(t *testing.T) {
+	sentinels := []error{
+		context.Canceled,
+		context.DeadlineExceeded,
+		provider.ErrSymbolNotFound,
+		provider.ErrRateLimited,
+		provider.ErrNoData,
+		provider.ErrProviderUnavailable,
+		provider.ErrUnknownInterval,
+		provider.ErrAdjustedUnsupported,
+		provider.ErrResponseTooLarge,
+		provider.ErrInvalidRange,
+		provider.ErrInvalidSymbol,
+		provider.ErrUnsupportedExchange,
+	}
+
+	for _, want := range sentinels {
+		err := fromStatus(toStatus(fmt.Errorf("fetching: %w", want)))
+		if !errors.Is(err, want) {
+			t.Errorf("Expected %v, got %v", want, err)
+		}
+		for _, other := range sentinels {
+			if other != want && errors.Is(err, other) {
+				t.Errorf("Expected %v not to also match %v", want, other)
+			}
+		}
+	}
+}
+
+func TestFromStatus_CodeOnly(t *testing.T) {
+	err := fromStatus(status.Error(codes.NotFound, "no candles"))
+	if !errors.Is(err, provider.ErrNoData) {
+		t.Errorf("Expected %v from a status without details, got %v", provider.ErrNoData, err)
+	}
+}
+
+func TestRemoteProvider_Quote(t *testing.T) {
+	quote := types.Quote{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, LastPrice: 1375.5, Volume: 5000, DateTime: time.Date(2024, 1, 2, 15, 30, 0, 0, ist), Source: "mock"}
+	remote := NewRemoteProvider(newConn(t, &mockProvider{quote: quote}))
+
+	got, err := remote.Quote(context.Background(), "RELIANCE", types.ExchangeNSE)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.LastPrice != 1375.5 || got.Volume != 5000 || got.Source != "mock" || !got.DateTime.Equal(quote.DateTime) {
+		t.Errorf("Unexpected quote: %+v", got)
+	}
+}
+
+func TestRemoteProvider_FallsBack(t *testing.T) {
+	remote := NewRemoteProvider(newConn(t, &mockProvider{err: provider.ErrRateLimited}))
+	local := &mockProvider{data: sampleCandles()}
+	md := marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(remote, local))
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	data, err := md.Fetch(context.Background(), "RELIANCE", types.Interval1d, start, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) != 2 {
+		t.Errorf("Expected 2 candles from the fallback, got %d", len(data))
+	}
+}
+
+func TestFromCandle_RoundTrip(t *testing.T) {
+	want := types.OHLCV{
+		Symbol: "NIFTY24JANFUT", Exchange: types.ExchangeNSE, Open: 21700, High: 21750, Low: 21680, Close: 21720, Volume: 1200,
+		DateTime: time.Date(2024, 1, 2, 9, 15, 0, 0, ist), Interval: types.Interval1m, Source: "mock", Freshness: types.FreshnessHistorical,
+		OpenInterest: 150000, TradeCount: 340, AdjustmentFactor: 1.5, Incomplete: true, Session: types.SessionRegular,
+	}
+
+	got := fromCandle(toCandle(want), ist)
+	if !got.DateTime.Equal(want.DateTime) {
+		t.Errorf("Expected %v, got %v", want.DateTime, got.DateTime)
+	}
+	got.DateTime = want.DateTime
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,182 @@
+// Package rpc serves MarketData over gRPC using the OHLCVService defined in
+// ohlcvpb, and as JSON over plain HTTP, and provides the providers fetching
+// from such a server.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/rpc/ohlcvpb"
+	"github.com/shahid-2020/gohlcv/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements ohlcvpb.OHLCVServiceServer on top of a MarketData.
+type Server struct {
+	ohlcvpb.UnimplementedOHLCVServiceServer
+
+	md *marketdata.MarketData
+}
+
+func NewServer(md *marketdata.MarketData) *Server {
+	return &Server{md: md}
+}
+
+// Register registers s with the gRPC server gs.
+func (s *Server) Register(gs grpc.ServiceRegistrar) {
+	ohlcvpb.RegisterOHLCVServiceServer(gs, s)
+}
+
+func (s *Server) Fetch(ctx context.Context, req *ohlcvpb.FetchRequest) (*ohlcvpb.FetchResponse, error) {
+	if err := checkSymbol(req.GetSymbol()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.md.Fetch(ctx, req.GetSymbol(), types.Interval(req.GetInterval()), timeOf(req.GetStart()), timeOf(req.GetEnd()), exchangeOf(req.GetExchange())...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ohlcvpb.FetchResponse{Candles: make([]*ohlcvpb.Candle, len(data))}
+	for i, c := range data {
+		resp.Candles[i] = toCandle(c)
+	}
+
+	return resp, nil
+}
+
+func (s *Server) FetchStream(req *ohlcvpb.FetchRequest, stream grpc.ServerStreamingServer[ohlcvpb.Candle]) error {
+	if err := checkSymbol(req.GetSymbol()); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for c, err := range s.md.FetchStream(ctx, req.GetSymbol(), types.Interval(req.GetInterval()), timeOf(req.GetStart()), timeOf(req.GetEnd()), exchangeOf(req.GetExchange())...) {
+		if err != nil {
+			return toStatus(err)
+		}
+		if err := stream.Send(toCandle(c)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) Quote(ctx context.Context, req *ohlcvpb.QuoteRequest) (*ohlcvpb.QuoteResponse, error) {
+	if err := checkSymbol(req.GetSymbol()); err != nil {
+		return nil, err
+	}
+
+	q, err := s.md.Quote(ctx, req.GetSymbol(), exchangeOf(req.GetExchange())...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ohlcvpb.QuoteResponse{
+		Symbol:        q.Symbol,
+		Exchange:      string(q.Exchange),
+		LastPrice:     q.LastPrice,
+		Open:          q.Open,
+		High:          q.High,
+		Low:           q.Low,
+		PreviousClose: q.PreviousClose,
+		Volume:        q.Volume,
+		Datetime:      timestampOf(q.DateTime),
+		Source:        q.Source,
+	}, nil
+}
+
+func checkSymbol(symbol string) error {
+	if symbol == "" {
+		return status.Error(codes.InvalidArgument, "symbol is required")
+	}
+	return nil
+}
+
+// exchangeOf fetches from exchange, or from the MarketData's own exchange if
+// it is empty.
+func exchangeOf(exchange string) []marketdata.FetchOption {
+	if exchange == "" {
+		return nil
+	}
+	return []marketdata.FetchOption{marketdata.WithExchange(types.Exchange(exchange))}
+}
+
+// toStatus maps MarketData errors to gRPC status codes, naming the provider
+// error matched, if any, in an ErrorInfo detail for fromStatus.
+func toStatus(err error) error {
+	var pe *provider.ProviderError
+
+	var s *status.Status
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, provider.ErrSymbolNotFound):
+		s = status.New(codes.NotFound, err.Error())
+	case errors.Is(err, provider.ErrUnknownInterval), errors.Is(err, provider.ErrUnsupportedExchange),
+		errors.Is(err, provider.ErrInvalidRange), errors.Is(err, provider.ErrInvalidSymbol),
+		errors.Is(err, provider.ErrAdjustedUnsupported):
+		s = status.New(codes.InvalidArgument, err.Error())
+	case errors.Is(err, provider.ErrRateLimited):
+		s = status.New(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, provider.ErrNoData):
+		s = status.New(codes.NotFound, err.Error())
+	case errors.Is(err, provider.ErrProviderUnavailable), errors.As(err, &pe):
+		s = status.New(codes.Unavailable, err.Error())
+	default:
+		s = status.New(codes.Internal, err.Error())
+	}
+
+	if code := errorCode(err); code != "" {
+		if detailed, derr := s.WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: errorDomain}); derr == nil {
+			s = detailed
+		}
+	}
+	return s.Err()
+}
+
+func toCandle(c types.OHLCV) *ohlcvpb.Candle {
+	return &ohlcvpb.Candle{
+		Symbol:           c.Symbol,
+		Exchange:         string(c.Exchange),
+		Open:             c.Open,
+		High:             c.High,
+		Low:              c.Low,
+		Close:            c.Close,
+		Volume:           c.Volume,
+		Datetime:         timestampOf(c.DateTime),
+		Source:           c.Source,
+		Freshness:        string(c.Freshness),
+		AdjustmentFactor: c.AdjustmentFactor,
+		OpenInterest:     c.OpenInterest,
+		TradeCount:       c.TradeCount,
+		Interval:         string(c.Interval),
+		Session:          string(c.Session),
+		Incomplete:       c.Incomplete,
+	}
+}
+
+// timeOf treats an unset timestamp as the zero time, which Fetch fills in
+// with its defaults.
+func timeOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func timestampOf(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
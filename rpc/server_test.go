@@ -0,0 +1,210 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/rpc/ohlcvpb"
+	"github.com/shahid-2020/gohlcv/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var ist = time.FixedZone("IST", 5*3600+1800)
+
+type mockProvider struct {
+	exchange types.Exchange
+	data     []types.OHLCV
+	quote    types.Quote
+	err      error
+}
+
+func (m *mockProvider) Name() string {
+	return "mock"
+}
+
+func (m *mockProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+	m.exchange = exchange
+	return m.data, m.err
+}
+
+func (m *mockProvider) Quote(ctx context.Context, symbol string, exchange types.Exchange) (types.Quote, error) {
+	return m.quote, m.err
+}
+
+func newClient(t *testing.T, p provider.OHLCVProvider) ohlcvpb.OHLCVServiceClient {
+	t.Helper()
+	return ohlcvpb.NewOHLCVServiceClient(newConn(t, p))
+}
+
+func newConn(t *testing.T, p provider.OHLCVProvider) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	NewServer(marketdata.NewMarketData(types.ExchangeNSE, marketdata.WithProviders(p))).Register(gs)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func sampleCandles() []types.OHLCV {
+	return []types.OHLCV{
+		{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, Open: 100, High: 110, Low: 95, Close: 105, Volume: 1000, DateTime: time.Date(2024, 1, 2, 0, 0, 0, 0, ist), Source: "mock", Freshness: types.FreshnessHistorical},
+		{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, Open: 105, High: 112, Low: 101, Close: 110, Volume: 800, DateTime: time.Date(2024, 1, 3, 0, 0, 0, 0, ist), Source: "mock", Freshness: types.FreshnessHistorical},
+	}
+}
+
+func fetchRequest() *ohlcvpb.FetchRequest {
+	return &ohlcvpb.FetchRequest{
+		Symbol:   "RELIANCE",
+		Exchange: "NSE",
+		Interval: "1d",
+		Start:    timestamppb.New(time.Date(2024, 1, 2, 0, 0, 0, 0, ist)),
+		End:      timestamppb.New(time.Date(2024, 1, 4, 0, 0, 0, 0, ist)),
+	}
+}
+
+func TestServer_Fetch(t *testing.T) {
+	client := newClient(t, &mockProvider{data: sampleCandles()})
+
+	resp, err := client.Fetch(context.Background(), fetchRequest())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(resp.Candles) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(resp.Candles))
+	}
+	c := resp.Candles[0]
+	if c.Symbol != "RELIANCE" || c.Exchange != "NSE" || c.Open != 100 || c.Close != 105 || c.Volume != 1000 {
+		t.Errorf("Unexpected candle: %v", c)
+	}
+	if !c.Datetime.AsTime().Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, ist)) {
+		t.Errorf("Expected 2024-01-02 IST, got %v", c.Datetime.AsTime())
+	}
+	if c.Freshness != "historical" {
+		t.Errorf("Expected historical freshness, got %s", c.Freshness)
+	}
+}
+
+func TestServer_Fetch_Exchange(t *testing.T) {
+	p := &mockProvider{data: sampleCandles()}
+	client := newClient(t, p)
+
+	req := fetchRequest()
+	req.Exchange = "BSE"
+	if _, err := client.Fetch(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p.exchange != types.ExchangeBSE {
+		t.Errorf("Expected BSE, got %s", p.exchange)
+	}
+
+	req.Exchange = ""
+	if _, err := client.Fetch(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p.exchange != types.ExchangeNSE {
+		t.Errorf("Expected the server's exchange NSE, got %s", p.exchange)
+	}
+}
+
+func TestServer_Fetch_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		req  *ohlcvpb.FetchRequest
+		want codes.Code
+	}{
+		{"not found", provider.ErrSymbolNotFound, fetchRequest(), codes.NotFound},
+		{"invalid range", provider.ErrInvalidRange, fetchRequest(), codes.InvalidArgument},
+		{"invalid symbol", provider.ErrInvalidSymbol, fetchRequest(), codes.InvalidArgument},
+		{"rate limited", provider.ErrRateLimited, fetchRequest(), codes.ResourceExhausted},
+		{"unavailable", &provider.ProviderError{Provider: "mock", StatusCode: 502}, fetchRequest(), codes.Unavailable},
+		{"missing symbol", nil, &ohlcvpb.FetchRequest{Interval: "1d"}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		client := newClient(t, &mockProvider{err: tt.err})
+
+		_, err := client.Fetch(context.Background(), tt.req)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("%s: expected %s, got %s (%v)", tt.name, tt.want, got, err)
+		}
+	}
+}
+
+func TestServer_FetchStream(t *testing.T) {
+	client := newClient(t, &mockProvider{data: sampleCandles()})
+
+	stream, err := client.FetchStream(context.Background(), fetchRequest())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var got []*ohlcvpb.Candle
+	for {
+		c, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		got = append(got, c)
+	}
+
+	if len(got) != 2 || got[1].Close != 110 {
+		t.Errorf("Expected 2 candles ending at 110, got %v", got)
+	}
+}
+
+func TestServer_FetchStream_Error(t *testing.T) {
+	client := newClient(t, &mockProvider{err: provider.ErrSymbolNotFound})
+
+	stream, err := client.FetchStream(context.Background(), fetchRequest())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestServer_Quote(t *testing.T) {
+	quote := types.Quote{Symbol: "RELIANCE", Exchange: types.ExchangeNSE, LastPrice: 1375.5, PreviousClose: 1360, Volume: 5000, DateTime: time.Date(2024, 1, 2, 15, 30, 0, 0, ist), Source: "mock"}
+	client := newClient(t, &mockProvider{quote: quote})
+
+	resp, err := client.Quote(context.Background(), &ohlcvpb.QuoteRequest{Symbol: "RELIANCE"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.LastPrice != 1375.5 || resp.PreviousClose != 1360 || resp.Volume != 5000 {
+		t.Errorf("Unexpected quote: %v", resp)
+	}
+	if !resp.Datetime.AsTime().Equal(quote.DateTime) {
+		t.Errorf("Expected %v, got %v", quote.DateTime, resp.Datetime.AsTime())
+	}
+}
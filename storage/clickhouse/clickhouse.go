@@ -0,0 +1,425 @@
+// Package clickhouse archives candles in ClickHouse through its HTTP
+// interface, batching inserts for high volumes such as full-exchange minute
+// data.
+package clickhouse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const (
+	// DefaultTable is the table candles are written to unless WithTable is
+	// given.
+	DefaultTable = "candles"
+	// DefaultBatchSize is how many candles are buffered before a batch is
+	// sent unless WithBatchSize is given.
+	DefaultBatchSize = 100_000
+	// DefaultFlushInterval is how often buffered candles are sent however
+	// few there are, unless WithFlushInterval is given.
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// queuedBatches is how many full batches may wait to be sent before Write
+// blocks.
+const queuedBatches = 4
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("clickhouse writer closed")
+
+type config struct {
+	table         string
+	user          string
+	password      string
+	httpClient    *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	onError       func(error)
+}
+
+type Option func(*config)
+
+// WithTable writes to table, which may be qualified with a database. The
+// default is DefaultTable.
+func WithTable(table string) Option {
+	return func(c *config) {
+		c.table = table
+	}
+}
+
+// WithCredentials authenticates as user.
+func WithCredentials(user, password string) Option {
+	return func(c *config) {
+		c.user = user
+		c.password = password
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithBatchSize sets how many candles are buffered before they are sent in
+// the background. The default is DefaultBatchSize.
+func WithBatchSize(n int) Option {
+	return func(c *config) {
+		c.batchSize = n
+	}
+}
+
+// WithFlushInterval sets how often buffered candles are sent in the
+// background however few there are. The default is DefaultFlushInterval; a
+// non-positive interval only sends full batches and on Flush.
+func WithFlushInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.flushInterval = d
+	}
+}
+
+// WithErrorHandler calls fn with the error of every failed background insert,
+// as it happens. The errors are returned by the next Flush or Close either way.
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// row is a candle as inserted with FORMAT JSONEachRow.
+type row struct {
+	Symbol           string  `json:"symbol"`
+	Exchange         string  `json:"exchange"`
+	Timeframe        string  `json:"timeframe"`
+	DateTime         string  `json:"datetime"`
+	Open             float64 `json:"open"`
+	High             float64 `json:"high"`
+	Low              float64 `json:"low"`
+	Close            float64 `json:"close"`
+	Volume           int64   `json:"volume"`
+	Source           string  `json:"source"`
+	Freshness        string  `json:"freshness"`
+	AdjustmentFactor float64 `json:"adjustment_factor"`
+	Incomplete       bool    `json:"incomplete"`
+	Session          string  `json:"session"`
+}
+
+const dateTimeLayout = "2006-01-02 15:04:05.000"
+
+// ClickHouseWriter buffers candles and inserts them into a ClickHouse table in
+// large gzip-compressed batches from a background goroutine, so callers
+// archiving millions of candles aren't held up by each insert. Rows are
+// replaced by symbol, exchange, interval and time when ClickHouse merges
+// parts. It is safe for concurrent use.
+type ClickHouseWriter struct {
+	endpoint   string
+	table      string
+	user       string
+	password   string
+	httpClient *http.Client
+	batchSize  int
+	onError    func(error)
+
+	mu      sync.Mutex
+	buf     []row
+	err     error
+	closed  bool
+	pending sync.WaitGroup
+
+	batches chan []row
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewClickHouseWriter creates a ClickHouseWriter sending to the ClickHouse
+// HTTP interface at endpoint, such as "http://localhost:8123". Close must be
+// called to send the last candles and stop the background goroutine.
+func NewClickHouseWriter(endpoint string, opts ...Option) (*ClickHouseWriter, error) {
+	cfg := config{
+		table:         DefaultTable,
+		httpClient:    http.DefaultClient,
+		batchSize:     DefaultBatchSize,
+		flushInterval: DefaultFlushInterval,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if !tableName.MatchString(cfg.table) {
+		return nil, fmt.Errorf("invalid table name %q", cfg.table)
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	w := &ClickHouseWriter{
+		endpoint:   endpoint,
+		table:      cfg.table,
+		user:       cfg.user,
+		password:   cfg.password,
+		httpClient: cfg.httpClient,
+		batchSize:  max(cfg.batchSize, 1),
+		onError:    cfg.onError,
+		batches:    make(chan []row, queuedBatches),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go w.run(cfg.flushInterval)
+
+	return w, nil
+}
+
+// Migrate creates the table if it doesn't exist yet: a ReplacingMergeTree
+// ordered by symbol, exchange, interval and time and partitioned by month.
+func (w *ClickHouseWriter) Migrate(ctx context.Context) error {
+	query := `CREATE TABLE IF NOT EXISTS ` + w.table + ` (
+	symbol            LowCardinality(String),
+	exchange          LowCardinality(String),
+	timeframe         LowCardinality(String),
+	datetime          DateTime64(3, 'UTC'),
+	open              Float64,
+	high              Float64,
+	low               Float64,
+	close             Float64,
+	volume            Int64,
+	source            LowCardinality(String),
+	freshness         LowCardinality(String),
+	adjustment_factor Float64,
+	incomplete        Bool,
+	session           LowCardinality(String)
+)
+ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(datetime)
+ORDER BY (symbol, exchange, timeframe, datetime)`
+
+	if err := w.post(ctx, query, nil); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", w.table, err)
+	}
+	return nil
+}
+
+// Write buffers candles of interval. A full batch is handed to the background
+// goroutine; Write only blocks when several batches are already waiting to be
+// sent. If ctx is done first, the batches not handed over stay buffered.
+func (w *ClickHouseWriter) Write(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+
+	for _, c := range candles {
+		w.buf = append(w.buf, toRow(interval, c))
+	}
+
+	var full [][]row
+	for len(w.buf) >= w.batchSize {
+		full = append(full, w.buf[:w.batchSize:w.batchSize])
+		w.buf = w.buf[w.batchSize:]
+	}
+	w.pending.Add(len(full))
+	w.mu.Unlock()
+
+	for i, batch := range full {
+		select {
+		case w.batches <- batch:
+		case <-ctx.Done():
+			w.requeue(full[i:])
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// requeue puts batches Write couldn't hand over back in front of the buffer.
+func (w *ClickHouseWriter) requeue(batches [][]row) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var rows []row
+	for _, batch := range batches {
+		rows = append(rows, batch...)
+	}
+	w.buf = append(rows, w.buf...)
+	w.pending.Add(-len(batches))
+}
+
+// Flush sends the buffered candles and waits for batches already handed to
+// the background goroutine. It returns the errors of background inserts
+// since the last Flush.
+func (w *ClickHouseWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	batch := w.buf
+	w.buf = nil
+	w.mu.Unlock()
+
+	var err error
+	if len(batch) > 0 {
+		err = w.insert(ctx, batch)
+	}
+	w.pending.Wait()
+
+	w.mu.Lock()
+	err = errors.Join(w.err, err)
+	w.err = nil
+	w.mu.Unlock()
+
+	return err
+}
+
+// Close flushes the buffered candles and stops the background goroutine.
+// Later writes fail with ErrClosed.
+func (w *ClickHouseWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	err := w.Flush(ctx)
+	close(w.stop)
+	<-w.done
+	return err
+}
+
+// run sends batches handed over by Write and, every interval, whatever is
+// buffered.
+func (w *ClickHouseWriter) run(interval time.Duration) {
+	defer close(w.done)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case batch := <-w.batches:
+			w.background(batch)
+			w.pending.Done()
+		case <-tick:
+			w.mu.Lock()
+			batch := w.buf
+			w.buf = nil
+			w.mu.Unlock()
+			if len(batch) > 0 {
+				w.background(batch)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// background inserts batch and records its error for the next Flush.
+func (w *ClickHouseWriter) background(batch []row) {
+	err := w.insert(context.Background(), batch)
+	if err == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.err = errors.Join(w.err, err)
+	w.mu.Unlock()
+
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
+
+func (w *ClickHouseWriter) insert(ctx context.Context, batch []row) error {
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	enc := json.NewEncoder(gz)
+	for _, r := range batch {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to encode candle: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress candles: %w", err)
+	}
+
+	if err := w.post(ctx, "INSERT INTO "+w.table+" FORMAT JSONEachRow", &body); err != nil {
+		return fmt.Errorf("failed to insert %d candles: %w", len(batch), err)
+	}
+	return nil
+}
+
+// post runs query, with body as its gzip-compressed input if not nil.
+func (w *ClickHouseWriter) post(ctx context.Context, query string, body *bytes.Buffer) error {
+	u, err := url.Parse(w.endpoint)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	q.Set("query", query)
+	u.RawQuery = q.Encode()
+
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		reader = body
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if w.user != "" {
+		req.Header.Set("X-ClickHouse-User", w.user)
+		req.Header.Set("X-ClickHouse-Key", w.password)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func toRow(interval types.Interval, c types.OHLCV) row {
+	return row{
+		Symbol:           c.Symbol,
+		Exchange:         string(c.Exchange),
+		Timeframe:        string(interval),
+		DateTime:         c.DateTime.UTC().Format(dateTimeLayout),
+		Open:             c.Open,
+		High:             c.High,
+		Low:              c.Low,
+		Close:            c.Close,
+		Volume:           c.Volume,
+		Source:           c.Source,
+		Freshness:        string(c.Freshness),
+		AdjustmentFactor: c.AdjustmentFactor,
+		Incomplete:       c.Incomplete,
+		Session:          string(c.Session),
+	}
+}
@@ -0,0 +1,221 @@
+package clickhouse
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// fakeServer records the queries and rows it receives.
+type fakeServer struct {
+	mu      sync.Mutex
+	queries []string
+	inserts [][]row
+	users   []string
+	status  int
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query().Get("query")
+	f.queries = append(f.queries, query)
+	f.users = append(f.users, r.Header.Get("X-ClickHouse-User"))
+
+	if f.status != 0 {
+		http.Error(w, "Code: 60. Table default.candles does not exist", f.status)
+		return
+	}
+
+	if strings.HasPrefix(query, "INSERT") {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var rows []row
+		scanner := bufio.NewScanner(gz)
+		for scanner.Scan() {
+			var rw row
+			if err := json.Unmarshal(scanner.Bytes(), &rw); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			rows = append(rows, rw)
+		}
+		f.inserts = append(f.inserts, rows)
+	}
+}
+
+func (f *fakeServer) insertedRows() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var n int
+	for _, rows := range f.inserts {
+		n += len(rows)
+	}
+	return n
+}
+
+func newTestWriter(t *testing.T, f *fakeServer, opts ...Option) *ClickHouseWriter {
+	t.Helper()
+
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	w, err := NewClickHouseWriter(srv.URL, append([]Option{WithFlushInterval(0)}, opts...)...)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { w.Close(context.Background()) })
+	return w
+}
+
+func minuteCandles(n int) []types.OHLCV {
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, types.ExchangeNSE.Location())
+	candles := make([]types.OHLCV, n)
+	for i := range candles {
+		candles[i] = types.OHLCV{
+			Symbol:   "INFY",
+			Exchange: types.ExchangeNSE,
+			Close:    float64(100 + i),
+			Volume:   10,
+			DateTime: base.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return candles
+}
+
+func TestNewClickHouseWriter_InvalidTable(t *testing.T) {
+	if _, err := NewClickHouseWriter("http://localhost:8123", WithTable("candles; DROP TABLE x")); err == nil {
+		t.Error("Expected an error for an invalid table name")
+	}
+}
+
+func TestClickHouseWriter_Migrate(t *testing.T) {
+	f := &fakeServer{}
+	w := newTestWriter(t, f, WithTable("market.candles"), WithCredentials("archiver", "secret"))
+
+	if err := w.Migrate(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(f.queries[0], "CREATE TABLE IF NOT EXISTS market.candles") || !strings.Contains(f.queries[0], "ReplacingMergeTree") {
+		t.Errorf("Expected the table to be created, got %s", f.queries[0])
+	}
+	if f.users[0] != "archiver" {
+		t.Errorf("Expected user archiver, got %q", f.users[0])
+	}
+}
+
+func TestClickHouseWriter_BatchesInBackground(t *testing.T) {
+	f := &fakeServer{}
+	w := newTestWriter(t, f, WithBatchSize(10))
+
+	if err := w.Write(context.Background(), types.Interval1m, minuteCandles(25)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(f.inserts) != 3 {
+		t.Fatalf("Expected 3 inserts, got %d", len(f.inserts))
+	}
+	if got := f.insertedRows(); got != 25 {
+		t.Errorf("Expected 25 rows, got %d", got)
+	}
+	if f.queries[0] != "INSERT INTO candles FORMAT JSONEachRow" {
+		t.Errorf("Expected a JSONEachRow insert, got %s", f.queries[0])
+	}
+
+	// Batches may arrive in any order.
+	var first *row
+	for _, rows := range f.inserts {
+		for i := range rows {
+			if rows[i].Close == 100 {
+				first = &rows[i]
+			}
+		}
+	}
+	if first == nil || first.Symbol != "INFY" || first.Exchange != "NSE" || first.Timeframe != "1m" || first.DateTime != "2024-01-02 03:45:00.000" {
+		t.Errorf("Expected the candle's keys in UTC, got %+v", first)
+	}
+}
+
+func TestClickHouseWriter_FlushInterval(t *testing.T) {
+	f := &fakeServer{}
+	w := newTestWriter(t, f, WithFlushInterval(10*time.Millisecond))
+
+	if err := w.Write(context.Background(), types.Interval1m, minuteCandles(3)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for f.insertedRows() != 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := f.insertedRows(); got != 3 {
+		t.Errorf("Expected the buffer to be flushed on the interval, got %d rows", got)
+	}
+}
+
+func TestClickHouseWriter_BackgroundError(t *testing.T) {
+	f := &fakeServer{status: http.StatusNotFound}
+	var handled []error
+	var mu sync.Mutex
+	w := newTestWriter(t, f, WithBatchSize(2), WithErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, err)
+	}))
+
+	if err := w.Write(context.Background(), types.Interval1m, minuteCandles(2)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err := w.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Code: 60") {
+		t.Errorf("Expected the server's error, got %v", err)
+	}
+	mu.Lock()
+	if len(handled) != 1 {
+		t.Errorf("Expected the error handler to be called once, got %d", len(handled))
+	}
+	mu.Unlock()
+
+	if err := w.Flush(context.Background()); err != nil {
+		t.Errorf("Expected errors to be cleared by Flush, got %v", err)
+	}
+}
+
+func TestClickHouseWriter_Close(t *testing.T) {
+	f := &fakeServer{}
+	w := newTestWriter(t, f)
+
+	if err := w.Write(context.Background(), types.Interval1m, minuteCandles(5)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := f.insertedRows(); got != 5 {
+		t.Errorf("Expected Close to flush 5 rows, got %d", got)
+	}
+	if err := w.Write(context.Background(), types.Interval1m, minuteCandles(1)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
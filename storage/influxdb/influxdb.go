@@ -0,0 +1,205 @@
+// Package influxdb exports candles to InfluxDB 2.x as line protocol, for
+// charting them in tools such as Grafana.
+package influxdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const (
+	// DefaultMeasurement is the measurement candles are written to unless
+	// WithMeasurement is given.
+	DefaultMeasurement = "candles"
+	// DefaultBatchSize is how many candles are sent per write request unless
+	// WithBatchSize is given. InfluxDB recommends batches of about 5000
+	// points.
+	DefaultBatchSize = 5000
+)
+
+type config struct {
+	measurement string
+	httpClient  *http.Client
+	batchSize   int
+}
+
+type Option func(*config)
+
+// WithMeasurement writes candles to measurement. The default is
+// DefaultMeasurement.
+func WithMeasurement(measurement string) Option {
+	return func(c *config) {
+		c.measurement = measurement
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithBatchSize sets how many candles are sent per write request. The default
+// is DefaultBatchSize.
+func WithBatchSize(n int) Option {
+	return func(c *config) {
+		c.batchSize = n
+	}
+}
+
+// InfluxDBExporter writes candles to an InfluxDB 2.x bucket through the
+// /api/v2/write endpoint. Each candle becomes a point tagged with its symbol,
+// exchange, interval, source, freshness and session, so a point written again
+// for the same candle overwrites the old one.
+type InfluxDBExporter struct {
+	writeURL    string
+	token       string
+	measurement string
+	httpClient  *http.Client
+	batchSize   int
+}
+
+// NewInfluxDBExporter creates an InfluxDBExporter writing to bucket of org on
+// the server at serverURL, such as "http://localhost:8086", authenticating
+// with an API token.
+func NewInfluxDBExporter(serverURL, org, bucket, token string, opts ...Option) (*InfluxDBExporter, error) {
+	cfg := config{
+		measurement: DefaultMeasurement,
+		httpClient:  http.DefaultClient,
+		batchSize:   DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	u = u.JoinPath("api", "v2", "write")
+	u.RawQuery = url.Values{"org": {org}, "bucket": {bucket}, "precision": {"ns"}}.Encode()
+
+	return &InfluxDBExporter{
+		writeURL:    u.String(),
+		token:       token,
+		measurement: cfg.measurement,
+		httpClient:  cfg.httpClient,
+		batchSize:   max(cfg.batchSize, 1),
+	}, nil
+}
+
+// Export writes candles of interval in batches. It stops at the first batch
+// InfluxDB rejects; the batches before it are written.
+func (e *InfluxDBExporter) Export(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	for len(candles) > 0 {
+		n := min(len(candles), e.batchSize)
+		if err := e.write(ctx, interval, candles[:n]); err != nil {
+			return err
+		}
+		candles = candles[n:]
+	}
+	return nil
+}
+
+func (e *InfluxDBExporter) write(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	if err := WriteLineProtocol(gz, e.measurement, interval, candles); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress candles: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.writeURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+e.token)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write %d candles: %w", len(candles), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("failed to write %d candles: status %d: %s", len(candles), resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// WriteLineProtocol writes candles of interval to w in InfluxDB line protocol,
+// one point per candle in measurement with nanosecond timestamps. Empty tags
+// are left out, as line protocol requires.
+func WriteLineProtocol(w io.Writer, measurement string, interval types.Interval, candles []types.OHLCV) error {
+	var b []byte
+	for _, c := range candles {
+		b = appendPoint(b[:0], measurement, interval, c)
+		if _, err := w.Write(b); err != nil {
+			return fmt.Errorf("failed to write line protocol: %w", err)
+		}
+	}
+	return nil
+}
+
+func appendPoint(b []byte, measurement string, interval types.Interval, c types.OHLCV) []byte {
+	b = append(b, measurementEscaper.Replace(measurement)...)
+	b = appendTag(b, "symbol", c.Symbol)
+	b = appendTag(b, "exchange", string(c.Exchange))
+	b = appendTag(b, "interval", string(interval))
+	b = appendTag(b, "source", c.Source)
+	b = appendTag(b, "freshness", string(c.Freshness))
+	b = appendTag(b, "session", string(c.Session))
+
+	b = append(b, " open="...)
+	b = strconv.AppendFloat(b, c.Open, 'f', -1, 64)
+	b = append(b, ",high="...)
+	b = strconv.AppendFloat(b, c.High, 'f', -1, 64)
+	b = append(b, ",low="...)
+	b = strconv.AppendFloat(b, c.Low, 'f', -1, 64)
+	b = append(b, ",close="...)
+	b = strconv.AppendFloat(b, c.Close, 'f', -1, 64)
+	b = append(b, ",volume="...)
+	b = strconv.AppendInt(b, c.Volume, 10)
+	b = append(b, 'i')
+	if c.AdjustmentFactor != 0 {
+		b = append(b, ",adjustment_factor="...)
+		b = strconv.AppendFloat(b, c.AdjustmentFactor, 'f', -1, 64)
+	}
+	if c.Incomplete {
+		b = append(b, ",incomplete=true"...)
+	}
+
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, c.DateTime.UnixNano(), 10)
+	return append(b, '\n')
+}
+
+func appendTag(b []byte, key, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = append(b, ',')
+	b = append(b, key...)
+	b = append(b, '=')
+	return append(b, tagEscaper.Replace(value)...)
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `)
+	tagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `)
+)
@@ -0,0 +1,117 @@
+package influxdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestWriteLineProtocol(t *testing.T) {
+	candles := []types.OHLCV{
+		{
+			Symbol:    "M&M",
+			Exchange:  types.ExchangeNSE,
+			Open:      100.5,
+			High:      102,
+			Low:       99.25,
+			Close:     101,
+			Volume:    1200,
+			DateTime:  time.Unix(1704167100, 0),
+			Source:    "yahoo",
+			Freshness: types.FreshnessHistorical,
+		},
+		{
+			Symbol:           "BRK B",
+			Exchange:         types.ExchangeNYSE,
+			Close:            400,
+			DateTime:         time.Unix(1704167160, 0),
+			AdjustmentFactor: 0.5,
+			Incomplete:       true,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLineProtocol(&buf, "daily candles", types.Interval1d, candles); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `daily\ candles,symbol=M&M,exchange=NSE,interval=1d,source=yahoo,freshness=historical open=100.5,high=102,low=99.25,close=101,volume=1200i 1704167100000000000
+daily\ candles,symbol=BRK\ B,exchange=NYSE,interval=1d open=0,high=0,low=0,close=400,volume=0i,adjustment_factor=0.5,incomplete=true 1704167160000000000
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestInfluxDBExporter_Export(t *testing.T) {
+	var (
+		paths   []string
+		queries []string
+		lines   int
+		auth    string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		queries = append(queries, r.URL.RawQuery)
+		auth = r.Header.Get("Authorization")
+
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(gz)
+		lines += strings.Count(string(body), "\n")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	e, err := NewInfluxDBExporter(srv.URL, "acme", "market", "s3cret", WithBatchSize(2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	candles := make([]types.OHLCV, 5)
+	for i := range candles {
+		candles[i] = types.OHLCV{Symbol: "INFY", Exchange: types.ExchangeNSE, Close: 100, DateTime: time.Unix(int64(i*60), 0)}
+	}
+
+	if err := e.Export(context.Background(), types.Interval1m, candles); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(paths) != 3 || lines != 5 {
+		t.Errorf("Expected 5 points in 3 requests, got %d in %d", lines, len(paths))
+	}
+	if paths[0] != "/api/v2/write" || !strings.Contains(queries[0], "bucket=market") || !strings.Contains(queries[0], "org=acme") {
+		t.Errorf("Expected a v2 write to acme/market, got %s?%s", paths[0], queries[0])
+	}
+	if auth != "Token s3cret" {
+		t.Errorf("Expected token authentication, got %q", auth)
+	}
+}
+
+func TestInfluxDBExporter_Export_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":"unauthorized","message":"unauthorized access"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	e, err := NewInfluxDBExporter(srv.URL, "acme", "market", "wrong")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = e.Export(context.Background(), types.Interval1d, []types.OHLCV{{Symbol: "INFY", DateTime: time.Unix(0, 0)}})
+	if err == nil || !strings.Contains(err.Error(), "unauthorized access") {
+		t.Errorf("Expected the server's error, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+// Package postgres stores candles in PostgreSQL, optionally as a TimescaleDB
+// hypertable.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// DefaultTable is the table candles are stored in unless WithTable is given.
+const DefaultTable = "candles"
+
+// batchSize is how many candles Save writes per INSERT statement, keeping the
+// statement well under PostgreSQL's limit of 65535 parameters.
+const batchSize = 1000
+
+// columns are the stored columns in the order Save binds them.
+var columns = []string{
+	"symbol", "exchange", "timeframe", "datetime",
+	"open", "high", "low", "close", "volume",
+	"source", "freshness", "adjustment_factor", "incomplete", "session",
+}
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+type config struct {
+	table     string
+	timescale bool
+}
+
+type Option func(*config)
+
+// WithTable stores candles in table, which may be schema-qualified. The
+// default is DefaultTable.
+func WithTable(table string) Option {
+	return func(c *config) {
+		c.table = table
+	}
+}
+
+// WithTimescale makes Migrate turn the table into a TimescaleDB hypertable
+// partitioned by time. The timescaledb extension must be installed.
+func WithTimescale() Option {
+	return func(c *config) {
+		c.timescale = true
+	}
+}
+
+// PostgresStore is a storage.Store backed by a PostgreSQL table with one row
+// per candle. It works with any database/sql driver for PostgreSQL, such as
+// github.com/jackc/pgx/v5/stdlib or github.com/lib/pq, which the caller
+// imports and opens.
+type PostgresStore struct {
+	db        *sql.DB
+	table     string
+	timescale bool
+	batchSize int
+}
+
+// NewPostgresStore creates a PostgresStore writing to db. Call Migrate to
+// create the table.
+func NewPostgresStore(db *sql.DB, opts ...Option) (*PostgresStore, error) {
+	cfg := config{table: DefaultTable}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if !tableName.MatchString(cfg.table) {
+		return nil, fmt.Errorf("invalid table name %q", cfg.table)
+	}
+
+	return &PostgresStore{
+		db:        db,
+		table:     cfg.table,
+		timescale: cfg.timescale,
+		batchSize: batchSize,
+	}, nil
+}
+
+// Migrate creates the table and, with WithTimescale, its hypertable if they
+// don't exist yet. It is safe to call on every start.
+func (s *PostgresStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	symbol            TEXT NOT NULL,
+	exchange          TEXT NOT NULL,
+	timeframe         TEXT NOT NULL,
+	datetime          TIMESTAMPTZ NOT NULL,
+	open              DOUBLE PRECISION NOT NULL,
+	high              DOUBLE PRECISION NOT NULL,
+	low               DOUBLE PRECISION NOT NULL,
+	close             DOUBLE PRECISION NOT NULL,
+	volume            BIGINT NOT NULL,
+	source            TEXT NOT NULL DEFAULT '',
+	freshness         TEXT NOT NULL DEFAULT '',
+	adjustment_factor DOUBLE PRECISION NOT NULL DEFAULT 0,
+	incomplete        BOOLEAN NOT NULL DEFAULT FALSE,
+	session           TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (symbol, exchange, timeframe, datetime)
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create table %s: %w", s.table, err)
+	}
+
+	if s.timescale {
+		_, err := s.db.ExecContext(ctx, `SELECT create_hypertable($1, 'datetime', if_not_exists => TRUE, migrate_data => TRUE)`, s.table)
+		if err != nil {
+			return fmt.Errorf("failed to create hypertable %s: %w", s.table, err)
+		}
+	}
+
+	return nil
+}
+
+// Save upserts candles in a single transaction: a candle already stored for
+// the same symbol, exchange, interval and time is overwritten.
+func (s *PostgresStore) Save(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for len(candles) > 0 {
+		n := min(len(candles), s.batchSize)
+
+		args := make([]any, 0, n*len(columns))
+		for _, c := range candles[:n] {
+			args = append(args,
+				c.Symbol, string(c.Exchange), string(interval), c.DateTime.UTC(),
+				c.Open, c.High, c.Low, c.Close, c.Volume,
+				c.Source, string(c.Freshness), c.AdjustmentFactor, c.Incomplete, string(c.Session),
+			)
+		}
+
+		if _, err := tx.ExecContext(ctx, s.upsertQuery(n), args...); err != nil {
+			return fmt.Errorf("failed to save candles: %w", err)
+		}
+		candles = candles[n:]
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit candles: %w", err)
+	}
+	return nil
+}
+
+// upsertQuery returns an INSERT of n rows that overwrites conflicting rows.
+func (s *PostgresStore) upsertQuery(n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", s.table, strings.Join(columns, ", "))
+
+	for i := range n {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := range columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", i*len(columns)+j+1)
+		}
+		b.WriteByte(')')
+	}
+
+	b.WriteString(" ON CONFLICT (symbol, exchange, timeframe, datetime) DO UPDATE SET ")
+	for i, c := range columns[4:] {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s = EXCLUDED.%s", c, c)
+	}
+
+	return b.String()
+}
+
+// Load returns the stored candles of symbol between from and to, to excluded,
+// ordered by time. Times are in UTC.
+func (s *PostgresStore) Load(
+	ctx context.Context,
+	symbol string,
+	exchange types.Exchange,
+	interval types.Interval,
+	from, to time.Time,
+) ([]types.OHLCV, error) {
+	query := `SELECT datetime, open, high, low, close, volume, source, freshness, adjustment_factor, incomplete, session
+FROM ` + s.table + `
+WHERE symbol = $1 AND exchange = $2 AND timeframe = $3 AND datetime >= $4 AND datetime < $5
+ORDER BY datetime`
+
+	rows, err := s.db.QueryContext(ctx, query, symbol, string(exchange), string(interval), from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candles: %w", err)
+	}
+	defer rows.Close()
+
+	var candles []types.OHLCV
+	for rows.Next() {
+		c := types.OHLCV{Symbol: symbol, Exchange: exchange, Interval: interval}
+		var freshness, session string
+		err := rows.Scan(&c.DateTime, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume,
+			&c.Source, &freshness, &c.AdjustmentFactor, &c.Incomplete, &session)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		c.DateTime = c.DateTime.UTC()
+		c.Freshness = types.DataFreshness(freshness)
+		c.Session = types.TradingSession(session)
+		candles = append(candles, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load candles: %w", err)
+	}
+
+	return candles, nil
+}
+
+// Latest returns the time of the most recent stored candle of symbol, in UTC.
+func (s *PostgresStore) Latest(
+	ctx context.Context,
+	symbol string,
+	exchange types.Exchange,
+	interval types.Interval,
+) (time.Time, bool, error) {
+	query := `SELECT max(datetime) FROM ` + s.table + ` WHERE symbol = $1 AND exchange = $2 AND timeframe = $3`
+
+	var latest sql.NullTime
+	if err := s.db.QueryRowContext(ctx, query, symbol, string(exchange), string(interval)).Scan(&latest); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to read latest candle: %w", err)
+	}
+	if !latest.Valid {
+		return time.Time{}, false, nil
+	}
+	return latest.Time.UTC(), true, nil
+}
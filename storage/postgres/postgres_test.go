@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// fakeDriver records the statements it executes and answers every query with
+// rows.
+type fakeDriver struct {
+	mu        sync.Mutex
+	execs     []string
+	args      [][]driver.Value
+	committed int
+	columns   []string
+	rows      [][]driver.Value
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return &fakeTx{d: c.d}, nil
+}
+
+type fakeTx struct {
+	d *fakeDriver
+}
+
+func (t *fakeTx) Commit() error {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.d.committed++
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs = append(s.d.execs, s.query)
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.args = append(s.d.args, args)
+	return &fakeRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// driverSeq numbers the drivers registered by newTestStore, since a driver
+// name can only be registered once.
+var driverSeq struct {
+	sync.Mutex
+	n int
+}
+
+func newTestStore(t *testing.T, opts ...Option) (*PostgresStore, *fakeDriver) {
+	t.Helper()
+
+	d := &fakeDriver{}
+	driverSeq.Lock()
+	driverSeq.n++
+	name := fmt.Sprintf("fake%d", driverSeq.n)
+	driverSeq.Unlock()
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	s, err := NewPostgresStore(db, opts...)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return s, d
+}
+
+func TestNewPostgresStore_InvalidTable(t *testing.T) {
+	if _, err := NewPostgresStore(nil, WithTable("candles; DROP TABLE users")); err == nil {
+		t.Error("Expected an error for an invalid table name")
+	}
+	if _, err := NewPostgresStore(nil, WithTable("market.candles")); err != nil {
+		t.Errorf("Expected a schema-qualified name to be accepted, got %v", err)
+	}
+}
+
+func TestPostgresStore_Migrate(t *testing.T) {
+	s, d := newTestStore(t, WithTable("market.candles"), WithTimescale())
+
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(d.execs) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(d.execs))
+	}
+	if !strings.Contains(d.execs[0], "CREATE TABLE IF NOT EXISTS market.candles") {
+		t.Errorf("Expected the table to be created, got %s", d.execs[0])
+	}
+	if !strings.Contains(d.execs[1], "create_hypertable") || d.args[1][0] != "market.candles" {
+		t.Errorf("Expected a hypertable to be created, got %s %v", d.execs[1], d.args[1])
+	}
+}
+
+func TestPostgresStore_Save(t *testing.T) {
+	s, d := newTestStore(t)
+	s.batchSize = 2
+
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, types.ExchangeNSE.Location())
+	var candles []types.OHLCV
+	for i := range 3 {
+		candles = append(candles, types.OHLCV{
+			Symbol:   "INFY",
+			Exchange: types.ExchangeNSE,
+			Open:     100,
+			Close:    float64(101 + i),
+			Volume:   10,
+			DateTime: base.Add(time.Duration(i) * time.Minute),
+			Session:  types.SessionRegular,
+		})
+	}
+
+	if err := s.Save(context.Background(), types.Interval1m, candles); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(d.execs) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(d.execs))
+	}
+	if d.committed != 1 {
+		t.Errorf("Expected a single transaction, got %d commits", d.committed)
+	}
+	if !strings.Contains(d.execs[0], "ON CONFLICT (symbol, exchange, timeframe, datetime) DO UPDATE SET open = EXCLUDED.open") {
+		t.Errorf("Expected an upsert, got %s", d.execs[0])
+	}
+	if got := len(d.args[0]); got != 2*len(columns) {
+		t.Errorf("Expected %d arguments, got %d", 2*len(columns), got)
+	}
+	if got := len(d.args[1]); got != len(columns) {
+		t.Errorf("Expected %d arguments in the last batch, got %d", len(columns), got)
+	}
+
+	first := d.args[0]
+	if first[0] != "INFY" || first[1] != "NSE" || first[2] != "1m" || first[13] != "regular" {
+		t.Errorf("Expected the candle's keys to be bound, got %v", first[:4])
+	}
+	if at, ok := first[3].(time.Time); !ok || !at.Equal(base) || at.Location() != time.UTC {
+		t.Errorf("Expected the time in UTC, got %v", first[3])
+	}
+}
+
+func TestPostgresStore_Load(t *testing.T) {
+	s, d := newTestStore(t)
+	at := time.Date(2024, 1, 2, 3, 45, 0, 0, time.UTC)
+	d.columns = []string{"datetime", "open", "high", "low", "close", "volume", "source", "freshness", "adjustment_factor", "incomplete", "session"}
+	d.rows = [][]driver.Value{
+		{at, 100.0, 102.0, 99.0, 101.0, int64(10), "yahoo", "historical", 0.0, false, "regular"},
+	}
+
+	candles, err := s.Load(context.Background(), "INFY", types.ExchangeNSE, types.Interval1m, at, at.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(candles) != 1 {
+		t.Fatalf("Expected 1 candle, got %d", len(candles))
+	}
+	c := candles[0]
+	if c.Symbol != "INFY" || c.Exchange != types.ExchangeNSE || c.Close != 101 || c.Volume != 10 {
+		t.Errorf("Expected the stored candle, got %+v", c)
+	}
+	if c.Freshness != types.FreshnessHistorical || c.Session != types.SessionRegular || !c.DateTime.Equal(at) {
+		t.Errorf("Expected freshness, session and time to be read, got %+v", c)
+	}
+}
+
+func TestPostgresStore_Latest(t *testing.T) {
+	s, d := newTestStore(t)
+	d.columns = []string{"max"}
+	d.rows = [][]driver.Value{{nil}}
+
+	if _, ok, err := s.Latest(context.Background(), "INFY", types.ExchangeNSE, types.Interval1d); err != nil || ok {
+		t.Errorf("Expected no latest candle, got ok=%v err=%v", ok, err)
+	}
+
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	d.rows = [][]driver.Value{{at}}
+
+	latest, ok, err := s.Latest(context.Background(), "INFY", types.ExchangeNSE, types.Interval1d)
+	if err != nil || !ok || !latest.Equal(at) {
+		t.Errorf("Expected %v, got %v ok=%v err=%v", at, latest, ok, err)
+	}
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Checksum returns a hash of the values of c: its time, prices, volume and
+// adjustment factor. Two candles with equal checksums hold the same data;
+// source, freshness and other metadata are left out.
+func Checksum(c types.OHLCV) string {
+	var buf [8 * 7]byte
+	fields := []uint64{
+		uint64(c.DateTime.UnixNano()),
+		math.Float64bits(c.Open),
+		math.Float64bits(c.High),
+		math.Float64bits(c.Low),
+		math.Float64bits(c.Close),
+		uint64(c.Volume),
+		math.Float64bits(c.AdjustmentFactor),
+	}
+	for i, f := range fields {
+		binary.BigEndian.PutUint64(buf[i*8:], f)
+	}
+
+	sum := sha256.Sum256(buf[:])
+	return hex.EncodeToString(sum[:16])
+}
+
+// Revision is a stored candle that was saved again with different values,
+// as when a provider restates history.
+type Revision struct {
+	Interval         types.Interval
+	Previous         types.OHLCV
+	Current          types.OHLCV
+	PreviousChecksum string
+	Checksum         string
+	DetectedAt       time.Time
+}
+
+// RevisionLog records revisions. Implementations must be safe for concurrent
+// use.
+type RevisionLog interface {
+	// Record appends revisions to the log.
+	Record(ctx context.Context, revisions []Revision) error
+	// Revisions returns the recorded revisions of symbol's candles between
+	// from and to, to excluded, in the order they were recorded.
+	Revisions(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]Revision, error)
+}
+
+// MemoryRevisionLog is a RevisionLog held in memory.
+type MemoryRevisionLog struct {
+	mu        sync.Mutex
+	revisions []Revision
+}
+
+// NewMemoryRevisionLog creates an empty MemoryRevisionLog.
+func NewMemoryRevisionLog() *MemoryRevisionLog {
+	return &MemoryRevisionLog{}
+}
+
+func (l *MemoryRevisionLog) Record(ctx context.Context, revisions []Revision) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.revisions = append(l.revisions, revisions...)
+	return nil
+}
+
+func (l *MemoryRevisionLog) Revisions(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]Revision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var out []Revision
+	for _, r := range l.revisions {
+		c := r.Current
+		if c.Symbol != symbol || c.Exchange != exchange || r.Interval != interval {
+			continue
+		}
+		if c.DateTime.Before(from) || !c.DateTime.Before(to) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// RevisionTracker is a Store that compares every candle it saves with the one
+// already stored at the same time and records a Revision to its log when the
+// checksums differ. New candles aren't revisions.
+type RevisionTracker struct {
+	Store
+	log RevisionLog
+	now func() time.Time
+}
+
+// NewRevisionTracker wraps store, recording revisions to log.
+func NewRevisionTracker(store Store, log RevisionLog) *RevisionTracker {
+	return &RevisionTracker{Store: store, log: log, now: time.Now}
+}
+
+// Save saves candles to the wrapped store and then records the revisions
+// among them. Nothing is recorded if the save fails.
+func (t *RevisionTracker) Save(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	now := t.now()
+
+	var revisions []Revision
+	err := eachStored(ctx, t.Store, interval, candles, func(stored, group []types.OHLCV) {
+		byTime := make(map[int64]types.OHLCV, len(stored))
+		for _, c := range stored {
+			byTime[c.DateTime.UnixNano()] = c
+		}
+
+		for _, c := range group {
+			prev, ok := byTime[c.DateTime.UnixNano()]
+			if !ok {
+				continue
+			}
+			if before, after := Checksum(prev), Checksum(c); before != after {
+				revisions = append(revisions, Revision{
+					Interval:         interval,
+					Previous:         prev,
+					Current:          c,
+					PreviousChecksum: before,
+					Checksum:         after,
+					DetectedAt:       now,
+				})
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := t.Store.Save(ctx, interval, candles); err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	if err := t.log.Record(ctx, revisions); err != nil {
+		return fmt.Errorf("failed to record revisions: %w", err)
+	}
+	return nil
+}
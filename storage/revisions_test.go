@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestChecksum(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	c := types.OHLCV{Symbol: "INFY", Open: 100, High: 110, Low: 90, Close: 105, Volume: 1000, DateTime: day}
+
+	same := c
+	same.Source, same.Freshness = "yahoo", types.FreshnessHistorical
+	if Checksum(c) != Checksum(same) {
+		t.Error("Expected metadata to be left out of the checksum")
+	}
+
+	restated := c
+	restated.Close = 105.5
+	if Checksum(c) == Checksum(restated) {
+		t.Error("Expected a changed close to change the checksum")
+	}
+
+	moved := c
+	moved.DateTime = day.Add(time.Minute)
+	if Checksum(c) == Checksum(moved) {
+		t.Error("Expected a changed time to change the checksum")
+	}
+}
+
+func TestRevisionTracker_Save(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{candles: []types.OHLCV{
+		{Symbol: "INFY", Exchange: types.ExchangeNSE, Close: 100, DateTime: day},
+		{Symbol: "INFY", Exchange: types.ExchangeNSE, Close: 101, DateTime: day.AddDate(0, 0, 1)},
+	}}
+	log := NewMemoryRevisionLog()
+	tracker := NewRevisionTracker(store, log)
+	detected := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return detected }
+
+	err := tracker.Save(context.Background(), types.Interval1d, []types.OHLCV{
+		{Symbol: "INFY", Exchange: types.ExchangeNSE, Close: 100, DateTime: day},
+		{Symbol: "INFY", Exchange: types.ExchangeNSE, Close: 99.5, DateTime: day.AddDate(0, 0, 1)},
+		{Symbol: "INFY", Exchange: types.ExchangeNSE, Close: 102, DateTime: day.AddDate(0, 0, 2)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if store.saves != 1 || len(store.candles) != 5 {
+		t.Errorf("Expected every candle passed on to the store, got %d saves", store.saves)
+	}
+
+	revisions, err := log.Revisions(context.Background(), "INFY", types.ExchangeNSE, types.Interval1d, day, day.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("Expected 1 revision, got %+v", revisions)
+	}
+
+	r := revisions[0]
+	if r.Previous.Close != 101 || r.Current.Close != 99.5 || !r.DetectedAt.Equal(detected) {
+		t.Errorf("Unexpected revision: %+v", r)
+	}
+	if r.PreviousChecksum != Checksum(r.Previous) || r.Checksum != Checksum(r.Current) {
+		t.Errorf("Expected the checksums of both candles, got %+v", r)
+	}
+
+	if other, _ := log.Revisions(context.Background(), "INFY", types.ExchangeNSE, types.Interval1m, day, day.AddDate(0, 0, 3)); len(other) != 0 {
+		t.Errorf("Expected no revisions at another interval, got %+v", other)
+	}
+}
+
+func TestRevisionTracker_SaveError(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	saveErr := errors.New("disk full")
+	store := &memoryStore{
+		candles: []types.OHLCV{{Symbol: "INFY", Close: 100, DateTime: day}},
+		saveErr: saveErr,
+	}
+	log := NewMemoryRevisionLog()
+
+	err := NewRevisionTracker(store, log).Save(context.Background(), types.Interval1d, []types.OHLCV{{Symbol: "INFY", Close: 101, DateTime: day}})
+	if !errors.Is(err, saveErr) {
+		t.Errorf("Expected the save error, got %v", err)
+	}
+	if revisions, _ := log.Revisions(context.Background(), "INFY", "", types.Interval1d, day, day.Add(time.Hour)); len(revisions) != 0 {
+		t.Errorf("Expected nothing recorded after a failed save, got %+v", revisions)
+	}
+}
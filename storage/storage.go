@@ -0,0 +1,89 @@
+// Package storage persists candles for building a candle warehouse.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Store persists candles keyed by symbol, exchange, interval and time.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Save writes candles of interval, replacing any stored candle with the
+	// same symbol, exchange, interval and time.
+	Save(ctx context.Context, interval types.Interval, candles []types.OHLCV) error
+	// Load returns the stored candles of symbol between from and to, to
+	// excluded, ordered by time.
+	Load(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error)
+	// Latest returns the time of the most recent stored candle of symbol. ok
+	// is false if none is stored.
+	Latest(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval) (t time.Time, ok bool, err error)
+}
+
+// SaveChanges saves only the candles of interval that store doesn't already
+// hold with the same values, as found by ohlcv.Diff against the stored
+// candles over the same range, and returns how many were saved. Candles may
+// span several symbols.
+func SaveChanges(ctx context.Context, store Store, interval types.Interval, candles []types.OHLCV) (int, error) {
+	var changes []types.OHLCV
+	err := eachStored(ctx, store, interval, candles, func(stored, group []types.OHLCV) {
+		changes = append(changes, ohlcv.Diff(stored, group)...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	if err := store.Save(ctx, interval, changes); err != nil {
+		return 0, err
+	}
+	return len(changes), nil
+}
+
+// eachStored groups candles by symbol and exchange, in order of first
+// appearance, and calls fn with each group and the candles store holds over
+// the group's time range.
+func eachStored(ctx context.Context, store Store, interval types.Interval, candles []types.OHLCV, fn func(stored, group []types.OHLCV)) error {
+	type key struct {
+		symbol   string
+		exchange types.Exchange
+	}
+
+	var (
+		order  []key
+		groups = make(map[key][]types.OHLCV)
+	)
+	for _, c := range candles {
+		k := key{c.Symbol, c.Exchange}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], c)
+	}
+
+	for _, k := range order {
+		group := groups[k]
+		from, to := group[0].DateTime, group[0].DateTime
+		for _, c := range group[1:] {
+			if c.DateTime.Before(from) {
+				from = c.DateTime
+			}
+			if c.DateTime.After(to) {
+				to = c.DateTime
+			}
+		}
+
+		stored, err := store.Load(ctx, k.symbol, k.exchange, interval, from, to.Add(time.Nanosecond))
+		if err != nil {
+			return fmt.Errorf("failed to load stored %s candles: %w", k.symbol, err)
+		}
+		fn(stored, group)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type memoryStore struct {
+	candles []types.OHLCV
+	loads   []string
+	saves   int
+	err     error
+	saveErr error
+}
+
+func (s *memoryStore) Save(ctx context.Context, interval types.Interval, candles []types.OHLCV) error {
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.saves++
+	s.candles = append(s.candles, candles...)
+	return nil
+}
+
+func (s *memoryStore) Load(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	s.loads = append(s.loads, symbol)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var out []types.OHLCV
+	for _, c := range s.candles {
+		if c.Symbol == symbol && !c.DateTime.Before(from) && c.DateTime.Before(to) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryStore) Latest(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval) (time.Time, bool, error) {
+	return time.Time{}, false, nil
+}
+
+func TestSaveChanges(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{candles: []types.OHLCV{
+		{Symbol: "INFY", Close: 100, DateTime: day},
+		{Symbol: "INFY", Close: 101, DateTime: day.AddDate(0, 0, 1)},
+		{Symbol: "TCS", Close: 200, DateTime: day},
+	}}
+
+	saved, err := SaveChanges(context.Background(), store, types.Interval1d, []types.OHLCV{
+		{Symbol: "INFY", Close: 100, DateTime: day},
+		{Symbol: "INFY", Close: 102, DateTime: day.AddDate(0, 0, 1)},
+		{Symbol: "TCS", Close: 200, DateTime: day},
+		{Symbol: "TCS", Close: 201, DateTime: day.AddDate(0, 0, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if saved != 2 || store.saves != 1 {
+		t.Errorf("Expected 2 candles saved in one call, got %d in %d", saved, store.saves)
+	}
+	if got := store.candles[3:]; got[0].Close != 102 || got[1].Close != 201 {
+		t.Errorf("Expected the corrected and new candles saved, got %+v", got)
+	}
+	if len(store.loads) != 2 {
+		t.Errorf("Expected one load per symbol, got %v", store.loads)
+	}
+}
+
+func TestSaveChanges_NoChanges(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	candles := []types.OHLCV{{Symbol: "INFY", Close: 100, DateTime: day}}
+	store := &memoryStore{candles: candles}
+
+	saved, err := SaveChanges(context.Background(), store, types.Interval1d, candles)
+	if err != nil || saved != 0 || store.saves != 0 {
+		t.Errorf("Expected nothing saved, got %d saved in %d calls, err %v", saved, store.saves, err)
+	}
+}
+
+func TestSaveChanges_LoadError(t *testing.T) {
+	loadErr := errors.New("connection refused")
+	store := &memoryStore{err: loadErr}
+
+	_, err := SaveChanges(context.Background(), store, types.Interval1d, []types.OHLCV{{Symbol: "INFY", Close: 100}})
+	if !errors.Is(err, loadErr) || store.saves != 0 {
+		t.Errorf("Expected the load error and nothing saved, got %v", err)
+	}
+}
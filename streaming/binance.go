@@ -0,0 +1,101 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+const binanceURL = "wss://stream.binance.com:9443/stream"
+
+// BinanceStreamer streams trades from Binance's public combined streams. No
+// authentication is needed; symbols are Binance pairs such as "BTCUSDT" and
+// the exchange is ignored.
+type BinanceStreamer struct {
+	cfg config
+}
+
+func NewBinanceStreamer(opts ...Option) *BinanceStreamer {
+	return &BinanceStreamer{cfg: newConfig(binanceURL, opts)}
+}
+
+func (b *BinanceStreamer) Name() string {
+	return "binance"
+}
+
+func (b *BinanceStreamer) Stream(ctx context.Context, exchange types.Exchange, symbols ...string) (<-chan types.Tick, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols to stream")
+	}
+
+	streams := make([]string, len(symbols))
+	for i, s := range symbols {
+		streams[i] = strings.ToLower(s) + "@trade"
+	}
+
+	f := &binanceFeed{url: b.cfg.url + "?streams=" + strings.Join(streams, "/"), source: b.Name()}
+	out := make(chan types.Tick, b.cfg.bufferSize)
+	go run(ctx, f, b.cfg, out)
+
+	return out, nil
+}
+
+type binanceFeed struct {
+	url    string
+	source string
+}
+
+type binanceMessage struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		Event     string `json:"e"`
+		Symbol    string `json:"s"`
+		Price     string `json:"p"`
+		Quantity  string `json:"q"`
+		TradeTime int64  `json:"T"`
+	} `json:"data"`
+}
+
+// dial returns the combined stream URL. The streams are part of the URL, so
+// every reconnect resubscribes to them.
+func (f *binanceFeed) dial(ctx context.Context) (string, error) {
+	return f.url, nil
+}
+
+func (f *binanceFeed) subscribe(ctx context.Context, conn *websocket.Conn) error {
+	return nil
+}
+
+func (f *binanceFeed) decode(typ websocket.MessageType, data []byte) ([]types.Tick, error) {
+	var msg binanceMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+	if msg.Data.Event != "trade" {
+		return nil, nil
+	}
+
+	price, err := strconv.ParseFloat(msg.Data.Price, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q: %w", msg.Data.Price, err)
+	}
+	qty, err := strconv.ParseFloat(msg.Data.Quantity, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quantity %q: %w", msg.Data.Quantity, err)
+	}
+
+	return []types.Tick{{
+		Symbol:   msg.Data.Symbol,
+		Price:    price,
+		Quantity: qty,
+		DateTime: time.UnixMilli(msg.Data.TradeTime).UTC(),
+		Source:   f.source,
+		Trades:   1,
+	}}, nil
+}
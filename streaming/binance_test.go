@@ -0,0 +1,139 @@
+package streaming
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func wsURL(srv *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func receive(t *testing.T, ticks <-chan types.Tick) types.Tick {
+	t.Helper()
+
+	select {
+	case tick, ok := <-ticks:
+		if !ok {
+			t.Fatal("Expected a tick, got closed channel")
+		}
+		return tick
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a tick, got timeout")
+	}
+	return types.Tick{}
+}
+
+func TestBinanceStreamer_Stream(t *testing.T) {
+	var streams string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		streams = r.URL.Query().Get("streams")
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		msg := `{"stream":"btcusdt@trade","data":{"e":"trade","s":"BTCUSDT","p":"50000.50","q":"0.25","T":1704067200000}}`
+		conn.Write(r.Context(), websocket.MessageText, []byte(msg))
+		conn.Read(r.Context())
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewBinanceStreamer(WithURL(wsURL(srv)))
+	ticks, err := b.Stream(ctx, "", "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tick := receive(t, ticks)
+	if streams != "btcusdt@trade" {
+		t.Errorf("Expected streams btcusdt@trade, got %s", streams)
+	}
+	if tick.Symbol != "BTCUSDT" || tick.Price != 50000.50 || tick.Quantity != 0.25 || tick.Trades != 1 {
+		t.Errorf("Unexpected tick: %+v", tick)
+	}
+	if !tick.DateTime.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2024-01-01T00:00:00Z, got %v", tick.DateTime)
+	}
+	if tick.Source != "binance" {
+		t.Errorf("Expected source binance, got %s", tick.Source)
+	}
+
+	cancel()
+	for range ticks {
+	}
+}
+
+func TestBinanceStreamer_Stream_Reconnects(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		n := conns.Add(1)
+		msg := `{"stream":"btcusdt@trade","data":{"e":"trade","s":"BTCUSDT","p":"` + strconv.Itoa(int(n)) + `","q":"1","T":1704067200000}}`
+		conn.Write(r.Context(), websocket.MessageText, []byte(msg))
+		if n > 1 {
+			conn.Read(r.Context())
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var errs atomic.Int32
+	b := NewBinanceStreamer(
+		WithURL(wsURL(srv)),
+		WithReconnectBackoff(10*time.Millisecond, 10*time.Millisecond),
+		WithErrorHandler(func(error) { errs.Add(1) }),
+	)
+	ticks, err := b.Stream(ctx, "", "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if tick := receive(t, ticks); tick.Price != 1 {
+		t.Errorf("Expected price 1 from first connection, got %v", tick.Price)
+	}
+	if tick := receive(t, ticks); tick.Price != 2 {
+		t.Errorf("Expected price 2 after reconnect, got %v", tick.Price)
+	}
+	if errs.Load() == 0 {
+		t.Error("Expected the dropped connection to be reported")
+	}
+
+	cancel()
+	for range ticks {
+	}
+}
+
+func TestBinanceStreamer_Stream_NoSymbols(t *testing.T) {
+	b := NewBinanceStreamer()
+	if _, err := b.Stream(context.Background(), ""); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestBinanceFeed_Decode_InvalidMessage(t *testing.T) {
+	f := &binanceFeed{source: "binance"}
+	if _, err := f.decode(websocket.MessageText, []byte("not json")); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
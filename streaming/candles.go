@@ -0,0 +1,210 @@
+package streaming
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// CandleBuilder aggregates ticks into OHLCV bars of a fixed interval, one
+// in-progress bar per symbol and exchange. Bars are aligned the way
+// ohlcv.Resample aligns them, in the location of the ticks' timestamps, and
+// carry FreshnessRealtime. Their TradeCount sums the Trades of their ticks.
+// It is safe for concurrent use.
+type CandleBuilder struct {
+	interval types.Interval
+	clock    Clock
+
+	mu   sync.Mutex
+	bars map[barKey]*bar
+}
+
+type barKey struct {
+	symbol   string
+	exchange types.Exchange
+}
+
+type bar struct {
+	candle types.OHLCV
+	end    time.Time
+	// volume accumulates fractional tick quantities before they are rounded
+	// into the candle's volume.
+	volume float64
+	// closed is the end of the last bar emitted; ticks before it are late.
+	closed time.Time
+	open   bool
+}
+
+// NewCandleBuilder creates a builder for interval, which must be one
+// ohlcv.Resample can produce from minute candles. Of opts, only WithClock
+// applies.
+func NewCandleBuilder(interval types.Interval, opts ...Option) (*CandleBuilder, error) {
+	if interval != types.Interval1m && !ohlcv.CanResample(types.Interval1m, interval) {
+		return nil, fmt.Errorf("cannot build %s candles from ticks", interval)
+	}
+
+	return &CandleBuilder{
+		interval: interval,
+		clock:    newConfig("", opts).clock,
+		bars:     make(map[barKey]*bar),
+	}, nil
+}
+
+// Add folds t into its symbol's bar. If t belongs to a later bar, the current
+// one is finalized and returned. Ticks older than a bar already finalized are
+// dropped.
+func (b *CandleBuilder) Add(t types.Tick) []types.OHLCV {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := barKey{symbol: t.Symbol, exchange: t.Exchange}
+	br, ok := b.bars[key]
+	if !ok {
+		br = &bar{}
+		b.bars[key] = br
+	}
+
+	if t.DateTime.Before(br.closed) {
+		return nil
+	}
+
+	var closed []types.OHLCV
+	if br.open && !t.DateTime.Before(br.end) {
+		closed = append(closed, br.finalize())
+	}
+
+	if !br.open {
+		start, end := ohlcv.Bucket(t.DateTime, b.interval)
+		br.candle = types.OHLCV{
+			Symbol:    t.Symbol,
+			Exchange:  t.Exchange,
+			Open:      t.Price,
+			High:      t.Price,
+			Low:       t.Price,
+			DateTime:  start,
+			Interval:  b.interval,
+			Source:    t.Source,
+			Freshness: types.FreshnessRealtime,
+		}
+		br.end = end
+		br.volume = 0
+		br.open = true
+	}
+
+	br.candle.High = max(br.candle.High, t.Price)
+	br.candle.Low = min(br.candle.Low, t.Price)
+	br.candle.Close = t.Price
+	br.volume += t.Quantity
+	br.candle.Volume = int64(math.Round(br.volume))
+	br.candle.TradeCount += t.Trades
+
+	return closed
+}
+
+// Current returns the in-progress bar of symbol on exchange.
+func (b *CandleBuilder) Current(symbol string, exchange types.Exchange) (types.OHLCV, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br, ok := b.bars[barKey{symbol: symbol, exchange: exchange}]
+	if !ok || !br.open {
+		return types.OHLCV{}, false
+	}
+	return br.candle, true
+}
+
+// CloseDue finalizes and returns every bar that ends at or before now, so bars
+// close on time even when no further tick arrives.
+func (b *CandleBuilder) CloseDue(now time.Time) []types.OHLCV {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var closed []types.OHLCV
+	for _, br := range b.bars {
+		if br.open && !now.Before(br.end) {
+			closed = append(closed, br.finalize())
+		}
+	}
+	return sortBars(closed)
+}
+
+// Flush finalizes and returns every in-progress bar regardless of its end.
+func (b *CandleBuilder) Flush() []types.OHLCV {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var closed []types.OHLCV
+	for _, br := range b.bars {
+		if br.open {
+			closed = append(closed, br.finalize())
+		}
+	}
+	return sortBars(closed)
+}
+
+// Run feeds ticks into the builder and sends each bar on the returned channel
+// as it closes, either because a tick for a later bar arrived or because the
+// bar's end has passed on the builder's clock. It is meant for live ticks and
+// those of a ReplayStreamer whose clock the builder was given; build bars from
+// recorded ticks with Add instead. The channel is closed when ticks is
+// closed or ctx is done; bars still in progress are not sent.
+func (b *CandleBuilder) Run(ctx context.Context, ticks <-chan types.Tick) <-chan types.OHLCV {
+	out := make(chan types.OHLCV, 64)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			var closed []types.OHLCV
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-ticks:
+				if !ok {
+					return
+				}
+				closed = b.Add(t)
+			case <-ticker.C:
+				closed = b.CloseDue(b.clock.Now())
+			}
+
+			for _, c := range closed {
+				select {
+				case out <- c:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// sortBars orders bars closed together by time, then symbol, since map
+// iteration order is random.
+func sortBars(bars []types.OHLCV) []types.OHLCV {
+	slices.SortFunc(bars, func(a, b types.OHLCV) int {
+		if c := a.DateTime.Compare(b.DateTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Symbol, b.Symbol)
+	})
+	return bars
+}
+
+func (br *bar) finalize() types.OHLCV {
+	br.open = false
+	br.closed = br.end
+	return br.candle
+}
@@ -0,0 +1,147 @@
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var ist = time.FixedZone("IST", 5*3600+1800)
+
+func tick(symbol string, at time.Time, price, qty float64) types.Tick {
+	return types.Tick{Symbol: symbol, Exchange: types.ExchangeNSE, Price: price, Quantity: qty, DateTime: at, Source: "test"}
+}
+
+func TestNewCandleBuilder_UnsupportedInterval(t *testing.T) {
+	if _, err := NewCandleBuilder(types.Interval5d); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestCandleBuilder_Add(t *testing.T) {
+	b, err := NewCandleBuilder(types.Interval1m)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, ist)
+	for _, tk := range []types.Tick{
+		tick("RELIANCE", base.Add(1*time.Second), 100, 10),
+		tick("RELIANCE", base.Add(20*time.Second), 105, 5),
+		tick("RELIANCE", base.Add(40*time.Second), 98, 5),
+		tick("RELIANCE", base.Add(59*time.Second), 101, 0.4),
+	} {
+		if closed := b.Add(tk); len(closed) != 0 {
+			t.Fatalf("Expected no closed bar, got %v", closed)
+		}
+	}
+
+	current, ok := b.Current("RELIANCE", types.ExchangeNSE)
+	if !ok || current.Close != 101 {
+		t.Errorf("Expected in-progress bar closing at 101, got %+v", current)
+	}
+
+	closed := b.Add(tick("RELIANCE", base.Add(61*time.Second), 102, 1))
+	if len(closed) != 1 {
+		t.Fatalf("Expected 1 closed bar, got %d", len(closed))
+	}
+
+	c := closed[0]
+	if !c.DateTime.Equal(base) {
+		t.Errorf("Expected bar at %v, got %v", base, c.DateTime)
+	}
+	if c.Open != 100 || c.High != 105 || c.Low != 98 || c.Close != 101 || c.Volume != 20 {
+		t.Errorf("Unexpected bar: %+v", c)
+	}
+	if c.TradeCount != 0 {
+		t.Errorf("Expected no trade count from price updates, got %d", c.TradeCount)
+	}
+	if c.Freshness != types.FreshnessRealtime || c.Source != "test" {
+		t.Errorf("Expected realtime bar from test, got %s from %s", c.Freshness, c.Source)
+	}
+	if c.Interval != types.Interval1m || !c.EndTime().Equal(base.Add(time.Minute)) {
+		t.Errorf("Expected a 1m bar ending at %v, got %q ending at %v", base.Add(time.Minute), c.Interval, c.EndTime())
+	}
+
+	if closed := b.Add(tick("RELIANCE", base.Add(30*time.Second), 90, 1)); len(closed) != 0 {
+		t.Errorf("Expected late tick to be dropped, got %v", closed)
+	}
+	if current, _ := b.Current("RELIANCE", types.ExchangeNSE); current.Low != 102 {
+		t.Errorf("Expected late tick not to touch the current bar, got %+v", current)
+	}
+}
+
+func TestCandleBuilder_Add_TradeCount(t *testing.T) {
+	b, _ := NewCandleBuilder(types.Interval1m)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		trade := tick("BTCUSDT", base.Add(time.Duration(i)*time.Second), 50000, 0.1)
+		trade.Trades = 1
+		b.Add(trade)
+	}
+
+	if c, _ := b.Current("BTCUSDT", types.ExchangeNSE); c.TradeCount != 3 {
+		t.Errorf("Expected 3 trades, got %d", c.TradeCount)
+	}
+}
+
+func TestCandleBuilder_CloseDue(t *testing.T) {
+	b, _ := NewCandleBuilder(types.Interval5m)
+
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, ist)
+	b.Add(tick("TCS", base.Add(time.Minute), 3500, 1))
+	b.Add(tick("INFY", base.Add(2*time.Minute), 1500, 1))
+
+	if closed := b.CloseDue(base.Add(4 * time.Minute)); len(closed) != 0 {
+		t.Errorf("Expected no bar due, got %v", closed)
+	}
+
+	closed := b.CloseDue(base.Add(5 * time.Minute))
+	if len(closed) != 2 || closed[0].Symbol != "INFY" || closed[1].Symbol != "TCS" {
+		t.Fatalf("Expected INFY and TCS bars, got %v", closed)
+	}
+	if _, ok := b.Current("TCS", types.ExchangeNSE); ok {
+		t.Error("Expected no in-progress bar after close")
+	}
+}
+
+func TestCandleBuilder_Flush(t *testing.T) {
+	b, _ := NewCandleBuilder(types.Interval1d)
+	b.Add(tick("TCS", time.Date(2024, 1, 2, 10, 0, 0, 0, ist), 3500, 1))
+
+	closed := b.Flush()
+	if len(closed) != 1 || !closed[0].DateTime.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, ist)) {
+		t.Errorf("Expected daily bar at midnight, got %v", closed)
+	}
+}
+
+func TestCandleBuilder_Run(t *testing.T) {
+	b, _ := NewCandleBuilder(types.Interval1m)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks := make(chan types.Tick)
+	candles := b.Run(ctx, ticks)
+
+	// A bar that ended a few minutes ago closes on the next clock check
+	// without waiting for another tick.
+	ticks <- tick("RELIANCE", time.Now().Add(-3*time.Minute), 100, 1)
+
+	select {
+	case c := <-candles:
+		if c.Symbol != "RELIANCE" || c.Close != 100 {
+			t.Errorf("Unexpected bar: %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a closed bar, got timeout")
+	}
+
+	close(ticks)
+	if _, ok := <-candles; ok {
+		t.Error("Expected channel to be closed")
+	}
+}
@@ -0,0 +1,196 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Clock tells the time streaming runs on.
+type Clock interface {
+	Now() time.Time
+}
+
+type wallClock struct{}
+
+func (wallClock) Now() time.Time {
+	return time.Now()
+}
+
+// SimulatedClock is the clock of a ReplayStreamer: it reads the time of the
+// last tick replayed, so code under test can use it in place of the wall
+// clock. It is safe for concurrent use.
+type SimulatedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the time of the last tick replayed, or the start of the replay
+// before the first one.
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// advance moves the clock to t unless it is already past it.
+func (c *SimulatedClock) advance(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.After(c.now) {
+		c.now = t
+	}
+}
+
+// ReplayStreamer is a Streamer that replays historical candles from a provider
+// as ticks, in real time or faster, so live-trading code can be tested
+// against history through the same channel as a live feed. Each candle
+// becomes four ticks spread over its interval, open first and close last,
+// passing through the low before the high on an up candle and the high first
+// otherwise; its volume is split between them. The channel is closed once
+// the range has been replayed.
+type ReplayStreamer struct {
+	cfg        config
+	source     provider.OHLCVProvider
+	interval   types.Interval
+	start, end time.Time
+	clock      *SimulatedClock
+}
+
+// NewReplayStreamer creates a ReplayStreamer replaying the interval candles
+// source serves between start and end. Of opts, WithSpeed, WithBufferSize and
+// WithErrorHandler apply.
+func NewReplayStreamer(source provider.OHLCVProvider, interval types.Interval, start, end time.Time, opts ...Option) *ReplayStreamer {
+	return &ReplayStreamer{
+		cfg:      newConfig("", opts),
+		source:   source,
+		interval: interval,
+		start:    start,
+		end:      end,
+		clock:    &SimulatedClock{now: start},
+	}
+}
+
+func (r *ReplayStreamer) Name() string {
+	return "replay"
+}
+
+// Clock returns the simulated clock the replay advances. Pass it to
+// NewCandleBuilder with WithClock so bars close on replayed time.
+func (r *ReplayStreamer) Clock() *SimulatedClock {
+	return r.clock
+}
+
+// Stream fetches the history of symbols and replays it. A symbol whose
+// history can't be fetched is reported to the error handler and left out; if
+// none can be, Stream fails.
+func (r *ReplayStreamer) Stream(ctx context.Context, exchange types.Exchange, symbols ...string) (<-chan types.Tick, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols to stream")
+	}
+
+	var (
+		ticks []types.Tick
+		errs  []error
+	)
+	for _, symbol := range symbols {
+		candles, err := r.source.Provide(ctx, symbol, exchange, r.interval, r.start, r.end)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", symbol, err)
+			r.cfg.report(err)
+			errs = append(errs, err)
+			continue
+		}
+		for _, c := range candles {
+			c.Symbol, c.Exchange = symbol, exchange
+			ticks = append(ticks, r.candleTicks(c)...)
+		}
+	}
+	if len(errs) == len(symbols) {
+		return nil, fmt.Errorf("failed to load history: %w", errors.Join(errs...))
+	}
+
+	slices.SortStableFunc(ticks, func(a, b types.Tick) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+
+	out := make(chan types.Tick, r.cfg.bufferSize)
+	go r.replay(ctx, ticks, out)
+
+	return out, nil
+}
+
+// replay sends ticks to out, spaced by their time apart divided by the
+// replay speed, and closes out on return.
+func (r *ReplayStreamer) replay(ctx context.Context, ticks []types.Tick, out chan<- types.Tick) {
+	defer close(out)
+	if len(ticks) == 0 {
+		return
+	}
+
+	began, first := time.Now(), ticks[0].DateTime
+	for _, t := range ticks {
+		if r.cfg.speed > 0 {
+			due := began.Add(time.Duration(float64(t.DateTime.Sub(first)) / r.cfg.speed))
+			if wait := time.Until(due); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+		}
+
+		r.clock.advance(t.DateTime)
+		select {
+		case out <- t:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// candleTicks turns c into the four ticks of its path through the bar.
+func (r *ReplayStreamer) candleTicks(c types.OHLCV) []types.Tick {
+	start, end := ohlcv.Bucket(c.DateTime, r.interval)
+	step := end.Sub(start) / 4
+
+	prices := [4]float64{c.Open, c.High, c.Low, c.Close}
+	if c.Close >= c.Open {
+		prices[1], prices[2] = c.Low, c.High
+	}
+
+	source := c.Source
+	if source == "" {
+		source = r.Name()
+	}
+
+	ticks := make([]types.Tick, 4)
+	share := c.Volume / 4
+	for i, price := range prices {
+		quantity := share
+		if i == 3 {
+			quantity = c.Volume - 3*share
+		}
+		ticks[i] = types.Tick{
+			Symbol:   c.Symbol,
+			Exchange: c.Exchange,
+			Price:    price,
+			Quantity: float64(quantity),
+			DateTime: start.Add(time.Duration(i) * step),
+			Source:   source,
+		}
+	}
+	return ticks
+}
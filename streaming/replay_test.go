@@ -0,0 +1,178 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+type historyProvider struct {
+	candles map[string][]types.OHLCV
+}
+
+func (h *historyProvider) Name() string {
+	return "history"
+}
+
+func (h *historyProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, start, end time.Time) ([]types.OHLCV, error) {
+	candles, ok := h.candles[symbol]
+	if !ok {
+		return nil, provider.ErrSymbolNotFound
+	}
+	return candles, nil
+}
+
+func replayHistory() (*historyProvider, time.Time) {
+	base := time.Date(2024, 1, 2, 9, 15, 0, 0, ist)
+	return &historyProvider{candles: map[string][]types.OHLCV{
+		"INFY": {
+			{Open: 100, High: 105, Low: 99, Close: 104, Volume: 10, DateTime: base},
+			{Open: 104, High: 106, Low: 101, Close: 102, Volume: 7, DateTime: base.Add(time.Minute)},
+		},
+		"TCS": {
+			{Open: 50, High: 51, Low: 49, Close: 50, Volume: 4, DateTime: base},
+		},
+	}}, base
+}
+
+func collect(ticks <-chan types.Tick) []types.Tick {
+	var out []types.Tick
+	for t := range ticks {
+		out = append(out, t)
+	}
+	return out
+}
+
+func TestReplayStreamer_Stream(t *testing.T) {
+	history, base := replayHistory()
+	r := NewReplayStreamer(history, types.Interval1m, base, base.Add(time.Hour), WithSpeed(0))
+
+	ch, err := r.Stream(context.Background(), types.ExchangeNSE, "INFY", "TCS")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ticks := collect(ch)
+
+	if len(ticks) != 12 {
+		t.Fatalf("Expected 4 ticks per candle, got %d", len(ticks))
+	}
+	for i := 1; i < len(ticks); i++ {
+		if ticks[i].DateTime.Before(ticks[i-1].DateTime) {
+			t.Fatalf("Expected ticks in time order, got %v after %v", ticks[i].DateTime, ticks[i-1].DateTime)
+		}
+	}
+
+	var infy []float64
+	var volume float64
+	for _, tk := range ticks {
+		if tk.Symbol == "INFY" && tk.DateTime.Before(base.Add(time.Minute)) {
+			infy = append(infy, tk.Price)
+			volume += tk.Quantity
+			if tk.Exchange != types.ExchangeNSE || tk.Source != "replay" {
+				t.Errorf("Expected NSE ticks from replay, got %+v", tk)
+			}
+		}
+	}
+	if want := []float64{100, 99, 105, 104}; len(infy) != 4 || infy[0] != want[0] || infy[1] != want[1] || infy[2] != want[2] || infy[3] != want[3] {
+		t.Errorf("Expected an up candle to go open, low, high, close %v, got %v", want, infy)
+	}
+	if volume != 10 {
+		t.Errorf("Expected the candle's volume to be split across its ticks, got %v", volume)
+	}
+
+	if now := r.Clock().Now(); !now.Equal(base.Add(time.Minute + 45*time.Second)) {
+		t.Errorf("Expected the clock at the last tick, got %v", now)
+	}
+}
+
+func TestReplayStreamer_RebuildsCandles(t *testing.T) {
+	history, base := replayHistory()
+	r := NewReplayStreamer(history, types.Interval1m, base, base.Add(time.Hour), WithSpeed(0))
+	b, err := NewCandleBuilder(types.Interval1m, WithClock(r.Clock()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ch, err := r.Stream(context.Background(), types.ExchangeNSE, "INFY")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var bars []types.OHLCV
+	for c := range b.Run(context.Background(), ch) {
+		bars = append(bars, c)
+	}
+
+	if len(bars) != 1 {
+		t.Fatalf("Expected the first candle to close when the second starts, got %d bars", len(bars))
+	}
+	if c := bars[0]; c.Open != 100 || c.High != 105 || c.Low != 99 || c.Close != 104 || c.Volume != 10 {
+		t.Errorf("Expected the original candle back, got %+v", c)
+	}
+}
+
+func TestReplayStreamer_Speed(t *testing.T) {
+	history, base := replayHistory()
+	// 105 seconds of ticks at 1500x take 70ms.
+	r := NewReplayStreamer(history, types.Interval1m, base, base.Add(time.Hour), WithSpeed(1500))
+
+	began := time.Now()
+	ch, err := r.Stream(context.Background(), types.ExchangeNSE, "INFY")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	collect(ch)
+
+	if elapsed := time.Since(began); elapsed < 60*time.Millisecond {
+		t.Errorf("Expected the replay to be paced, took %v", elapsed)
+	}
+}
+
+func TestReplayStreamer_Errors(t *testing.T) {
+	history, base := replayHistory()
+	var reported []error
+	r := NewReplayStreamer(history, types.Interval1m, base, base.Add(time.Hour), WithSpeed(0), WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	ch, err := r.Stream(context.Background(), types.ExchangeNSE, "INFY", "UNKNOWN")
+	if err != nil {
+		t.Fatalf("Expected the known symbol to be replayed, got %v", err)
+	}
+	if ticks := collect(ch); len(ticks) != 8 {
+		t.Errorf("Expected 8 ticks, got %d", len(ticks))
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], provider.ErrSymbolNotFound) {
+		t.Errorf("Expected the unknown symbol to be reported, got %v", reported)
+	}
+
+	if _, err := r.Stream(context.Background(), types.ExchangeNSE, "UNKNOWN"); !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+}
+
+func TestReplayStreamer_ContextCancelled(t *testing.T) {
+	history, base := replayHistory()
+	r := NewReplayStreamer(history, types.Interval1m, base, base.Add(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := r.Stream(ctx, types.ExchangeNSE, "INFY")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	<-ch
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		for ok {
+			_, ok = <-ch
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the channel to close after cancellation")
+	}
+}
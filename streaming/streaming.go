@@ -0,0 +1,224 @@
+// Package streaming delivers live ticks from provider WebSocket feeds.
+package streaming
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Streamer is the live counterpart of provider.OHLCVProvider. Stream
+// subscribes to symbols on exchange and delivers ticks until ctx is done,
+// reconnecting and resubscribing whenever the connection drops. The returned
+// channel is closed once streaming stops.
+type Streamer interface {
+	Name() string
+	Stream(ctx context.Context, exchange types.Exchange, symbols ...string) (<-chan types.Tick, error)
+}
+
+type config struct {
+	url          string
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	heartbeat    time.Duration
+	errorHandler func(error)
+	bufferSize   int
+	clock        Clock
+	speed        float64
+}
+
+type Option func(*config)
+
+// WithURL overrides the feed's endpoint: the WebSocket URL for Binance and
+// the feed authorization URL for Upstox.
+func WithURL(url string) Option {
+	return func(c *config) {
+		c.url = url
+	}
+}
+
+// WithReconnectBackoff sets the delay before the first reconnect attempt and
+// the cap it doubles up to on consecutive failures. The defaults are one
+// second and one minute.
+func WithReconnectBackoff(min, max time.Duration) Option {
+	return func(c *config) {
+		c.minBackoff = min
+		c.maxBackoff = max
+	}
+}
+
+// WithHeartbeat sets how often the connection is pinged; a ping that isn't
+// answered within the same interval forces a reconnect. The default is 30
+// seconds.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(c *config) {
+		c.heartbeat = interval
+	}
+}
+
+// WithErrorHandler is called with every connection, subscription and decode
+// error. Streaming carries on after each of them.
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *config) {
+		c.errorHandler = fn
+	}
+}
+
+// WithBufferSize sets the capacity of the tick channel. The default is 256.
+func WithBufferSize(n int) Option {
+	return func(c *config) {
+		c.bufferSize = n
+	}
+}
+
+// WithClock sets the clock a CandleBuilder closes bars on, such as the
+// SimulatedClock of a ReplayStreamer. The default is the wall clock.
+func WithClock(c Clock) Option {
+	return func(cfg *config) {
+		cfg.clock = c
+	}
+}
+
+// WithSpeed makes a ReplayStreamer replay history factor times faster than it
+// happened; 60 replays an hour in a minute. Zero or less replays as fast as
+// the ticks are read. The default is 1, real time.
+func WithSpeed(factor float64) Option {
+	return func(c *config) {
+		c.speed = factor
+	}
+}
+
+func newConfig(url string, opts []Option) config {
+	cfg := config{
+		url:        url,
+		minBackoff: time.Second,
+		maxBackoff: time.Minute,
+		heartbeat:  30 * time.Second,
+		bufferSize: 256,
+		clock:      wallClock{},
+		speed:      1,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// feed is the part of a streamer that differs per provider.
+type feed interface {
+	// dial returns the URL to connect to, authorizing first if needed.
+	dial(ctx context.Context) (string, error)
+	// subscribe is called after every (re)connect.
+	subscribe(ctx context.Context, conn *websocket.Conn) error
+	decode(typ websocket.MessageType, data []byte) ([]types.Tick, error)
+}
+
+// run keeps f connected until ctx is done, sending decoded ticks to out and
+// closing it on return.
+func run(ctx context.Context, f feed, cfg config, out chan<- types.Tick) {
+	defer close(out)
+
+	backoff := cfg.minBackoff
+	for {
+		err := session(ctx, f, cfg, out, func() { backoff = cfg.minBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+		cfg.report(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, cfg.maxBackoff)
+	}
+}
+
+// session runs a single connection until it fails or ctx is done. connected
+// is called once the subscription succeeds.
+func session(ctx context.Context, f feed, cfg config, out chan<- types.Tick, connected func()) error {
+	url, err := f.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(1 << 20)
+
+	if err := f.subscribe(ctx, conn); err != nil {
+		return err
+	}
+	connected()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go heartbeat(ctx, conn, cfg.heartbeat, cancel)
+
+	for {
+		typ, data, err := conn.Read(ctx)
+		if err != nil {
+			if cause := context.Cause(ctx); cause != nil {
+				return cause
+			}
+			return err
+		}
+
+		ticks, err := f.decode(typ, data)
+		if err != nil {
+			cfg.report(err)
+			continue
+		}
+
+		for _, t := range ticks {
+			select {
+			case out <- t:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// errHeartbeat ends a session whose ping went unanswered.
+var errHeartbeat = errors.New("heartbeat timed out")
+
+// heartbeat pings conn every interval and cancels the session when a ping
+// goes unanswered.
+func heartbeat(ctx context.Context, conn *websocket.Conn, interval time.Duration, cancel context.CancelCauseFunc) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, pingCancel := context.WithTimeout(ctx, interval)
+			err := conn.Ping(pingCtx)
+			pingCancel()
+			if err != nil {
+				cancel(errHeartbeat)
+				return
+			}
+		}
+	}
+}
+
+func (c config) report(err error) {
+	if err == nil || c.errorHandler == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	c.errorHandler(err)
+}
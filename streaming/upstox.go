@@ -0,0 +1,253 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/provider/upstox"
+	"github.com/shahid-2020/gohlcv/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const upstoxAuthorizeURL = "https://api.upstox.com/v3/feed/market-data-feed/authorize"
+
+// UpstoxStreamer streams last traded prices from the Upstox v3 market data
+// feed, which requires an access token.
+type UpstoxStreamer struct {
+	cfg         config
+	accessToken string
+	instruments *upstox.InstrumentStore
+	httpClient  *http.Client
+}
+
+// NewUpstoxStreamer creates a streamer that resolves symbols through
+// instruments, or the embedded instrument list if it is nil.
+func NewUpstoxStreamer(accessToken string, instruments *upstox.InstrumentStore, opts ...Option) *UpstoxStreamer {
+	if instruments == nil {
+		instruments = upstox.NewInstrumentStore()
+	}
+
+	return &UpstoxStreamer{
+		cfg:         newConfig(upstoxAuthorizeURL, opts),
+		accessToken: accessToken,
+		instruments: instruments,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (u *UpstoxStreamer) Name() string {
+	return "upstox"
+}
+
+func (u *UpstoxStreamer) Stream(ctx context.Context, exchange types.Exchange, symbols ...string) (<-chan types.Tick, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols to stream")
+	}
+
+	f := &upstoxFeed{
+		streamer: u,
+		exchange: exchange,
+		symbols:  make(map[string]string, len(symbols)),
+	}
+	for _, s := range symbols {
+		key, ok := u.instruments.Key(s, string(exchange))
+		if !ok {
+			return nil, fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, s, exchange)
+		}
+		f.symbols[key] = s
+		f.keys = append(f.keys, key)
+	}
+
+	out := make(chan types.Tick, u.cfg.bufferSize)
+	go run(ctx, f, u.cfg, out)
+
+	return out, nil
+}
+
+type upstoxFeed struct {
+	streamer *UpstoxStreamer
+	exchange types.Exchange
+	keys     []string
+	// symbols maps instrument keys back to trading symbols.
+	symbols map[string]string
+}
+
+// dial authorizes with the access token and returns the single-use feed URL
+// it grants.
+func (f *upstoxFeed) dial(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", f.streamer.cfg.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+f.streamer.accessToken)
+
+	res, err := f.streamer.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", &provider.ProviderError{Provider: f.streamer.Name(), StatusCode: res.StatusCode, Body: string(body)}
+	}
+
+	var resp struct {
+		Data struct {
+			URI string `json:"authorized_redirect_uri"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return resp.Data.URI, nil
+}
+
+// subscribe requests LTPC (last traded price, time, quantity and close)
+// updates for every instrument. The feed expects the request as a binary
+// frame.
+func (f *upstoxFeed) subscribe(ctx context.Context, conn *websocket.Conn) error {
+	msg, err := json.Marshal(map[string]any{
+		"guid":   uuid.NewString(),
+		"method": "sub",
+		"data": map[string]any{
+			"mode":           "ltpc",
+			"instrumentKeys": f.keys,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return conn.Write(ctx, websocket.MessageBinary, msg)
+}
+
+// decode reads a FeedResponse protobuf message. Only the fields needed for
+// ticks are decoded:
+//
+//	FeedResponse { map<string, Feed> feeds = 2; }
+//	Feed         { LTPC ltpc = 1; }
+//	LTPC         { double ltp = 1; int64 ltt = 2; int64 ltq = 3; }
+func (f *upstoxFeed) decode(typ websocket.MessageType, data []byte) ([]types.Tick, error) {
+	if typ != websocket.MessageBinary {
+		return nil, nil
+	}
+
+	var ticks []types.Tick
+	err := eachField(data, func(num protowire.Number, wt protowire.Type, v []byte) error {
+		if num != 2 || wt != protowire.BytesType {
+			return nil
+		}
+
+		var key string
+		var feed []byte
+		if err := eachField(v, func(num protowire.Number, wt protowire.Type, v []byte) error {
+			switch {
+			case num == 1 && wt == protowire.BytesType:
+				key = string(v)
+			case num == 2 && wt == protowire.BytesType:
+				feed = v
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		symbol, ok := f.symbols[key]
+		if !ok {
+			return nil
+		}
+
+		return eachField(feed, func(num protowire.Number, wt protowire.Type, v []byte) error {
+			if num != 1 || wt != protowire.BytesType {
+				return nil
+			}
+
+			tick, err := decodeLTPC(v)
+			if err != nil {
+				return err
+			}
+			tick.Symbol = symbol
+			tick.Exchange = f.exchange
+			tick.Source = f.streamer.Name()
+			ticks = append(ticks, tick)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode feed message: %w", err)
+	}
+
+	return ticks, nil
+}
+
+func decodeLTPC(data []byte) (types.Tick, error) {
+	loc := types.ExchangeNSE.Location()
+
+	var tick types.Tick
+	err := eachField(data, func(num protowire.Number, wt protowire.Type, v []byte) error {
+		switch {
+		case num == 1 && wt == protowire.Fixed64Type:
+			bits, _ := protowire.ConsumeFixed64(v)
+			tick.Price = math.Float64frombits(bits)
+		case num == 2 && wt == protowire.VarintType:
+			ms, _ := protowire.ConsumeVarint(v)
+			tick.DateTime = time.UnixMilli(int64(ms)).In(loc)
+		case num == 3 && wt == protowire.VarintType:
+			qty, _ := protowire.ConsumeVarint(v)
+			tick.Quantity = float64(qty)
+		}
+		return nil
+	})
+
+	return tick, err
+}
+
+// eachField calls fn for every top-level field of the protobuf message in
+// data. For length-delimited fields v holds the payload; for the others it
+// holds the raw encoded value.
+func eachField(data []byte, fn func(num protowire.Number, wt protowire.Type, v []byte) error) error {
+	for len(data) > 0 {
+		num, wt, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var v []byte
+		if wt == protowire.BytesType {
+			b, m := protowire.ConsumeBytes(data)
+			if m < 0 {
+				return protowire.ParseError(m)
+			}
+			v, n = b, m
+		} else {
+			n = protowire.ConsumeFieldValue(num, wt, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			v = data[:n]
+		}
+		data = data[n:]
+
+		if err := fn(num, wt, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
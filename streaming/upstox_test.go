@@ -0,0 +1,155 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// feedResponse encodes a FeedResponse carrying the LTPC of a single
+// instrument.
+func feedResponse(key string, ltp float64, ltt, ltq int64) []byte {
+	var ltpc []byte
+	ltpc = protowire.AppendTag(ltpc, 1, protowire.Fixed64Type)
+	ltpc = protowire.AppendFixed64(ltpc, math.Float64bits(ltp))
+	ltpc = protowire.AppendTag(ltpc, 2, protowire.VarintType)
+	ltpc = protowire.AppendVarint(ltpc, uint64(ltt))
+	ltpc = protowire.AppendTag(ltpc, 3, protowire.VarintType)
+	ltpc = protowire.AppendVarint(ltpc, uint64(ltq))
+
+	var feed []byte
+	feed = protowire.AppendTag(feed, 1, protowire.BytesType)
+	feed = protowire.AppendBytes(feed, ltpc)
+
+	var entry []byte
+	entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+	entry = protowire.AppendString(entry, key)
+	entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+	entry = protowire.AppendBytes(entry, feed)
+
+	var msg []byte
+	msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, 1)
+	msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, entry)
+	return msg
+}
+
+func TestUpstoxStreamer_Stream(t *testing.T) {
+	var (
+		auth string
+		sub  struct {
+			Method string `json:"method"`
+			Data   struct {
+				Mode           string   `json:"mode"`
+				InstrumentKeys []string `json:"instrumentKeys"`
+			} `json:"data"`
+		}
+	)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"status":"success","data":{"authorized_redirect_uri":"` + wsURL(srv) + `/feed"}}`))
+	})
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		_, data, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+		json.Unmarshal(data, &sub)
+
+		conn.Write(r.Context(), websocket.MessageBinary, feedResponse("NSE_EQ|INE002A01018", 2950.5, 1704180600000, 10))
+		conn.Read(r.Context())
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	u := NewUpstoxStreamer("token", nil, WithURL(srv.URL+"/authorize"))
+	ticks, err := u.Stream(ctx, types.ExchangeNSE, "RELIANCE")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tick := receive(t, ticks)
+	if auth != "Bearer token" {
+		t.Errorf("Expected Bearer token, got %q", auth)
+	}
+	if sub.Method != "sub" || sub.Data.Mode != "ltpc" || len(sub.Data.InstrumentKeys) != 1 || sub.Data.InstrumentKeys[0] != "NSE_EQ|INE002A01018" {
+		t.Errorf("Unexpected subscription: %+v", sub)
+	}
+	if tick.Symbol != "RELIANCE" || tick.Exchange != types.ExchangeNSE {
+		t.Errorf("Expected RELIANCE on NSE, got %+v", tick)
+	}
+	if tick.Price != 2950.5 || tick.Quantity != 10 {
+		t.Errorf("Expected 10 @ 2950.5, got %v @ %v", tick.Quantity, tick.Price)
+	}
+	if !tick.DateTime.Equal(time.UnixMilli(1704180600000)) {
+		t.Errorf("Expected %v, got %v", time.UnixMilli(1704180600000), tick.DateTime)
+	}
+	if tick.Source != "upstox" {
+		t.Errorf("Expected source upstox, got %s", tick.Source)
+	}
+
+	cancel()
+	for range ticks {
+	}
+}
+
+func TestUpstoxStreamer_Stream_SymbolNotFound(t *testing.T) {
+	u := NewUpstoxStreamer("token", nil)
+	_, err := u.Stream(context.Background(), types.ExchangeNSE, "UNKNOWN")
+	if !errors.Is(err, provider.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+}
+
+func TestUpstoxStreamer_Stream_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 1)
+	u := NewUpstoxStreamer("bad", nil,
+		WithURL(srv.URL),
+		WithReconnectBackoff(time.Hour, time.Hour),
+		WithErrorHandler(func(err error) { errs <- err }),
+	)
+	if _, err := u.Stream(ctx, types.ExchangeNSE, "RELIANCE"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		var pe *provider.ProviderError
+		if !errors.As(err, &pe) || pe.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected 401 ProviderError, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected an error, got timeout")
+	}
+}
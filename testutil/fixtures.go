@@ -0,0 +1,51 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+var ist = types.ExchangeNSE.Location()
+
+// DailyCandles returns the daily candles of RELIANCE on NSE for the first
+// trading week of 2024, stamped at midnight IST. Each call returns a new
+// slice.
+func DailyCandles() []types.OHLCV {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, ist) }
+	return []types.OHLCV{
+		fixture("RELIANCE", day(1), 2589.00, 2605.00, 2571.25, 2580.90, 3126489),
+		fixture("RELIANCE", day(2), 2580.90, 2597.70, 2566.40, 2584.50, 4474301),
+		fixture("RELIANCE", day(3), 2584.50, 2604.95, 2570.00, 2595.75, 5182354),
+		fixture("RELIANCE", day(4), 2598.00, 2641.00, 2595.55, 2630.20, 7912476),
+		fixture("RELIANCE", day(5), 2630.20, 2644.30, 2606.10, 2619.90, 5639210),
+	}
+}
+
+// MinuteCandles returns the first five one-minute candles of INFY on NSE on
+// 2 January 2024, from the 09:15 IST open. Each call returns a new slice.
+func MinuteCandles() []types.OHLCV {
+	minute := func(m int) time.Time { return time.Date(2024, 1, 2, 9, 15+m, 0, 0, ist) }
+	return []types.OHLCV{
+		fixture("INFY", minute(0), 1547.00, 1551.35, 1545.10, 1549.80, 98213),
+		fixture("INFY", minute(1), 1549.80, 1550.45, 1547.60, 1548.05, 41877),
+		fixture("INFY", minute(2), 1548.05, 1549.00, 1546.25, 1546.90, 36502),
+		fixture("INFY", minute(3), 1546.90, 1548.70, 1546.30, 1548.40, 29844),
+		fixture("INFY", minute(4), 1548.40, 1552.20, 1548.10, 1551.75, 52106),
+	}
+}
+
+func fixture(symbol string, t time.Time, open, high, low, close float64, volume int64) types.OHLCV {
+	return types.OHLCV{
+		Symbol:    symbol,
+		Exchange:  types.ExchangeNSE,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+		DateTime:  t,
+		Source:    "fixture",
+		Freshness: types.FreshnessHistorical,
+	}
+}
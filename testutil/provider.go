@@ -0,0 +1,313 @@
+// Package testutil helps applications test code built on MarketData without
+// calling real APIs: a deterministic synthetic provider and canned fixtures.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/marketdata"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+// Epoch is the first day SyntheticProvider has candles for. Earlier ranges
+// have no data.
+var Epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type config struct {
+	name       string
+	seed       uint64
+	startPrice float64
+	volatility float64
+	volume     int64
+	gaps       float64
+	freshness  types.DataFreshness
+	symbols    []string
+	err        error
+}
+
+type Option func(*config)
+
+// WithName sets the provider name. The default is "synthetic".
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithSeed sets the seed of the random walk. Providers with the same seed and
+// settings return the same candles. The default is 1.
+func WithSeed(seed uint64) Option {
+	return func(c *config) {
+		c.seed = seed
+	}
+}
+
+// WithStartPrice sets the price every symbol's walk starts from at Epoch. The
+// default is 100.
+func WithStartPrice(price float64) Option {
+	return func(c *config) {
+		c.startPrice = price
+	}
+}
+
+// WithVolatility sets the standard deviation of daily log returns. The
+// default is 0.02, a 2% daily move.
+func WithVolatility(v float64) Option {
+	return func(c *config) {
+		c.volatility = v
+	}
+}
+
+// WithDailyVolume sets the average volume traded per day. The default is
+// 1,000,000.
+func WithDailyVolume(volume int64) Option {
+	return func(c *config) {
+		c.volume = volume
+	}
+}
+
+// WithGaps drops each returned candle with probability p, to exercise gap
+// handling. Which candles are dropped is deterministic too.
+func WithGaps(p float64) Option {
+	return func(c *config) {
+		c.gaps = p
+	}
+}
+
+// WithFreshness sets the freshness of the provider and its candles. The
+// default is types.FreshnessHistorical.
+func WithFreshness(f types.DataFreshness) Option {
+	return func(c *config) {
+		c.freshness = f
+	}
+}
+
+// WithSymbols restricts the provider to symbols; others fail with
+// provider.ErrSymbolNotFound. By default every symbol has data.
+func WithSymbols(symbols ...string) Option {
+	return func(c *config) {
+		c.symbols = symbols
+	}
+}
+
+// WithError makes every Provide call fail with err, to exercise fallbacks.
+func WithError(err error) Option {
+	return func(c *config) {
+		c.err = err
+	}
+}
+
+// SyntheticProvider generates candles from a seeded random walk. Each trading
+// day's closes follow a daily walk from Epoch, and within the day's session
+// one-minute candles follow a Brownian bridge from the previous close to the
+// day's close. Every interval is resampled from those minutes, so a symbol's
+// candles agree across intervals and are the same whatever range is asked
+// for. Trading days and sessions come from the exchange's calendar.
+type SyntheticProvider struct {
+	name       string
+	seed       uint64
+	startPrice float64
+	volatility float64
+	volume     int64
+	gaps       float64
+	freshness  types.DataFreshness
+	symbols    []string
+	err        error
+
+	calls atomic.Int64
+}
+
+// NewSyntheticProvider creates a SyntheticProvider.
+func NewSyntheticProvider(opts ...Option) *SyntheticProvider {
+	cfg := config{
+		name:       "synthetic",
+		seed:       1,
+		startPrice: 100,
+		volatility: 0.02,
+		volume:     1_000_000,
+		freshness:  types.FreshnessHistorical,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &SyntheticProvider{
+		name:       cfg.name,
+		seed:       cfg.seed,
+		startPrice: cfg.startPrice,
+		volatility: cfg.volatility,
+		volume:     cfg.volume,
+		gaps:       cfg.gaps,
+		freshness:  cfg.freshness,
+		symbols:    cfg.symbols,
+		err:        cfg.err,
+	}
+}
+
+func (p *SyntheticProvider) Name() string {
+	return p.name
+}
+
+func (p *SyntheticProvider) Freshness() types.DataFreshness {
+	return p.freshness
+}
+
+// Calls returns how many times Provide has been called.
+func (p *SyntheticProvider) Calls() int {
+	return int(p.calls.Load())
+}
+
+// Provide returns the candles of symbol starting in [from, to).
+func (p *SyntheticProvider) Provide(ctx context.Context, symbol string, exchange types.Exchange, interval types.Interval, from, to time.Time) ([]types.OHLCV, error) {
+	p.calls.Add(1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.symbols != nil && !slices.Contains(p.symbols, symbol) {
+		return nil, fmt.Errorf("%w: %s on exchange %s", provider.ErrSymbolNotFound, symbol, exchange)
+	}
+	if interval != types.Interval1m && !ohlcv.CanResample(types.Interval1m, interval) {
+		return nil, fmt.Errorf("%w: %s", provider.ErrUnknownInterval, interval)
+	}
+
+	cal, err := calendar.ForExchange(exchange)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", provider.ErrUnsupportedExchange, err)
+	}
+	loc := cal.Location()
+
+	// Whole bars are generated, so the first and last are complete.
+	first, _ := ohlcv.Bucket(from.In(loc), interval)
+	last, _ := ohlcv.Bucket(to.In(loc), interval)
+	_, last = ohlcv.Bucket(last, interval)
+
+	minutes := p.minutes(symbol, exchange, cal, first, last)
+	minutes = ohlcv.Round(provider.Decimals(2)).Transform(minutes)
+	bars, err := ohlcv.Resample(minutes, types.Interval1m, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var ohlcvs []types.OHLCV
+	for _, bar := range bars {
+		if bar.DateTime.Before(from) || !bar.DateTime.Before(to) {
+			continue
+		}
+		if p.gaps > 0 && p.rng(symbol, bar.DateTime.UnixNano()).Float64() < p.gaps {
+			continue
+		}
+		ohlcvs = append(ohlcvs, bar)
+	}
+
+	if len(ohlcvs) == 0 {
+		return nil, fmt.Errorf("%w for symbol %s on exchange %s", provider.ErrNoData, symbol, exchange)
+	}
+	return ohlcvs, nil
+}
+
+// minutes returns the one-minute candles of the trading days from the day
+// of from until to.
+func (p *SyntheticProvider) minutes(symbol string, exchange types.Exchange, cal *calendar.Calendar, from, to time.Time) []types.OHLCV {
+	loc := cal.Location()
+	epoch := time.Date(Epoch.Year(), Epoch.Month(), Epoch.Day(), 0, 0, 0, 0, loc)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	if from.Before(epoch) {
+		from = epoch
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	// The daily walk is replayed from the epoch, so a day's close doesn't
+	// depend on the range asked for.
+	walk := p.rng(symbol, -1)
+	days := make(map[string][2]float64)
+	logPrice := math.Log(p.startPrice)
+	for day := epoch; day.Before(to); day = day.AddDate(0, 0, 1) {
+		prev := logPrice
+		logPrice += p.volatility * walk.NormFloat64()
+		if !day.Before(from) {
+			days[day.Format(time.DateOnly)] = [2]float64{prev, logPrice}
+		}
+	}
+
+	var candles []types.OHLCV
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		open, close, ok := cal.SessionBounds(day)
+		if !ok {
+			continue
+		}
+		prices := days[day.Format(time.DateOnly)]
+		candles = append(candles, p.session(symbol, exchange, day, open, close, prices[0], prices[1])...)
+	}
+	return candles
+}
+
+// session returns the one-minute candles from open to close, following a
+// Brownian bridge between the log prices start and end.
+func (p *SyntheticProvider) session(symbol string, exchange types.Exchange, day, open, close time.Time, start, end float64) []types.OHLCV {
+	n := int(close.Sub(open) / time.Minute)
+	if n <= 0 {
+		return nil
+	}
+
+	r := p.rng(symbol, day.Unix())
+	sigma := p.volatility / math.Sqrt(float64(n))
+
+	walk := make([]float64, n+1)
+	for j := 1; j <= n; j++ {
+		walk[j] = walk[j-1] + sigma*r.NormFloat64()
+	}
+
+	price := func(j int) float64 {
+		frac := float64(j) / float64(n)
+		return math.Exp(start + frac*(end-start) + walk[j] - frac*walk[n])
+	}
+
+	candles := make([]types.OHLCV, n)
+	perMinute := float64(p.volume) / float64(n)
+	for j := 1; j <= n; j++ {
+		o, c := price(j-1), price(j)
+		candles[j-1] = types.OHLCV{
+			Symbol:    symbol,
+			Exchange:  exchange,
+			Open:      o,
+			High:      max(o, c) * (1 + math.Abs(r.NormFloat64())*sigma/2),
+			Low:       min(o, c) * (1 - math.Abs(r.NormFloat64())*sigma/2),
+			Close:     c,
+			Volume:    int64(perMinute * (0.5 + r.Float64())),
+			DateTime:  open.Add(time.Duration(j-1) * time.Minute),
+			Interval:  types.Interval1m,
+			Source:    p.name,
+			Freshness: p.freshness,
+		}
+	}
+	return candles
+}
+
+// rng returns a generator seeded by the provider's seed, symbol and key.
+func (p *SyntheticProvider) rng(symbol string, key int64) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%d", p.seed, symbol, key)
+	sum := h.Sum64()
+	return rand.New(rand.NewPCG(sum, sum^p.seed))
+}
+
+// NewMarketData returns a MarketData for exchange served only by p, so tests
+// run offline.
+func NewMarketData(exchange types.Exchange, p provider.OHLCVProvider, opts ...marketdata.Option) *marketdata.MarketData {
+	return marketdata.NewMarketData(exchange, append([]marketdata.Option{marketdata.WithProviders(p)}, opts...)...)
+}
@@ -0,0 +1,172 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shahid-2020/gohlcv/calendar"
+	"github.com/shahid-2020/gohlcv/ohlcv"
+	"github.com/shahid-2020/gohlcv/provider"
+	"github.com/shahid-2020/gohlcv/types"
+)
+
+func TestSyntheticProvider_Deterministic(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, ist)
+	to := from.AddDate(0, 1, 0)
+
+	a, err := NewSyntheticProvider().Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b, _ := NewSyntheticProvider().Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, from, to)
+	if report := ohlcv.Compare(a, b, ohlcv.Tolerance{}); !report.Consistent() {
+		t.Errorf("Expected the same candles from the same seed, got %+v", report)
+	}
+
+	c, _ := NewSyntheticProvider(WithSeed(2)).Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, from, to)
+	if report := ohlcv.Compare(a, c, ohlcv.Tolerance{}); report.Consistent() {
+		t.Error("Expected another seed to give other candles")
+	}
+
+	// A sub-range returns the same candles as the full range.
+	mid := from.AddDate(0, 0, 10)
+	d, _ := NewSyntheticProvider().Provide(context.Background(), "RELIANCE", types.ExchangeNSE, types.Interval1d, mid, to)
+	if report := ohlcv.Compare(d, a, ohlcv.Tolerance{}); len(report.Discrepancies) != 0 || len(report.OnlyInA) != 0 {
+		t.Errorf("Expected a sub-range to match, got %+v", report)
+	}
+}
+
+func TestSyntheticProvider_TradingDays(t *testing.T) {
+	// 2024-01-22 and 2024-01-26 are NSE holidays.
+	from := time.Date(2024, 1, 22, 0, 0, 0, 0, ist)
+	to := from.AddDate(0, 0, 7)
+
+	data, err := NewSyntheticProvider().Provide(context.Background(), "INFY", types.ExchangeNSE, types.Interval1d, from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cal, _ := calendar.ForExchange(types.ExchangeNSE)
+	if len(data) != 3 {
+		t.Errorf("Expected 3 trading days, got %d", len(data))
+	}
+	for _, c := range data {
+		if !cal.IsTradingDay(c.DateTime) {
+			t.Errorf("Expected only trading days, got %v", c.DateTime)
+		}
+		if c.Low > min(c.Open, c.Close) || c.High < max(c.Open, c.Close) || c.Volume <= 0 {
+			t.Errorf("Expected a well-formed candle, got %+v", c)
+		}
+	}
+}
+
+func TestSyntheticProvider_IntervalsAgree(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	p := NewSyntheticProvider()
+
+	daily, err := p.Provide(context.Background(), "TCS", types.ExchangeNSE, types.Interval1d, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	minutes, err := p.Provide(context.Background(), "TCS", types.ExchangeNSE, types.Interval1m, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(minutes) != 375 {
+		t.Errorf("Expected 375 session minutes, got %d", len(minutes))
+	}
+	if !minutes[0].DateTime.Equal(day.Add(9*time.Hour + 15*time.Minute)) {
+		t.Errorf("Expected the first minute at the 09:15 open, got %v", minutes[0].DateTime)
+	}
+
+	resampled, _ := ohlcv.Resample(minutes, types.Interval1m, types.Interval1d)
+	if report := ohlcv.Compare(daily, resampled, ohlcv.Tolerance{}); !report.Consistent() {
+		t.Errorf("Expected the daily candle to match its minutes, got %+v", report)
+	}
+
+	// The first minute opens at the previous day's close.
+	prev, _ := p.Provide(context.Background(), "TCS", types.ExchangeNSE, types.Interval1d, day.AddDate(0, 0, -1), day)
+	if len(prev) != 1 || prev[0].Close != minutes[0].Open {
+		t.Errorf("Expected the day to open at the previous close, got %+v and %+v", prev, minutes[0])
+	}
+}
+
+func TestSyntheticProvider_Gaps(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	data, err := NewSyntheticProvider(WithGaps(0.5)).Provide(context.Background(), "TCS", types.ExchangeNSE, types.Interval1m, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) < 100 || len(data) > 275 {
+		t.Errorf("Expected about half of 375 minutes, got %d", len(data))
+	}
+}
+
+func TestSyntheticProvider_Errors(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, ist)
+	failure := errors.New("upstream down")
+
+	tests := []struct {
+		name     string
+		p        *SyntheticProvider
+		symbol   string
+		interval types.Interval
+		from     time.Time
+		target   error
+	}{
+		{"UnknownSymbol", NewSyntheticProvider(WithSymbols("INFY")), "TCS", types.Interval1d, day, provider.ErrSymbolNotFound},
+		{"UnknownInterval", NewSyntheticProvider(), "TCS", types.Interval5d, day, provider.ErrUnknownInterval},
+		{"BeforeEpoch", NewSyntheticProvider(), "TCS", types.Interval1d, Epoch.AddDate(-1, 0, 0), provider.ErrNoData},
+		{"Injected", NewSyntheticProvider(WithError(failure)), "TCS", types.Interval1d, day, failure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.p.Provide(context.Background(), tt.symbol, types.ExchangeNSE, tt.interval, tt.from, tt.from.AddDate(0, 0, 1))
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+			if tt.p.Calls() != 1 {
+				t.Errorf("Expected 1 call counted, got %d", tt.p.Calls())
+			}
+		})
+	}
+}
+
+func TestNewMarketData(t *testing.T) {
+	p := NewSyntheticProvider(WithStartPrice(1500))
+	md := NewMarketData(types.ExchangeNSE, p)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, ist)
+	data, err := md.Fetch(context.Background(), "INFY", types.Interval1wk, from, from.AddDate(0, 2, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data) < 8 || p.Calls() == 0 {
+		t.Errorf("Expected weekly candles from the synthetic provider, got %d in %d calls", len(data), p.Calls())
+	}
+	if data[0].Source != "synthetic" || data[0].DateTime.Weekday() != time.Monday {
+		t.Errorf("Unexpected candle: %+v", data[0])
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	cal, _ := calendar.ForExchange(types.ExchangeNSE)
+	session := ohlcv.SessionOf(cal)
+
+	if report := ohlcv.Validate(DailyCandles(), types.Interval1d, session); !report.Valid() {
+		t.Errorf("Expected valid daily fixtures, got %+v", report)
+	}
+	if report := ohlcv.Validate(MinuteCandles(), types.Interval1m, session); !report.Valid() {
+		t.Errorf("Expected valid minute fixtures, got %+v", report)
+	}
+
+	a := DailyCandles()
+	a[0].Close = 0
+	if DailyCandles()[0].Close == 0 {
+		t.Error("Expected each call to return a new slice")
+	}
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Decimal is a fixed-point number, Units scaled down by 10^Scale, for
+// callers that need exact prices rather than float64 approximations, such as
+// when summing or comparing prices with 4-8 decimal places.
+type Decimal struct {
+	Units int64
+	Scale uint8
+}
+
+// NewDecimal converts v to a Decimal with places decimal places, rounding
+// halves away from zero.
+func NewDecimal(v float64, places uint8) Decimal {
+	return Decimal{Units: int64(math.Round(v * math.Pow10(int(places)))), Scale: places}
+}
+
+// ParseDecimal parses a decimal string such as "1234.5678" exactly.
+func ParseDecimal(s string) (Decimal, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 18 {
+		return Decimal{}, fmt.Errorf("decimal %q: too many decimal places", s)
+	}
+
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("decimal %q: %w", s, err)
+	}
+
+	return Decimal{Units: units, Scale: uint8(len(frac))}, nil
+}
+
+// Float64 returns d as the closest float64.
+func (d Decimal) Float64() float64 {
+	return float64(d.Units) / math.Pow10(int(d.Scale))
+}
+
+// String formats d with exactly Scale decimal places.
+func (d Decimal) String() string {
+	sign, units := "", d.Units
+	if units < 0 {
+		sign, units = "-", -units
+	}
+
+	s := strconv.FormatInt(units, 10)
+	if d.Scale == 0 {
+		return sign + s
+	}
+
+	scale := int(d.Scale)
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-scale] + "." + s[len(s)-scale:]
+}
+
+// DecimalPrices returns the open, high, low and close of c as Decimals with
+// places decimal places.
+func (c OHLCV) DecimalPrices(places uint8) (open, high, low, close Decimal) {
+	return NewDecimal(c.Open, places), NewDecimal(c.High, places), NewDecimal(c.Low, places), NewDecimal(c.Close, places)
+}
@@ -0,0 +1,51 @@
+package types
+
+import "testing"
+
+func TestNewDecimal(t *testing.T) {
+	tests := []struct {
+		input    float64
+		places   uint8
+		expected string
+	}{
+		{2934.55, 2, "2934.55"},
+		{0.1 + 0.2, 2, "0.30"},
+		{0.00001234, 8, "0.00001234"},
+		{-1.5, 0, "-2"},
+		{-0.05, 2, "-0.05"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			if got := NewDecimal(tc.input, tc.places).String(); got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	d, err := ParseDecimal("1234.5678")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if d.Units != 12345678 || d.Scale != 4 {
+		t.Errorf("Expected 12345678e-4, got %de-%d", d.Units, d.Scale)
+	}
+	if d.Float64() != 1234.5678 {
+		t.Errorf("Expected 1234.5678, got %v", d.Float64())
+	}
+
+	if _, err := ParseDecimal("12a.5"); err == nil {
+		t.Error("Expected an error for an invalid decimal")
+	}
+}
+
+func TestOHLCV_DecimalPrices(t *testing.T) {
+	c := OHLCV{Open: 1.1, High: 1.25, Low: 1.05, Close: 1.2}
+
+	open, high, low, close := c.DecimalPrices(2)
+	if open.Units != 110 || high.Units != 125 || low.Units != 105 || close.Units != 120 {
+		t.Errorf("Expected 110, 125, 105, 120 hundredths, got %d, %d, %d, %d", open.Units, high.Units, low.Units, close.Units)
+	}
+}
@@ -0,0 +1,135 @@
+package types
+
+import "time"
+
+// Frame holds the candles of one symbol from one source column by column:
+// the i-th candle is Times[i], Opens[i], Highs[i] and so on. Processing a
+// long series column by column allocates less and stays in cache better than
+// a []OHLCV, whose every candle repeats the symbol, exchange and source.
+type Frame struct {
+	Symbol    string
+	Exchange  Exchange
+	Interval  Interval
+	Source    string
+	Freshness DataFreshness
+
+	Times             []time.Time
+	Opens             []float64
+	Highs             []float64
+	Lows              []float64
+	Closes            []float64
+	Volumes           []int64
+	OpenInterests     []int64
+	TradeCounts       []int64
+	AdjustmentFactors []float64
+	Incomplete        []bool
+	Sessions          []TradingSession
+}
+
+// NewFrame returns an empty frame with room for capacity candles.
+func NewFrame(capacity int) *Frame {
+	return &Frame{
+		Times:             make([]time.Time, 0, capacity),
+		Opens:             make([]float64, 0, capacity),
+		Highs:             make([]float64, 0, capacity),
+		Lows:              make([]float64, 0, capacity),
+		Closes:            make([]float64, 0, capacity),
+		Volumes:           make([]int64, 0, capacity),
+		OpenInterests:     make([]int64, 0, capacity),
+		TradeCounts:       make([]int64, 0, capacity),
+		AdjustmentFactors: make([]float64, 0, capacity),
+		Incomplete:        make([]bool, 0, capacity),
+		Sessions:          make([]TradingSession, 0, capacity),
+	}
+}
+
+// FrameOf returns a frame of candles, which are assumed to be of one symbol
+// from one source.
+func FrameOf(candles []OHLCV) *Frame {
+	f := NewFrame(len(candles))
+	for _, c := range candles {
+		f.Append(c)
+	}
+	return f
+}
+
+// Append adds c to the end of f. The first candle appended sets the symbol,
+// exchange, interval, source and freshness of f; those of later candles are
+// ignored.
+func (f *Frame) Append(c OHLCV) {
+	if f.Len() == 0 {
+		f.Symbol, f.Exchange, f.Source, f.Freshness = c.Symbol, c.Exchange, c.Source, c.Freshness
+		f.Interval = c.Interval
+	}
+
+	f.Times = append(f.Times, c.DateTime)
+	f.Opens = append(f.Opens, c.Open)
+	f.Highs = append(f.Highs, c.High)
+	f.Lows = append(f.Lows, c.Low)
+	f.Closes = append(f.Closes, c.Close)
+	f.Volumes = append(f.Volumes, c.Volume)
+	f.OpenInterests = append(f.OpenInterests, c.OpenInterest)
+	f.TradeCounts = append(f.TradeCounts, c.TradeCount)
+	f.AdjustmentFactors = append(f.AdjustmentFactors, c.AdjustmentFactor)
+	f.Incomplete = append(f.Incomplete, c.Incomplete)
+	f.Sessions = append(f.Sessions, c.Session)
+}
+
+// Len returns the number of candles in f.
+func (f *Frame) Len() int {
+	return len(f.Times)
+}
+
+// At returns the i-th candle of f.
+func (f *Frame) At(i int) OHLCV {
+	return OHLCV{
+		Symbol:           f.Symbol,
+		Exchange:         f.Exchange,
+		Open:             f.Opens[i],
+		High:             f.Highs[i],
+		Low:              f.Lows[i],
+		Close:            f.Closes[i],
+		Volume:           f.Volumes[i],
+		OpenInterest:     f.OpenInterests[i],
+		TradeCount:       f.TradeCounts[i],
+		DateTime:         f.Times[i],
+		Interval:         f.Interval,
+		Source:           f.Source,
+		Freshness:        f.Freshness,
+		AdjustmentFactor: f.AdjustmentFactors[i],
+		Incomplete:       f.Incomplete[i],
+		Session:          f.Sessions[i],
+	}
+}
+
+// Candles returns the candles of f as a Series.
+func (f *Frame) Candles() Series {
+	out := make(Series, f.Len())
+	for i := range out {
+		out[i] = f.At(i)
+	}
+	return out
+}
+
+// Slice returns the candles [i, j) of f. The result shares its columns with
+// f.
+func (f *Frame) Slice(i, j int) *Frame {
+	return &Frame{
+		Symbol:            f.Symbol,
+		Exchange:          f.Exchange,
+		Interval:          f.Interval,
+		Source:            f.Source,
+		Freshness:         f.Freshness,
+		Times:             f.Times[i:j],
+		Opens:             f.Opens[i:j],
+		Highs:             f.Highs[i:j],
+		Lows:              f.Lows[i:j],
+		Closes:            f.Closes[i:j],
+		Volumes:           f.Volumes[i:j],
+		OpenInterests:     f.OpenInterests[i:j],
+		TradeCounts:       f.TradeCounts[i:j],
+		AdjustmentFactors: f.AdjustmentFactors[i:j],
+		Incomplete:        f.Incomplete[i:j],
+		Sessions:          f.Sessions[i:j],
+	}
+}
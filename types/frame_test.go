@@ -0,0 +1,75 @@
+package types
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func sampleFrameCandles() Series {
+	s := sampleSeries().SortByTime()
+	for i := range s {
+		s[i].Symbol = "RELIANCE"
+		s[i].Exchange = ExchangeNSE
+		s[i].Interval = Interval1d
+		s[i].Source = "yahoo"
+		s[i].Freshness = FreshnessDelayed
+	}
+	s[1].AdjustmentFactor = 0.5
+	s[1].Incomplete = true
+	s[2].Session = SessionRegular
+	s[2].OpenInterest = 5000
+	s[2].TradeCount = 42
+	return s
+}
+
+func TestFrameOf(t *testing.T) {
+	candles := sampleFrameCandles()
+	f := FrameOf(candles)
+
+	if f.Len() != 3 {
+		t.Fatalf("Expected 3 candles, got %d", f.Len())
+	}
+	if f.Symbol != "RELIANCE" || f.Exchange != ExchangeNSE || f.Source != "yahoo" || f.Freshness != FreshnessDelayed || f.Interval != Interval1d {
+		t.Errorf("Unexpected frame metadata: %s %s %s %s %s", f.Symbol, f.Exchange, f.Source, f.Freshness, f.Interval)
+	}
+	if !slices.Equal(f.Closes, []float64{11, 12, 14}) {
+		t.Errorf("Expected closes [11 12 14], got %v", f.Closes)
+	}
+	if !slices.Equal(f.Volumes, []int64{100, 200, 300}) {
+		t.Errorf("Expected volumes [100 200 300], got %v", f.Volumes)
+	}
+}
+
+func TestFrame_Candles(t *testing.T) {
+	candles := sampleFrameCandles()
+
+	if got := FrameOf(candles).Candles(); !reflect.DeepEqual(got, candles) {
+		t.Errorf("Expected the candles back unchanged, got %v", got)
+	}
+}
+
+func TestFrame_Slice(t *testing.T) {
+	f := FrameOf(sampleFrameCandles())
+	s := f.Slice(1, 3)
+
+	if s.Len() != 2 || s.Symbol != "RELIANCE" {
+		t.Fatalf("Expected 2 RELIANCE candles, got %d %s", s.Len(), s.Symbol)
+	}
+	if c := s.At(0); c.Close != 12 || !c.Incomplete || c.AdjustmentFactor != 0.5 {
+		t.Errorf("Unexpected first candle: %+v", c)
+	}
+
+	s.Closes[0] = 99
+	if f.Closes[1] != 99 {
+		t.Error("Expected the slice to share its columns with the frame")
+	}
+}
+
+func TestFrame_Empty(t *testing.T) {
+	f := NewFrame(0)
+
+	if f.Len() != 0 || len(f.Candles()) != 0 {
+		t.Errorf("Expected an empty frame, got %d candles", f.Len())
+	}
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidInterval is returned by ParseInterval for strings that aren't an
+// interval.
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// intervalUnits are the units an interval counts, longest suffix first so
+// "mo" isn't read as minutes.
+var intervalUnits = []string{"mo", "wk", "m", "h", "d"}
+
+// ParseInterval parses s as a count followed by a unit: m for minutes, h for
+// hours, d for days, wk for weeks or mo for months. Besides the Interval
+// constants this allows any multiple, such as "2m", "75m" or "4h", which
+// providers don't serve but ohlcv.Resample can build. The count is
+// normalized, so "05m" parses as "5m".
+func ParseInterval(s string) (Interval, error) {
+	n, unit, ok := Interval(strings.TrimSpace(s)).Split()
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidInterval, s)
+	}
+	return Interval(strconv.Itoa(n) + unit), nil
+}
+
+// Split returns the count and unit of i, such as 75 and "m" for "75m", and
+// false if i isn't a positive count followed by a known unit, or is a count
+// of minutes or hours too long for a time.Duration.
+func (i Interval) Split() (n int, unit string, ok bool) {
+	for _, u := range intervalUnits {
+		count, found := strings.CutSuffix(string(i), u)
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 || strings.HasPrefix(count, "+") {
+			return 0, "", false
+		}
+		if (u == "m" && int64(n) > math.MaxInt64/int64(time.Minute)) || (u == "h" && int64(n) > math.MaxInt64/int64(time.Hour)) {
+			return 0, "", false
+		}
+		return n, u, true
+	}
+	return 0, "", false
+}
+
+// Valid reports whether i parses as an interval.
+func (i Interval) Valid() bool {
+	_, _, ok := i.Split()
+	return ok
+}
+
+// Duration returns the length of an intraday interval, or zero for daily and
+// longer intervals, whose length varies with the calendar, and for invalid
+// ones.
+func (i Interval) Duration() time.Duration {
+	n, unit, ok := i.Split()
+	switch {
+	case !ok:
+		return 0
+	case unit == "m":
+		return time.Duration(n) * time.Minute
+	case unit == "h":
+		return time.Duration(n) * time.Hour
+	default:
+		return 0
+	}
+}
+
+// IsIntraday reports whether i is shorter than a day: a count of minutes or
+// hours under 24 hours.
+func (i Interval) IsIntraday() bool {
+	d := i.Duration()
+	return d > 0 && d < 24*time.Hour
+}
+
+// Truncate returns the start of the bar of i containing t, in the location
+// of t: intraday bars are counted from midnight, weeks start on Monday, and
+// months on the first of the month, counted from January for multiples of
+// months. Multiples of days are counted from the day of t. It returns t
+// unchanged if i is invalid.
+func (i Interval) Truncate(t time.Time) time.Time {
+	n, unit, ok := i.Split()
+	if !ok {
+		return t
+	}
+
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch unit {
+	case "d":
+		return day
+	case "wk":
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case "mo":
+		month := (int(t.Month()) - 1) / n * n
+		return time.Date(t.Year(), time.Month(month+1), 1, 0, 0, 0, 0, t.Location())
+	default:
+		d := i.Duration()
+		if d <= 0 {
+			return t
+		}
+		return day.Add(t.Sub(day) / d * d)
+	}
+}
+
+// advance returns t moved forward by one i, or the zero time if i is
+// invalid.
+func (i Interval) advance(t time.Time) time.Time {
+	n, unit, ok := i.Split()
+	if !ok {
+		return time.Time{}
+	}
+
+	switch unit {
+	case "d":
+		return t.AddDate(0, 0, n)
+	case "wk":
+		return t.AddDate(0, 0, 7*n)
+	case "mo":
+		return t.AddDate(0, n, 0)
+	default:
+		return t.Add(i.Duration())
+	}
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"slices"
+	"time"
+)
+
+// Series is a sequence of candles, usually of one symbol and interval.
+type Series []OHLCV
+
+// SortByTime sorts s in place by DateTime, keeping the order of candles with
+// equal times, and returns it.
+func (s Series) SortByTime() Series {
+	slices.SortStableFunc(s, func(a, b OHLCV) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+	return s
+}
+
+// Between returns the candles with DateTime in [from, to]. A zero from or to
+// leaves that side open.
+func (s Series) Between(from, to time.Time) Series {
+	var out Series
+	for _, c := range s {
+		if !from.IsZero() && c.DateTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && c.DateTime.After(to) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// Last returns the last n candles of s, or all of them if s is shorter.
+func (s Series) Last(n int) Series {
+	if n <= 0 {
+		return nil
+	}
+	if n > len(s) {
+		n = len(s)
+	}
+	return s[len(s)-n:]
+}
+
+func (s Series) Opens() []float64 {
+	return s.values(func(c OHLCV) float64 { return c.Open })
+}
+
+func (s Series) Highs() []float64 {
+	return s.values(func(c OHLCV) float64 { return c.High })
+}
+
+func (s Series) Lows() []float64 {
+	return s.values(func(c OHLCV) float64 { return c.Low })
+}
+
+func (s Series) Closes() []float64 {
+	return s.values(func(c OHLCV) float64 { return c.Close })
+}
+
+func (s Series) Volumes() []int64 {
+	out := make([]int64, len(s))
+	for i, c := range s {
+		out[i] = c.Volume
+	}
+	return out
+}
+
+// TypicalPrice returns (high + low + close) / 3 of every candle.
+func (s Series) TypicalPrice() []float64 {
+	return s.values(func(c OHLCV) float64 { return (c.High + c.Low + c.Close) / 3 })
+}
+
+// VWAP returns the volume-weighted average of the typical price over the
+// whole series, or zero if it has no volume.
+func (s Series) VWAP() float64 {
+	var pv, volume float64
+	for _, c := range s {
+		pv += (c.High + c.Low + c.Close) / 3 * float64(c.Volume)
+		volume += float64(c.Volume)
+	}
+	if volume == 0 {
+		return 0
+	}
+	return pv / volume
+}
+
+func (s Series) values(field func(OHLCV) float64) []float64 {
+	out := make([]float64, len(s))
+	for i, c := range s {
+		out[i] = field(c)
+	}
+	return out
+}
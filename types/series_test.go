@@ -0,0 +1,83 @@
+package types
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func sampleSeries() Series {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	return Series{
+		{DateTime: day.AddDate(0, 0, 2), Open: 12, High: 15, Low: 11, Close: 14, Volume: 300},
+		{DateTime: day, Open: 10, High: 12, Low: 9, Close: 11, Volume: 100},
+		{DateTime: day.AddDate(0, 0, 1), Open: 11, High: 13, Low: 10, Close: 12, Volume: 200},
+	}
+}
+
+func TestSeries_SortByTime(t *testing.T) {
+	s := sampleSeries().SortByTime()
+
+	if got := s.Closes(); !slices.Equal(got, []float64{11, 12, 14}) {
+		t.Errorf("Expected closes [11 12 14], got %v", got)
+	}
+}
+
+func TestSeries_Between(t *testing.T) {
+	s := sampleSeries().SortByTime()
+	day := s[0].DateTime
+
+	if got := s.Between(day.AddDate(0, 0, 1), day.AddDate(0, 0, 2)); len(got) != 2 || got[0].Close != 12 {
+		t.Errorf("Expected the last 2 candles, got %v", got)
+	}
+	if got := s.Between(time.Time{}, day); len(got) != 1 || got[0].Close != 11 {
+		t.Errorf("Expected the first candle, got %v", got)
+	}
+}
+
+func TestSeries_Last(t *testing.T) {
+	s := sampleSeries().SortByTime()
+
+	if got := s.Last(2); len(got) != 2 || got[1].Close != 14 {
+		t.Errorf("Expected the last 2 candles, got %v", got)
+	}
+	if got := s.Last(10); len(got) != 3 {
+		t.Errorf("Expected all 3 candles, got %d", len(got))
+	}
+	if got := s.Last(0); got != nil {
+		t.Errorf("Expected nil, got %v", got)
+	}
+}
+
+func TestSeries_Values(t *testing.T) {
+	s := sampleSeries().SortByTime()
+
+	if got := s.Opens(); !slices.Equal(got, []float64{10, 11, 12}) {
+		t.Errorf("Expected opens [10 11 12], got %v", got)
+	}
+	if got := s.Highs(); !slices.Equal(got, []float64{12, 13, 15}) {
+		t.Errorf("Expected highs [12 13 15], got %v", got)
+	}
+	if got := s.Lows(); !slices.Equal(got, []float64{9, 10, 11}) {
+		t.Errorf("Expected lows [9 10 11], got %v", got)
+	}
+	if got := s.Volumes(); !slices.Equal(got, []int64{100, 200, 300}) {
+		t.Errorf("Expected volumes [100 200 300], got %v", got)
+	}
+	if got := s.TypicalPrice(); !slices.Equal(got, []float64{32.0 / 3, 35.0 / 3, 40.0 / 3}) {
+		t.Errorf("Unexpected typical prices %v", got)
+	}
+}
+
+func TestSeries_VWAP(t *testing.T) {
+	s := sampleSeries()
+
+	want := (32.0/3*100 + 35.0/3*200 + 40.0/3*300) / 600
+	if got := s.VWAP(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected VWAP %v, got %v", want, got)
+	}
+	if got := (Series{{High: 10, Low: 10, Close: 10}}).VWAP(); got != 0 {
+		t.Errorf("Expected 0 without volume, got %v", got)
+	}
+}
@@ -1,18 +1,79 @@
 package types
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 type Market string
 
 const (
 	MarketStocks Market = "stocks"
+	MarketCrypto Market = "crypto"
 )
 
 type Exchange string
 
 const (
-	ExchangeNSE Exchange = "NSE"
-	ExchangeBSE Exchange = "BSE"
+	ExchangeNSE    Exchange = "NSE"
+	ExchangeBSE    Exchange = "BSE"
+	ExchangeNASDAQ Exchange = "NASDAQ"
+	ExchangeNYSE   Exchange = "NYSE"
+
+	ExchangeBinance  Exchange = "BINANCE"
+	ExchangeCoinbase Exchange = "COINBASE"
+)
+
+// Market returns the market e belongs to; exchanges not known to trade
+// crypto are stock exchanges.
+func (e Exchange) Market() Market {
+	switch e {
+	case ExchangeBinance, ExchangeCoinbase:
+		return MarketCrypto
+	default:
+		return MarketStocks
+	}
+}
+
+// exchangeZones maps exchanges to the IANA time zone they trade in.
+var exchangeZones = map[Exchange]string{
+	ExchangeNSE:    "Asia/Kolkata",
+	ExchangeBSE:    "Asia/Kolkata",
+	ExchangeNASDAQ: "America/New_York",
+	ExchangeNYSE:   "America/New_York",
+	// Crypto trades around the clock, so its days are counted in UTC.
+	ExchangeBinance:  "UTC",
+	ExchangeCoinbase: "UTC",
+}
+
+var locations sync.Map
+
+// Location returns the time zone e trades in, which candles of e are
+// reported in. Unknown exchanges, and zones missing from the system's time
+// zone database, fall back to UTC.
+func (e Exchange) Location() *time.Location {
+	if loc, ok := locations.Load(e); ok {
+		return loc.(*time.Location)
+	}
+
+	loc := time.UTC
+	if name, ok := exchangeZones[e]; ok {
+		if l, err := time.LoadLocation(name); err == nil {
+			loc = l
+		}
+	}
+
+	locations.Store(e, loc)
+	return loc
+}
+
+// TradingSession is a part of the trading day.
+type TradingSession string
+
+const (
+	SessionPre     TradingSession = "pre"
+	SessionRegular TradingSession = "regular"
+	SessionPost    TradingSession = "post"
 )
 
 type DataFreshness string
@@ -25,16 +86,50 @@ const (
 )
 
 type OHLCV struct {
-	Symbol    string        `json:"symbol"`
-	Exchange  Exchange      `json:"exchange"`
-	Open      float64       `json:"open"`
-	High      float64       `json:"high"`
-	Low       float64       `json:"low"`
-	Close     float64       `json:"close"`
-	Volume    int64         `json:"volume"`
-	DateTime  time.Time     `json:"datetime"`
+	Symbol   string    `json:"symbol"`
+	Exchange Exchange  `json:"exchange"`
+	Open     float64   `json:"open"`
+	High     float64   `json:"high"`
+	Low      float64   `json:"low"`
+	Close    float64   `json:"close"`
+	Volume   int64     `json:"volume"`
+	DateTime time.Time `json:"datetime"`
+	// Interval is the bar size of the candle, which opens at DateTime. It is
+	// empty when the producer of the candle didn't say.
+	Interval  Interval      `json:"interval,omitempty"`
 	Source    string        `json:"source"`
 	Freshness DataFreshness `json:"freshness"`
+	// OpenInterest is the number of derivative contracts outstanding at the
+	// close of the candle, for futures and options from providers that
+	// report it, such as Upstox. It is zero otherwise.
+	OpenInterest int64 `json:"open_interest,omitempty"`
+	// TradeCount is the number of trades in the candle, from providers and
+	// feeds that report it, such as Binance. It is zero when unknown.
+	TradeCount int64 `json:"trade_count,omitempty"`
+	// AdjustmentFactor is the factor applied to the prices of candles
+	// adjusted for splits and dividends; dividing by it gives raw prices. It
+	// is zero for raw candles.
+	AdjustmentFactor float64 `json:"adjustment_factor,omitempty"`
+	// Incomplete is set on candles the provider reported with missing
+	// values, such as minutes a symbol was halted. Their missing prices are
+	// zero or, if forward-filled, copied from the previous close.
+	Incomplete bool `json:"incomplete,omitempty"`
+	// Session is the part of the trading day an intraday candle falls in. It
+	// is empty for daily and longer candles and when the provider doesn't
+	// say.
+	Session TradingSession `json:"session,omitempty"`
+}
+
+// IsComplete reports whether the provider reported every value of c.
+func (c OHLCV) IsComplete() bool {
+	return !c.Incomplete
+}
+
+// EndTime returns when the bar of c closes: DateTime advanced by one
+// Interval, in calendar days, weeks or months for daily and longer
+// intervals. It returns the zero time if Interval is empty or invalid.
+func (c OHLCV) EndTime() time.Time {
+	return c.Interval.advance(c.DateTime)
 }
 
 type Interval string
@@ -51,3 +146,162 @@ const (
 	Interval1mo Interval = "1mo"
 	Interval3mo Interval = "3mo"
 )
+
+type Instrument struct {
+	Symbol         string   `json:"symbol"`
+	Name           string   `json:"name"`
+	Exchange       Exchange `json:"exchange"`
+	ISIN           string   `json:"isin"`
+	Segment        string   `json:"segment"`
+	InstrumentType string   `json:"instrument_type"`
+	LotSize        int      `json:"lot_size"`
+	TickSize       float64  `json:"tick_size"`
+}
+
+type Dividend struct {
+	Symbol   string    `json:"symbol"`
+	Exchange Exchange  `json:"exchange"`
+	Date     time.Time `json:"date"`
+	Amount   float64   `json:"amount"`
+}
+
+// Split is a stock split of Numerator new shares for every Denominator held.
+type Split struct {
+	Symbol      string    `json:"symbol"`
+	Exchange    Exchange  `json:"exchange"`
+	Date        time.Time `json:"date"`
+	Numerator   float64   `json:"numerator"`
+	Denominator float64   `json:"denominator"`
+}
+
+type CorporateActions struct {
+	Dividends []Dividend `json:"dividends"`
+	Splits    []Split    `json:"splits"`
+}
+
+// Quote is the latest price of a symbol along with the current session's
+// range.
+type Quote struct {
+	Symbol        string    `json:"symbol"`
+	Exchange      Exchange  `json:"exchange"`
+	LastPrice     float64   `json:"last_price"`
+	Open          float64   `json:"open"`
+	High          float64   `json:"high"`
+	Low           float64   `json:"low"`
+	PreviousClose float64   `json:"previous_close"`
+	Volume        int64     `json:"volume"`
+	DateTime      time.Time `json:"datetime"`
+	Source        string    `json:"source"`
+}
+
+// EventKind is the kind of a CalendarEvent.
+type EventKind string
+
+const (
+	EventEarnings        EventKind = "earnings"
+	EventExDividend      EventKind = "ex_dividend"
+	EventDividendPayment EventKind = "dividend_payment"
+)
+
+// CalendarEvent is a scheduled corporate event of a symbol, such as an
+// earnings announcement or an ex-dividend date.
+type CalendarEvent struct {
+	Symbol   string    `json:"symbol"`
+	Exchange Exchange  `json:"exchange"`
+	Kind     EventKind `json:"kind"`
+	Date     time.Time `json:"date"`
+	// EndDate is set when the event isn't confirmed for Date but expected
+	// between Date and EndDate, as earnings often are.
+	EndDate time.Time `json:"end_date"`
+	// EPSEstimate and RevenueEstimate are the average analyst estimates for
+	// earnings events, zero otherwise.
+	EPSEstimate     float64 `json:"eps_estimate,omitempty"`
+	RevenueEstimate float64 `json:"revenue_estimate,omitempty"`
+	Source          string  `json:"source"`
+}
+
+// Fundamentals is a snapshot of the key statistics of a company. Values the
+// provider doesn't report are zero.
+type Fundamentals struct {
+	Symbol            string   `json:"symbol"`
+	Exchange          Exchange `json:"exchange"`
+	Currency          string   `json:"currency"`
+	MarketCap         float64  `json:"market_cap"`
+	SharesOutstanding int64    `json:"shares_outstanding"`
+	TrailingPE        float64  `json:"trailing_pe"`
+	ForwardPE         float64  `json:"forward_pe"`
+	TrailingEPS       float64  `json:"trailing_eps"`
+	ForwardEPS        float64  `json:"forward_eps"`
+	PriceToBook       float64  `json:"price_to_book"`
+	// DividendYield is a fraction of the price: 0.012 is 1.2%.
+	DividendYield    float64 `json:"dividend_yield"`
+	Beta             float64 `json:"beta"`
+	FiftyTwoWeekHigh float64 `json:"fifty_two_week_high"`
+	FiftyTwoWeekLow  float64 `json:"fifty_two_week_low"`
+	Source           string  `json:"source"`
+}
+
+// DepthLevel is one price level of an order book.
+type DepthLevel struct {
+	Price    float64 `json:"price"`
+	Quantity int64   `json:"quantity"`
+	// Orders is the number of orders at the price, zero if not reported.
+	Orders int `json:"orders,omitempty"`
+}
+
+// Depth is a snapshot of the order book of a symbol. Bids and Asks hold the
+// best levels the provider reports, best first: the first of each is the
+// top of the book.
+type Depth struct {
+	Symbol   string       `json:"symbol"`
+	Exchange Exchange     `json:"exchange"`
+	Bids     []DepthLevel `json:"bids"`
+	Asks     []DepthLevel `json:"asks"`
+	// TotalBidQuantity and TotalAskQuantity are the quantities pending
+	// across the whole book, not only the levels in Bids and Asks.
+	TotalBidQuantity int64     `json:"total_bid_quantity"`
+	TotalAskQuantity int64     `json:"total_ask_quantity"`
+	DateTime         time.Time `json:"datetime"`
+	Source           string    `json:"source"`
+}
+
+// BestBid returns the highest bid, if there is one.
+func (d Depth) BestBid() (DepthLevel, bool) {
+	if len(d.Bids) == 0 {
+		return DepthLevel{}, false
+	}
+	return d.Bids[0], true
+}
+
+// BestAsk returns the lowest ask, if there is one.
+func (d Depth) BestAsk() (DepthLevel, bool) {
+	if len(d.Asks) == 0 {
+		return DepthLevel{}, false
+	}
+	return d.Asks[0], true
+}
+
+// Spread returns the best ask less the best bid, or zero if either side of
+// the book is empty.
+func (d Depth) Spread() float64 {
+	bid, ok := d.BestBid()
+	ask, ok2 := d.BestAsk()
+	if !ok || !ok2 {
+		return 0
+	}
+	return ask.Price - bid.Price
+}
+
+// Tick is a single trade or last-traded-price update from a live feed.
+type Tick struct {
+	Symbol   string    `json:"symbol"`
+	Exchange Exchange  `json:"exchange"`
+	Price    float64   `json:"price"`
+	Quantity float64   `json:"quantity"`
+	DateTime time.Time `json:"datetime"`
+	Source   string    `json:"source"`
+	// Trades is the number of trades the tick stands for: 1 for feeds of
+	// individual trades, such as Binance's, and zero for feeds of price
+	// updates, which may cover several trades.
+	Trades int64 `json:"trades,omitempty"`
+}
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExchange_Location(t *testing.T) {
+	tests := []struct {
+		exchange Exchange
+		expected string
+	}{
+		{ExchangeNSE, "Asia/Kolkata"},
+		{ExchangeBSE, "Asia/Kolkata"},
+		{ExchangeNASDAQ, "America/New_York"},
+		{ExchangeNYSE, "America/New_York"},
+		{ExchangeBinance, "UTC"},
+		{Exchange("UNKNOWN"), "UTC"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.exchange), func(t *testing.T) {
+			if loc := tc.exchange.Location(); loc.String() != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, loc)
+			}
+		})
+	}
+}
+
+func TestExchange_Location_Cached(t *testing.T) {
+	if ExchangeNSE.Location() != ExchangeNSE.Location() {
+		t.Error("Expected the same location on every call")
+	}
+	if _, offset := time.Date(2024, 1, 2, 0, 0, 0, 0, ExchangeNSE.Location()).Zone(); offset != 5*3600+1800 {
+		t.Errorf("Expected IST offset, got %d", offset)
+	}
+}
+
+func TestExchange_Market(t *testing.T) {
+	tests := []struct {
+		exchange Exchange
+		expected Market
+	}{
+		{ExchangeNSE, MarketStocks},
+		{ExchangeNYSE, MarketStocks},
+		{ExchangeBinance, MarketCrypto},
+		{ExchangeCoinbase, MarketCrypto},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.exchange), func(t *testing.T) {
+			if market := tc.exchange.Market(); market != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, market)
+			}
+		})
+	}
+}
+
+func TestOHLCV_EndTime(t *testing.T) {
+	ist := ExchangeNSE.Location()
+	open := time.Date(2024, 1, 31, 9, 15, 0, 0, ist)
+	tests := []struct {
+		interval Interval
+		expected time.Time
+	}{
+		{Interval1m, time.Date(2024, 1, 31, 9, 16, 0, 0, ist)},
+		{Interval1h, time.Date(2024, 1, 31, 10, 15, 0, 0, ist)},
+		{Interval1d, time.Date(2024, 2, 1, 9, 15, 0, 0, ist)},
+		{Interval1wk, time.Date(2024, 2, 7, 9, 15, 0, 0, ist)},
+		{Interval1mo, time.Date(2024, 3, 2, 9, 15, 0, 0, ist)},
+		{Interval3mo, time.Date(2024, 5, 1, 9, 15, 0, 0, ist)},
+		{"75m", time.Date(2024, 1, 31, 10, 30, 0, 0, ist)},
+		{"2d", time.Date(2024, 2, 2, 9, 15, 0, 0, ist)},
+		{"", time.Time{}},
+		{"2x", time.Time{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.interval), func(t *testing.T) {
+			c := OHLCV{DateTime: open, Interval: tc.interval}
+			if got := c.EndTime(); !got.Equal(tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestInterval_Duration(t *testing.T) {
+	if d := Interval15m.Duration(); d != 15*time.Minute {
+		t.Errorf("Expected 15m, got %v", d)
+	}
+	if d := Interval("4h").Duration(); d != 4*time.Hour {
+		t.Errorf("Expected 4h, got %v", d)
+	}
+	if d := Interval1d.Duration(); d != 0 {
+		t.Errorf("Expected no fixed length for 1d, got %v", d)
+	}
+}
+
+func TestInterval_IsIntraday(t *testing.T) {
+	for _, i := range []Interval{Interval1m, "75m", Interval1h, "4h"} {
+		if !i.IsIntraday() {
+			t.Errorf("Expected %s to be intraday", i)
+		}
+	}
+	for _, i := range []Interval{Interval1d, Interval1wk, Interval3mo, "24h", "1440m", "2x"} {
+		if i.IsIntraday() {
+			t.Errorf("Expected %s not to be intraday", i)
+		}
+	}
+}
+
+func TestInterval_Truncate(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	at := time.Date(2024, 5, 15, 10, 47, 30, 0, ist) // a Wednesday
+
+	tests := []struct {
+		interval Interval
+		expected time.Time
+	}{
+		{Interval15m, time.Date(2024, 5, 15, 10, 45, 0, 0, ist)},
+		{"75m", time.Date(2024, 5, 15, 10, 0, 0, 0, ist)},
+		{Interval1h, time.Date(2024, 5, 15, 10, 0, 0, 0, ist)},
+		{Interval1d, time.Date(2024, 5, 15, 0, 0, 0, 0, ist)},
+		{Interval1wk, time.Date(2024, 5, 13, 0, 0, 0, 0, ist)},
+		{Interval1mo, time.Date(2024, 5, 1, 0, 0, 0, 0, ist)},
+		{Interval3mo, time.Date(2024, 4, 1, 0, 0, 0, 0, ist)},
+		{"2x", at},
+		{"9007199254740992m", at},
+	}
+	for _, tc := range tests {
+		if got := tc.interval.Truncate(at); !got.Equal(tc.expected) || got.Location() != ist {
+			t.Errorf("%s: expected %v, got %v", tc.interval, tc.expected, got)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Interval
+	}{
+		{"1m", Interval1m},
+		{"75m", "75m"},
+		{" 4h", "4h"},
+		{"05m", "5m"},
+		{"3mo", Interval3mo},
+		{"2wk", "2wk"},
+	}
+	for _, tc := range tests {
+		if got, err := ParseInterval(tc.in); err != nil || got != tc.expected {
+			t.Errorf("ParseInterval(%q): expected %s, got %s, %v", tc.in, tc.expected, got, err)
+		}
+	}
+
+	for _, in := range []string{"", "m", "0m", "-5m", "+5m", "1.5h", "4hours", "1y", "9007199254740992m", "2562048h"} {
+		if _, err := ParseInterval(in); !errors.Is(err, ErrInvalidInterval) {
+			t.Errorf("ParseInterval(%q): expected ErrInvalidInterval, got %v", in, err)
+		}
+	}
+}
+
+func TestDepth_BestBidAsk(t *testing.T) {
+	d := Depth{
+		Bids: []DepthLevel{{Price: 99.5, Quantity: 10}, {Price: 99.4, Quantity: 20}},
+		Asks: []DepthLevel{{Price: 100, Quantity: 5}},
+	}
+
+	if bid, ok := d.BestBid(); !ok || bid.Price != 99.5 {
+		t.Errorf("Expected best bid 99.5, got %+v", bid)
+	}
+	if ask, ok := d.BestAsk(); !ok || ask.Price != 100 {
+		t.Errorf("Expected best ask 100, got %+v", ask)
+	}
+	if s := d.Spread(); s != 0.5 {
+		t.Errorf("Expected spread 0.5, got %v", s)
+	}
+
+	d.Asks = nil
+	if _, ok := d.BestAsk(); ok || d.Spread() != 0 {
+		t.Error("Expected no best ask and no spread for a one-sided book")
+	}
+}
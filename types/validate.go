@@ -0,0 +1,111 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// IssueKind identifies a data-integrity problem found in candles.
+type IssueKind string
+
+const (
+	// IssueHighBelowLow is a candle whose high is below its low.
+	IssueHighBelowLow IssueKind = "high_below_low"
+	// IssueHighBelowBody is a candle whose high is below its open or close.
+	IssueHighBelowBody IssueKind = "high_below_body"
+	// IssueLowAboveBody is a candle whose low is above its open or close.
+	IssueLowAboveBody IssueKind = "low_above_body"
+	// IssueNegativePrice is a candle with a negative open, high, low or close.
+	IssueNegativePrice IssueKind = "negative_price"
+	// IssueNegativeVolume is a candle with a negative volume.
+	IssueNegativeVolume IssueKind = "negative_volume"
+	// IssueDuplicate is a candle at the same time as an earlier one.
+	IssueDuplicate IssueKind = "duplicate"
+	// IssueOutOfOrder is a candle earlier than the one before it.
+	IssueOutOfOrder IssueKind = "out_of_order"
+)
+
+// Issue is a problem found in the candle at Index of the validated candles.
+type Issue struct {
+	Index  int
+	Kind   IssueKind
+	Candle OHLCV
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s at %s", i.Kind, i.Candle.DateTime.Format(time.RFC3339))
+}
+
+// ValidationError is returned by OHLCV.Validate and Series.Validate, listing
+// every issue found.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Issues) == 1 {
+		return "invalid candle: " + e.Issues[0].String()
+	}
+	return fmt.Sprintf("%d candle issues, the first %s", len(e.Issues), e.Issues[0])
+}
+
+// Validate checks that the high of c is at or above its low, open and close,
+// that its low is at or below its open and close, and that no price or the
+// volume is negative. It returns a *ValidationError listing what is wrong,
+// or nil.
+func (c OHLCV) Validate() error {
+	if issues := c.issues(0, nil); len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+// issues appends the issues of c, the index-th candle, to issues.
+func (c OHLCV) issues(index int, issues []Issue) []Issue {
+	add := func(kind IssueKind) {
+		issues = append(issues, Issue{Index: index, Kind: kind, Candle: c})
+	}
+
+	if c.High < c.Low {
+		add(IssueHighBelowLow)
+	}
+	if c.High < c.Open || c.High < c.Close {
+		add(IssueHighBelowBody)
+	}
+	if c.Low > c.Open || c.Low > c.Close {
+		add(IssueLowAboveBody)
+	}
+	if c.Open < 0 || c.High < 0 || c.Low < 0 || c.Close < 0 {
+		add(IssueNegativePrice)
+	}
+	if c.Volume < 0 {
+		add(IssueNegativeVolume)
+	}
+	return issues
+}
+
+// Validate checks every candle of s as OHLCV.Validate does, and that their
+// times are in order without duplicates. It returns a *ValidationError
+// listing every issue, in order of the candles, or nil.
+func (s Series) Validate() error {
+	var issues []Issue
+	seen := make(map[time.Time]bool, len(s))
+	for i, c := range s {
+		issues = c.issues(i, issues)
+
+		key := c.DateTime.UTC()
+		if seen[key] {
+			issues = append(issues, Issue{Index: i, Kind: IssueDuplicate, Candle: c})
+		}
+		seen[key] = true
+
+		if i > 0 && c.DateTime.Before(s[i-1].DateTime) {
+			issues = append(issues, Issue{Index: i, Kind: IssueOutOfOrder, Candle: c})
+		}
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOHLCV_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		candle   OHLCV
+		expected []IssueKind
+	}{
+		{"valid", OHLCV{Open: 100, High: 105, Low: 98, Close: 102, Volume: 10}, nil},
+		{"flat", OHLCV{Open: 100, High: 100, Low: 100, Close: 100}, nil},
+		{"high below low", OHLCV{Open: 100, High: 98, Low: 105, Close: 100}, []IssueKind{IssueHighBelowLow, IssueHighBelowBody, IssueLowAboveBody}},
+		{"high below close", OHLCV{Open: 100, High: 104, Low: 98, Close: 106}, []IssueKind{IssueHighBelowBody}},
+		{"low above open", OHLCV{Open: 97, High: 104, Low: 98, Close: 100}, []IssueKind{IssueLowAboveBody}},
+		{"negative price", OHLCV{Open: -1, High: 1, Low: -1, Close: 0}, []IssueKind{IssueNegativePrice}},
+		{"negative volume", OHLCV{Open: 1, High: 1, Low: 1, Close: 1, Volume: -5}, []IssueKind{IssueNegativeVolume}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.candle.Validate()
+			if tc.expected == nil {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected a *ValidationError, got %v", err)
+			}
+			var kinds []IssueKind
+			for _, issue := range verr.Issues {
+				kinds = append(kinds, issue.Kind)
+			}
+			if !slices.Equal(kinds, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, kinds)
+			}
+		})
+	}
+}
+
+func TestSeries_Validate(t *testing.T) {
+	at := func(m int) time.Time { return time.Date(2024, 1, 2, 9, 15+m, 0, 0, time.UTC) }
+	s := Series{
+		{Open: 100, High: 101, Low: 99, Close: 100, DateTime: at(0)},
+		{Open: 100, High: 99, Low: 98, Close: 100, DateTime: at(2)},
+		{Open: 100, High: 101, Low: 99, Close: 100, DateTime: at(1)},
+		{Open: 100, High: 101, Low: 99, Close: 100, DateTime: at(1)},
+	}
+
+	var verr *ValidationError
+	if !errors.As(s.Validate(), &verr) {
+		t.Fatal("Expected a *ValidationError")
+	}
+
+	expected := []Issue{
+		{Index: 1, Kind: IssueHighBelowBody, Candle: s[1]},
+		{Index: 2, Kind: IssueOutOfOrder, Candle: s[2]},
+		{Index: 3, Kind: IssueDuplicate, Candle: s[3]},
+	}
+	if !slices.Equal(verr.Issues, expected) {
+		t.Errorf("Expected %v, got %v", expected, verr.Issues)
+	}
+	if msg := verr.Error(); msg != "3 candle issues, the first high_below_body at 2024-01-02T09:17:00Z" {
+		t.Errorf("Unexpected message %q", msg)
+	}
+
+	if err := s[:1].Validate(); err != nil {
+		t.Errorf("Expected a valid series, got %v", err)
+	}
+}